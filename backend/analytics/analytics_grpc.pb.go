@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: analytics.proto
+
+package analytics
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AnalyticsService_GetSpendingBreakdown_FullMethodName = "/analytics.AnalyticsService/GetSpendingBreakdown"
+	AnalyticsService_GetCashFlow_FullMethodName          = "/analytics.AnalyticsService/GetCashFlow"
+	AnalyticsService_GetTopItems_FullMethodName          = "/analytics.AnalyticsService/GetTopItems"
+	AnalyticsService_ComparePeriods_FullMethodName       = "/analytics.AnalyticsService/ComparePeriods"
+)
+
+// AnalyticsServiceClient is the client API for AnalyticsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Aggregates over the caller's products, incomes and transfers, computed in
+// the database. Spend is the sum of product line totals, quantity times
+// price; of split products, only the parts the caller keeps count, each
+// under its own category. Dates are YYYY-MM-DD; from_date defaults to the first of the
+// current month and to_date, which is inclusive, to today.
+type AnalyticsServiceClient interface {
+	GetSpendingBreakdown(ctx context.Context, in *SpendingBreakdownRequest, opts ...grpc.CallOption) (*SpendingBreakdown, error)
+	GetCashFlow(ctx context.Context, in *CashFlowRequest, opts ...grpc.CallOption) (*CashFlow, error)
+	GetTopItems(ctx context.Context, in *TopItemsRequest, opts ...grpc.CallOption) (*TopItems, error)
+	ComparePeriods(ctx context.Context, in *ComparePeriodsRequest, opts ...grpc.CallOption) (*PeriodComparison, error)
+}
+
+type analyticsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnalyticsServiceClient(cc grpc.ClientConnInterface) AnalyticsServiceClient {
+	return &analyticsServiceClient{cc}
+}
+
+func (c *analyticsServiceClient) GetSpendingBreakdown(ctx context.Context, in *SpendingBreakdownRequest, opts ...grpc.CallOption) (*SpendingBreakdown, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SpendingBreakdown)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetSpendingBreakdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetCashFlow(ctx context.Context, in *CashFlowRequest, opts ...grpc.CallOption) (*CashFlow, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CashFlow)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetCashFlow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetTopItems(ctx context.Context, in *TopItemsRequest, opts ...grpc.CallOption) (*TopItems, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopItems)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetTopItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) ComparePeriods(ctx context.Context, in *ComparePeriodsRequest, opts ...grpc.CallOption) (*PeriodComparison, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PeriodComparison)
+	err := c.cc.Invoke(ctx, AnalyticsService_ComparePeriods_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AnalyticsServiceServer is the server API for AnalyticsService service.
+// All implementations must embed UnimplementedAnalyticsServiceServer
+// for forward compatibility.
+//
+// Aggregates over the caller's products, incomes and transfers, computed in
+// the database. Spend is the sum of product line totals, quantity times
+// price; of split products, only the parts the caller keeps count, each
+// under its own category. Dates are YYYY-MM-DD; from_date defaults to the first of the
+// current month and to_date, which is inclusive, to today.
+type AnalyticsServiceServer interface {
+	GetSpendingBreakdown(context.Context, *SpendingBreakdownRequest) (*SpendingBreakdown, error)
+	GetCashFlow(context.Context, *CashFlowRequest) (*CashFlow, error)
+	GetTopItems(context.Context, *TopItemsRequest) (*TopItems, error)
+	ComparePeriods(context.Context, *ComparePeriodsRequest) (*PeriodComparison, error)
+	mustEmbedUnimplementedAnalyticsServiceServer()
+}
+
+// UnimplementedAnalyticsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAnalyticsServiceServer struct{}
+
+func (UnimplementedAnalyticsServiceServer) GetSpendingBreakdown(context.Context, *SpendingBreakdownRequest) (*SpendingBreakdown, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpendingBreakdown not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetCashFlow(context.Context, *CashFlowRequest) (*CashFlow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCashFlow not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetTopItems(context.Context, *TopItemsRequest) (*TopItems, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopItems not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) ComparePeriods(context.Context, *ComparePeriodsRequest) (*PeriodComparison, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComparePeriods not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) mustEmbedUnimplementedAnalyticsServiceServer() {}
+func (UnimplementedAnalyticsServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeAnalyticsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnalyticsServiceServer will
+// result in compilation errors.
+type UnsafeAnalyticsServiceServer interface {
+	mustEmbedUnimplementedAnalyticsServiceServer()
+}
+
+func RegisterAnalyticsServiceServer(s grpc.ServiceRegistrar, srv AnalyticsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAnalyticsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AnalyticsService_ServiceDesc, srv)
+}
+
+func _AnalyticsService_GetSpendingBreakdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendingBreakdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetSpendingBreakdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetSpendingBreakdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetSpendingBreakdown(ctx, req.(*SpendingBreakdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetCashFlow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CashFlowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetCashFlow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetCashFlow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetCashFlow(ctx, req.(*CashFlowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetTopItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetTopItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetTopItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetTopItems(ctx, req.(*TopItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_ComparePeriods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComparePeriodsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).ComparePeriods(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_ComparePeriods_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).ComparePeriods(ctx, req.(*ComparePeriodsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AnalyticsService_ServiceDesc is the grpc.ServiceDesc for AnalyticsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AnalyticsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "analytics.AnalyticsService",
+	HandlerType: (*AnalyticsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSpendingBreakdown",
+			Handler:    _AnalyticsService_GetSpendingBreakdown_Handler,
+		},
+		{
+			MethodName: "GetCashFlow",
+			Handler:    _AnalyticsService_GetCashFlow_Handler,
+		},
+		{
+			MethodName: "GetTopItems",
+			Handler:    _AnalyticsService_GetTopItems_Handler,
+		},
+		{
+			MethodName: "ComparePeriods",
+			Handler:    _AnalyticsService_ComparePeriods_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "analytics.proto",
+}
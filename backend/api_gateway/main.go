@@ -49,6 +49,7 @@ func main() {
 
 	// File upload endpoint using Echo
 	e.POST("/upload", utils.Upload)
+	e.GET("/files/:id/content", utils.FileContent)
 	e.POST("/refresh", utils.SetRefreshTokenHandler)
 	e.GET("/get-refresh-token", utils.GetRefreshTokenHandler)
 
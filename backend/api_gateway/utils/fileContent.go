@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	pb "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// FileContent serves a stored receipt image through the File Service. When
+// objects are encrypted at rest this is the only way a browser can load them.
+func FileContent(c echo.Context) error {
+	if fileServiceClient == nil {
+		log.Print("ERROR: File Service gRPC client not initialized - call InitFileServiceClient() first")
+		return c.JSON(500, map[string]interface{}{
+			"success": false,
+			"error":   "File Service not configured - server configuration error",
+		})
+	}
+
+	fileID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(400, map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Invalid file id: %v", err),
+		})
+	}
+
+	_, token, refreshToken, httpErr := authenticate(c)
+	if httpErr != nil {
+		return authFailure(c, httpErr)
+	}
+
+	grpcCtx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	md := metadata.Pairs("refresh_token", refreshToken, "authentication", fmt.Sprintf("Bearer %s", token))
+	grpcCtx = metadata.NewOutgoingContext(grpcCtx, md)
+
+	content, err := fileServiceClient.GetFileContent(grpcCtx, &pb.GetFileContentRequest{
+		FileId:  int32(fileID),
+		Variant: c.QueryParam("variant"),
+	})
+	if err != nil {
+		log.Printf("ERROR: gRPC GetFileContent for file %d failed: %v", fileID, err)
+		code := 500
+		switch status.Code(err) {
+		case codes.NotFound:
+			code = 404
+		case codes.InvalidArgument:
+			code = 400
+		case codes.Unauthenticated:
+			code = 401
+		}
+		return c.JSON(code, map[string]interface{}{
+			"success": false,
+			"error":   status.Convert(err).Message(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "private, max-age=3600")
+	return c.Blob(200, content.ContentType, content.Data)
+}
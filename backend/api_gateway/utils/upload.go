@@ -14,10 +14,16 @@ import (
 	"github.com/Aneesh-Hegde/expenseManager/middleware"
 	"github.com/labstack/echo/v4"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// maxChunkBytes is the largest upload chunk the File Service accepts, its
+// gRPC server's default message size less room for the other fields.
+const maxChunkBytes = 4<<20 - 64<<10
+
 var fileServiceClient pb.FileServiceClient
 var grpcClientOnce sync.Once
 
@@ -25,7 +31,9 @@ var grpcClientOnce sync.Once
 func InitFileServiceClient(fileServiceAddr string) {
 	grpcClientOnce.Do(func() {
 		log.Printf("Connecting to File Service gRPC server at: %s", fileServiceAddr)
-		conn, err := grpc.NewClient(fileServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		// File content responses carry whole images, larger than gRPC's 4 MiB default.
+		conn, err := grpc.NewClient(fileServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(32<<20)))
 		if err != nil {
 			log.Fatalf("Failed to connect to File Service: %v", err)
 		}
@@ -34,35 +42,10 @@ func InitFileServiceClient(fileServiceAddr string) {
 	})
 }
 
-// Upload handles the HTTP request for file uploads in the API Gateway.
-func Upload(c echo.Context) error {
-	if fileServiceClient == nil {
-		log.Print("ERROR: File Service gRPC client not initialized - call InitFileServiceClient() first")
-		return c.JSON(500, map[string]interface{}{
-			"success": false,
-			"error":   "File Service not configured - server configuration error",
-		})
-	}
-
-	log.Print("API Gateway: Starting upload process...")
-
-	fileHeader, err := c.FormFile("file")
-	if err != nil {
-		log.Printf("ERROR: Error getting file from form: %v", err)
-		return c.JSON(400, map[string]interface{}{
-			"success": false,
-			"error":   fmt.Sprintf("Error getting file: %v", err),
-		})
-	}
-
-	log.Printf("API Gateway: File received: %s", fileHeader.Filename)
-
-	chunkNumberStr := c.FormValue("chunk_number")
-	totalChunksStr := c.FormValue("total_chunks")
-	filename := c.FormValue("filename")
-	// userIdStr := c.FormValue("userId")
-	// refreshToken := c.FormValue("refresh_token")
-var token, refreshToken string
+// authenticate resolves the user from the token cookies, refreshing the access
+// token if needed. It returns the user ID and both tokens for forwarding.
+func authenticate(c echo.Context) (int, string, string, *echo.HTTPError) {
+	var token, refreshToken string
 	
 	// Try to get tokens from cookies first
 	if tokenCookie, err := c.Cookie("token"); err == nil {
@@ -80,29 +63,72 @@ var token, refreshToken string
 			_, refreshedUserID, refreshErr := middleware.RefreshAccessToken(refreshToken)
 			if refreshErr != nil {
 				log.Printf("API Gateway: Refresh token failed: %v", refreshErr)
-				return c.JSON(401, map[string]interface{}{
-					"success": false,
-					"error":   "Authentication failed: Invalid or expired tokens",
-				})
+				return 0, "", "", echo.NewHTTPError(401, "Authentication failed: Invalid or expired tokens")
 			}
 			userId = refreshedUserID
 			log.Printf("API Gateway: Token refreshed, new user ID: %d", userId)
 		} else {
-			return c.JSON(401, map[string]interface{}{
-				"success": false,
-				"error":   "Authentication required: No valid user ID token or refresh token",
-			})
+			return 0, "", "", echo.NewHTTPError(401, "Authentication required: No valid user ID token or refresh token")
 		}
 	}
 
 	if userId == 0 {
 		log.Printf("API Gateway: Failed to determine user ID.")
+		return 0, "", "", echo.NewHTTPError(400, "Invalid or missing user ID")
+	}
+
+	return userId, token, refreshToken, nil
+}
+
+func authFailure(c echo.Context, httpErr *echo.HTTPError) error {
+	return c.JSON(httpErr.Code, map[string]interface{}{
+		"success": false,
+		"error":   httpErr.Message,
+	})
+}
+
+// Upload handles the HTTP request for file uploads in the API Gateway.
+func Upload(c echo.Context) error {
+	if fileServiceClient == nil {
+		log.Print("ERROR: File Service gRPC client not initialized - call InitFileServiceClient() first")
+		return c.JSON(500, map[string]interface{}{
+			"success": false,
+			"error":   "File Service not configured - server configuration error",
+		})
+	}
+
+	log.Print("API Gateway: Starting upload process...")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("ERROR: Error getting file from form: %v", err)
 		return c.JSON(400, map[string]interface{}{
 			"success": false,
-			"error":   "Invalid or missing user ID",
+			"error":   fmt.Sprintf("Error getting file: %v", err),
 		})
 	}
 
+	log.Printf("API Gateway: File received: %s", fileHeader.Filename)
+
+	// Reject chunks the File Service could not receive before reading them;
+	// it validates content and enforces the size limits and quotas itself.
+	if fileHeader.Size > maxChunkBytes {
+		return c.JSON(413, map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("chunks must be at most %d MiB", maxChunkBytes>>20),
+		})
+	}
+
+	chunkNumberStr := c.FormValue("chunk_number")
+	totalChunksStr := c.FormValue("total_chunks")
+	filename := c.FormValue("filename")
+	// userIdStr := c.FormValue("userId")
+	// refreshToken := c.FormValue("refresh_token")
+	userId, token, refreshToken, httpErr := authenticate(c)
+	if httpErr != nil {
+		return authFailure(c, httpErr)
+	}
+
 	chunkNum, err := strconv.Atoi(chunkNumberStr)
 	if err != nil {
 		log.Printf("ERROR: Invalid chunk number '%s': %v", chunkNumberStr, err)
@@ -159,6 +185,23 @@ var token, refreshToken string
 	grpcRes, err := fileServiceClient.UploadFile(grpcCtx, grpcReq)
 	if err != nil {
 		log.Printf("ERROR: gRPC call to File Service failed: %v", err)
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			return c.JSON(400, map[string]interface{}{
+				"success": false,
+				"error":   status.Convert(err).Message(),
+			})
+		case codes.ResourceExhausted:
+			return c.JSON(413, map[string]interface{}{
+				"success": false,
+				"error":   status.Convert(err).Message(),
+			})
+		case codes.AlreadyExists:
+			return c.JSON(409, map[string]interface{}{
+				"success": false,
+				"error":   status.Convert(err).Message(),
+			})
+		}
 		return c.JSON(500, map[string]interface{}{
 			"success": false,
 			"error":   fmt.Sprintf("File processing service error: %v", err),
@@ -173,5 +216,6 @@ var token, refreshToken string
 		"image_url": grpcRes.ImageUrl,
 		"user_id":   grpcRes.UserId,
 		"chunk":     grpcRes.ChunkStatus,
+		"file_id":   grpcRes.FileId,
 	})
 }
@@ -10,6 +10,7 @@ import (
 	// "github.com/Aneesh-Hegde/expenseManager/states"
 	pb "github.com/Aneesh-Hegde/expenseManager/grpc"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
 	"github.com/Aneesh-Hegde/expenseManager/states"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
@@ -57,76 +58,27 @@ func CloseDB() {
 }
 
 func StoreProductData(ctx context.Context, userID int, filename string, products []*pb.Product) (*pb.DBMessage, error) {
-	// Step 1: Collect all unique categories from the products
-	categoryNames := make(map[string]bool)
-	for _, product := range products {
-		categoryNames[product.Category] = true
-	}
-
-	// Convert map keys to a slice for the query
-	categoryNamesList := make([]string, 0, len(categoryNames))
-	for category := range categoryNames {
-		categoryNamesList = append(categoryNamesList, category)
-	}
-
-	// Step 2: Query for existing categories using the ANY operator
-	categoryQuery := `SELECT category_id, name FROM product_category_service.categories WHERE name = ANY($1)`
-	rows, err := DB.Query(context.Background(), categoryQuery, categoryNamesList)
+	// Step 1: Begin transaction for categories and products
+	tx, err := DB.Begin(ctx)
 	if err != nil {
-		log.Printf("Error querying categories: %v", err)
+		log.Printf("Error starting transaction: %v", err)
 		return nil, err
 	}
-	defer rows.Close()
-
-	// Step 3: Store the existing categories in a map
-	existingCategories := map[string]int{}
-	for rows.Next() {
-		var categoryID int
-		var categoryName string
-		if err := rows.Scan(&categoryID, &categoryName); err != nil {
-			log.Printf("Error scanning category: %v", err)
-			return nil, err
-		}
-		existingCategories[categoryName] = categoryID
-	}
+	defer tx.Rollback(context.Background())
 
-	// Check for any errors during row iteration
-	if err := rows.Err(); err != nil {
-		log.Printf("Error during rows iteration: %v", err)
+	// Step 2: Map the extracted category labels onto the user's taxonomy
+	// instead of matching or creating global categories by name
+	if err := productDB.EnsureUserCategories(ctx, tx, int32(userID)); err != nil {
+		log.Printf("Error seeding categories: %v", err)
 		return nil, err
 	}
-
-	// Step 4: Handle missing categories and insert them if necessary
-	missingCategories := []string{}
-	for category := range categoryNames {
-		if _, exists := existingCategories[category]; !exists {
-			missingCategories = append(missingCategories, category)
-		}
-	}
-
-	if len(missingCategories) > 0 {
-		// Insert missing categories
-		insertCategoryQuery := `INSERT INTO product_category_service.categories (name) VALUES ($1) RETURNING category_id`
-		for _, category := range missingCategories {
-			var newCategoryID int
-			err := DB.QueryRow(context.Background(), insertCategoryQuery, category).Scan(&newCategoryID)
-			if err != nil {
-				log.Printf("Error inserting missing category: %v", err)
-				return nil, err
-			}
-			existingCategories[category] = newCategoryID
-		}
-	}
-
-	// Step 5: Begin transaction for inserting products
-	tx, err := DB.Begin(ctx)
+	resolver, err := productDB.LoadCategoryResolver(ctx, tx, int32(userID))
 	if err != nil {
-		log.Printf("Error starting transaction: %v", err)
+		log.Printf("Error loading categories: %v", err)
 		return nil, err
 	}
-	defer tx.Rollback(context.Background())
 
-	// Step 6: Insert products into the database
+	// Step 3: Insert products into the database
 	insertProductQuery := `
         INSERT INTO product_category_service.products (user_id, product_name, quantity, price, category_id, file_name, description,date_added)
         VALUES ($1, $2, $3, $4, $5, $6, $7,TO_TIMESTAMP($8,'DD/MM/YYYY')) RETURNING product_id`
@@ -139,7 +91,7 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 	var updatedProducts []states.Product
 	var message string
 	for _, product := range products {
-		categoryID := existingCategories[product.Category]
+		categoryID := resolver.Resolve(product.Category).CategoryID
 		var count, productID int
 		// Check if product exists
 		id, _ := strconv.Atoi(product.Id)
@@ -192,7 +144,7 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 		// Inserting product data into the database
 	}
 
-	// Step 7: Commit the transaction
+	// Step 4: Commit the transaction
 	if err := tx.Commit(context.Background()); err != nil {
 		log.Printf("Error committing transaction: %v", err)
 		return nil, err
@@ -0,0 +1,603 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: group.proto
+
+package group
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GroupService_CreateGroup_FullMethodName              = "/group.GroupService/CreateGroup"
+	GroupService_ListGroups_FullMethodName               = "/group.GroupService/ListGroups"
+	GroupService_GetGroup_FullMethodName                 = "/group.GroupService/GetGroup"
+	GroupService_InviteGroupMember_FullMethodName        = "/group.GroupService/InviteGroupMember"
+	GroupService_ListGroupInvitations_FullMethodName     = "/group.GroupService/ListGroupInvitations"
+	GroupService_RespondToGroupInvitation_FullMethodName = "/group.GroupService/RespondToGroupInvitation"
+	GroupService_RemoveGroupMember_FullMethodName        = "/group.GroupService/RemoveGroupMember"
+	GroupService_AddGroupExpense_FullMethodName          = "/group.GroupService/AddGroupExpense"
+	GroupService_ListGroupExpenses_FullMethodName        = "/group.GroupService/ListGroupExpenses"
+	GroupService_DeleteGroupExpense_FullMethodName       = "/group.GroupService/DeleteGroupExpense"
+	GroupService_GetGroupBalances_FullMethodName         = "/group.GroupService/GetGroupBalances"
+	GroupService_SettleUp_FullMethodName                 = "/group.GroupService/SettleUp"
+	GroupService_ListSettlements_FullMethodName          = "/group.GroupService/ListSettlements"
+)
+
+// GroupServiceClient is the client API for GroupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Groups of users sharing expenses, served by the user service. Every call
+// is limited to groups the caller is in. Amounts are exact to the cent;
+// dates are YYYY-MM-DD and default to today.
+type GroupServiceClient interface {
+	CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*GroupList, error)
+	GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error)
+	// Invites a user by email; they join once they accept. Unknown emails
+	// fail with the same error whatever the reason, so accounts cannot be
+	// discovered this way.
+	InviteGroupMember(ctx context.Context, in *InviteGroupMemberRequest, opts ...grpc.CallOption) (*Group, error)
+	ListGroupInvitations(ctx context.Context, in *ListGroupInvitationsRequest, opts ...grpc.CallOption) (*GroupInvitationList, error)
+	// Accepting joins the group and returns it; declining returns an empty
+	// group.
+	RespondToGroupInvitation(ctx context.Context, in *RespondToGroupInvitationRequest, opts ...grpc.CallOption) (*Group, error)
+	// Members can leave, and the creator remove others, once their balance
+	// in the group is settled. The creator also withdraws invitations here.
+	RemoveGroupMember(ctx context.Context, in *RemoveGroupMemberRequest, opts ...grpc.CallOption) (*Group, error)
+	AddGroupExpense(ctx context.Context, in *AddGroupExpenseRequest, opts ...grpc.CallOption) (*GroupExpense, error)
+	ListGroupExpenses(ctx context.Context, in *ListGroupExpensesRequest, opts ...grpc.CallOption) (*GroupExpenseList, error)
+	// Expenses recorded from receipts are removed with
+	// FileService.AssignFileToGroup instead.
+	DeleteGroupExpense(ctx context.Context, in *DeleteGroupExpenseRequest, opts ...grpc.CallOption) (*DeleteGroupExpenseResponse, error)
+	GetGroupBalances(ctx context.Context, in *GetGroupBalancesRequest, opts ...grpc.CallOption) (*GroupBalances, error)
+	SettleUp(ctx context.Context, in *SettleUpRequest, opts ...grpc.CallOption) (*Settlement, error)
+	ListSettlements(ctx context.Context, in *ListSettlementsRequest, opts ...grpc.CallOption) (*SettlementList, error)
+}
+
+type groupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGroupServiceClient(cc grpc.ClientConnInterface) GroupServiceClient {
+	return &groupServiceClient{cc}
+}
+
+func (c *groupServiceClient) CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, GroupService_CreateGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*GroupList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupList)
+	err := c.cc.Invoke(ctx, GroupService_ListGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, GroupService_GetGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) InviteGroupMember(ctx context.Context, in *InviteGroupMemberRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, GroupService_InviteGroupMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) ListGroupInvitations(ctx context.Context, in *ListGroupInvitationsRequest, opts ...grpc.CallOption) (*GroupInvitationList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupInvitationList)
+	err := c.cc.Invoke(ctx, GroupService_ListGroupInvitations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) RespondToGroupInvitation(ctx context.Context, in *RespondToGroupInvitationRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, GroupService_RespondToGroupInvitation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) RemoveGroupMember(ctx context.Context, in *RemoveGroupMemberRequest, opts ...grpc.CallOption) (*Group, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Group)
+	err := c.cc.Invoke(ctx, GroupService_RemoveGroupMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) AddGroupExpense(ctx context.Context, in *AddGroupExpenseRequest, opts ...grpc.CallOption) (*GroupExpense, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupExpense)
+	err := c.cc.Invoke(ctx, GroupService_AddGroupExpense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) ListGroupExpenses(ctx context.Context, in *ListGroupExpensesRequest, opts ...grpc.CallOption) (*GroupExpenseList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupExpenseList)
+	err := c.cc.Invoke(ctx, GroupService_ListGroupExpenses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) DeleteGroupExpense(ctx context.Context, in *DeleteGroupExpenseRequest, opts ...grpc.CallOption) (*DeleteGroupExpenseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteGroupExpenseResponse)
+	err := c.cc.Invoke(ctx, GroupService_DeleteGroupExpense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) GetGroupBalances(ctx context.Context, in *GetGroupBalancesRequest, opts ...grpc.CallOption) (*GroupBalances, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupBalances)
+	err := c.cc.Invoke(ctx, GroupService_GetGroupBalances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) SettleUp(ctx context.Context, in *SettleUpRequest, opts ...grpc.CallOption) (*Settlement, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Settlement)
+	err := c.cc.Invoke(ctx, GroupService_SettleUp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *groupServiceClient) ListSettlements(ctx context.Context, in *ListSettlementsRequest, opts ...grpc.CallOption) (*SettlementList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SettlementList)
+	err := c.cc.Invoke(ctx, GroupService_ListSettlements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GroupServiceServer is the server API for GroupService service.
+// All implementations must embed UnimplementedGroupServiceServer
+// for forward compatibility.
+//
+// Groups of users sharing expenses, served by the user service. Every call
+// is limited to groups the caller is in. Amounts are exact to the cent;
+// dates are YYYY-MM-DD and default to today.
+type GroupServiceServer interface {
+	CreateGroup(context.Context, *CreateGroupRequest) (*Group, error)
+	ListGroups(context.Context, *ListGroupsRequest) (*GroupList, error)
+	GetGroup(context.Context, *GetGroupRequest) (*Group, error)
+	// Invites a user by email; they join once they accept. Unknown emails
+	// fail with the same error whatever the reason, so accounts cannot be
+	// discovered this way.
+	InviteGroupMember(context.Context, *InviteGroupMemberRequest) (*Group, error)
+	ListGroupInvitations(context.Context, *ListGroupInvitationsRequest) (*GroupInvitationList, error)
+	// Accepting joins the group and returns it; declining returns an empty
+	// group.
+	RespondToGroupInvitation(context.Context, *RespondToGroupInvitationRequest) (*Group, error)
+	// Members can leave, and the creator remove others, once their balance
+	// in the group is settled. The creator also withdraws invitations here.
+	RemoveGroupMember(context.Context, *RemoveGroupMemberRequest) (*Group, error)
+	AddGroupExpense(context.Context, *AddGroupExpenseRequest) (*GroupExpense, error)
+	ListGroupExpenses(context.Context, *ListGroupExpensesRequest) (*GroupExpenseList, error)
+	// Expenses recorded from receipts are removed with
+	// FileService.AssignFileToGroup instead.
+	DeleteGroupExpense(context.Context, *DeleteGroupExpenseRequest) (*DeleteGroupExpenseResponse, error)
+	GetGroupBalances(context.Context, *GetGroupBalancesRequest) (*GroupBalances, error)
+	SettleUp(context.Context, *SettleUpRequest) (*Settlement, error)
+	ListSettlements(context.Context, *ListSettlementsRequest) (*SettlementList, error)
+	mustEmbedUnimplementedGroupServiceServer()
+}
+
+// UnimplementedGroupServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGroupServiceServer struct{}
+
+func (UnimplementedGroupServiceServer) CreateGroup(context.Context, *CreateGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (UnimplementedGroupServiceServer) ListGroups(context.Context, *ListGroupsRequest) (*GroupList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (UnimplementedGroupServiceServer) GetGroup(context.Context, *GetGroupRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedGroupServiceServer) InviteGroupMember(context.Context, *InviteGroupMemberRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteGroupMember not implemented")
+}
+func (UnimplementedGroupServiceServer) ListGroupInvitations(context.Context, *ListGroupInvitationsRequest) (*GroupInvitationList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroupInvitations not implemented")
+}
+func (UnimplementedGroupServiceServer) RespondToGroupInvitation(context.Context, *RespondToGroupInvitationRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToGroupInvitation not implemented")
+}
+func (UnimplementedGroupServiceServer) RemoveGroupMember(context.Context, *RemoveGroupMemberRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveGroupMember not implemented")
+}
+func (UnimplementedGroupServiceServer) AddGroupExpense(context.Context, *AddGroupExpenseRequest) (*GroupExpense, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGroupExpense not implemented")
+}
+func (UnimplementedGroupServiceServer) ListGroupExpenses(context.Context, *ListGroupExpensesRequest) (*GroupExpenseList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroupExpenses not implemented")
+}
+func (UnimplementedGroupServiceServer) DeleteGroupExpense(context.Context, *DeleteGroupExpenseRequest) (*DeleteGroupExpenseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroupExpense not implemented")
+}
+func (UnimplementedGroupServiceServer) GetGroupBalances(context.Context, *GetGroupBalancesRequest) (*GroupBalances, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroupBalances not implemented")
+}
+func (UnimplementedGroupServiceServer) SettleUp(context.Context, *SettleUpRequest) (*Settlement, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SettleUp not implemented")
+}
+func (UnimplementedGroupServiceServer) ListSettlements(context.Context, *ListSettlementsRequest) (*SettlementList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSettlements not implemented")
+}
+func (UnimplementedGroupServiceServer) mustEmbedUnimplementedGroupServiceServer() {}
+func (UnimplementedGroupServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeGroupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GroupServiceServer will
+// result in compilation errors.
+type UnsafeGroupServiceServer interface {
+	mustEmbedUnimplementedGroupServiceServer()
+}
+
+func RegisterGroupServiceServer(s grpc.ServiceRegistrar, srv GroupServiceServer) {
+	// If the following call pancis, it indicates UnimplementedGroupServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GroupService_ServiceDesc, srv)
+}
+
+func _GroupService_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_CreateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).CreateGroup(ctx, req.(*CreateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_ListGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_GetGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).GetGroup(ctx, req.(*GetGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_InviteGroupMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteGroupMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).InviteGroupMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_InviteGroupMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).InviteGroupMember(ctx, req.(*InviteGroupMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_ListGroupInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupInvitationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListGroupInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_ListGroupInvitations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListGroupInvitations(ctx, req.(*ListGroupInvitationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_RespondToGroupInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToGroupInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).RespondToGroupInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_RespondToGroupInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).RespondToGroupInvitation(ctx, req.(*RespondToGroupInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_RemoveGroupMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveGroupMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).RemoveGroupMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_RemoveGroupMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).RemoveGroupMember(ctx, req.(*RemoveGroupMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_AddGroupExpense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddGroupExpenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).AddGroupExpense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_AddGroupExpense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).AddGroupExpense(ctx, req.(*AddGroupExpenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_ListGroupExpenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupExpensesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListGroupExpenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_ListGroupExpenses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListGroupExpenses(ctx, req.(*ListGroupExpensesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_DeleteGroupExpense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGroupExpenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).DeleteGroupExpense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_DeleteGroupExpense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).DeleteGroupExpense(ctx, req.(*DeleteGroupExpenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_GetGroupBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).GetGroupBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_GetGroupBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).GetGroupBalances(ctx, req.(*GetGroupBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_SettleUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleUpRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).SettleUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_SettleUp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).SettleUp(ctx, req.(*SettleUpRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GroupService_ListSettlements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSettlementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GroupServiceServer).ListSettlements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GroupService_ListSettlements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GroupServiceServer).ListSettlements(ctx, req.(*ListSettlementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GroupService_ServiceDesc is the grpc.ServiceDesc for GroupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GroupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "group.GroupService",
+	HandlerType: (*GroupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGroup",
+			Handler:    _GroupService_CreateGroup_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _GroupService_ListGroups_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _GroupService_GetGroup_Handler,
+		},
+		{
+			MethodName: "InviteGroupMember",
+			Handler:    _GroupService_InviteGroupMember_Handler,
+		},
+		{
+			MethodName: "ListGroupInvitations",
+			Handler:    _GroupService_ListGroupInvitations_Handler,
+		},
+		{
+			MethodName: "RespondToGroupInvitation",
+			Handler:    _GroupService_RespondToGroupInvitation_Handler,
+		},
+		{
+			MethodName: "RemoveGroupMember",
+			Handler:    _GroupService_RemoveGroupMember_Handler,
+		},
+		{
+			MethodName: "AddGroupExpense",
+			Handler:    _GroupService_AddGroupExpense_Handler,
+		},
+		{
+			MethodName: "ListGroupExpenses",
+			Handler:    _GroupService_ListGroupExpenses_Handler,
+		},
+		{
+			MethodName: "DeleteGroupExpense",
+			Handler:    _GroupService_DeleteGroupExpense_Handler,
+		},
+		{
+			MethodName: "GetGroupBalances",
+			Handler:    _GroupService_GetGroupBalances_Handler,
+		},
+		{
+			MethodName: "SettleUp",
+			Handler:    _GroupService_SettleUp_Handler,
+		},
+		{
+			MethodName: "ListSettlements",
+			Handler:    _GroupService_ListSettlements_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "group.proto",
+}
@@ -27,6 +27,10 @@ const (
 	BalanceService_GetIncomes_FullMethodName       = "/balance.BalanceService/GetIncomes"
 	BalanceService_AddIncomeSource_FullMethodName  = "/balance.BalanceService/AddIncomeSource"
 	BalanceService_UpdateIncome_FullMethodName     = "/balance.BalanceService/UpdateIncome"
+	BalanceService_CheckLedger_FullMethodName      = "/balance.BalanceService/CheckLedger"
+	BalanceService_GetExchangeRate_FullMethodName  = "/balance.BalanceService/GetExchangeRate"
+	BalanceService_UpdateAccount_FullMethodName    = "/balance.BalanceService/UpdateAccount"
+	BalanceService_GetNetWorth_FullMethodName      = "/balance.BalanceService/GetNetWorth"
 )
 
 // BalanceServiceClient is the client API for BalanceService service.
@@ -36,11 +40,26 @@ type BalanceServiceClient interface {
 	GetBalances(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
 	AddBalanceSource(ctx context.Context, in *AddBalanceSourceRequest, opts ...grpc.CallOption) (*AddBalanceSourceResponse, error)
 	UpdateBalance(ctx context.Context, in *UpdateBalanceRequest, opts ...grpc.CallOption) (*UpdateBalanceResponse, error)
+	// Moves money between two of the caller's balance accounts in one
+	// database transaction. A repeated idempotency_key returns the transfer
+	// it first made instead of moving the money again.
 	InternalTransfer(ctx context.Context, in *TransferFunds, opts ...grpc.CallOption) (*TransferFundsResponse, error)
 	GetTransfer(ctx context.Context, in *GetTransferRequest, opts ...grpc.CallOption) (*GetTransferResponse, error)
 	GetIncomes(ctx context.Context, in *GetIncomeRequest, opts ...grpc.CallOption) (*GetIncomeResponse, error)
 	AddIncomeSource(ctx context.Context, in *AddIncomeSourceRequest, opts ...grpc.CallOption) (*AddIncomeSourceResponse, error)
 	UpdateIncome(ctx context.Context, in *UpdateIncomeRequest, opts ...grpc.CallOption) (*UpdateIncomeResponse, error)
+	// Checks the caller's journal: that every entry balances, that snapshots
+	// agree with the postings they summarise, and that products, refunds and
+	// transfers are posted at the amounts they record.
+	CheckLedger(ctx context.Context, in *CheckLedgerRequest, opts ...grpc.CallOption) (*CheckLedgerResponse, error)
+	// The rate amounts are converted between two currencies at on a date: the
+	// latest stored on or before it.
+	GetExchangeRate(ctx context.Context, in *GetExchangeRateRequest, opts ...grpc.CallOption) (*ExchangeRate, error)
+	// Changes an account's name, type and type-specific fields.
+	UpdateAccount(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*UpdateAccountResponse, error)
+	// The caller's assets, liabilities and net worth now and over time, in
+	// their base currency.
+	GetNetWorth(ctx context.Context, in *GetNetWorthRequest, opts ...grpc.CallOption) (*NetWorthResponse, error)
 }
 
 type balanceServiceClient struct {
@@ -131,6 +150,46 @@ func (c *balanceServiceClient) UpdateIncome(ctx context.Context, in *UpdateIncom
 	return out, nil
 }
 
+func (c *balanceServiceClient) CheckLedger(ctx context.Context, in *CheckLedgerRequest, opts ...grpc.CallOption) (*CheckLedgerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckLedgerResponse)
+	err := c.cc.Invoke(ctx, BalanceService_CheckLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceServiceClient) GetExchangeRate(ctx context.Context, in *GetExchangeRateRequest, opts ...grpc.CallOption) (*ExchangeRate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExchangeRate)
+	err := c.cc.Invoke(ctx, BalanceService_GetExchangeRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceServiceClient) UpdateAccount(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*UpdateAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateAccountResponse)
+	err := c.cc.Invoke(ctx, BalanceService_UpdateAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balanceServiceClient) GetNetWorth(ctx context.Context, in *GetNetWorthRequest, opts ...grpc.CallOption) (*NetWorthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetWorthResponse)
+	err := c.cc.Invoke(ctx, BalanceService_GetNetWorth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BalanceServiceServer is the server API for BalanceService service.
 // All implementations must embed UnimplementedBalanceServiceServer
 // for forward compatibility.
@@ -138,11 +197,26 @@ type BalanceServiceServer interface {
 	GetBalances(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
 	AddBalanceSource(context.Context, *AddBalanceSourceRequest) (*AddBalanceSourceResponse, error)
 	UpdateBalance(context.Context, *UpdateBalanceRequest) (*UpdateBalanceResponse, error)
+	// Moves money between two of the caller's balance accounts in one
+	// database transaction. A repeated idempotency_key returns the transfer
+	// it first made instead of moving the money again.
 	InternalTransfer(context.Context, *TransferFunds) (*TransferFundsResponse, error)
 	GetTransfer(context.Context, *GetTransferRequest) (*GetTransferResponse, error)
 	GetIncomes(context.Context, *GetIncomeRequest) (*GetIncomeResponse, error)
 	AddIncomeSource(context.Context, *AddIncomeSourceRequest) (*AddIncomeSourceResponse, error)
 	UpdateIncome(context.Context, *UpdateIncomeRequest) (*UpdateIncomeResponse, error)
+	// Checks the caller's journal: that every entry balances, that snapshots
+	// agree with the postings they summarise, and that products, refunds and
+	// transfers are posted at the amounts they record.
+	CheckLedger(context.Context, *CheckLedgerRequest) (*CheckLedgerResponse, error)
+	// The rate amounts are converted between two currencies at on a date: the
+	// latest stored on or before it.
+	GetExchangeRate(context.Context, *GetExchangeRateRequest) (*ExchangeRate, error)
+	// Changes an account's name, type and type-specific fields.
+	UpdateAccount(context.Context, *UpdateAccountRequest) (*UpdateAccountResponse, error)
+	// The caller's assets, liabilities and net worth now and over time, in
+	// their base currency.
+	GetNetWorth(context.Context, *GetNetWorthRequest) (*NetWorthResponse, error)
 	mustEmbedUnimplementedBalanceServiceServer()
 }
 
@@ -177,6 +251,18 @@ func (UnimplementedBalanceServiceServer) AddIncomeSource(context.Context, *AddIn
 func (UnimplementedBalanceServiceServer) UpdateIncome(context.Context, *UpdateIncomeRequest) (*UpdateIncomeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateIncome not implemented")
 }
+func (UnimplementedBalanceServiceServer) CheckLedger(context.Context, *CheckLedgerRequest) (*CheckLedgerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckLedger not implemented")
+}
+func (UnimplementedBalanceServiceServer) GetExchangeRate(context.Context, *GetExchangeRateRequest) (*ExchangeRate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExchangeRate not implemented")
+}
+func (UnimplementedBalanceServiceServer) UpdateAccount(context.Context, *UpdateAccountRequest) (*UpdateAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAccount not implemented")
+}
+func (UnimplementedBalanceServiceServer) GetNetWorth(context.Context, *GetNetWorthRequest) (*NetWorthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNetWorth not implemented")
+}
 func (UnimplementedBalanceServiceServer) mustEmbedUnimplementedBalanceServiceServer() {}
 func (UnimplementedBalanceServiceServer) testEmbeddedByValue()                        {}
 
@@ -342,6 +428,78 @@ func _BalanceService_UpdateIncome_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BalanceService_CheckLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServiceServer).CheckLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BalanceService_CheckLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServiceServer).CheckLedger(ctx, req.(*CheckLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BalanceService_GetExchangeRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExchangeRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServiceServer).GetExchangeRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BalanceService_GetExchangeRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServiceServer).GetExchangeRate(ctx, req.(*GetExchangeRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BalanceService_UpdateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServiceServer).UpdateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BalanceService_UpdateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServiceServer).UpdateAccount(ctx, req.(*UpdateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BalanceService_GetNetWorth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNetWorthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalanceServiceServer).GetNetWorth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BalanceService_GetNetWorth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalanceServiceServer).GetNetWorth(ctx, req.(*GetNetWorthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BalanceService_ServiceDesc is the grpc.ServiceDesc for BalanceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -381,6 +539,22 @@ var BalanceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateIncome",
 			Handler:    _BalanceService_UpdateIncome_Handler,
 		},
+		{
+			MethodName: "CheckLedger",
+			Handler:    _BalanceService_CheckLedger_Handler,
+		},
+		{
+			MethodName: "GetExchangeRate",
+			Handler:    _BalanceService_GetExchangeRate_Handler,
+		},
+		{
+			MethodName: "UpdateAccount",
+			Handler:    _BalanceService_UpdateAccount_Handler,
+		},
+		{
+			MethodName: "GetNetWorth",
+			Handler:    _BalanceService_GetNetWorth_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "balance.proto",
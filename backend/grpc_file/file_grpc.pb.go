@@ -19,8 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileService_GetAllFiles_FullMethodName = "/file.FileService/GetAllFiles"
-	FileService_UploadFile_FullMethodName  = "/file.FileService/UploadFile"
+	FileService_GetAllFiles_FullMethodName         = "/file.FileService/GetAllFiles"
+	FileService_UploadFile_FullMethodName          = "/file.FileService/UploadFile"
+	FileService_DeleteFile_FullMethodName          = "/file.FileService/DeleteFile"
+	FileService_RenameFile_FullMethodName          = "/file.FileService/RenameFile"
+	FileService_ResetFileExtraction_FullMethodName = "/file.FileService/ResetFileExtraction"
+	FileService_GetStorageUsage_FullMethodName     = "/file.FileService/GetStorageUsage"
+	FileService_GetFileContent_FullMethodName      = "/file.FileService/GetFileContent"
+	FileService_AssignFileToGroup_FullMethodName   = "/file.FileService/AssignFileToGroup"
 )
 
 // FileServiceClient is the client API for FileService service.
@@ -29,6 +35,18 @@ const (
 type FileServiceClient interface {
 	GetAllFiles(ctx context.Context, in *GetFileByUser, opts ...grpc.CallOption) (*FileList, error)
 	UploadFile(ctx context.Context, in *UploadFileRequest, opts ...grpc.CallOption) (*UploadFileResponse, error)
+	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*FileActionResponse, error)
+	RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*FileActionResponse, error)
+	// Discards the products extracted from a receipt and its cached
+	// extraction. It does not extract them again: the upload service's GetText
+	// reads the stored image afresh the next time the receipt is opened.
+	ResetFileExtraction(ctx context.Context, in *ResetFileExtractionRequest, opts ...grpc.CallOption) (*FileActionResponse, error)
+	GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*StorageUsage, error)
+	GetFileContent(ctx context.Context, in *GetFileContentRequest, opts ...grpc.CallOption) (*FileContent, error)
+	// Records a receipt as an expense the caller paid in one of their groups
+	// and splits its products so only the caller's share counts as their
+	// spend. group_id 0 takes the receipt out of its group again.
+	AssignFileToGroup(ctx context.Context, in *AssignFileToGroupRequest, opts ...grpc.CallOption) (*FileActionResponse, error)
 }
 
 type fileServiceClient struct {
@@ -59,12 +77,84 @@ func (c *fileServiceClient) UploadFile(ctx context.Context, in *UploadFileReques
 	return out, nil
 }
 
+func (c *fileServiceClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*FileActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileActionResponse)
+	err := c.cc.Invoke(ctx, FileService_DeleteFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*FileActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileActionResponse)
+	err := c.cc.Invoke(ctx, FileService_RenameFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) ResetFileExtraction(ctx context.Context, in *ResetFileExtractionRequest, opts ...grpc.CallOption) (*FileActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileActionResponse)
+	err := c.cc.Invoke(ctx, FileService_ResetFileExtraction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*StorageUsage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageUsage)
+	err := c.cc.Invoke(ctx, FileService_GetStorageUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) GetFileContent(ctx context.Context, in *GetFileContentRequest, opts ...grpc.CallOption) (*FileContent, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileContent)
+	err := c.cc.Invoke(ctx, FileService_GetFileContent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) AssignFileToGroup(ctx context.Context, in *AssignFileToGroupRequest, opts ...grpc.CallOption) (*FileActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileActionResponse)
+	err := c.cc.Invoke(ctx, FileService_AssignFileToGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileServiceServer is the server API for FileService service.
 // All implementations must embed UnimplementedFileServiceServer
 // for forward compatibility.
 type FileServiceServer interface {
 	GetAllFiles(context.Context, *GetFileByUser) (*FileList, error)
 	UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error)
+	DeleteFile(context.Context, *DeleteFileRequest) (*FileActionResponse, error)
+	RenameFile(context.Context, *RenameFileRequest) (*FileActionResponse, error)
+	// Discards the products extracted from a receipt and its cached
+	// extraction. It does not extract them again: the upload service's GetText
+	// reads the stored image afresh the next time the receipt is opened.
+	ResetFileExtraction(context.Context, *ResetFileExtractionRequest) (*FileActionResponse, error)
+	GetStorageUsage(context.Context, *GetStorageUsageRequest) (*StorageUsage, error)
+	GetFileContent(context.Context, *GetFileContentRequest) (*FileContent, error)
+	// Records a receipt as an expense the caller paid in one of their groups
+	// and splits its products so only the caller's share counts as their
+	// spend. group_id 0 takes the receipt out of its group again.
+	AssignFileToGroup(context.Context, *AssignFileToGroupRequest) (*FileActionResponse, error)
 	mustEmbedUnimplementedFileServiceServer()
 }
 
@@ -81,6 +171,24 @@ func (UnimplementedFileServiceServer) GetAllFiles(context.Context, *GetFileByUse
 func (UnimplementedFileServiceServer) UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
 }
+func (UnimplementedFileServiceServer) DeleteFile(context.Context, *DeleteFileRequest) (*FileActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (UnimplementedFileServiceServer) RenameFile(context.Context, *RenameFileRequest) (*FileActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFile not implemented")
+}
+func (UnimplementedFileServiceServer) ResetFileExtraction(context.Context, *ResetFileExtractionRequest) (*FileActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetFileExtraction not implemented")
+}
+func (UnimplementedFileServiceServer) GetStorageUsage(context.Context, *GetStorageUsageRequest) (*StorageUsage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageUsage not implemented")
+}
+func (UnimplementedFileServiceServer) GetFileContent(context.Context, *GetFileContentRequest) (*FileContent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileContent not implemented")
+}
+func (UnimplementedFileServiceServer) AssignFileToGroup(context.Context, *AssignFileToGroupRequest) (*FileActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignFileToGroup not implemented")
+}
 func (UnimplementedFileServiceServer) mustEmbedUnimplementedFileServiceServer() {}
 func (UnimplementedFileServiceServer) testEmbeddedByValue()                     {}
 
@@ -138,6 +246,114 @@ func _FileService_UploadFile_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_DeleteFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).DeleteFile(ctx, req.(*DeleteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_RenameFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).RenameFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_RenameFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).RenameFile(ctx, req.(*RenameFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_ResetFileExtraction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetFileExtractionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).ResetFileExtraction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_ResetFileExtraction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).ResetFileExtraction(ctx, req.(*ResetFileExtractionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_GetStorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).GetStorageUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_GetStorageUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).GetStorageUsage(ctx, req.(*GetStorageUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_GetFileContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileContentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).GetFileContent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_GetFileContent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).GetFileContent(ctx, req.(*GetFileContentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_AssignFileToGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignFileToGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).AssignFileToGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileService_AssignFileToGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).AssignFileToGroup(ctx, req.(*AssignFileToGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FileService_ServiceDesc is the grpc.ServiceDesc for FileService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +369,30 @@ var FileService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UploadFile",
 			Handler:    _FileService_UploadFile_Handler,
 		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _FileService_DeleteFile_Handler,
+		},
+		{
+			MethodName: "RenameFile",
+			Handler:    _FileService_RenameFile_Handler,
+		},
+		{
+			MethodName: "ResetFileExtraction",
+			Handler:    _FileService_ResetFileExtraction_Handler,
+		},
+		{
+			MethodName: "GetStorageUsage",
+			Handler:    _FileService_GetStorageUsage_Handler,
+		},
+		{
+			MethodName: "GetFileContent",
+			Handler:    _FileService_GetFileContent_Handler,
+		},
+		{
+			MethodName: "AssignFileToGroup",
+			Handler:    _FileService_AssignFileToGroup_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "file.proto",
@@ -0,0 +1,403 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: import.proto
+
+package imports
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ImportService_ListImportMappings_FullMethodName  = "/imports.ImportService/ListImportMappings"
+	ImportService_SaveImportMapping_FullMethodName   = "/imports.ImportService/SaveImportMapping"
+	ImportService_DeleteImportMapping_FullMethodName = "/imports.ImportService/DeleteImportMapping"
+	ImportService_PreviewImport_FullMethodName       = "/imports.ImportService/PreviewImport"
+	ImportService_CommitImport_FullMethodName        = "/imports.ImportService/CommitImport"
+	ImportService_UndoImport_FullMethodName          = "/imports.ImportService/UndoImport"
+	ImportService_GetImport_FullMethodName           = "/imports.ImportService/GetImport"
+	ImportService_ListImports_FullMethodName         = "/imports.ImportService/ListImports"
+)
+
+// ImportServiceClient is the client API for ImportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Imports bank and card statements, served by the product service. A file
+// is previewed first: it is read into rows, each marked new, a duplicate of
+// an entry already recorded, or unsupported, and nothing is recorded. The
+// preview is then committed, which records money out of the account as
+// products paid from it and money in as incomes into it, or undone. Undoing
+// a committed import deletes what it recorded. Dates are YYYY-MM-DD.
+type ImportServiceClient interface {
+	ListImportMappings(ctx context.Context, in *ListImportMappingsRequest, opts ...grpc.CallOption) (*ImportMappingList, error)
+	// Creates a mapping when mapping_id is 0, else replaces it.
+	SaveImportMapping(ctx context.Context, in *ImportMapping, opts ...grpc.CallOption) (*ImportMapping, error)
+	DeleteImportMapping(ctx context.Context, in *DeleteImportMappingRequest, opts ...grpc.CallOption) (*DeleteImportMappingResponse, error)
+	PreviewImport(ctx context.Context, in *PreviewImportRequest, opts ...grpc.CallOption) (*ImportBatch, error)
+	CommitImport(ctx context.Context, in *CommitImportRequest, opts ...grpc.CallOption) (*ImportBatch, error)
+	UndoImport(ctx context.Context, in *UndoImportRequest, opts ...grpc.CallOption) (*ImportBatch, error)
+	GetImport(ctx context.Context, in *GetImportRequest, opts ...grpc.CallOption) (*ImportBatch, error)
+	ListImports(ctx context.Context, in *ListImportsRequest, opts ...grpc.CallOption) (*ImportBatchList, error)
+}
+
+type importServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewImportServiceClient(cc grpc.ClientConnInterface) ImportServiceClient {
+	return &importServiceClient{cc}
+}
+
+func (c *importServiceClient) ListImportMappings(ctx context.Context, in *ListImportMappingsRequest, opts ...grpc.CallOption) (*ImportMappingList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportMappingList)
+	err := c.cc.Invoke(ctx, ImportService_ListImportMappings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) SaveImportMapping(ctx context.Context, in *ImportMapping, opts ...grpc.CallOption) (*ImportMapping, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportMapping)
+	err := c.cc.Invoke(ctx, ImportService_SaveImportMapping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) DeleteImportMapping(ctx context.Context, in *DeleteImportMappingRequest, opts ...grpc.CallOption) (*DeleteImportMappingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteImportMappingResponse)
+	err := c.cc.Invoke(ctx, ImportService_DeleteImportMapping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) PreviewImport(ctx context.Context, in *PreviewImportRequest, opts ...grpc.CallOption) (*ImportBatch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportBatch)
+	err := c.cc.Invoke(ctx, ImportService_PreviewImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) CommitImport(ctx context.Context, in *CommitImportRequest, opts ...grpc.CallOption) (*ImportBatch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportBatch)
+	err := c.cc.Invoke(ctx, ImportService_CommitImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) UndoImport(ctx context.Context, in *UndoImportRequest, opts ...grpc.CallOption) (*ImportBatch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportBatch)
+	err := c.cc.Invoke(ctx, ImportService_UndoImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) GetImport(ctx context.Context, in *GetImportRequest, opts ...grpc.CallOption) (*ImportBatch, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportBatch)
+	err := c.cc.Invoke(ctx, ImportService_GetImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *importServiceClient) ListImports(ctx context.Context, in *ListImportsRequest, opts ...grpc.CallOption) (*ImportBatchList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportBatchList)
+	err := c.cc.Invoke(ctx, ImportService_ListImports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ImportServiceServer is the server API for ImportService service.
+// All implementations must embed UnimplementedImportServiceServer
+// for forward compatibility.
+//
+// Imports bank and card statements, served by the product service. A file
+// is previewed first: it is read into rows, each marked new, a duplicate of
+// an entry already recorded, or unsupported, and nothing is recorded. The
+// preview is then committed, which records money out of the account as
+// products paid from it and money in as incomes into it, or undone. Undoing
+// a committed import deletes what it recorded. Dates are YYYY-MM-DD.
+type ImportServiceServer interface {
+	ListImportMappings(context.Context, *ListImportMappingsRequest) (*ImportMappingList, error)
+	// Creates a mapping when mapping_id is 0, else replaces it.
+	SaveImportMapping(context.Context, *ImportMapping) (*ImportMapping, error)
+	DeleteImportMapping(context.Context, *DeleteImportMappingRequest) (*DeleteImportMappingResponse, error)
+	PreviewImport(context.Context, *PreviewImportRequest) (*ImportBatch, error)
+	CommitImport(context.Context, *CommitImportRequest) (*ImportBatch, error)
+	UndoImport(context.Context, *UndoImportRequest) (*ImportBatch, error)
+	GetImport(context.Context, *GetImportRequest) (*ImportBatch, error)
+	ListImports(context.Context, *ListImportsRequest) (*ImportBatchList, error)
+	mustEmbedUnimplementedImportServiceServer()
+}
+
+// UnimplementedImportServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedImportServiceServer struct{}
+
+func (UnimplementedImportServiceServer) ListImportMappings(context.Context, *ListImportMappingsRequest) (*ImportMappingList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImportMappings not implemented")
+}
+func (UnimplementedImportServiceServer) SaveImportMapping(context.Context, *ImportMapping) (*ImportMapping, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveImportMapping not implemented")
+}
+func (UnimplementedImportServiceServer) DeleteImportMapping(context.Context, *DeleteImportMappingRequest) (*DeleteImportMappingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteImportMapping not implemented")
+}
+func (UnimplementedImportServiceServer) PreviewImport(context.Context, *PreviewImportRequest) (*ImportBatch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewImport not implemented")
+}
+func (UnimplementedImportServiceServer) CommitImport(context.Context, *CommitImportRequest) (*ImportBatch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitImport not implemented")
+}
+func (UnimplementedImportServiceServer) UndoImport(context.Context, *UndoImportRequest) (*ImportBatch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndoImport not implemented")
+}
+func (UnimplementedImportServiceServer) GetImport(context.Context, *GetImportRequest) (*ImportBatch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImport not implemented")
+}
+func (UnimplementedImportServiceServer) ListImports(context.Context, *ListImportsRequest) (*ImportBatchList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImports not implemented")
+}
+func (UnimplementedImportServiceServer) mustEmbedUnimplementedImportServiceServer() {}
+func (UnimplementedImportServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeImportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ImportServiceServer will
+// result in compilation errors.
+type UnsafeImportServiceServer interface {
+	mustEmbedUnimplementedImportServiceServer()
+}
+
+func RegisterImportServiceServer(s grpc.ServiceRegistrar, srv ImportServiceServer) {
+	// If the following call pancis, it indicates UnimplementedImportServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ImportService_ServiceDesc, srv)
+}
+
+func _ImportService_ListImportMappings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImportMappingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).ListImportMappings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_ListImportMappings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).ListImportMappings(ctx, req.(*ListImportMappingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_SaveImportMapping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMapping)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).SaveImportMapping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_SaveImportMapping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).SaveImportMapping(ctx, req.(*ImportMapping))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_DeleteImportMapping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteImportMappingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).DeleteImportMapping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_DeleteImportMapping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).DeleteImportMapping(ctx, req.(*DeleteImportMappingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_PreviewImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).PreviewImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_PreviewImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).PreviewImport(ctx, req.(*PreviewImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_CommitImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).CommitImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_CommitImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).CommitImport(ctx, req.(*CommitImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_UndoImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndoImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).UndoImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_UndoImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).UndoImport(ctx, req.(*UndoImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_GetImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).GetImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_GetImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).GetImport(ctx, req.(*GetImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImportService_ListImports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImportServiceServer).ListImports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ImportService_ListImports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImportServiceServer).ListImports(ctx, req.(*ListImportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ImportService_ServiceDesc is the grpc.ServiceDesc for ImportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ImportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "imports.ImportService",
+	HandlerType: (*ImportServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListImportMappings",
+			Handler:    _ImportService_ListImportMappings_Handler,
+		},
+		{
+			MethodName: "SaveImportMapping",
+			Handler:    _ImportService_SaveImportMapping_Handler,
+		},
+		{
+			MethodName: "DeleteImportMapping",
+			Handler:    _ImportService_DeleteImportMapping_Handler,
+		},
+		{
+			MethodName: "PreviewImport",
+			Handler:    _ImportService_PreviewImport_Handler,
+		},
+		{
+			MethodName: "CommitImport",
+			Handler:    _ImportService_CommitImport_Handler,
+		},
+		{
+			MethodName: "UndoImport",
+			Handler:    _ImportService_UndoImport_Handler,
+		},
+		{
+			MethodName: "GetImport",
+			Handler:    _ImportService_GetImport_Handler,
+		},
+		{
+			MethodName: "ListImports",
+			Handler:    _ImportService_ListImports_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "import.proto",
+}
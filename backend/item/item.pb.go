@@ -0,0 +1,1501 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        v5.29.3
+// source: item.proto
+
+package item
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemId          int32   `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Name            string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Purchases       int32   `protobuf:"varint,3,opt,name=purchases,proto3" json:"purchases,omitempty"`
+	LatestUnitPrice float64 `protobuf:"fixed64,4,opt,name=latest_unit_price,json=latestUnitPrice,proto3" json:"latest_unit_price,omitempty"`
+	LastPurchased   string  `protobuf:"bytes,5,opt,name=last_purchased,json=lastPurchased,proto3" json:"last_purchased,omitempty"` // YYYY-MM-DD, empty if never bought
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_item_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Item) GetItemId() int32 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *Item) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Item) GetPurchases() int32 {
+	if x != nil {
+		return x.Purchases
+	}
+	return 0
+}
+
+func (x *Item) GetLatestUnitPrice() float64 {
+	if x != nil {
+		return x.LatestUnitPrice
+	}
+	return 0
+}
+
+func (x *Item) GetLastPurchased() string {
+	if x != nil {
+		return x.LastPurchased
+	}
+	return ""
+}
+
+type ListItemsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`  // case-insensitive substring of the item name
+	Limit int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // defaults to 50, at most 200
+}
+
+func (x *ListItemsRequest) Reset() {
+	*x = ListItemsRequest{}
+	mi := &file_item_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListItemsRequest) ProtoMessage() {}
+
+func (x *ListItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListItemsRequest.ProtoReflect.Descriptor instead.
+func (*ListItemsRequest) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListItemsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListItemsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ItemList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"` // most recently bought first
+}
+
+func (x *ItemList) Reset() {
+	*x = ItemList{}
+	mi := &file_item_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemList) ProtoMessage() {}
+
+func (x *ItemList) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemList.ProtoReflect.Descriptor instead.
+func (*ItemList) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ItemList) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type RenameItemRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemId int32  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Name   string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *RenameItemRequest) Reset() {
+	*x = RenameItemRequest{}
+	mi := &file_item_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameItemRequest) ProtoMessage() {}
+
+func (x *RenameItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameItemRequest.ProtoReflect.Descriptor instead.
+func (*RenameItemRequest) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RenameItemRequest) GetItemId() int32 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *RenameItemRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SuggestItemMergesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // defaults to 20, at most 100
+}
+
+func (x *SuggestItemMergesRequest) Reset() {
+	*x = SuggestItemMergesRequest{}
+	mi := &file_item_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestItemMergesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestItemMergesRequest) ProtoMessage() {}
+
+func (x *SuggestItemMergesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestItemMergesRequest.ProtoReflect.Descriptor instead.
+func (*SuggestItemMergesRequest) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SuggestItemMergesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ItemMergeSuggestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Item       *Item   `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Other      *Item   `protobuf:"bytes,2,opt,name=other,proto3" json:"other,omitempty"`
+	Similarity float64 `protobuf:"fixed64,3,opt,name=similarity,proto3" json:"similarity,omitempty"` // 0 to 1
+}
+
+func (x *ItemMergeSuggestion) Reset() {
+	*x = ItemMergeSuggestion{}
+	mi := &file_item_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemMergeSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemMergeSuggestion) ProtoMessage() {}
+
+func (x *ItemMergeSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemMergeSuggestion.ProtoReflect.Descriptor instead.
+func (*ItemMergeSuggestion) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ItemMergeSuggestion) GetItem() *Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *ItemMergeSuggestion) GetOther() *Item {
+	if x != nil {
+		return x.Other
+	}
+	return nil
+}
+
+func (x *ItemMergeSuggestion) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type ItemMergeSuggestions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Suggestions []*ItemMergeSuggestion `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"` // most similar first
+}
+
+func (x *ItemMergeSuggestions) Reset() {
+	*x = ItemMergeSuggestions{}
+	mi := &file_item_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemMergeSuggestions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemMergeSuggestions) ProtoMessage() {}
+
+func (x *ItemMergeSuggestions) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemMergeSuggestions.ProtoReflect.Descriptor instead.
+func (*ItemMergeSuggestions) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ItemMergeSuggestions) GetSuggestions() []*ItemMergeSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// Purchases of the source items move to the target, as do later purchases
+// under their names.
+type MergeItemsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TargetItemId  int32   `protobuf:"varint,1,opt,name=target_item_id,json=targetItemId,proto3" json:"target_item_id,omitempty"`
+	SourceItemIds []int32 `protobuf:"varint,2,rep,packed,name=source_item_ids,json=sourceItemIds,proto3" json:"source_item_ids,omitempty"`
+}
+
+func (x *MergeItemsRequest) Reset() {
+	*x = MergeItemsRequest{}
+	mi := &file_item_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeItemsRequest) ProtoMessage() {}
+
+func (x *MergeItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeItemsRequest.ProtoReflect.Descriptor instead.
+func (*MergeItemsRequest) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MergeItemsRequest) GetTargetItemId() int32 {
+	if x != nil {
+		return x.TargetItemId
+	}
+	return 0
+}
+
+func (x *MergeItemsRequest) GetSourceItemIds() []int32 {
+	if x != nil {
+		return x.SourceItemIds
+	}
+	return nil
+}
+
+type MergeItemsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Item           *Item `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	PurchasesMoved int32 `protobuf:"varint,2,opt,name=purchases_moved,json=purchasesMoved,proto3" json:"purchases_moved,omitempty"`
+}
+
+func (x *MergeItemsResponse) Reset() {
+	*x = MergeItemsResponse{}
+	mi := &file_item_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeItemsResponse) ProtoMessage() {}
+
+func (x *MergeItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeItemsResponse.ProtoReflect.Descriptor instead.
+func (*MergeItemsResponse) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MergeItemsResponse) GetItem() *Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *MergeItemsResponse) GetPurchasesMoved() int32 {
+	if x != nil {
+		return x.PurchasesMoved
+	}
+	return 0
+}
+
+type DismissItemMergeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemId      int32 `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	OtherItemId int32 `protobuf:"varint,2,opt,name=other_item_id,json=otherItemId,proto3" json:"other_item_id,omitempty"`
+}
+
+func (x *DismissItemMergeRequest) Reset() {
+	*x = DismissItemMergeRequest{}
+	mi := &file_item_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DismissItemMergeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DismissItemMergeRequest) ProtoMessage() {}
+
+func (x *DismissItemMergeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DismissItemMergeRequest.ProtoReflect.Descriptor instead.
+func (*DismissItemMergeRequest) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DismissItemMergeRequest) GetItemId() int32 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *DismissItemMergeRequest) GetOtherItemId() int32 {
+	if x != nil {
+		return x.OtherItemId
+	}
+	return 0
+}
+
+type DismissItemMergeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *DismissItemMergeResponse) Reset() {
+	*x = DismissItemMergeResponse{}
+	mi := &file_item_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DismissItemMergeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DismissItemMergeResponse) ProtoMessage() {}
+
+func (x *DismissItemMergeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DismissItemMergeResponse.ProtoReflect.Descriptor instead.
+func (*DismissItemMergeResponse) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DismissItemMergeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetPriceHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ItemId   int32  `protobuf:"varint,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Merchant string `protobuf:"bytes,2,opt,name=merchant,proto3" json:"merchant,omitempty"`                 // only purchases from this merchant, ignoring case
+	FromDate string `protobuf:"bytes,3,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"` // YYYY-MM-DD, inclusive
+	ToDate   string `protobuf:"bytes,4,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`       // YYYY-MM-DD, inclusive
+}
+
+func (x *GetPriceHistoryRequest) Reset() {
+	*x = GetPriceHistoryRequest{}
+	mi := &file_item_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceHistoryRequest) ProtoMessage() {}
+
+func (x *GetPriceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetPriceHistoryRequest) GetItemId() int32 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *GetPriceHistoryRequest) GetMerchant() string {
+	if x != nil {
+		return x.Merchant
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryRequest) GetFromDate() string {
+	if x != nil {
+		return x.FromDate
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryRequest) GetToDate() string {
+	if x != nil {
+		return x.ToDate
+	}
+	return ""
+}
+
+type PricePoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProductId   int32   `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ProductName string  `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"` // as it appeared on the receipt
+	Date        string  `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	UnitPrice   float64 `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Quantity    int32   `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Merchant    string  `protobuf:"bytes,6,opt,name=merchant,proto3" json:"merchant,omitempty"` // empty if the receipt's merchant was not recognised
+	FileName    string  `protobuf:"bytes,7,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+}
+
+func (x *PricePoint) Reset() {
+	*x = PricePoint{}
+	mi := &file_item_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricePoint) ProtoMessage() {}
+
+func (x *PricePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricePoint.ProtoReflect.Descriptor instead.
+func (*PricePoint) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PricePoint) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *PricePoint) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *PricePoint) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *PricePoint) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *PricePoint) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *PricePoint) GetMerchant() string {
+	if x != nil {
+		return x.Merchant
+	}
+	return ""
+}
+
+func (x *PricePoint) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+// Percentage changes are of the latest price; they are unset when there is
+// no earlier purchase on a different day to compare against.
+type PriceSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Merchant                string   `protobuf:"bytes,1,opt,name=merchant,proto3" json:"merchant,omitempty"` // empty for the summary across all merchants
+	Purchases               int32    `protobuf:"varint,2,opt,name=purchases,proto3" json:"purchases,omitempty"`
+	FirstUnitPrice          float64  `protobuf:"fixed64,3,opt,name=first_unit_price,json=firstUnitPrice,proto3" json:"first_unit_price,omitempty"`
+	LatestUnitPrice         float64  `protobuf:"fixed64,4,opt,name=latest_unit_price,json=latestUnitPrice,proto3" json:"latest_unit_price,omitempty"`
+	MinUnitPrice            float64  `protobuf:"fixed64,5,opt,name=min_unit_price,json=minUnitPrice,proto3" json:"min_unit_price,omitempty"`
+	MaxUnitPrice            float64  `protobuf:"fixed64,6,opt,name=max_unit_price,json=maxUnitPrice,proto3" json:"max_unit_price,omitempty"`
+	AverageUnitPrice        float64  `protobuf:"fixed64,7,opt,name=average_unit_price,json=averageUnitPrice,proto3" json:"average_unit_price,omitempty"` // weighted by quantity
+	ChangeSinceFirstPercent *float64 `protobuf:"fixed64,8,opt,name=change_since_first_percent,json=changeSinceFirstPercent,proto3,oneof" json:"change_since_first_percent,omitempty"`
+	ChangeSinceLastPercent  *float64 `protobuf:"fixed64,9,opt,name=change_since_last_percent,json=changeSinceLastPercent,proto3,oneof" json:"change_since_last_percent,omitempty"`
+}
+
+func (x *PriceSummary) Reset() {
+	*x = PriceSummary{}
+	mi := &file_item_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceSummary) ProtoMessage() {}
+
+func (x *PriceSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceSummary.ProtoReflect.Descriptor instead.
+func (*PriceSummary) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PriceSummary) GetMerchant() string {
+	if x != nil {
+		return x.Merchant
+	}
+	return ""
+}
+
+func (x *PriceSummary) GetPurchases() int32 {
+	if x != nil {
+		return x.Purchases
+	}
+	return 0
+}
+
+func (x *PriceSummary) GetFirstUnitPrice() float64 {
+	if x != nil {
+		return x.FirstUnitPrice
+	}
+	return 0
+}
+
+func (x *PriceSummary) GetLatestUnitPrice() float64 {
+	if x != nil {
+		return x.LatestUnitPrice
+	}
+	return 0
+}
+
+func (x *PriceSummary) GetMinUnitPrice() float64 {
+	if x != nil {
+		return x.MinUnitPrice
+	}
+	return 0
+}
+
+func (x *PriceSummary) GetMaxUnitPrice() float64 {
+	if x != nil {
+		return x.MaxUnitPrice
+	}
+	return 0
+}
+
+func (x *PriceSummary) GetAverageUnitPrice() float64 {
+	if x != nil {
+		return x.AverageUnitPrice
+	}
+	return 0
+}
+
+func (x *PriceSummary) GetChangeSinceFirstPercent() float64 {
+	if x != nil && x.ChangeSinceFirstPercent != nil {
+		return *x.ChangeSinceFirstPercent
+	}
+	return 0
+}
+
+func (x *PriceSummary) GetChangeSinceLastPercent() float64 {
+	if x != nil && x.ChangeSinceLastPercent != nil {
+		return *x.ChangeSinceLastPercent
+	}
+	return 0
+}
+
+type PriceHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Item      *Item           `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Points    []*PricePoint   `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"` // oldest first
+	Summary   *PriceSummary   `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	Merchants []*PriceSummary `protobuf:"bytes,4,rep,name=merchants,proto3" json:"merchants,omitempty"` // one per merchant, most purchases first
+}
+
+func (x *PriceHistory) Reset() {
+	*x = PriceHistory{}
+	mi := &file_item_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceHistory) ProtoMessage() {}
+
+func (x *PriceHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceHistory.ProtoReflect.Descriptor instead.
+func (*PriceHistory) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PriceHistory) GetItem() *Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *PriceHistory) GetPoints() []*PricePoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *PriceHistory) GetSummary() *PriceSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *PriceHistory) GetMerchants() []*PriceSummary {
+	if x != nil {
+		return x.Merchants
+	}
+	return nil
+}
+
+type GetPriceInsightsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Since      string `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`                              // YYYY-MM-DD; items last bought from this day, defaults to 30 days ago
+	CategoryId int32  `protobuf:"varint,2,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"` // 0 for all; includes subcategories
+	// Smallest change worth reporting, as a percentage either way; defaults
+	// to 5.
+	MinChangePercent *float64 `protobuf:"fixed64,3,opt,name=min_change_percent,json=minChangePercent,proto3,oneof" json:"min_change_percent,omitempty"`
+	ByMerchant       bool     `protobuf:"varint,4,opt,name=by_merchant,json=byMerchant,proto3" json:"by_merchant,omitempty"` // compare only purchases from the same merchant
+	Limit            int32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                             // defaults to 20, at most 100
+}
+
+func (x *GetPriceInsightsRequest) Reset() {
+	*x = GetPriceInsightsRequest{}
+	mi := &file_item_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceInsightsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceInsightsRequest) ProtoMessage() {}
+
+func (x *GetPriceInsightsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceInsightsRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceInsightsRequest) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetPriceInsightsRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *GetPriceInsightsRequest) GetCategoryId() int32 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *GetPriceInsightsRequest) GetMinChangePercent() float64 {
+	if x != nil && x.MinChangePercent != nil {
+		return *x.MinChangePercent
+	}
+	return 0
+}
+
+func (x *GetPriceInsightsRequest) GetByMerchant() bool {
+	if x != nil {
+		return x.ByMerchant
+	}
+	return false
+}
+
+func (x *GetPriceInsightsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// "Price went up 12% since last purchase": an item's latest unit price
+// against the previous day it was bought. Several purchases on one day count
+// once, at their average unit price.
+type PriceInsight struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Item              *Item   `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Merchant          string  `protobuf:"bytes,2,opt,name=merchant,proto3" json:"merchant,omitempty"`
+	LatestDate        string  `protobuf:"bytes,3,opt,name=latest_date,json=latestDate,proto3" json:"latest_date,omitempty"`
+	LatestUnitPrice   float64 `protobuf:"fixed64,4,opt,name=latest_unit_price,json=latestUnitPrice,proto3" json:"latest_unit_price,omitempty"`
+	PreviousDate      string  `protobuf:"bytes,5,opt,name=previous_date,json=previousDate,proto3" json:"previous_date,omitempty"`
+	PreviousUnitPrice float64 `protobuf:"fixed64,6,opt,name=previous_unit_price,json=previousUnitPrice,proto3" json:"previous_unit_price,omitempty"`
+	PreviousMerchant  string  `protobuf:"bytes,7,opt,name=previous_merchant,json=previousMerchant,proto3" json:"previous_merchant,omitempty"`
+	ChangePercent     float64 `protobuf:"fixed64,8,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	Message           string  `protobuf:"bytes,9,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *PriceInsight) Reset() {
+	*x = PriceInsight{}
+	mi := &file_item_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceInsight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceInsight) ProtoMessage() {}
+
+func (x *PriceInsight) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceInsight.ProtoReflect.Descriptor instead.
+func (*PriceInsight) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PriceInsight) GetItem() *Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *PriceInsight) GetMerchant() string {
+	if x != nil {
+		return x.Merchant
+	}
+	return ""
+}
+
+func (x *PriceInsight) GetLatestDate() string {
+	if x != nil {
+		return x.LatestDate
+	}
+	return ""
+}
+
+func (x *PriceInsight) GetLatestUnitPrice() float64 {
+	if x != nil {
+		return x.LatestUnitPrice
+	}
+	return 0
+}
+
+func (x *PriceInsight) GetPreviousDate() string {
+	if x != nil {
+		return x.PreviousDate
+	}
+	return ""
+}
+
+func (x *PriceInsight) GetPreviousUnitPrice() float64 {
+	if x != nil {
+		return x.PreviousUnitPrice
+	}
+	return 0
+}
+
+func (x *PriceInsight) GetPreviousMerchant() string {
+	if x != nil {
+		return x.PreviousMerchant
+	}
+	return ""
+}
+
+func (x *PriceInsight) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *PriceInsight) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type PriceInsights struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Insights []*PriceInsight `protobuf:"bytes,1,rep,name=insights,proto3" json:"insights,omitempty"` // largest change first
+	// Average change across every item compared, including ones below
+	// min_change_percent or cut by limit; a rough inflation figure for the
+	// selection.
+	AverageChangePercent float64 `protobuf:"fixed64,2,opt,name=average_change_percent,json=averageChangePercent,proto3" json:"average_change_percent,omitempty"`
+	ItemsCompared        int32   `protobuf:"varint,3,opt,name=items_compared,json=itemsCompared,proto3" json:"items_compared,omitempty"`
+}
+
+func (x *PriceInsights) Reset() {
+	*x = PriceInsights{}
+	mi := &file_item_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceInsights) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceInsights) ProtoMessage() {}
+
+func (x *PriceInsights) ProtoReflect() protoreflect.Message {
+	mi := &file_item_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceInsights.ProtoReflect.Descriptor instead.
+func (*PriceInsights) Descriptor() ([]byte, []int) {
+	return file_item_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PriceInsights) GetInsights() []*PriceInsight {
+	if x != nil {
+		return x.Insights
+	}
+	return nil
+}
+
+func (x *PriceInsights) GetAverageChangePercent() float64 {
+	if x != nil {
+		return x.AverageChangePercent
+	}
+	return 0
+}
+
+func (x *PriceInsights) GetItemsCompared() int32 {
+	if x != nil {
+		return x.ItemsCompared
+	}
+	return 0
+}
+
+var File_item_proto protoreflect.FileDescriptor
+
+var file_item_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x69, 0x74,
+	0x65, 0x6d, 0x22, 0xa4, 0x01, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x69,
+	0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69, 0x74,
+	0x65, 0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x75, 0x72, 0x63,
+	0x68, 0x61, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74,
+	0x5f, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0f, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x55, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x73, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74,
+	0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x22, 0x3e, 0x0a, 0x10, 0x4c, 0x69, 0x73,
+	0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75,
+	0x65, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x2c, 0x0a, 0x08, 0x49, 0x74, 0x65,
+	0x6d, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x40, 0x0a, 0x11, 0x52, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69,
+	0x74, 0x65, 0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x30, 0x0a, 0x18, 0x53, 0x75, 0x67,
+	0x67, 0x65, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x77, 0x0a, 0x13, 0x49,
+	0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x69, 0x74,
+	0x65, 0x6d, 0x12, 0x20, 0x0a, 0x05, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x6f,
+	0x74, 0x68, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x69,
+	0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x73, 0x69, 0x6d, 0x69, 0x6c, 0x61,
+	0x72, 0x69, 0x74, 0x79, 0x22, 0x53, 0x0a, 0x14, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67,
+	0x65, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3b, 0x0a, 0x0b,
+	0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72,
+	0x67, 0x65, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x73, 0x75,
+	0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x61, 0x0a, 0x11, 0x4d, 0x65, 0x72,
+	0x67, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24,
+	0x0a, 0x0e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x74,
+	0x65, 0x6d, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69,
+	0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0d, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x73, 0x22, 0x5d, 0x0a, 0x12,
+	0x4d, 0x65, 0x72, 0x67, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1e, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x69, 0x74,
+	0x65, 0x6d, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x5f,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x70, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x4d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0x56, 0x0a, 0x17, 0x44,
+	0x69, 0x73, 0x6d, 0x69, 0x73, 0x73, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x12,
+	0x22, 0x0a, 0x0d, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x49, 0x74, 0x65,
+	0x6d, 0x49, 0x64, 0x22, 0x34, 0x0a, 0x18, 0x44, 0x69, 0x73, 0x6d, 0x69, 0x73, 0x73, 0x49, 0x74,
+	0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x83, 0x01, 0x0a, 0x16, 0x47, 0x65,
+	0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x72, 0x6f,
+	0x6d, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x72,
+	0x6f, 0x6d, 0x44, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x6f, 0x5f, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6f, 0x44, 0x61, 0x74, 0x65, 0x22,
+	0xd6, 0x01, 0x0a, 0x0a, 0x50, 0x72, 0x69, 0x63, 0x65, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x75, 0x6e, 0x69, 0x74, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12,
+	0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xd7, 0x03, 0x0a, 0x0c, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x72,
+	0x63, 0x68, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x72,
+	0x63, 0x68, 0x61, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61,
+	0x73, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x75, 0x6e, 0x69,
+	0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x55, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x2a, 0x0a,
+	0x11, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74,
+	0x55, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x6d, 0x69, 0x6e,
+	0x5f, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x55, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12,
+	0x24, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x55, 0x6e, 0x69, 0x74,
+	0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x5f, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x10, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x1a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x73, 0x69,
+	0x6e, 0x63, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x17, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x46, 0x69, 0x72, 0x73, 0x74, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x3e, 0x0a, 0x19, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f,
+	0x73, 0x69, 0x6e, 0x63, 0x65, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x16, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x4c, 0x61, 0x73, 0x74, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x1d, 0x0a, 0x1b, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x5f, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x70, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x42, 0x1c, 0x0a, 0x1a, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f,
+	0x73, 0x69, 0x6e, 0x63, 0x65, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x22, 0xb8, 0x01, 0x0a, 0x0c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x12, 0x1e, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x69,
+	0x74, 0x65, 0x6d, 0x12, 0x28, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x2c, 0x0a,
+	0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x30, 0x0a, 0x09, 0x6d,
+	0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x09, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x73, 0x22, 0xd1, 0x01,
+	0x0a, 0x17, 0x47, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x49, 0x6e, 0x73, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x49, 0x64,
+	0x12, 0x31, 0x0a, 0x12, 0x6d, 0x69, 0x6e, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x10,
+	0x6d, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x79, 0x5f, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x62, 0x79, 0x4d, 0x65, 0x72, 0x63,
+	0x68, 0x61, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6d,
+	0x69, 0x6e, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x22, 0xda, 0x02, 0x0a, 0x0c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x49, 0x6e, 0x73, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x1e, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x04, 0x69, 0x74,
+	0x65, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12,
+	0x2a, 0x0a, 0x11, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70,
+	0x72, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6c, 0x61, 0x74, 0x65,
+	0x73, 0x74, 0x55, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x70,
+	0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x44, 0x61, 0x74, 0x65,
+	0x12, 0x2e, 0x0a, 0x13, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x75, 0x6e, 0x69,
+	0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x70,
+	0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x55, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65,
+	0x12, 0x2b, 0x0a, 0x11, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x6d, 0x65, 0x72,
+	0x63, 0x68, 0x61, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x12, 0x25, 0x0a,
+	0x0e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9c,
+	0x01, 0x0a, 0x0d, 0x50, 0x72, 0x69, 0x63, 0x65, 0x49, 0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x12, 0x2e, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x49,
+	0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x12, 0x34, 0x0a, 0x16, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x14, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x5f,
+	0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x65, 0x64, 0x32, 0xe7, 0x03,
+	0x0a, 0x0b, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x33, 0x0a,
+	0x09, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x16, 0x2e, 0x69, 0x74, 0x65,
+	0x6d, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x4c, 0x69,
+	0x73, 0x74, 0x12, 0x31, 0x0a, 0x0a, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x74, 0x65, 0x6d,
+	0x12, 0x17, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a, 0x2e, 0x69, 0x74, 0x65, 0x6d,
+	0x2e, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x4f, 0x0a, 0x11, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x69, 0x74, 0x65,
+	0x6d, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72,
+	0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x69, 0x74, 0x65,
+	0x6d, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x53, 0x75, 0x67, 0x67, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3f, 0x0a, 0x0a, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x12, 0x17, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x4d, 0x65, 0x72, 0x67,
+	0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x69, 0x74, 0x65, 0x6d, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x10, 0x44, 0x69, 0x73, 0x6d, 0x69,
+	0x73, 0x73, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x12, 0x1d, 0x2e, 0x69, 0x74,
+	0x65, 0x6d, 0x2e, 0x44, 0x69, 0x73, 0x6d, 0x69, 0x73, 0x73, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65,
+	0x72, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x69, 0x74, 0x65,
+	0x6d, 0x2e, 0x44, 0x69, 0x73, 0x6d, 0x69, 0x73, 0x73, 0x49, 0x74, 0x65, 0x6d, 0x4d, 0x65, 0x72,
+	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0f, 0x47, 0x65,
+	0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x2e,
+	0x69, 0x74, 0x65, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x69, 0x74,
+	0x65, 0x6d, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x46, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x49, 0x6e, 0x73, 0x69, 0x67,
+	0x68, 0x74, 0x73, 0x12, 0x1d, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x49, 0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x13, 0x2e, 0x69, 0x74, 0x65, 0x6d, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x49,
+	0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x73, 0x42, 0x07, 0x5a, 0x05, 0x2f, 0x69, 0x74, 0x65, 0x6d,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_item_proto_rawDescOnce sync.Once
+	file_item_proto_rawDescData = file_item_proto_rawDesc
+)
+
+func file_item_proto_rawDescGZIP() []byte {
+	file_item_proto_rawDescOnce.Do(func() {
+		file_item_proto_rawDescData = protoimpl.X.CompressGZIP(file_item_proto_rawDescData)
+	})
+	return file_item_proto_rawDescData
+}
+
+var file_item_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_item_proto_goTypes = []any{
+	(*Item)(nil),                     // 0: item.Item
+	(*ListItemsRequest)(nil),         // 1: item.ListItemsRequest
+	(*ItemList)(nil),                 // 2: item.ItemList
+	(*RenameItemRequest)(nil),        // 3: item.RenameItemRequest
+	(*SuggestItemMergesRequest)(nil), // 4: item.SuggestItemMergesRequest
+	(*ItemMergeSuggestion)(nil),      // 5: item.ItemMergeSuggestion
+	(*ItemMergeSuggestions)(nil),     // 6: item.ItemMergeSuggestions
+	(*MergeItemsRequest)(nil),        // 7: item.MergeItemsRequest
+	(*MergeItemsResponse)(nil),       // 8: item.MergeItemsResponse
+	(*DismissItemMergeRequest)(nil),  // 9: item.DismissItemMergeRequest
+	(*DismissItemMergeResponse)(nil), // 10: item.DismissItemMergeResponse
+	(*GetPriceHistoryRequest)(nil),   // 11: item.GetPriceHistoryRequest
+	(*PricePoint)(nil),               // 12: item.PricePoint
+	(*PriceSummary)(nil),             // 13: item.PriceSummary
+	(*PriceHistory)(nil),             // 14: item.PriceHistory
+	(*GetPriceInsightsRequest)(nil),  // 15: item.GetPriceInsightsRequest
+	(*PriceInsight)(nil),             // 16: item.PriceInsight
+	(*PriceInsights)(nil),            // 17: item.PriceInsights
+}
+var file_item_proto_depIdxs = []int32{
+	0,  // 0: item.ItemList.items:type_name -> item.Item
+	0,  // 1: item.ItemMergeSuggestion.item:type_name -> item.Item
+	0,  // 2: item.ItemMergeSuggestion.other:type_name -> item.Item
+	5,  // 3: item.ItemMergeSuggestions.suggestions:type_name -> item.ItemMergeSuggestion
+	0,  // 4: item.MergeItemsResponse.item:type_name -> item.Item
+	0,  // 5: item.PriceHistory.item:type_name -> item.Item
+	12, // 6: item.PriceHistory.points:type_name -> item.PricePoint
+	13, // 7: item.PriceHistory.summary:type_name -> item.PriceSummary
+	13, // 8: item.PriceHistory.merchants:type_name -> item.PriceSummary
+	0,  // 9: item.PriceInsight.item:type_name -> item.Item
+	16, // 10: item.PriceInsights.insights:type_name -> item.PriceInsight
+	1,  // 11: item.ItemService.ListItems:input_type -> item.ListItemsRequest
+	3,  // 12: item.ItemService.RenameItem:input_type -> item.RenameItemRequest
+	4,  // 13: item.ItemService.SuggestItemMerges:input_type -> item.SuggestItemMergesRequest
+	7,  // 14: item.ItemService.MergeItems:input_type -> item.MergeItemsRequest
+	9,  // 15: item.ItemService.DismissItemMerge:input_type -> item.DismissItemMergeRequest
+	11, // 16: item.ItemService.GetPriceHistory:input_type -> item.GetPriceHistoryRequest
+	15, // 17: item.ItemService.GetPriceInsights:input_type -> item.GetPriceInsightsRequest
+	2,  // 18: item.ItemService.ListItems:output_type -> item.ItemList
+	0,  // 19: item.ItemService.RenameItem:output_type -> item.Item
+	6,  // 20: item.ItemService.SuggestItemMerges:output_type -> item.ItemMergeSuggestions
+	8,  // 21: item.ItemService.MergeItems:output_type -> item.MergeItemsResponse
+	10, // 22: item.ItemService.DismissItemMerge:output_type -> item.DismissItemMergeResponse
+	14, // 23: item.ItemService.GetPriceHistory:output_type -> item.PriceHistory
+	17, // 24: item.ItemService.GetPriceInsights:output_type -> item.PriceInsights
+	18, // [18:25] is the sub-list for method output_type
+	11, // [11:18] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_item_proto_init() }
+func file_item_proto_init() {
+	if File_item_proto != nil {
+		return
+	}
+	file_item_proto_msgTypes[13].OneofWrappers = []any{}
+	file_item_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_item_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_item_proto_goTypes,
+		DependencyIndexes: file_item_proto_depIdxs,
+		MessageInfos:      file_item_proto_msgTypes,
+	}.Build()
+	File_item_proto = out.File
+	file_item_proto_rawDesc = nil
+	file_item_proto_goTypes = nil
+	file_item_proto_depIdxs = nil
+}
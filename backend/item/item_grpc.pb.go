@@ -0,0 +1,359 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: item.proto
+
+package item
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ItemService_ListItems_FullMethodName         = "/item.ItemService/ListItems"
+	ItemService_RenameItem_FullMethodName        = "/item.ItemService/RenameItem"
+	ItemService_SuggestItemMerges_FullMethodName = "/item.ItemService/SuggestItemMerges"
+	ItemService_MergeItems_FullMethodName        = "/item.ItemService/MergeItems"
+	ItemService_DismissItemMerge_FullMethodName  = "/item.ItemService/DismissItemMerge"
+	ItemService_GetPriceHistory_FullMethodName   = "/item.ItemService/GetPriceHistory"
+	ItemService_GetPriceInsights_FullMethodName  = "/item.ItemService/GetPriceInsights"
+)
+
+// ItemServiceClient is the client API for ItemService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Canonical items group purchases of the same thing across receipts, so its
+// unit price can be followed over time. Product names are folded into items
+// automatically, ignoring case, sizes and word order; items that are still
+// the same thing can be merged by the user.
+type ItemServiceClient interface {
+	ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ItemList, error)
+	RenameItem(ctx context.Context, in *RenameItemRequest, opts ...grpc.CallOption) (*Item, error)
+	SuggestItemMerges(ctx context.Context, in *SuggestItemMergesRequest, opts ...grpc.CallOption) (*ItemMergeSuggestions, error)
+	MergeItems(ctx context.Context, in *MergeItemsRequest, opts ...grpc.CallOption) (*MergeItemsResponse, error)
+	DismissItemMerge(ctx context.Context, in *DismissItemMergeRequest, opts ...grpc.CallOption) (*DismissItemMergeResponse, error)
+	GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*PriceHistory, error)
+	GetPriceInsights(ctx context.Context, in *GetPriceInsightsRequest, opts ...grpc.CallOption) (*PriceInsights, error)
+}
+
+type itemServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewItemServiceClient(cc grpc.ClientConnInterface) ItemServiceClient {
+	return &itemServiceClient{cc}
+}
+
+func (c *itemServiceClient) ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ItemList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ItemList)
+	err := c.cc.Invoke(ctx, ItemService_ListItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemServiceClient) RenameItem(ctx context.Context, in *RenameItemRequest, opts ...grpc.CallOption) (*Item, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Item)
+	err := c.cc.Invoke(ctx, ItemService_RenameItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemServiceClient) SuggestItemMerges(ctx context.Context, in *SuggestItemMergesRequest, opts ...grpc.CallOption) (*ItemMergeSuggestions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ItemMergeSuggestions)
+	err := c.cc.Invoke(ctx, ItemService_SuggestItemMerges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemServiceClient) MergeItems(ctx context.Context, in *MergeItemsRequest, opts ...grpc.CallOption) (*MergeItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeItemsResponse)
+	err := c.cc.Invoke(ctx, ItemService_MergeItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemServiceClient) DismissItemMerge(ctx context.Context, in *DismissItemMergeRequest, opts ...grpc.CallOption) (*DismissItemMergeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DismissItemMergeResponse)
+	err := c.cc.Invoke(ctx, ItemService_DismissItemMerge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemServiceClient) GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*PriceHistory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PriceHistory)
+	err := c.cc.Invoke(ctx, ItemService_GetPriceHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemServiceClient) GetPriceInsights(ctx context.Context, in *GetPriceInsightsRequest, opts ...grpc.CallOption) (*PriceInsights, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PriceInsights)
+	err := c.cc.Invoke(ctx, ItemService_GetPriceInsights_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ItemServiceServer is the server API for ItemService service.
+// All implementations must embed UnimplementedItemServiceServer
+// for forward compatibility.
+//
+// Canonical items group purchases of the same thing across receipts, so its
+// unit price can be followed over time. Product names are folded into items
+// automatically, ignoring case, sizes and word order; items that are still
+// the same thing can be merged by the user.
+type ItemServiceServer interface {
+	ListItems(context.Context, *ListItemsRequest) (*ItemList, error)
+	RenameItem(context.Context, *RenameItemRequest) (*Item, error)
+	SuggestItemMerges(context.Context, *SuggestItemMergesRequest) (*ItemMergeSuggestions, error)
+	MergeItems(context.Context, *MergeItemsRequest) (*MergeItemsResponse, error)
+	DismissItemMerge(context.Context, *DismissItemMergeRequest) (*DismissItemMergeResponse, error)
+	GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*PriceHistory, error)
+	GetPriceInsights(context.Context, *GetPriceInsightsRequest) (*PriceInsights, error)
+	mustEmbedUnimplementedItemServiceServer()
+}
+
+// UnimplementedItemServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedItemServiceServer struct{}
+
+func (UnimplementedItemServiceServer) ListItems(context.Context, *ListItemsRequest) (*ItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListItems not implemented")
+}
+func (UnimplementedItemServiceServer) RenameItem(context.Context, *RenameItemRequest) (*Item, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameItem not implemented")
+}
+func (UnimplementedItemServiceServer) SuggestItemMerges(context.Context, *SuggestItemMergesRequest) (*ItemMergeSuggestions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestItemMerges not implemented")
+}
+func (UnimplementedItemServiceServer) MergeItems(context.Context, *MergeItemsRequest) (*MergeItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeItems not implemented")
+}
+func (UnimplementedItemServiceServer) DismissItemMerge(context.Context, *DismissItemMergeRequest) (*DismissItemMergeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DismissItemMerge not implemented")
+}
+func (UnimplementedItemServiceServer) GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*PriceHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceHistory not implemented")
+}
+func (UnimplementedItemServiceServer) GetPriceInsights(context.Context, *GetPriceInsightsRequest) (*PriceInsights, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceInsights not implemented")
+}
+func (UnimplementedItemServiceServer) mustEmbedUnimplementedItemServiceServer() {}
+func (UnimplementedItemServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeItemServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ItemServiceServer will
+// result in compilation errors.
+type UnsafeItemServiceServer interface {
+	mustEmbedUnimplementedItemServiceServer()
+}
+
+func RegisterItemServiceServer(s grpc.ServiceRegistrar, srv ItemServiceServer) {
+	// If the following call pancis, it indicates UnimplementedItemServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ItemService_ServiceDesc, srv)
+}
+
+func _ItemService_ListItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemServiceServer).ListItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemService_ListItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemServiceServer).ListItems(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemService_RenameItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemServiceServer).RenameItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemService_RenameItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemServiceServer).RenameItem(ctx, req.(*RenameItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemService_SuggestItemMerges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestItemMergesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemServiceServer).SuggestItemMerges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemService_SuggestItemMerges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemServiceServer).SuggestItemMerges(ctx, req.(*SuggestItemMergesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemService_MergeItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemServiceServer).MergeItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemService_MergeItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemServiceServer).MergeItems(ctx, req.(*MergeItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemService_DismissItemMerge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DismissItemMergeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemServiceServer).DismissItemMerge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemService_DismissItemMerge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemServiceServer).DismissItemMerge(ctx, req.(*DismissItemMergeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemService_GetPriceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemServiceServer).GetPriceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemService_GetPriceHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemServiceServer).GetPriceHistory(ctx, req.(*GetPriceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemService_GetPriceInsights_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceInsightsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemServiceServer).GetPriceInsights(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemService_GetPriceInsights_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemServiceServer).GetPriceInsights(ctx, req.(*GetPriceInsightsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ItemService_ServiceDesc is the grpc.ServiceDesc for ItemService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ItemService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "item.ItemService",
+	HandlerType: (*ItemServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListItems",
+			Handler:    _ItemService_ListItems_Handler,
+		},
+		{
+			MethodName: "RenameItem",
+			Handler:    _ItemService_RenameItem_Handler,
+		},
+		{
+			MethodName: "SuggestItemMerges",
+			Handler:    _ItemService_SuggestItemMerges_Handler,
+		},
+		{
+			MethodName: "MergeItems",
+			Handler:    _ItemService_MergeItems_Handler,
+		},
+		{
+			MethodName: "DismissItemMerge",
+			Handler:    _ItemService_DismissItemMerge_Handler,
+		},
+		{
+			MethodName: "GetPriceHistory",
+			Handler:    _ItemService_GetPriceHistory_Handler,
+		},
+		{
+			MethodName: "GetPriceInsights",
+			Handler:    _ItemService_GetPriceInsights_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "item.proto",
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/Aneesh-Hegde/expenseManager/product"
 	"github.com/Aneesh-Hegde/expenseManager/products"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
+	balanceService "github.com/Aneesh-Hegde/expenseManager/services/balance/balance"
 	user "github.com/Aneesh-Hegde/expenseManager/user_grpc"
 	"github.com/Aneesh-Hegde/expenseManager/utils"
 	"github.com/Aneesh-Hegde/expenseManager/utils/auth"
@@ -111,14 +112,44 @@ func (s *BalanceService) GetBalances(ctx context.Context, req *balance.GetBalanc
 	return balanceHandler.GetBalance(ctx, req)
 }
 
+// AddBalanceSource is served by the balance service's implementation, which
+// sets account types and posts the opening balance to the journal.
 func (s *BalanceService) AddBalanceSource(ctx context.Context, req *balance.AddBalanceSourceRequest) (*balance.AddBalanceSourceResponse, error) {
-	return balanceHandler.AddBalance(ctx, req)
+	return balanceService.AddBalance(ctx, req)
 }
 
 func (s *BalanceService) UpdateBalance(ctx context.Context, req *balance.UpdateBalanceRequest) (*balance.UpdateBalanceResponse, error) {
 	return balanceHandler.UpdateBalance(ctx, req)
 }
 
+// InternalTransfer is served by the balance service's implementation, which
+// keeps its accounts in account_income_service.
+func (s *BalanceService) InternalTransfer(ctx context.Context, req *balance.TransferFunds) (*balance.TransferFundsResponse, error) {
+	return balanceService.InternalTransfer(ctx, req)
+}
+
+// CheckLedger is served by the balance service's implementation, which
+// owns the journal.
+func (s *BalanceService) CheckLedger(ctx context.Context, req *balance.CheckLedgerRequest) (*balance.CheckLedgerResponse, error) {
+	return balanceService.CheckLedger(ctx, req)
+}
+
+// GetExchangeRate is served by the balance service's implementation, which
+// owns the exchange rates.
+func (s *BalanceService) GetExchangeRate(ctx context.Context, req *balance.GetExchangeRateRequest) (*balance.ExchangeRate, error) {
+	return balanceService.GetExchangeRate(ctx, req)
+}
+
+// UpdateAccount and GetNetWorth are served by the balance service's
+// implementation, which owns account types.
+func (s *BalanceService) UpdateAccount(ctx context.Context, req *balance.UpdateAccountRequest) (*balance.UpdateAccountResponse, error) {
+	return balanceService.UpdateAccount(ctx, req)
+}
+
+func (s *BalanceService) GetNetWorth(ctx context.Context, req *balance.GetNetWorthRequest) (*balance.NetWorthResponse, error) {
+	return balanceService.GetNetWorth(ctx, req)
+}
+
 func (s *BalanceService) GetTransfer(ctx context.Context, req *balance.GetTransferRequest) (*balance.GetTransferResponse, error) {
 	return balanceHandler.GetTransfer(ctx, req)
 }
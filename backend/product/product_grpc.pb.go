@@ -19,10 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ProductService_AddProduct_FullMethodName        = "/product.ProductService/AddProduct"
-	ProductService_UpdateProduct_FullMethodName     = "/product.ProductService/UpdateProduct"
-	ProductService_DeleteProduct_FullMethodName     = "/product.ProductService/DeleteProduct"
-	ProductService_GetProductsByUser_FullMethodName = "/product.ProductService/GetProductsByUser"
+	ProductService_AddProduct_FullMethodName              = "/product.ProductService/AddProduct"
+	ProductService_UpdateProduct_FullMethodName           = "/product.ProductService/UpdateProduct"
+	ProductService_DeleteProduct_FullMethodName           = "/product.ProductService/DeleteProduct"
+	ProductService_GetProductsByUser_FullMethodName       = "/product.ProductService/GetProductsByUser"
+	ProductService_SearchExpenses_FullMethodName          = "/product.ProductService/SearchExpenses"
+	ProductService_ExportProducts_FullMethodName          = "/product.ProductService/ExportProducts"
+	ProductService_ListCustomFields_FullMethodName        = "/product.ProductService/ListCustomFields"
+	ProductService_CreateCustomField_FullMethodName       = "/product.ProductService/CreateCustomField"
+	ProductService_UpdateCustomField_FullMethodName       = "/product.ProductService/UpdateCustomField"
+	ProductService_DeleteCustomField_FullMethodName       = "/product.ProductService/DeleteCustomField"
+	ProductService_GetReceiptAnnotation_FullMethodName    = "/product.ProductService/GetReceiptAnnotation"
+	ProductService_UpdateReceiptAnnotation_FullMethodName = "/product.ProductService/UpdateReceiptAnnotation"
+	ProductService_GetProductSplits_FullMethodName        = "/product.ProductService/GetProductSplits"
+	ProductService_SetProductSplits_FullMethodName        = "/product.ProductService/SetProductSplits"
+	ProductService_SplitReceipt_FullMethodName            = "/product.ProductService/SplitReceipt"
+	ProductService_CreateRefund_FullMethodName            = "/product.ProductService/CreateRefund"
+	ProductService_ListRefunds_FullMethodName             = "/product.ProductService/ListRefunds"
+	ProductService_DeleteRefund_FullMethodName            = "/product.ProductService/DeleteRefund"
 )
 
 // ProductServiceClient is the client API for ProductService service.
@@ -33,6 +47,31 @@ type ProductServiceClient interface {
 	UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
 	DeleteProduct(ctx context.Context, in *DeleteProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
 	GetProductsByUser(ctx context.Context, in *GetProductsByUserRequest, opts ...grpc.CallOption) (*ProductsList, error)
+	SearchExpenses(ctx context.Context, in *SearchExpensesRequest, opts ...grpc.CallOption) (*SearchExpensesResponse, error)
+	ExportProducts(ctx context.Context, in *ExportProductsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportProductsResponse], error)
+	// Custom field definitions. A field's key and type are fixed once created.
+	ListCustomFields(ctx context.Context, in *ListCustomFieldsRequest, opts ...grpc.CallOption) (*CustomFieldList, error)
+	CreateCustomField(ctx context.Context, in *CreateCustomFieldRequest, opts ...grpc.CallOption) (*CustomField, error)
+	UpdateCustomField(ctx context.Context, in *UpdateCustomFieldRequest, opts ...grpc.CallOption) (*CustomField, error)
+	DeleteCustomField(ctx context.Context, in *DeleteCustomFieldRequest, opts ...grpc.CallOption) (*DeleteCustomFieldResponse, error)
+	// Tags, notes and custom field values of a whole receipt.
+	GetReceiptAnnotation(ctx context.Context, in *GetReceiptAnnotationRequest, opts ...grpc.CallOption) (*ReceiptAnnotation, error)
+	UpdateReceiptAnnotation(ctx context.Context, in *UpdateReceiptAnnotationRequest, opts ...grpc.CallOption) (*ReceiptAnnotation, error)
+	// Splits of a product's cost across categories and people. Aggregates
+	// and budgets count only the parts the caller keeps, each under its own
+	// category.
+	GetProductSplits(ctx context.Context, in *GetProductSplitsRequest, opts ...grpc.CallOption) (*ProductSplits, error)
+	SetProductSplits(ctx context.Context, in *SetProductSplitsRequest, opts ...grpc.CallOption) (*ProductSplits, error)
+	SplitReceipt(ctx context.Context, in *SplitReceiptRequest, opts ...grpc.CallOption) (*SplitReceiptResponse, error)
+	// Money back for a purchase: returned items, a partial refund or a whole
+	// reversal of a product or receipt. The original products are kept;
+	// aggregates and budgets count refunds as negative spend under the
+	// products' categories on the refund date. Refunded products cannot be
+	// deleted until their refunds are.
+	CreateRefund(ctx context.Context, in *CreateRefundRequest, opts ...grpc.CallOption) (*Refund, error)
+	ListRefunds(ctx context.Context, in *ListRefundsRequest, opts ...grpc.CallOption) (*RefundList, error)
+	// Also takes the amount back off the balance account it credited.
+	DeleteRefund(ctx context.Context, in *DeleteRefundRequest, opts ...grpc.CallOption) (*DeleteRefundResponse, error)
 }
 
 type productServiceClient struct {
@@ -83,6 +122,155 @@ func (c *productServiceClient) GetProductsByUser(ctx context.Context, in *GetPro
 	return out, nil
 }
 
+func (c *productServiceClient) SearchExpenses(ctx context.Context, in *SearchExpensesRequest, opts ...grpc.CallOption) (*SearchExpensesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchExpensesResponse)
+	err := c.cc.Invoke(ctx, ProductService_SearchExpenses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ExportProducts(ctx context.Context, in *ExportProductsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportProductsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[0], ProductService_ExportProducts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportProductsRequest, ExportProductsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ExportProductsClient = grpc.ServerStreamingClient[ExportProductsResponse]
+
+func (c *productServiceClient) ListCustomFields(ctx context.Context, in *ListCustomFieldsRequest, opts ...grpc.CallOption) (*CustomFieldList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CustomFieldList)
+	err := c.cc.Invoke(ctx, ProductService_ListCustomFields_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CreateCustomField(ctx context.Context, in *CreateCustomFieldRequest, opts ...grpc.CallOption) (*CustomField, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CustomField)
+	err := c.cc.Invoke(ctx, ProductService_CreateCustomField_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) UpdateCustomField(ctx context.Context, in *UpdateCustomFieldRequest, opts ...grpc.CallOption) (*CustomField, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CustomField)
+	err := c.cc.Invoke(ctx, ProductService_UpdateCustomField_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeleteCustomField(ctx context.Context, in *DeleteCustomFieldRequest, opts ...grpc.CallOption) (*DeleteCustomFieldResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteCustomFieldResponse)
+	err := c.cc.Invoke(ctx, ProductService_DeleteCustomField_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetReceiptAnnotation(ctx context.Context, in *GetReceiptAnnotationRequest, opts ...grpc.CallOption) (*ReceiptAnnotation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReceiptAnnotation)
+	err := c.cc.Invoke(ctx, ProductService_GetReceiptAnnotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) UpdateReceiptAnnotation(ctx context.Context, in *UpdateReceiptAnnotationRequest, opts ...grpc.CallOption) (*ReceiptAnnotation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReceiptAnnotation)
+	err := c.cc.Invoke(ctx, ProductService_UpdateReceiptAnnotation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetProductSplits(ctx context.Context, in *GetProductSplitsRequest, opts ...grpc.CallOption) (*ProductSplits, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductSplits)
+	err := c.cc.Invoke(ctx, ProductService_GetProductSplits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SetProductSplits(ctx context.Context, in *SetProductSplitsRequest, opts ...grpc.CallOption) (*ProductSplits, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductSplits)
+	err := c.cc.Invoke(ctx, ProductService_SetProductSplits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SplitReceipt(ctx context.Context, in *SplitReceiptRequest, opts ...grpc.CallOption) (*SplitReceiptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SplitReceiptResponse)
+	err := c.cc.Invoke(ctx, ProductService_SplitReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CreateRefund(ctx context.Context, in *CreateRefundRequest, opts ...grpc.CallOption) (*Refund, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Refund)
+	err := c.cc.Invoke(ctx, ProductService_CreateRefund_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListRefunds(ctx context.Context, in *ListRefundsRequest, opts ...grpc.CallOption) (*RefundList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefundList)
+	err := c.cc.Invoke(ctx, ProductService_ListRefunds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeleteRefund(ctx context.Context, in *DeleteRefundRequest, opts ...grpc.CallOption) (*DeleteRefundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRefundResponse)
+	err := c.cc.Invoke(ctx, ProductService_DeleteRefund_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServiceServer is the server API for ProductService service.
 // All implementations must embed UnimplementedProductServiceServer
 // for forward compatibility.
@@ -91,6 +279,31 @@ type ProductServiceServer interface {
 	UpdateProduct(context.Context, *UpdateProductRequest) (*ProductResponse, error)
 	DeleteProduct(context.Context, *DeleteProductRequest) (*ProductResponse, error)
 	GetProductsByUser(context.Context, *GetProductsByUserRequest) (*ProductsList, error)
+	SearchExpenses(context.Context, *SearchExpensesRequest) (*SearchExpensesResponse, error)
+	ExportProducts(*ExportProductsRequest, grpc.ServerStreamingServer[ExportProductsResponse]) error
+	// Custom field definitions. A field's key and type are fixed once created.
+	ListCustomFields(context.Context, *ListCustomFieldsRequest) (*CustomFieldList, error)
+	CreateCustomField(context.Context, *CreateCustomFieldRequest) (*CustomField, error)
+	UpdateCustomField(context.Context, *UpdateCustomFieldRequest) (*CustomField, error)
+	DeleteCustomField(context.Context, *DeleteCustomFieldRequest) (*DeleteCustomFieldResponse, error)
+	// Tags, notes and custom field values of a whole receipt.
+	GetReceiptAnnotation(context.Context, *GetReceiptAnnotationRequest) (*ReceiptAnnotation, error)
+	UpdateReceiptAnnotation(context.Context, *UpdateReceiptAnnotationRequest) (*ReceiptAnnotation, error)
+	// Splits of a product's cost across categories and people. Aggregates
+	// and budgets count only the parts the caller keeps, each under its own
+	// category.
+	GetProductSplits(context.Context, *GetProductSplitsRequest) (*ProductSplits, error)
+	SetProductSplits(context.Context, *SetProductSplitsRequest) (*ProductSplits, error)
+	SplitReceipt(context.Context, *SplitReceiptRequest) (*SplitReceiptResponse, error)
+	// Money back for a purchase: returned items, a partial refund or a whole
+	// reversal of a product or receipt. The original products are kept;
+	// aggregates and budgets count refunds as negative spend under the
+	// products' categories on the refund date. Refunded products cannot be
+	// deleted until their refunds are.
+	CreateRefund(context.Context, *CreateRefundRequest) (*Refund, error)
+	ListRefunds(context.Context, *ListRefundsRequest) (*RefundList, error)
+	// Also takes the amount back off the balance account it credited.
+	DeleteRefund(context.Context, *DeleteRefundRequest) (*DeleteRefundResponse, error)
 	mustEmbedUnimplementedProductServiceServer()
 }
 
@@ -113,6 +326,48 @@ func (UnimplementedProductServiceServer) DeleteProduct(context.Context, *DeleteP
 func (UnimplementedProductServiceServer) GetProductsByUser(context.Context, *GetProductsByUserRequest) (*ProductsList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetProductsByUser not implemented")
 }
+func (UnimplementedProductServiceServer) SearchExpenses(context.Context, *SearchExpensesRequest) (*SearchExpensesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchExpenses not implemented")
+}
+func (UnimplementedProductServiceServer) ExportProducts(*ExportProductsRequest, grpc.ServerStreamingServer[ExportProductsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportProducts not implemented")
+}
+func (UnimplementedProductServiceServer) ListCustomFields(context.Context, *ListCustomFieldsRequest) (*CustomFieldList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCustomFields not implemented")
+}
+func (UnimplementedProductServiceServer) CreateCustomField(context.Context, *CreateCustomFieldRequest) (*CustomField, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCustomField not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateCustomField(context.Context, *UpdateCustomFieldRequest) (*CustomField, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCustomField not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteCustomField(context.Context, *DeleteCustomFieldRequest) (*DeleteCustomFieldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCustomField not implemented")
+}
+func (UnimplementedProductServiceServer) GetReceiptAnnotation(context.Context, *GetReceiptAnnotationRequest) (*ReceiptAnnotation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceiptAnnotation not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateReceiptAnnotation(context.Context, *UpdateReceiptAnnotationRequest) (*ReceiptAnnotation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReceiptAnnotation not implemented")
+}
+func (UnimplementedProductServiceServer) GetProductSplits(context.Context, *GetProductSplitsRequest) (*ProductSplits, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductSplits not implemented")
+}
+func (UnimplementedProductServiceServer) SetProductSplits(context.Context, *SetProductSplitsRequest) (*ProductSplits, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProductSplits not implemented")
+}
+func (UnimplementedProductServiceServer) SplitReceipt(context.Context, *SplitReceiptRequest) (*SplitReceiptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SplitReceipt not implemented")
+}
+func (UnimplementedProductServiceServer) CreateRefund(context.Context, *CreateRefundRequest) (*Refund, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRefund not implemented")
+}
+func (UnimplementedProductServiceServer) ListRefunds(context.Context, *ListRefundsRequest) (*RefundList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRefunds not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteRefund(context.Context, *DeleteRefundRequest) (*DeleteRefundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRefund not implemented")
+}
 func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
 func (UnimplementedProductServiceServer) testEmbeddedByValue()                        {}
 
@@ -206,6 +461,251 @@ func _ProductService_GetProductsByUser_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_SearchExpenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchExpensesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SearchExpenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SearchExpenses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SearchExpenses(ctx, req.(*SearchExpensesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ExportProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportProductsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ExportProducts(m, &grpc.GenericServerStream[ExportProductsRequest, ExportProductsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ExportProductsServer = grpc.ServerStreamingServer[ExportProductsResponse]
+
+func _ProductService_ListCustomFields_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCustomFieldsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListCustomFields(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ListCustomFields_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListCustomFields(ctx, req.(*ListCustomFieldsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CreateCustomField_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCustomFieldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreateCustomField(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CreateCustomField_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreateCustomField(ctx, req.(*CreateCustomFieldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateCustomField_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCustomFieldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateCustomField(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_UpdateCustomField_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateCustomField(ctx, req.(*UpdateCustomFieldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteCustomField_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCustomFieldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteCustomField(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_DeleteCustomField_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteCustomField(ctx, req.(*DeleteCustomFieldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetReceiptAnnotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReceiptAnnotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetReceiptAnnotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetReceiptAnnotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetReceiptAnnotation(ctx, req.(*GetReceiptAnnotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateReceiptAnnotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReceiptAnnotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateReceiptAnnotation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_UpdateReceiptAnnotation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateReceiptAnnotation(ctx, req.(*UpdateReceiptAnnotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProductSplits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductSplitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProductSplits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetProductSplits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProductSplits(ctx, req.(*GetProductSplitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_SetProductSplits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProductSplitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SetProductSplits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SetProductSplits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SetProductSplits(ctx, req.(*SetProductSplitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_SplitReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SplitReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SplitReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SplitReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SplitReceipt(ctx, req.(*SplitReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CreateRefund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreateRefund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CreateRefund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreateRefund(ctx, req.(*CreateRefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListRefunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRefundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListRefunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ListRefunds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListRefunds(ctx, req.(*ListRefundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteRefund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteRefund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_DeleteRefund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteRefund(ctx, req.(*DeleteRefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProductService_ServiceDesc is the grpc.ServiceDesc for ProductService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,7 +729,65 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProductsByUser",
 			Handler:    _ProductService_GetProductsByUser_Handler,
 		},
+		{
+			MethodName: "SearchExpenses",
+			Handler:    _ProductService_SearchExpenses_Handler,
+		},
+		{
+			MethodName: "ListCustomFields",
+			Handler:    _ProductService_ListCustomFields_Handler,
+		},
+		{
+			MethodName: "CreateCustomField",
+			Handler:    _ProductService_CreateCustomField_Handler,
+		},
+		{
+			MethodName: "UpdateCustomField",
+			Handler:    _ProductService_UpdateCustomField_Handler,
+		},
+		{
+			MethodName: "DeleteCustomField",
+			Handler:    _ProductService_DeleteCustomField_Handler,
+		},
+		{
+			MethodName: "GetReceiptAnnotation",
+			Handler:    _ProductService_GetReceiptAnnotation_Handler,
+		},
+		{
+			MethodName: "UpdateReceiptAnnotation",
+			Handler:    _ProductService_UpdateReceiptAnnotation_Handler,
+		},
+		{
+			MethodName: "GetProductSplits",
+			Handler:    _ProductService_GetProductSplits_Handler,
+		},
+		{
+			MethodName: "SetProductSplits",
+			Handler:    _ProductService_SetProductSplits_Handler,
+		},
+		{
+			MethodName: "SplitReceipt",
+			Handler:    _ProductService_SplitReceipt_Handler,
+		},
+		{
+			MethodName: "CreateRefund",
+			Handler:    _ProductService_CreateRefund_Handler,
+		},
+		{
+			MethodName: "ListRefunds",
+			Handler:    _ProductService_ListRefunds_Handler,
+		},
+		{
+			MethodName: "DeleteRefund",
+			Handler:    _ProductService_DeleteRefund_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportProducts",
+			Handler:       _ProductService_ExportProducts_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "product.proto",
 }
@@ -0,0 +1,373 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: recurring.proto
+
+package recurring
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RecurringService_ListRecurringExpenses_FullMethodName  = "/recurring.RecurringService/ListRecurringExpenses"
+	RecurringService_CreateRecurringExpense_FullMethodName = "/recurring.RecurringService/CreateRecurringExpense"
+	RecurringService_UpdateRecurringExpense_FullMethodName = "/recurring.RecurringService/UpdateRecurringExpense"
+	RecurringService_DeleteRecurringExpense_FullMethodName = "/recurring.RecurringService/DeleteRecurringExpense"
+	RecurringService_GetUpcomingExpenses_FullMethodName    = "/recurring.RecurringService/GetUpcomingExpenses"
+	RecurringService_DetectSubscriptions_FullMethodName    = "/recurring.RecurringService/DetectSubscriptions"
+	RecurringService_DismissSubscription_FullMethodName    = "/recurring.RecurringService/DismissSubscription"
+)
+
+// RecurringServiceClient is the client API for RecurringService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Expenses that repeat on a schedule, such as rent and subscriptions, served
+// by the product service. Each occurrence is recorded as a product tagged
+// "recurring" on the day it falls due. Dates are YYYY-MM-DD.
+type RecurringServiceClient interface {
+	ListRecurringExpenses(ctx context.Context, in *ListRecurringExpensesRequest, opts ...grpc.CallOption) (*RecurringExpenseList, error)
+	// Occurrences from the start date up to today are recorded straight away,
+	// going back at most 90 days; older ones are skipped. The scheduler
+	// likewise skips occurrences it is more than 90 days late for.
+	CreateRecurringExpense(ctx context.Context, in *RecurringExpenseRequest, opts ...grpc.CallOption) (*RecurringExpense, error)
+	// Changes apply to occurrences not recorded yet. Resuming a paused
+	// expense skips the occurrences it missed while paused.
+	UpdateRecurringExpense(ctx context.Context, in *RecurringExpenseRequest, opts ...grpc.CallOption) (*RecurringExpense, error)
+	DeleteRecurringExpense(ctx context.Context, in *DeleteRecurringExpenseRequest, opts ...grpc.CallOption) (*DeleteRecurringExpenseResponse, error)
+	GetUpcomingExpenses(ctx context.Context, in *GetUpcomingExpensesRequest, opts ...grpc.CallOption) (*UpcomingExpenses, error)
+	// Suggests charges in the caller's history from the same merchant for
+	// about the same amount at a steady cadence. Accept a suggestion by
+	// creating a recurring expense starting on its next_expected date.
+	DetectSubscriptions(ctx context.Context, in *DetectSubscriptionsRequest, opts ...grpc.CallOption) (*SubscriptionSuggestions, error)
+	DismissSubscription(ctx context.Context, in *DismissSubscriptionRequest, opts ...grpc.CallOption) (*DismissSubscriptionResponse, error)
+}
+
+type recurringServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRecurringServiceClient(cc grpc.ClientConnInterface) RecurringServiceClient {
+	return &recurringServiceClient{cc}
+}
+
+func (c *recurringServiceClient) ListRecurringExpenses(ctx context.Context, in *ListRecurringExpensesRequest, opts ...grpc.CallOption) (*RecurringExpenseList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecurringExpenseList)
+	err := c.cc.Invoke(ctx, RecurringService_ListRecurringExpenses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recurringServiceClient) CreateRecurringExpense(ctx context.Context, in *RecurringExpenseRequest, opts ...grpc.CallOption) (*RecurringExpense, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecurringExpense)
+	err := c.cc.Invoke(ctx, RecurringService_CreateRecurringExpense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recurringServiceClient) UpdateRecurringExpense(ctx context.Context, in *RecurringExpenseRequest, opts ...grpc.CallOption) (*RecurringExpense, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecurringExpense)
+	err := c.cc.Invoke(ctx, RecurringService_UpdateRecurringExpense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recurringServiceClient) DeleteRecurringExpense(ctx context.Context, in *DeleteRecurringExpenseRequest, opts ...grpc.CallOption) (*DeleteRecurringExpenseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRecurringExpenseResponse)
+	err := c.cc.Invoke(ctx, RecurringService_DeleteRecurringExpense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recurringServiceClient) GetUpcomingExpenses(ctx context.Context, in *GetUpcomingExpensesRequest, opts ...grpc.CallOption) (*UpcomingExpenses, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpcomingExpenses)
+	err := c.cc.Invoke(ctx, RecurringService_GetUpcomingExpenses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recurringServiceClient) DetectSubscriptions(ctx context.Context, in *DetectSubscriptionsRequest, opts ...grpc.CallOption) (*SubscriptionSuggestions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscriptionSuggestions)
+	err := c.cc.Invoke(ctx, RecurringService_DetectSubscriptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recurringServiceClient) DismissSubscription(ctx context.Context, in *DismissSubscriptionRequest, opts ...grpc.CallOption) (*DismissSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DismissSubscriptionResponse)
+	err := c.cc.Invoke(ctx, RecurringService_DismissSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RecurringServiceServer is the server API for RecurringService service.
+// All implementations must embed UnimplementedRecurringServiceServer
+// for forward compatibility.
+//
+// Expenses that repeat on a schedule, such as rent and subscriptions, served
+// by the product service. Each occurrence is recorded as a product tagged
+// "recurring" on the day it falls due. Dates are YYYY-MM-DD.
+type RecurringServiceServer interface {
+	ListRecurringExpenses(context.Context, *ListRecurringExpensesRequest) (*RecurringExpenseList, error)
+	// Occurrences from the start date up to today are recorded straight away,
+	// going back at most 90 days; older ones are skipped. The scheduler
+	// likewise skips occurrences it is more than 90 days late for.
+	CreateRecurringExpense(context.Context, *RecurringExpenseRequest) (*RecurringExpense, error)
+	// Changes apply to occurrences not recorded yet. Resuming a paused
+	// expense skips the occurrences it missed while paused.
+	UpdateRecurringExpense(context.Context, *RecurringExpenseRequest) (*RecurringExpense, error)
+	DeleteRecurringExpense(context.Context, *DeleteRecurringExpenseRequest) (*DeleteRecurringExpenseResponse, error)
+	GetUpcomingExpenses(context.Context, *GetUpcomingExpensesRequest) (*UpcomingExpenses, error)
+	// Suggests charges in the caller's history from the same merchant for
+	// about the same amount at a steady cadence. Accept a suggestion by
+	// creating a recurring expense starting on its next_expected date.
+	DetectSubscriptions(context.Context, *DetectSubscriptionsRequest) (*SubscriptionSuggestions, error)
+	DismissSubscription(context.Context, *DismissSubscriptionRequest) (*DismissSubscriptionResponse, error)
+	mustEmbedUnimplementedRecurringServiceServer()
+}
+
+// UnimplementedRecurringServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRecurringServiceServer struct{}
+
+func (UnimplementedRecurringServiceServer) ListRecurringExpenses(context.Context, *ListRecurringExpensesRequest) (*RecurringExpenseList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecurringExpenses not implemented")
+}
+func (UnimplementedRecurringServiceServer) CreateRecurringExpense(context.Context, *RecurringExpenseRequest) (*RecurringExpense, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRecurringExpense not implemented")
+}
+func (UnimplementedRecurringServiceServer) UpdateRecurringExpense(context.Context, *RecurringExpenseRequest) (*RecurringExpense, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRecurringExpense not implemented")
+}
+func (UnimplementedRecurringServiceServer) DeleteRecurringExpense(context.Context, *DeleteRecurringExpenseRequest) (*DeleteRecurringExpenseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRecurringExpense not implemented")
+}
+func (UnimplementedRecurringServiceServer) GetUpcomingExpenses(context.Context, *GetUpcomingExpensesRequest) (*UpcomingExpenses, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpcomingExpenses not implemented")
+}
+func (UnimplementedRecurringServiceServer) DetectSubscriptions(context.Context, *DetectSubscriptionsRequest) (*SubscriptionSuggestions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetectSubscriptions not implemented")
+}
+func (UnimplementedRecurringServiceServer) DismissSubscription(context.Context, *DismissSubscriptionRequest) (*DismissSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DismissSubscription not implemented")
+}
+func (UnimplementedRecurringServiceServer) mustEmbedUnimplementedRecurringServiceServer() {}
+func (UnimplementedRecurringServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeRecurringServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RecurringServiceServer will
+// result in compilation errors.
+type UnsafeRecurringServiceServer interface {
+	mustEmbedUnimplementedRecurringServiceServer()
+}
+
+func RegisterRecurringServiceServer(s grpc.ServiceRegistrar, srv RecurringServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRecurringServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RecurringService_ServiceDesc, srv)
+}
+
+func _RecurringService_ListRecurringExpenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecurringExpensesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecurringServiceServer).ListRecurringExpenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecurringService_ListRecurringExpenses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecurringServiceServer).ListRecurringExpenses(ctx, req.(*ListRecurringExpensesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecurringService_CreateRecurringExpense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecurringExpenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecurringServiceServer).CreateRecurringExpense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecurringService_CreateRecurringExpense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecurringServiceServer).CreateRecurringExpense(ctx, req.(*RecurringExpenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecurringService_UpdateRecurringExpense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecurringExpenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecurringServiceServer).UpdateRecurringExpense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecurringService_UpdateRecurringExpense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecurringServiceServer).UpdateRecurringExpense(ctx, req.(*RecurringExpenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecurringService_DeleteRecurringExpense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRecurringExpenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecurringServiceServer).DeleteRecurringExpense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecurringService_DeleteRecurringExpense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecurringServiceServer).DeleteRecurringExpense(ctx, req.(*DeleteRecurringExpenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecurringService_GetUpcomingExpenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUpcomingExpensesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecurringServiceServer).GetUpcomingExpenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecurringService_GetUpcomingExpenses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecurringServiceServer).GetUpcomingExpenses(ctx, req.(*GetUpcomingExpensesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecurringService_DetectSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecurringServiceServer).DetectSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecurringService_DetectSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecurringServiceServer).DetectSubscriptions(ctx, req.(*DetectSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecurringService_DismissSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DismissSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecurringServiceServer).DismissSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RecurringService_DismissSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecurringServiceServer).DismissSubscription(ctx, req.(*DismissSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RecurringService_ServiceDesc is the grpc.ServiceDesc for RecurringService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RecurringService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "recurring.RecurringService",
+	HandlerType: (*RecurringServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRecurringExpenses",
+			Handler:    _RecurringService_ListRecurringExpenses_Handler,
+		},
+		{
+			MethodName: "CreateRecurringExpense",
+			Handler:    _RecurringService_CreateRecurringExpense_Handler,
+		},
+		{
+			MethodName: "UpdateRecurringExpense",
+			Handler:    _RecurringService_UpdateRecurringExpense_Handler,
+		},
+		{
+			MethodName: "DeleteRecurringExpense",
+			Handler:    _RecurringService_DeleteRecurringExpense_Handler,
+		},
+		{
+			MethodName: "GetUpcomingExpenses",
+			Handler:    _RecurringService_GetUpcomingExpenses_Handler,
+		},
+		{
+			MethodName: "DetectSubscriptions",
+			Handler:    _RecurringService_DetectSubscriptions_Handler,
+		},
+		{
+			MethodName: "DismissSubscription",
+			Handler:    _RecurringService_DismissSubscription_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "recurring.proto",
+}
@@ -3,11 +3,15 @@ package balance
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
 	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func AddBalance(ctx context.Context, req *balance.AddBalanceSourceRequest) (*balance.AddBalanceSourceResponse, error) {
@@ -22,23 +26,63 @@ func AddBalance(ctx context.Context, req *balance.AddBalanceSourceRequest) (*bal
 
 	userId := md["user_id"][0]
 
-	// Insert new balance
-	balanceID, err := balanceDB.CreateAccountWithIncome(ctx, userId, "Default Cash Account", req.GetBalanceSource(), req.GetInitialAmount())
+	requested, err := money.Requested(req.GetInitialAmount(), req.GetInitialAmountMoney())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	currency := requested.Currency()
+	if currency == "" {
+		currency = money.Normalize(req.GetCurrency())
+	}
+	amount, err := requested.In(currency)
 	if err != nil {
-		return nil, err
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// Format balance_amount as a string
-	balanceAmount := fmt.Sprintf("$%.2f", req.GetInitialAmount())
+	details := balanceDB.AccountDetails{
+		Name:         strings.TrimSpace(req.GetAccountName()),
+		Type:         strings.TrimSpace(req.GetAccountType()),
+		StatementDay: req.StatementDay,
+		InterestRate: req.InterestRate,
+	}
+	if details.Name == "" {
+		details.Name = "Default Cash Account"
+	}
+	if req.GetCreditLimitMoney() != nil {
+		requestedLimit, err := money.Requested(0, req.GetCreditLimitMoney())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		limit, err := requestedLimit.In(currency)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "credit_limit_money must be in %s, the account's currency", currency)
+		}
+		creditLimit := limit.Float64()
+		details.CreditLimit = &creditLimit
+	}
+	if err := details.Validate(); err != nil {
+		return nil, balanceError(err)
+	}
+
+	// Insert new balance
+	balanceID, err := balanceDB.CreateAccountWithIncome(ctx, userId, details, req.GetBalanceSource(), amount)
+	if err != nil {
+		return nil, balanceError(err)
+	}
 
-	// Create Balance message
-	b := &balance.Balance{
-		BalanceId:     balanceID,
-		BalanceAmount: balanceAmount,
-		Balance:       req.GetInitialAmount(),
+	// A card or loan's initial amount is what it owes
+	result := balanceDB.BalanceResult{
+		BalanceID:      balanceID,
+		BalanceSource:  req.GetBalanceSource(),
+		Amount:         amount.Float64(),
+		Currency:       amount.Currency,
+		AccountDetails: details,
+	}
+	if balanceDB.IsLiability(details.Type) {
+		result.Amount = -result.Amount
 	}
 
 	return &balance.AddBalanceSourceResponse{
-		Balance: b,
+		Balance: toBalance(result),
 	}, nil
 }
@@ -6,8 +6,11 @@ import (
 
 	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
 	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func AddIncome(ctx context.Context, req *balance.AddIncomeSourceRequest) (*balance.AddIncomeSourceResponse, error) {
@@ -22,20 +25,23 @@ func AddIncome(ctx context.Context, req *balance.AddIncomeSourceRequest) (*balan
 
 	userId := md["user_id"][0]
 
+	requested, err := money.Requested(req.GetInitialAmount(), req.GetInitialAmountMoney())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// Insert new balance
-incomeID, err := balanceDB.InsertIncome(ctx, userId, req.GetIncomeSource(), req.GetInitialAmount(), "Default Cash Account")
+incomeID, amount, err := balanceDB.InsertIncome(ctx, userId, req.GetIncomeSource(), requested, "Default Cash Account")
     if err != nil {
-        return nil, err
+        return nil, balanceError(err)
     }
 
-	// Format balance_amount as a string
-	balanceAmount := fmt.Sprintf("$%.2f", req.GetInitialAmount())
-
 	// Create Balance message
 	b := &balance.Income{
 		IncomeId:     incomeID,
-		IncomeAmount: balanceAmount,
-		Income:       req.GetInitialAmount(),
+		IncomeAmount: formatAmount(amount.Float64(), amount.Currency),
+		Income:       amount.Float64(),
+		IncomeMoney:  amount.Message(),
 	}
 
 	return &balance.AddIncomeSourceResponse{
@@ -0,0 +1,52 @@
+package balance
+
+import (
+	"context"
+
+	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CheckLedger reports where the caller's journal disagrees with itself or
+// with the products, refunds and transfers posted to it.
+func CheckLedger(ctx context.Context, req *balance.CheckLedgerRequest) (*balance.CheckLedgerResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata not found in context")
+	}
+
+	// Forward token if present
+	if len(md["token"]) > 0 && len(md["token"][0]) > 0 {
+		headers := metadata.Pairs("token", md["token"][0])
+		grpc.SendHeader(ctx, headers)
+	}
+
+	if len(md["user_id"]) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "user ID not found in context")
+	}
+	userId := md["user_id"][0]
+
+	check, err := balanceDB.CheckLedger(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &balance.CheckLedgerResponse{
+		Consistent:     len(check.Issues) == 0,
+		EntriesChecked: check.EntriesChecked,
+	}
+	for _, issue := range check.Issues {
+		resp.Issues = append(resp.Issues, &balance.LedgerIssue{
+			Kind:      issue.Kind,
+			Reference: issue.Reference,
+			Expected:  issue.Expected,
+			Actual:    issue.Actual,
+			Detail:    issue.Detail,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,53 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/fx"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GetExchangeRate returns the rate amounts are converted between two
+// currencies at on a date.
+func GetExchangeRate(ctx context.Context, req *balance.GetExchangeRateRequest) (*balance.ExchangeRate, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata not found in context")
+	}
+
+	// Forward token if present
+	if len(md["token"]) > 0 && len(md["token"][0]) > 0 {
+		headers := metadata.Pairs("token", md["token"][0])
+		grpc.SendHeader(ctx, headers)
+	}
+
+	if req.GetFromCurrency() == "" || req.GetToCurrency() == "" {
+		return nil, status.Error(codes.InvalidArgument, "from_currency and to_currency are required")
+	}
+	date := time.Now()
+	if req.GetDate() != "" {
+		var err error
+		if date, err = time.Parse("2006-01-02", req.GetDate()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "date must be YYYY-MM-DD")
+		}
+	}
+	from, to := money.Normalize(req.GetFromCurrency()), money.Normalize(req.GetToCurrency())
+
+	rate, err := fx.Lookup(ctx, sharedDB.GetDB(), from, to, date)
+	if err != nil {
+		return nil, balanceError(err)
+	}
+	return &balance.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Date:         date.Format("2006-01-02"),
+		Rate:         rate,
+	}, nil
+}
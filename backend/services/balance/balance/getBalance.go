@@ -27,15 +27,7 @@ func GetBalance(ctx context.Context, req *balance.GetBalanceRequest) (*balance.G
 	// Build response with all balances
 	var balances []*balance.Balance
 	for _, result := range balanceResults {
-		balanceAmount := fmt.Sprintf("$%.2f", result.Amount)
-
-		b := &balance.Balance{
-			BalanceId:     result.BalanceID,
-			BalanceSource: result.BalanceSource,
-			BalanceAmount: balanceAmount,
-			Balance:       result.Amount,
-		}
-		balances = append(balances, b)
+		balances = append(balances, toBalance(result))
 	}
 	fmt.Println(balances)
 
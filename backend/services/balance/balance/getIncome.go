@@ -5,6 +5,7 @@ import (
 	"fmt"
 	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
 	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -26,14 +27,16 @@ func GetIncome(ctx context.Context, req *balance.GetIncomeRequest) (*balance.Get
 	// Build response with all incomes
 	var incomes []*balance.Income
 	for _, result := range incomeResults {
-		incomeAmount := fmt.Sprintf("$%.2f", result.Amount)
-
 		income := &balance.Income{
 			IncomeId:     result.IncomeID,
 			IncomeSource: result.BalanceSource,
-			IncomeAmount: incomeAmount,
+			IncomeAmount: formatAmount(result.Amount, result.Currency),
 			Income:       result.Amount,
 			Date:         result.DateAdded.String(),
+			IncomeMoney:  money.MessageOf(result.Amount, result.Currency),
+		}
+		if result.ExchangeRate != nil {
+			income.ExchangeRate = *result.ExchangeRate
 		}
 		incomes = append(incomes, income)
 	}
@@ -0,0 +1,80 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GetNetWorth reports the caller's assets, liabilities and net worth today,
+// the accounts they are made of, and their history over the range asked
+// for.
+func GetNetWorth(ctx context.Context, req *balance.GetNetWorthRequest) (*balance.NetWorthResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata not found in context")
+	}
+
+	// Forward token if present
+	if len(md["token"]) > 0 && len(md["token"][0]) > 0 {
+		headers := metadata.Pairs("token", md["token"][0])
+		grpc.SendHeader(ctx, headers)
+	}
+
+	if len(md["user_id"]) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "user ID not found in context")
+	}
+	userId := md["user_id"][0]
+
+	to := time.Now()
+	if req.GetToDate() != "" {
+		var err error
+		if to, err = time.Parse("2006-01-02", req.GetToDate()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "to_date must be YYYY-MM-DD")
+		}
+	}
+	from := to.AddDate(-1, 0, 0)
+	if req.GetFromDate() != "" {
+		var err error
+		if from, err = time.Parse("2006-01-02", req.GetFromDate()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "from_date must be YYYY-MM-DD")
+		}
+	}
+	interval := req.GetInterval()
+	if interval == "" {
+		interval = "month"
+	}
+
+	worth, err := balanceDB.GetNetWorth(ctx, userId, from, to, interval)
+	if err != nil {
+		return nil, balanceError(err)
+	}
+
+	resp := &balance.NetWorthResponse{
+		Currency:          worth.Currency,
+		Assets:            money.MessageOf(worth.Assets, worth.Currency),
+		Liabilities:       money.MessageOf(worth.Liabilities, worth.Currency),
+		NetWorth:          money.MessageOf(worth.NetWorth, worth.Currency),
+		UnratedBalanceIds: worth.Unrated,
+	}
+	for _, account := range worth.Accounts {
+		resp.Accounts = append(resp.Accounts, toBalance(account))
+	}
+	for _, point := range worth.History {
+		resp.History = append(resp.History, &balance.NetWorthPoint{
+			Date:              point.Date.Format("2006-01-02"),
+			Assets:            money.MessageOf(point.Assets, worth.Currency),
+			Liabilities:       money.MessageOf(point.Liabilities, worth.Currency),
+			NetWorth:          money.MessageOf(point.NetWorth, worth.Currency),
+			UnratedBalanceIds: point.Unrated,
+		})
+	}
+	return resp, nil
+}
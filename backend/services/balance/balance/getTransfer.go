@@ -6,6 +6,7 @@ import (
 
 	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
 	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -26,11 +27,15 @@ func GetTransfer(ctx context.Context, req *balance.GetTransferRequest) (*balance
 	var transferFunds []*balance.TransferFunds
 	for _, result := range transferResults {
 		transfer := &balance.TransferFunds{
-			TransferId: result.TransferID,
-			FromSource: result.FromSource,
-			ToSource:   result.ToSource,
-			Amount:     result.Amount,
-			Date:       result.Date.String(),
+			TransferId:        result.TransferID,
+			FromSource:        result.FromSource,
+			ToSource:          result.ToSource,
+			Amount:            result.Amount,
+			Date:              result.Date.String(),
+			AmountMoney:       money.MessageOf(result.Amount, result.FromCurrency),
+			TargetAmountMoney: money.MessageOf(result.TargetAmount, result.ToCurrency),
+			ExchangeRate:      result.ExchangeRate,
+			Payment:           result.Payment,
 		}
 		transferFunds = append(transferFunds, transfer)
 	}
@@ -0,0 +1,103 @@
+package balance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// maxIdempotencyKey is the longest idempotency key stored.
+const maxIdempotencyKey = 255
+
+func InternalTransfer(ctx context.Context, req *balance.TransferFunds) (*balance.TransferFundsResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata not found in context")
+	}
+
+	// Forward token if present
+	if len(md["token"]) > 0 && len(md["token"][0]) > 0 {
+		headers := metadata.Pairs("token", md["token"][0])
+		grpc.SendHeader(ctx, headers)
+	}
+
+	if len(md["user_id"]) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "user ID not found in context")
+	}
+	userId := md["user_id"][0]
+
+	if req.GetFromBalanceId() == 0 || req.GetToBalanceId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "from_balance_id and to_balance_id are required")
+	}
+	amount, err := money.Requested(req.GetAmount(), req.GetAmountMoney())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	key := strings.TrimSpace(req.GetIdempotencyKey())
+	if len(key) > maxIdempotencyKey {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency_key must be at most %d characters", maxIdempotencyKey)
+	}
+
+	record, err := balanceDB.InternalTransfer(ctx, userId, balanceDB.TransferRequest{
+		FromBalanceID:  req.GetFromBalanceId(),
+		ToBalanceID:    req.GetToBalanceId(),
+		Amount:         amount,
+		Description:    strings.TrimSpace(req.GetDescription()),
+		IdempotencyKey: key,
+		AllowOverdraft: req.GetAllowOverdraft(),
+	})
+	switch {
+	case errors.Is(err, balanceDB.ErrInvalidTransfer):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, balanceDB.ErrAccountNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, balanceDB.ErrInsufficientFunds):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, balanceDB.ErrIdempotencyConflict):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, balanceError(err)
+	}
+
+	return &balance.TransferFundsResponse{
+		TransactionId: record.TransferID,
+		Transfer: &balance.TransferFunds{
+			TransferId:        record.TransferID,
+			FromSource:        record.FromSource,
+			ToSource:          record.ToSource,
+			Amount:            record.Amount,
+			AmountMoney:       money.MessageOf(record.Amount, record.FromCurrency),
+			Date:              record.Date.Format(time.RFC3339),
+			FromBalanceId:     record.FromBalanceID,
+			ToBalanceId:       record.ToBalanceID,
+			Description:       record.Description,
+			TargetAmountMoney: money.MessageOf(record.TargetAmount, record.ToCurrency),
+			ExchangeRate:      record.ExchangeRate,
+			Payment:           record.Payment,
+		},
+		FromBalance: &balance.Balance{
+			BalanceId:     record.FromBalanceID,
+			BalanceSource: record.FromSource,
+			BalanceAmount: formatAmount(record.FromBalance, record.FromCurrency),
+			Balance:       record.FromBalance,
+			BalanceMoney:  money.MessageOf(record.FromBalance, record.FromCurrency),
+		},
+		ToBalance: &balance.Balance{
+			BalanceId:     record.ToBalanceID,
+			BalanceSource: record.ToSource,
+			BalanceAmount: formatAmount(record.ToBalance, record.ToCurrency),
+			Balance:       record.ToBalance,
+			BalanceMoney:  money.MessageOf(record.ToBalance, record.ToCurrency),
+		},
+		Replayed: record.Replayed,
+	}, nil
+}
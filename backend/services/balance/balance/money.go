@@ -0,0 +1,63 @@
+package balance
+
+import (
+	"errors"
+
+	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/fx"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// formatAmount renders an amount for the legacy string fields: "$12.30"
+// for dollars, "12.30 EUR" otherwise.
+func formatAmount(amount float64, currency string) string {
+	m := money.FromFloat(amount, currency)
+	if m.Currency == money.DefaultCurrency {
+		return "$" + m.String()
+	}
+	return m.String() + " " + m.Currency
+}
+
+// balanceError maps account and currency failures to their status codes.
+func balanceError(err error) error {
+	switch {
+	case errors.Is(err, balanceDB.ErrCurrencyMismatch), errors.Is(err, balanceDB.ErrInvalidAccount),
+		errors.Is(err, balanceDB.ErrInvalidPeriod):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, balanceDB.ErrAccountNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, balanceDB.ErrLiabilityIncome), fx.IsNoRate(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
+
+// toBalance converts a balance account to its message.
+func toBalance(result balanceDB.BalanceResult) *balance.Balance {
+	b := &balance.Balance{
+		BalanceId:     result.BalanceID,
+		BalanceSource: result.BalanceSource,
+		BalanceAmount: formatAmount(result.Amount, result.Currency),
+		Balance:       result.Amount,
+		BalanceMoney:  money.MessageOf(result.Amount, result.Currency),
+		AccountName:   result.Name,
+		AccountType:   result.Type,
+		Liability:     balanceDB.IsLiability(result.Type),
+	}
+	if result.BaseAmount != nil {
+		b.BaseBalanceMoney = money.MessageOf(*result.BaseAmount, result.BaseCurrency)
+	}
+	if result.CreditLimit != nil {
+		b.CreditLimitMoney = money.MessageOf(*result.CreditLimit, result.Currency)
+	}
+	if result.StatementDay != nil {
+		b.StatementDay = *result.StatementDay
+	}
+	if result.InterestRate != nil {
+		b.InterestRate = *result.InterestRate
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+package balance
+
+import (
+	"context"
+	"strings"
+
+	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateAccount changes the name, type and type-specific fields of one of
+// the caller's balance accounts.
+func UpdateAccount(ctx context.Context, req *balance.UpdateAccountRequest) (*balance.UpdateAccountResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata not found in context")
+	}
+
+	// Forward token if present
+	if len(md["token"]) > 0 && len(md["token"][0]) > 0 {
+		headers := metadata.Pairs("token", md["token"][0])
+		grpc.SendHeader(ctx, headers)
+	}
+
+	if len(md["user_id"]) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "user ID not found in context")
+	}
+	userId := md["user_id"][0]
+
+	if req.GetBalanceId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "balance_id is required")
+	}
+	update := balanceDB.AccountDetails{
+		Name:         strings.TrimSpace(req.GetAccountName()),
+		Type:         strings.TrimSpace(req.GetAccountType()),
+		StatementDay: req.StatementDay,
+		InterestRate: req.InterestRate,
+	}
+	if req.AccountName != nil && update.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_name must not be empty")
+	}
+	var limit *money.Amount
+	if req.GetCreditLimitMoney() != nil {
+		requested, err := money.Requested(0, req.GetCreditLimitMoney())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		limit = &requested
+	}
+
+	result, err := balanceDB.UpdateAccount(ctx, userId, req.GetBalanceId(), update, limit)
+	if err != nil {
+		return nil, balanceError(err)
+	}
+	return &balance.UpdateAccountResponse{
+		Balance: toBalance(*result),
+	}, nil
+}
@@ -6,8 +6,11 @@ import (
 
 	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
 	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func UpdateBalance(ctx context.Context, req *balance.UpdateBalanceRequest) (*balance.UpdateBalanceResponse, error) {
@@ -24,19 +27,21 @@ func UpdateBalance(ctx context.Context, req *balance.UpdateBalanceRequest) (*bal
 	// Extract userId from metadata
 	userId := md["user_id"][0]
 
-	fmt.Println(req.GetAmount())
+	requested, err := money.Requested(req.GetAmount(), req.GetAmountMoney())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	fmt.Println(req.GetBalanceId())
-	balanceID, _, amount, err := balanceDB.UpdateAccountBalance(ctx, req.GetBalanceId(), userId, req.GetAmount())
+	balanceID, _, amount, err := balanceDB.UpdateAccountBalance(ctx, req.GetBalanceId(), userId, requested)
 	if err != nil {
-		return nil, err
+		return nil, balanceError(err)
 	}
 
-	balanceAmount := fmt.Sprintf("$%.2f", amount)
-
 	b := &balance.Balance{
 		BalanceId:     balanceID,
-		BalanceAmount: balanceAmount,
-		Balance:       amount,
+		BalanceAmount: formatAmount(amount.Float64(), amount.Currency),
+		Balance:       amount.Float64(),
+		BalanceMoney:  amount.Message(),
 	}
 
 	return &balance.UpdateBalanceResponse{
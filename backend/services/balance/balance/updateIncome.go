@@ -6,8 +6,11 @@ import (
 
 	balance "github.com/Aneesh-Hegde/expenseManager/grpc_balance"
 	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func UpdateIncome(ctx context.Context, req *balance.UpdateIncomeRequest) (*balance.UpdateIncomeResponse, error) {
@@ -24,18 +27,20 @@ func UpdateIncome(ctx context.Context, req *balance.UpdateIncomeRequest) (*balan
 	// Extract userId from metadata
 	userId := md["user_id"][0]
 
-	fmt.Println(req.GetAmount())
-	incomeID, _, amount, err := balanceDB.UpdateIncome(ctx, req.GetIncomeId(), userId, req.GetAmount())
+	requested, err := money.Requested(req.GetAmount(), req.GetAmountMoney())
 	if err != nil {
-		return nil, err
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	incomeID, _, amount, err := balanceDB.UpdateIncome(ctx, req.GetIncomeId(), userId, requested)
+	if err != nil {
+		return nil, balanceError(err)
 	}
-
-	balanceAmount := fmt.Sprintf("$%.2f", amount)
 
 	b := &balance.Income{
 		IncomeId:     incomeID,
-		IncomeAmount: balanceAmount,
-		Income:       amount,
+		IncomeAmount: formatAmount(amount.Float64(), amount.Currency),
+		Income:       amount.Float64(),
+		IncomeMoney:  amount.Message(),
 	}
 	return &balance.UpdateIncomeResponse{
 		Income: b,
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+// Values of accounts.account_type.
+const (
+	AccountCash       = "cash"
+	AccountChecking   = "checking"
+	AccountSavings    = "savings"
+	AccountCreditCard = "credit_card"
+	AccountLoan       = "loan"
+	AccountInvestment = "investment"
+)
+
+var (
+	ErrInvalidAccount = errors.New("invalid account")
+	// ErrLiabilityIncome is returned for incomes paid into a credit card or
+	// loan; payments towards them are transfers.
+	ErrLiabilityIncome = errors.New("credit cards and loans take payments as transfers, not incomes")
+)
+
+// IsLiability reports whether accounts of the type hold what is owed.
+func IsLiability(accountType string) bool {
+	return accountType == AccountCreditCard || accountType == AccountLoan
+}
+
+// AccountDetails are the type-specific fields of a balance account.
+// CreditLimit and StatementDay apply to credit cards; InterestRate, an
+// annual percentage, to credit cards, loans and savings.
+type AccountDetails struct {
+	Name         string
+	Type         string
+	CreditLimit  *float64
+	StatementDay *int32
+	InterestRate *float64
+}
+
+// Validate fills in the default type and checks the fields suit it.
+func (d *AccountDetails) Validate() error {
+	if d.Type == "" {
+		d.Type = AccountCash
+	}
+	switch d.Type {
+	case AccountCash, AccountChecking, AccountSavings, AccountCreditCard, AccountLoan, AccountInvestment:
+	default:
+		return fmt.Errorf("%w: unknown account type %q", ErrInvalidAccount, d.Type)
+	}
+	if d.CreditLimit != nil {
+		if d.Type != AccountCreditCard {
+			return fmt.Errorf("%w: only credit cards have a credit limit", ErrInvalidAccount)
+		}
+		if *d.CreditLimit < 0 {
+			return fmt.Errorf("%w: the credit limit must not be negative", ErrInvalidAccount)
+		}
+	}
+	if d.StatementDay != nil {
+		if d.Type != AccountCreditCard {
+			return fmt.Errorf("%w: only credit cards have a statement day", ErrInvalidAccount)
+		}
+		if *d.StatementDay < 1 || *d.StatementDay > 31 {
+			return fmt.Errorf("%w: the statement day must be between 1 and 31", ErrInvalidAccount)
+		}
+	}
+	if d.InterestRate != nil {
+		if d.Type != AccountCreditCard && d.Type != AccountLoan && d.Type != AccountSavings {
+			return fmt.Errorf("%w: only credit cards, loans and savings have an interest rate", ErrInvalidAccount)
+		}
+		if *d.InterestRate < 0 || *d.InterestRate > 1000 {
+			return fmt.Errorf("%w: the interest rate must be between 0 and 1000 percent", ErrInvalidAccount)
+		}
+	}
+	return nil
+}
+
+// accountType returns the type of one of the user's balance accounts.
+func accountType(ctx context.Context, tx pgx.Tx, userID int32, accountID int32) (string, error) {
+	var accountType string
+	err := tx.QueryRow(ctx, `
+        SELECT account_type FROM account_income_service.accounts
+        WHERE account_id = $1 AND user_id = $2`,
+		accountID, userID).Scan(&accountType)
+	if err == pgx.ErrNoRows {
+		return "", fmt.Errorf("%w: %d", ErrAccountNotFound, accountID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read account type: %v", err)
+	}
+	return accountType, nil
+}
+
+// openLiability records what a new credit card or loan owed when it was
+// added: owed taken out of the account, leaving its balance at -owed.
+func openLiability(ctx context.Context, tx pgx.Tx, userID int32, accountID int32, owed money.Money) error {
+	_, err := ledger.Post(ctx, tx, ledger.Entry{
+		UserID:        userID,
+		Kind:          "opening",
+		ReferenceType: "account",
+		ReferenceID:   strconv.Itoa(int(accountID)),
+		Description:   "Opening balance",
+		Postings:      ledger.Move(ledger.BalanceAccount(accountID), ledger.OpeningAccount, owed),
+	})
+	return err
+}
+
+// UpdateAccount changes the name, type and type-specific fields of one of
+// the user's balance accounts. Fields left nil keep their values, except
+// that those the new type does not have are cleared. A card or loan with
+// incomes paid into it cannot be made one. limit, when set, is the new
+// credit limit, rounded to the account's currency; it takes the place of
+// update's CreditLimit.
+func UpdateAccount(ctx context.Context, userID string, accountID int32, update AccountDetails, limit *money.Amount) (*BalanceResult, error) {
+	userIDInt, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %v", err)
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current AccountDetails
+	var currency string
+	err = tx.QueryRow(ctx, `
+        SELECT account_name, account_type, credit_limit::float8, statement_day, interest_rate::float8, currency
+        FROM account_income_service.accounts
+        WHERE account_id = $1 AND user_id = $2
+        FOR UPDATE`,
+		accountID, int32(userIDInt)).Scan(&current.Name, &current.Type, &current.CreditLimit,
+		&current.StatementDay, &current.InterestRate, &currency)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%w: %d", ErrAccountNotFound, accountID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account: %v", err)
+	}
+	if limit != nil {
+		m, err := inAccountCurrency(*limit, currency)
+		if err != nil {
+			return nil, err
+		}
+		creditLimit := m.Float64()
+		update.CreditLimit = &creditLimit
+	}
+
+	details := current
+	if update.Name != "" {
+		details.Name = update.Name
+	}
+	if update.Type != "" && update.Type != current.Type {
+		details.Type = update.Type
+		if details.Type != AccountCreditCard {
+			details.CreditLimit, details.StatementDay = nil, nil
+		}
+		if details.Type != AccountCreditCard && details.Type != AccountLoan && details.Type != AccountSavings {
+			details.InterestRate = nil
+		}
+	}
+	if update.CreditLimit != nil {
+		details.CreditLimit = update.CreditLimit
+	}
+	if update.StatementDay != nil {
+		details.StatementDay = update.StatementDay
+	}
+	if update.InterestRate != nil {
+		details.InterestRate = update.InterestRate
+	}
+	if err := details.Validate(); err != nil {
+		return nil, err
+	}
+	if IsLiability(details.Type) && !IsLiability(current.Type) {
+		var incomes bool
+		err := tx.QueryRow(ctx, `
+            SELECT EXISTS (SELECT 1 FROM account_income_service.incomes WHERE account_id = $1)`,
+			accountID).Scan(&incomes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check account incomes: %v", err)
+		}
+		if incomes {
+			return nil, fmt.Errorf("%w: the account has incomes paid into it", ErrLiabilityIncome)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE account_income_service.accounts
+        SET account_name = $1, account_type = $2, credit_limit = $3, statement_day = $4, interest_rate = $5
+        WHERE account_id = $6 AND user_id = $7`,
+		details.Name, details.Type, details.CreditLimit, details.StatementDay, details.InterestRate,
+		accountID, int32(userIDInt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update account: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit account: %v", err)
+	}
+
+	balances, err := GetUserBalances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range balances {
+		if balances[i].BalanceID == accountID {
+			return &balances[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrAccountNotFound, accountID)
+}
@@ -0,0 +1,171 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+// newLiability creates a credit card or loan owing owed in USD and returns
+// its account ID.
+func newLiability(t *testing.T, ctx context.Context, userID string, kind string, owed float64, limit *float64) int32 {
+	t.Helper()
+	accountID, err := balanceDB.CreateAccountWithIncome(ctx, userID,
+		balanceDB.AccountDetails{Name: kind, Type: kind, CreditLimit: limit}, kind, money.FromFloat(owed, "USD"))
+	if err != nil {
+		t.Fatalf("creating %s: %v", kind, err)
+	}
+	return accountID
+}
+
+func TestLiabilityBalancesAreNegatedWhatIsOwed(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	cash, _ := newCashAccount(t, ctx, userID, 500)
+	card := newLiability(t, ctx, userID, balanceDB.AccountCreditCard, 300, nil)
+
+	if got := balanceOf(t, ctx, userID, card); got != -300 {
+		t.Fatalf("new card balance = %.2f, want -300", got)
+	}
+
+	// Setting a card's balance takes what it owes, as adding it does.
+	_, _, balance, err := balanceDB.UpdateAccountBalance(ctx, card, userID, requested(120))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != money.New(-12000, "USD") {
+		t.Errorf("UpdateAccountBalance returned %s, want -120.00", balance)
+	}
+	if got := balanceOf(t, ctx, userID, card); got != -120 {
+		t.Errorf("card balance after update = %.2f, want -120", got)
+	}
+
+	// Assets are set to the amount as given.
+	if _, _, balance, err = balanceDB.UpdateAccountBalance(ctx, cash, userID, requested(450)); err != nil {
+		t.Fatal(err)
+	}
+	if balance != money.New(45000, "USD") || balanceOf(t, ctx, userID, cash) != 450 {
+		t.Errorf("cash balance after update = %s, want 450.00", balance)
+	}
+
+	// A payment brings what the card owes down.
+	_, err = balanceDB.InternalTransfer(ctx, userID, balanceDB.TransferRequest{
+		FromBalanceID: cash, ToBalanceID: card, Amount: requested(20)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := balanceOf(t, ctx, userID, card); got != -100 {
+		t.Errorf("card balance after payment = %.2f, want -100", got)
+	}
+	if got := balanceOf(t, ctx, userID, cash); got != 430 {
+		t.Errorf("cash balance after payment = %.2f, want 430", got)
+	}
+}
+
+func TestCreditCardSpendsUpToItsLimit(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	cash, _ := newCashAccount(t, ctx, userID, 0)
+	limit := 500.0
+	card := newLiability(t, ctx, userID, balanceDB.AccountCreditCard, 450, &limit)
+
+	withdraw := func(amount float64, overdraft bool) error {
+		_, err := balanceDB.InternalTransfer(ctx, userID, balanceDB.TransferRequest{
+			FromBalanceID: card, ToBalanceID: cash, Amount: requested(amount), AllowOverdraft: overdraft})
+		return err
+	}
+
+	if err := withdraw(100, false); !errors.Is(err, balanceDB.ErrInsufficientFunds) {
+		t.Fatalf("withdrawing past the limit = %v, want ErrInsufficientFunds", err)
+	}
+	if got := balanceOf(t, ctx, userID, card); got != -450 {
+		t.Errorf("refused withdrawal left the card at %.2f, want -450", got)
+	}
+
+	if err := withdraw(50, false); err != nil {
+		t.Fatalf("withdrawing the credit left: %v", err)
+	}
+	if err := withdraw(0.01, false); !errors.Is(err, balanceDB.ErrInsufficientFunds) {
+		t.Fatalf("withdrawing from a maxed card = %v, want ErrInsufficientFunds", err)
+	}
+
+	if err := withdraw(100, true); err != nil {
+		t.Fatalf("withdrawing with an overdraft allowed: %v", err)
+	}
+	if got := balanceOf(t, ctx, userID, card); got != -600 {
+		t.Errorf("card balance = %.2f, want -600", got)
+	}
+	if got := balanceOf(t, ctx, userID, cash); got != 150 {
+		t.Errorf("cash balance = %.2f, want 150", got)
+	}
+}
+
+func TestNetWorthHistory(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	id, _ := strconv.Atoi(userID)
+	cash, _ := newCashAccount(t, ctx, userID, 1000)
+	newLiability(t, ctx, userID, balanceDB.AccountLoan, 300, nil)
+	// XTS is reserved for testing, so no rate ever converts it.
+	unrated, err := balanceDB.CreateAccountWithIncome(ctx, userID,
+		balanceDB.AccountDetails{Name: "Test currency"}, "cash", money.FromFloat(50, "XTS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An income from before today shows in the history from its day.
+	today := time.Now()
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	_, err = ledger.Post(ctx, tx, ledger.Entry{
+		UserID:        int32(id),
+		Kind:          "income",
+		ReferenceType: "income",
+		ReferenceID:   "networth-test",
+		OccurredAt:    today.AddDate(0, 0, -15),
+		Postings:      ledger.Move(ledger.IncomeAccount, ledger.BalanceAccount(cash), money.FromFloat(200, "USD")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	worth, err := balanceDB.GetNetWorth(ctx, userID, today.AddDate(0, 0, -20), today, "day")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if worth.Assets != 1200 || worth.Liabilities != 300 || worth.NetWorth != 900 {
+		t.Errorf("net worth = %.2f - %.2f = %.2f, want 1200 - 300 = 900",
+			worth.Assets, worth.Liabilities, worth.NetWorth)
+	}
+	if len(worth.Unrated) != 1 || worth.Unrated[0] != unrated {
+		t.Errorf("unrated accounts = %v, want [%d]", worth.Unrated, unrated)
+	}
+
+	if len(worth.History) != 21 {
+		t.Fatalf("got %d history points, want 21", len(worth.History))
+	}
+	for i, want := range map[int]float64{0: 0, 4: 0, 5: 200, 10: 200, 20: 900} {
+		if got := worth.History[i].NetWorth; got != want {
+			t.Errorf("net worth %d days in = %.2f, want %.2f", i, got, want)
+		}
+	}
+	if len(worth.History[10].Unrated) != 0 {
+		t.Errorf("unrated accounts before any were opened = %v", worth.History[10].Unrated)
+	}
+}
@@ -3,52 +3,223 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
 	"strconv"
 	"time"
 )
 
-// CreateAccountWithIncome creates a new account with initial income
-func CreateAccountWithIncome(ctx context.Context, userID, accountName, source string, amount float64) (int32, error) {
+// ErrCurrencyMismatch is returned when an amount is not in the currency of
+// the balance account it is for.
+var ErrCurrencyMismatch = errors.New("amount is not in the account's currency")
+
+// inAccountCurrency is amount in currency, the one the balance account it
+// is for holds.
+func inAccountCurrency(amount money.Amount, currency string) (money.Money, error) {
+	m, err := amount.In(currency)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("%w: the account holds %s", ErrCurrencyMismatch, money.Normalize(currency))
+	}
+	return m, nil
+}
+
+// journalIncome restates the journal entry of an income row: its amount
+// paid into the row's balance account, in the account's currency. When
+// currency is set the income must be in it. It returns the income's
+// currency. Incomes cannot be paid into credit cards or loans.
+func journalIncome(ctx context.Context, tx pgx.Tx, userID int32, incomeID int32, currency string) (string, error) {
+	var accountID *int32
+	var amount float64
+	var incomeCurrency string
+	var description *string
+	var date *time.Time
+	var accountType *string
+	err := tx.QueryRow(ctx, `
+        SELECT i.account_id, i.amount::float8, i.currency, i.description, i.date_added, a.account_type
+        FROM account_income_service.incomes i
+        LEFT JOIN account_income_service.accounts a ON a.account_id = i.account_id
+        WHERE i.income_id = $1 AND i.user_id = $2`,
+		incomeID, userID,
+	).Scan(&accountID, &amount, &incomeCurrency, &description, &date, &accountType)
+	if err != nil {
+		return "", fmt.Errorf("failed to read income: %v", err)
+	}
+	if accountType != nil && IsLiability(*accountType) {
+		return "", ErrLiabilityIncome
+	}
+	if currency != "" && money.Normalize(currency) != incomeCurrency {
+		return "", fmt.Errorf("%w: the account holds %s", ErrCurrencyMismatch, incomeCurrency)
+	}
+
+	entry := ledger.Entry{
+		UserID:        userID,
+		Kind:          "income",
+		ReferenceType: "income",
+		ReferenceID:   strconv.Itoa(int(incomeID)),
+	}
+	if accountID != nil {
+		entry.Postings = ledger.Move(ledger.IncomeAccount, ledger.BalanceAccount(*accountID), money.FromFloat(amount, incomeCurrency))
+	}
+	if description != nil {
+		entry.Description = *description
+	}
+	if date != nil {
+		entry.OccurredAt = *date
+	}
+	if _, err := ledger.Restate(ctx, tx, entry); err != nil {
+		return "", err
+	}
+	return incomeCurrency, nil
+}
+
+// CreateAccountWithIncome creates a new account holding amount's currency,
+// with an initial income of amount in it. For credit cards and loans amount
+// is what is owed, recorded as an opening entry instead.
+func CreateAccountWithIncome(ctx context.Context, userID string, details AccountDetails, source string, amount money.Money) (int32, error) {
+	userIDInt, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID: %v", err)
+	}
+	if err := details.Validate(); err != nil {
+		return 0, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// As account_income_service.create_account_with_income, with the
+	// account's currency set before its income is recorded in it.
 	var balanceID int32
-	err := sharedDB.GetDB().QueryRow(ctx,
-		"SELECT account_income_service.create_account_with_income($1, $2, $3, $4)",
-		userID, accountName, source, amount,
+	err = tx.QueryRow(ctx, `
+        INSERT INTO account_income_service.accounts
+            (user_id, account_name, balance_source, currency, account_type, credit_limit, statement_day, interest_rate)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING account_id`,
+		int32(userIDInt), details.Name, source, money.Normalize(amount.Currency), details.Type,
+		details.CreditLimit, details.StatementDay, details.InterestRate,
 	).Scan(&balanceID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create account: %v", err)
 	}
+	if IsLiability(details.Type) {
+		if err := openLiability(ctx, tx, int32(userIDInt), balanceID, amount); err != nil {
+			return 0, err
+		}
+	} else {
+		var incomeID int32
+		err = tx.QueryRow(ctx, `
+            INSERT INTO account_income_service.incomes (user_id, amount, description, date_added, account_id)
+            VALUES ($1, $2, $3, CURRENT_TIMESTAMP, $4)
+            RETURNING income_id`,
+			int32(userIDInt), amount.String(), details.Name, balanceID,
+		).Scan(&incomeID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create account income: %v", err)
+		}
+		if _, err := journalIncome(ctx, tx, int32(userIDInt), incomeID, ""); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit account: %v", err)
+	}
 	return balanceID, nil
 }
 
-// InsertIncome inserts new income record
-func InsertIncome(ctx context.Context, userID, source string, amount float64, accountName string) (int32, error) {
+// InsertIncome inserts new income record. The income is in the currency of
+// the account named accountName it is paid into, and amount is rounded to
+// it; the income as recorded is returned.
+func InsertIncome(ctx context.Context, userID, source string, amount money.Amount, accountName string) (int32, money.Money, error) {
+	userIDInt, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil {
+		return 0, money.Money{}, fmt.Errorf("invalid user ID: %v", err)
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, money.Money{}, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// insert_income opens the account when the user has none of that name,
+	// in the accounts' default currency.
+	accountCurrency := money.DefaultCurrency
+	err = tx.QueryRow(ctx, `
+        SELECT currency FROM account_income_service.accounts
+        WHERE user_id = $1 AND account_name = $2
+        ORDER BY account_id LIMIT 1`,
+		int32(userIDInt), accountName).Scan(&accountCurrency)
+	if err != nil && err != pgx.ErrNoRows {
+		return 0, money.Money{}, fmt.Errorf("failed to find income account: %v", err)
+	}
+	income, err := inAccountCurrency(amount, accountCurrency)
+	if err != nil {
+		return 0, money.Money{}, err
+	}
+
 	var incomeID int32
-	err := sharedDB.GetDB().QueryRow(ctx,
+	err = tx.QueryRow(ctx,
 		"SELECT account_income_service.insert_income($1, $2, $3, $4)",
-		userID, source, amount, accountName,
+		userID, source, income.String(), accountName,
 	).Scan(&incomeID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to insert income: %v", err)
+		return 0, money.Money{}, fmt.Errorf("failed to insert income: %v", err)
 	}
-	return incomeID, nil
+	if _, err := journalIncome(ctx, tx, int32(userIDInt), incomeID, income.Currency); err != nil {
+		return 0, money.Money{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, money.Money{}, fmt.Errorf("failed to commit income: %v", err)
+	}
+	return incomeID, income, nil
 }
 
-// BalanceResult represents a balance record
+// BalanceResult represents a balance record. Amount is negative for
+// credit cards and loans that are owed money.
 type BalanceResult struct {
 	BalanceID     int32
 	UserID        int
 	BalanceSource string
 	Amount        float64
+	// Currency is the currency the account holds Amount in.
+	Currency string
+	// BaseAmount is Amount in the user's BaseCurrency at today's rate; nil
+	// when no rate is known.
+	BaseAmount   *float64
+	BaseCurrency string
+	AccountDetails
 }
 
-// GetUserBalances retrieves all balances for a user
+// GetUserBalances retrieves all balances for a user, as the journal has
+// them.
 func GetUserBalances(ctx context.Context, userID string) ([]BalanceResult, error) {
-	fmt.Printf("%T\n", userID)
-	rows, err := sharedDB.GetDB().Query(ctx,
-		"SELECT * FROM account_income_service.get_user_balances($1)",
-		userID,
+	userIDInt, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %v", err)
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        WITH base AS (SELECT account_income_service.base_currency($1) AS currency)
+        SELECT a.account_id, a.user_id, a.balance_source, a.account_name, a.account_type,
+            a.credit_limit::float8, a.statement_day, a.interest_rate::float8, a.currency, COALESCE(b.balance, 0)::float8,
+            (COALESCE(b.balance, 0) * account_income_service.fx_rate(a.currency, base.currency, CURRENT_DATE))::float8,
+            base.currency
+        FROM account_income_service.accounts a
+        CROSS JOIN base
+        LEFT JOIN account_income_service.ledger_balances($1) b
+            ON b.account_type = 'asset' AND b.code = a.account_id::text AND b.currency = a.currency
+        WHERE a.user_id = $1
+        ORDER BY a.account_id`,
+		int32(userIDInt),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query balances: %v", err)
@@ -58,7 +229,9 @@ func GetUserBalances(ctx context.Context, userID string) ([]BalanceResult, error
 	var balances []BalanceResult
 	for rows.Next() {
 		var balance BalanceResult
-		if err := rows.Scan(&balance.BalanceID, &balance.UserID, &balance.BalanceSource, &balance.Amount); err != nil {
+		if err := rows.Scan(&balance.BalanceID, &balance.UserID, &balance.BalanceSource, &balance.Name,
+			&balance.Type, &balance.CreditLimit, &balance.StatementDay, &balance.InterestRate, &balance.Currency,
+			&balance.Amount, &balance.BaseAmount, &balance.BaseCurrency); err != nil {
 			return nil, fmt.Errorf("failed to scan balance: %v", err)
 		}
 		balances = append(balances, balance)
@@ -81,12 +254,19 @@ type IncomeResult struct {
 	Description   sql.NullString
 	DateAdded     time.Time
 	LastUpdated   time.Time
+	Currency      string
+	// ExchangeRate is what one unit of Currency was worth in the user's
+	// base currency on DateAdded; nil while no rate is known.
+	ExchangeRate *float64
 }
 
 // GetUserIncomes retrieves all incomes for a user
 func GetUserIncomes(ctx context.Context, userID string) ([]IncomeResult, error) {
 	rows, err := sharedDB.GetDB().Query(ctx,
-		"SELECT * FROM account_income_service.get_user_incomes($1)",
+		`SELECT gi.*, i.currency,
+            account_income_service.recorded_rate(i.exchange_rate, i.currency, i.user_id, i.date_added::date)::float8
+        FROM account_income_service.get_user_incomes($1) gi
+        JOIN account_income_service.incomes i ON i.income_id = gi.income_id`,
 		userID,
 	)
 	if err != nil {
@@ -106,6 +286,8 @@ func GetUserIncomes(ctx context.Context, userID string) ([]IncomeResult, error)
 			&income.BalanceSource,
 			&income.DateAdded,
 			&income.LastUpdated,
+			&income.Currency,
+			&income.ExchangeRate,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan income: %v", err)
 		}
@@ -119,20 +301,34 @@ func GetUserIncomes(ctx context.Context, userID string) ([]IncomeResult, error)
 	return incomes, nil
 }
 
-// TransferResult represents a transfer record
+// TransferResult represents a transfer record. Amount is in FromCurrency;
+// the target was credited TargetAmount in ToCurrency.
 type TransferResult struct {
-	TransferID int32
-	UserID     int32
-	FromSource string
-	ToSource   string
-	Amount     float64
-	Date       time.Time
+	TransferID   int32
+	UserID       int32
+	FromSource   string
+	ToSource     string
+	Amount       float64
+	Date         time.Time
+	FromCurrency string
+	ToCurrency   string
+	TargetAmount float64
+	// ExchangeRate is what Amount was converted to TargetAmount at.
+	ExchangeRate float64
+	// Payment is set when the target is a credit card or loan, whose debt
+	// the transfer pays down.
+	Payment bool
 }
 
 // GetUserTransfers retrieves all transfers for a user
 func GetUserTransfers(ctx context.Context, userID string) ([]TransferResult, error) {
 	rows, err := sharedDB.GetDB().Query(ctx,
-		"SELECT * FROM transfer_service.get_user_transfers($1)",
+		`SELECT gt.*, sa.currency, ta.currency, COALESCE(t.target_amount, t.amount)::float8,
+            COALESCE(t.exchange_rate, 1)::float8, account_income_service.is_liability(ta.account_type)
+        FROM transfer_service.get_user_transfers($1) gt
+        JOIN transfer_service.transfers t ON t.transfer_id = gt.transfer_id
+        JOIN account_income_service.accounts sa ON sa.account_id = t.source_account_id
+        JOIN account_income_service.accounts ta ON ta.account_id = t.target_account_id`,
 		userID,
 	)
 	if err != nil {
@@ -150,6 +346,11 @@ func GetUserTransfers(ctx context.Context, userID string) ([]TransferResult, err
 			&transfer.ToSource,
 			&transfer.Amount,
 			&transfer.Date,
+			&transfer.FromCurrency,
+			&transfer.ToCurrency,
+			&transfer.TargetAmount,
+			&transfer.ExchangeRate,
+			&transfer.Payment,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan transfer: %v", err)
 		}
@@ -163,41 +364,109 @@ func GetUserTransfers(ctx context.Context, userID string) ([]TransferResult, err
 	return transfers, nil
 }
 
-// UpdateAccountBalance updates balance for an account
-func UpdateAccountBalance(ctx context.Context, balanceID int32, userID string, amount float64) (int32, int, float64, error) {
-	var resultBalanceID int32
-	var dbUserID int
-	var resultAmount float64
-
+// UpdateAccountBalance updates balance for an account. The journal records
+// the change as an adjustment that brings the account to amount, rounded to
+// the account's currency. For a credit card or loan amount is what it owes,
+// as when it was added, and the balance becomes that negated. The balance
+// is returned. The adjustment is the only record of the change: the
+// account's income rows keep the amounts they were paid in at.
+func UpdateAccountBalance(ctx context.Context, balanceID int32, userID string, amount money.Amount) (int32, int, money.Money, error) {
 	userIDInt, err := strconv.ParseInt(userID, 10, 32)
-	err = sharedDB.GetDB().QueryRow(ctx,
-		"SELECT * FROM account_income_service.update_account_balance($1, $2, $3)",
-		balanceID, int32(userIDInt), amount,
-	).Scan(&resultBalanceID, &dbUserID, &resultAmount)
+	if err != nil {
+		return 0, 0, money.Money{}, fmt.Errorf("invalid user ID: %v", err)
+	}
 
+	tx, err := sharedDB.GetDB().Begin(ctx)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("failed to update balance: %v", err)
+		return 0, 0, money.Money{}, fmt.Errorf("failed to start transaction: %v", err)
 	}
+	defer tx.Rollback(ctx)
 
-	return resultBalanceID, dbUserID, resultAmount, nil
+	kind, err := accountType(ctx, tx, int32(userIDInt), balanceID)
+	if err != nil {
+		return 0, 0, money.Money{}, err
+	}
+	accountCurrency, err := ledger.AccountCurrency(ctx, tx, int32(userIDInt), balanceID)
+	if err != nil {
+		return 0, 0, money.Money{}, err
+	}
+	balance, err := inAccountCurrency(amount, accountCurrency)
+	if err != nil {
+		return 0, 0, money.Money{}, err
+	}
+	if IsLiability(kind) {
+		balance.Minor = -balance.Minor
+	}
+
+	balances, err := ledger.Balances(ctx, tx, int32(userIDInt))
+	if err != nil {
+		return 0, 0, money.Money{}, err
+	}
+	current := money.FromFloat(balances[balanceID], accountCurrency)
+	_, err = ledger.Post(ctx, tx, ledger.Entry{
+		UserID:        int32(userIDInt),
+		Kind:          "adjustment",
+		ReferenceType: "account",
+		ReferenceID:   strconv.Itoa(int(balanceID)),
+		Description:   fmt.Sprintf("Balance set to %s", balance),
+		Postings: ledger.Move(ledger.AdjustmentAccount, ledger.BalanceAccount(balanceID),
+			money.New(balance.Minor-current.Minor, accountCurrency)),
+	})
+	if err != nil {
+		return 0, 0, money.Money{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, money.Money{}, fmt.Errorf("failed to commit balance: %v", err)
+	}
+	return balanceID, int(userIDInt), balance, nil
 }
 
-// UpdateIncome updates an income record
-func UpdateIncome(ctx context.Context, incomeID int32, userID string, amount float64) (int32, int, float64, error) {
+// UpdateIncome updates an income record, with amount rounded to the
+// income's currency. The income as recorded is returned.
+func UpdateIncome(ctx context.Context, incomeID int32, userID string, amount money.Amount) (int32, int, money.Money, error) {
 	var resultIncomeID int32
 	var dbUserID int
 	var resultAmount float64
-	fmt.Println(incomeID,userID,amount)
 
 	userIDInt, err := strconv.ParseInt(userID, 10, 32)
-	err = sharedDB.GetDB().QueryRow(ctx,
+	if err != nil {
+		return 0, 0, money.Money{}, fmt.Errorf("invalid user ID: %v", err)
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, 0, money.Money{}, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var incomeCurrency string
+	err = tx.QueryRow(ctx, `
+        SELECT currency FROM account_income_service.incomes
+        WHERE income_id = $1 AND user_id = $2`,
+		incomeID, int32(userIDInt)).Scan(&incomeCurrency)
+	if err != nil {
+		return 0, 0, money.Money{}, fmt.Errorf("failed to read income: %v", err)
+	}
+	income, err := inAccountCurrency(amount, incomeCurrency)
+	if err != nil {
+		return 0, 0, money.Money{}, err
+	}
+
+	err = tx.QueryRow(ctx,
 		"SELECT * FROM account_income_service.update_income($1, $2, $3)",
-		incomeID, int32(userIDInt), amount,
+		incomeID, int32(userIDInt), income.String(),
 	).Scan(&resultIncomeID, &dbUserID, &resultAmount)
 
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("failed to update income: %v", err)
+		return 0, 0, money.Money{}, fmt.Errorf("failed to update income: %v", err)
+	}
+	if _, err := journalIncome(ctx, tx, int32(userIDInt), resultIncomeID, income.Currency); err != nil {
+		return 0, 0, money.Money{}, err
 	}
 
-	return resultIncomeID, dbUserID, resultAmount, nil
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, money.Money{}, fmt.Errorf("failed to commit income: %v", err)
+	}
+	return resultIncomeID, dbUserID, money.FromFloat(resultAmount, income.Currency), nil
 }
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Aneesh-Hegde/expenseManager/grpc_money"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+// These tests need no database, unlike the rest of the package's.
+
+func TestInAccountCurrency(t *testing.T) {
+	legacy, err := money.Requested(10.005, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yen, err := money.Requested(0, &grpc_money.Money{Value: "1500", Currency: "JPY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := inAccountCurrency(legacy, "usd"); err != nil || got != money.New(1001, "USD") {
+		t.Errorf("a legacy amount in USD = %v, %v; want 10.01 USD", got, err)
+	}
+	if got, err := inAccountCurrency(legacy, "JPY"); err != nil || got != money.New(10, "JPY") {
+		t.Errorf("a legacy amount in JPY = %v, %v; want 10 JPY", got, err)
+	}
+	if got, err := inAccountCurrency(yen, "JPY"); err != nil || got != money.New(1500, "JPY") {
+		t.Errorf("a JPY amount in JPY = %v, %v; want 1500 JPY", got, err)
+	}
+	if _, err := inAccountCurrency(yen, "eur"); !errors.Is(err, ErrCurrencyMismatch) || err.Error() != ErrCurrencyMismatch.Error()+": the account holds EUR" {
+		t.Errorf("a JPY amount in EUR = %v, want ErrCurrencyMismatch naming EUR", err)
+	}
+}
+
+func TestCheckFunds(t *testing.T) {
+	limit := 500.0
+	tests := []struct {
+		name        string
+		accountType string
+		balance     float64
+		creditLimit *float64
+		amount      money.Money
+		wantErr     string
+	}{
+		{name: "all of the balance", accountType: AccountChecking, balance: 25.5, amount: money.New(2550, "USD")},
+		{name: "a cent past the balance", accountType: AccountChecking, balance: 25.5, amount: money.New(2551, "USD"),
+			wantErr: "insufficient funds: the account holds 25.50"},
+		{name: "a balance short by float error", accountType: AccountSavings, balance: 0.1 + 0.2, amount: money.New(30, "USD")},
+		{name: "no decimals", accountType: AccountCash, balance: 1500, amount: money.New(1500, "JPY")},
+		{name: "card within its limit", accountType: AccountCreditCard, balance: -400, creditLimit: &limit,
+			amount: money.New(10000, "USD")},
+		{name: "card past its limit", accountType: AccountCreditCard, balance: -400, creditLimit: &limit,
+			amount: money.New(10001, "USD"), wantErr: "insufficient funds: the card has 100.00 of credit left"},
+		{name: "card without a limit", accountType: AccountCreditCard, balance: 0, amount: money.New(1, "USD"),
+			wantErr: "insufficient funds: the card has 0.00 of credit left"},
+		{name: "limit only counts for cards", accountType: AccountLoan, balance: 0, creditLimit: &limit,
+			amount: money.New(1, "USD"), wantErr: "insufficient funds: the account holds 0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFunds(tt.accountType, tt.balance, tt.creditLimit, tt.amount)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkFunds() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInsufficientFunds) || err.Error() != tt.wantErr {
+				t.Fatalf("checkFunds() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransferKind(t *testing.T) {
+	tests := []struct {
+		toType, description string
+		wantKind, wantDesc  string
+	}{
+		{AccountSavings, "", "transfer", "Internal transfer"},
+		{AccountCreditCard, "", "payment", "Credit card payment"},
+		{AccountLoan, "", "payment", "Loan payment"},
+		{AccountLoan, "Extra repayment", "payment", "Extra repayment"},
+		{AccountChecking, "Rent pot", "transfer", "Rent pot"},
+	}
+	for _, tt := range tests {
+		kind, description := transferKind(tt.toType, tt.description)
+		if kind != tt.wantKind || description != tt.wantDesc {
+			t.Errorf("transferKind(%q, %q) = %q, %q; want %q, %q",
+				tt.toType, tt.description, kind, description, tt.wantKind, tt.wantDesc)
+		}
+	}
+}
+
+func TestAccountDetailsValidate(t *testing.T) {
+	negative, day, badDay, rate := -1.0, int32(15), int32(32), 19.9
+	tests := []struct {
+		name     string
+		details  AccountDetails
+		wantType string
+		wantErr  bool
+	}{
+		{name: "defaults to cash", wantType: AccountCash},
+		{name: "card with its fields", details: AccountDetails{Type: AccountCreditCard, StatementDay: &day, InterestRate: &rate},
+			wantType: AccountCreditCard},
+		{name: "savings with interest", details: AccountDetails{Type: AccountSavings, InterestRate: &rate}, wantType: AccountSavings},
+		{name: "unknown type", details: AccountDetails{Type: "piggy bank"}, wantErr: true},
+		{name: "limit on a checking account", details: AccountDetails{Type: AccountChecking, CreditLimit: &rate}, wantErr: true},
+		{name: "negative limit", details: AccountDetails{Type: AccountCreditCard, CreditLimit: &negative}, wantErr: true},
+		{name: "statement day out of range", details: AccountDetails{Type: AccountCreditCard, StatementDay: &badDay}, wantErr: true},
+		{name: "interest on cash", details: AccountDetails{InterestRate: &rate}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := tt.details
+			err := details.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAccount) {
+					t.Fatalf("Validate() = %v, want ErrInvalidAccount", err)
+				}
+				return
+			}
+			if err != nil || details.Type != tt.wantType {
+				t.Fatalf("Validate() = %v with type %q, want nil with %q", err, details.Type, tt.wantType)
+			}
+		})
+	}
+}
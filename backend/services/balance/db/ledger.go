@@ -0,0 +1,246 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+// LedgerIssue is one way the journal disagrees with itself or with the
+// records it is posted from.
+type LedgerIssue struct {
+	Kind      string
+	Reference string
+	Expected  float64
+	Actual    float64
+	Detail    string
+}
+
+// LedgerCheck is the outcome of CheckLedger.
+type LedgerCheck struct {
+	EntriesChecked int32
+	Issues         []LedgerIssue
+}
+
+// recordedAmounts says, for each kind of record posted to the journal, what
+// its postings to one type of ledger account should come to. The query
+// yields (reference_id, code, amount) for the user $1.
+var recordedAmounts = []struct {
+	kind        string
+	accountType string
+	query       string
+}{
+	{"product", "expense", `
+        SELECT product_id::text, COALESCE(category_id::text, 'uncategorized'),
+            ROUND(COALESCE(quantity, 0) * price, account_income_service.currency_exponent(currency))
+        FROM product_category_service.products
+        WHERE user_id = $1`},
+	{"refund", "expense", `
+        SELECT r.refund_id::text, COALESCE(ri.category_id::text, 'uncategorized'), -ri.amount
+        FROM product_category_service.refunds r
+        JOIN product_category_service.refund_items ri ON ri.refund_id = r.refund_id
+        WHERE r.user_id = $1`},
+	{"transfer", "asset", `
+        SELECT transfer_id::text, source_account_id::text, -amount
+        FROM transfer_service.transfers
+        WHERE user_id = $1
+        UNION ALL
+        SELECT transfer_id::text, target_account_id::text, COALESCE(target_amount, amount)
+        FROM transfer_service.transfers
+        WHERE user_id = $1`},
+}
+
+// unbalancedEntries finds the user's entries whose postings do not sum to
+// zero in some currency.
+func unbalancedEntries(ctx context.Context, tx pgx.Tx, userID int32) ([]LedgerIssue, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT je.entry_id, je.reference_type, je.reference_id, jp.currency, SUM(jp.amount)::float8
+        FROM account_income_service.journal_entries je
+        JOIN account_income_service.journal_postings jp ON jp.entry_id = je.entry_id
+        WHERE je.user_id = $1
+        GROUP BY je.entry_id, je.reference_type, je.reference_id, jp.currency
+        HAVING SUM(jp.amount) <> 0
+        ORDER BY je.entry_id`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check entries: %v", err)
+	}
+	defer rows.Close()
+
+	var issues []LedgerIssue
+	for rows.Next() {
+		var entryID int64
+		var referenceType, referenceID, currency string
+		var sum float64
+		if err := rows.Scan(&entryID, &referenceType, &referenceID, &currency, &sum); err != nil {
+			return nil, fmt.Errorf("failed to scan entry: %v", err)
+		}
+		issues = append(issues, LedgerIssue{
+			Kind:      "unbalanced_entry",
+			Reference: fmt.Sprintf("entry %d", entryID),
+			Actual:    sum,
+			Detail:    fmt.Sprintf("%s postings for %s %s", currency, referenceType, referenceID),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entries: %v", err)
+	}
+	return issues, nil
+}
+
+// snapshotMismatches finds snapshots of the user's accounts that differ from
+// the postings up to the entry they were taken through.
+func snapshotMismatches(ctx context.Context, tx pgx.Tx, userID int32) ([]LedgerIssue, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT a.account_type, a.code, s.currency, s.through_entry_id, s.balance::float8,
+            COALESCE(SUM(jp.amount), 0)::float8
+        FROM account_income_service.ledger_snapshots s
+        JOIN account_income_service.ledger_accounts a ON a.ledger_account_id = s.ledger_account_id
+        LEFT JOIN account_income_service.journal_postings jp
+            ON jp.ledger_account_id = s.ledger_account_id AND jp.currency = s.currency
+            AND jp.entry_id <= s.through_entry_id
+        WHERE a.user_id = $1
+        GROUP BY s.ledger_account_id, s.currency, s.through_entry_id, s.balance, a.account_type, a.code
+        HAVING s.balance <> COALESCE(SUM(jp.amount), 0)
+        ORDER BY a.account_type, a.code, s.through_entry_id`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check snapshots: %v", err)
+	}
+	defer rows.Close()
+
+	var issues []LedgerIssue
+	for rows.Next() {
+		var accountType, code, currency string
+		var throughEntryID int64
+		var snapshot, posted float64
+		if err := rows.Scan(&accountType, &code, &currency, &throughEntryID, &snapshot, &posted); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %v", err)
+		}
+		issues = append(issues, LedgerIssue{
+			Kind:      "snapshot_mismatch",
+			Reference: fmt.Sprintf("%s %s", accountType, code),
+			Expected:  posted,
+			Actual:    snapshot,
+			Detail:    fmt.Sprintf("%s snapshot through entry %d", currency, throughEntryID),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshots: %v", err)
+	}
+	return issues, nil
+}
+
+// referenceMismatches compares what is posted for each record of one kind
+// with what the record says, account by account. Records deleted with
+// postings left standing are reported too.
+func referenceMismatches(ctx context.Context, tx pgx.Tx, userID int32, kind, accountType, recorded string) ([]LedgerIssue, error) {
+	rows, err := tx.Query(ctx, `
+        WITH recorded (reference_id, code, amount) AS (`+recorded+`
+        ),
+        posted AS (
+            SELECT je.reference_id, a.code, SUM(jp.amount) AS amount
+            FROM account_income_service.journal_entries je
+            JOIN account_income_service.journal_postings jp ON jp.entry_id = je.entry_id
+            JOIN account_income_service.ledger_accounts a ON a.ledger_account_id = jp.ledger_account_id
+            WHERE je.user_id = $1 AND je.reference_type = $2 AND a.account_type = $3
+            GROUP BY je.reference_id, a.code
+        ),
+        totals AS (
+            SELECT reference_id, code, SUM(amount) AS amount
+            FROM recorded
+            GROUP BY reference_id, code
+        )
+        SELECT COALESCE(t.reference_id, p.reference_id), COALESCE(t.code, p.code),
+            COALESCE(t.amount, 0)::float8, COALESCE(p.amount, 0)::float8
+        FROM totals t
+        FULL JOIN posted p ON p.reference_id = t.reference_id AND p.code = t.code
+        WHERE COALESCE(t.amount, 0) <> COALESCE(p.amount, 0)
+        ORDER BY 1, 2`,
+		userID, kind, accountType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %ss: %v", kind, err)
+	}
+	defer rows.Close()
+
+	var issues []LedgerIssue
+	for rows.Next() {
+		var referenceID, code string
+		var expected, actual float64
+		if err := rows.Scan(&referenceID, &code, &expected, &actual); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %v", kind, err)
+		}
+		issues = append(issues, LedgerIssue{
+			Kind:      kind,
+			Reference: fmt.Sprintf("%s %s", kind, referenceID),
+			Expected:  expected,
+			Actual:    actual,
+			Detail:    fmt.Sprintf("posted to %s %s", accountType, code),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %ss: %v", kind, err)
+	}
+	return issues, nil
+}
+
+// CheckLedger checks the user's journal from one consistent view of the
+// database: every entry balances, snapshots match the postings they sum,
+// and products, refunds and transfers are posted at what they record.
+func CheckLedger(ctx context.Context, userID string) (*LedgerCheck, error) {
+	userIDInt, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %v", err)
+	}
+
+	tx, err := sharedDB.GetDB().BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var check LedgerCheck
+	err = tx.QueryRow(ctx,
+		"SELECT COUNT(*) FROM account_income_service.journal_entries WHERE user_id = $1",
+		int32(userIDInt),
+	).Scan(&check.EntriesChecked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count entries: %v", err)
+	}
+
+	issues, err := unbalancedEntries(ctx, tx, int32(userIDInt))
+	if err != nil {
+		return nil, err
+	}
+	check.Issues = append(check.Issues, issues...)
+
+	if issues, err = snapshotMismatches(ctx, tx, int32(userIDInt)); err != nil {
+		return nil, err
+	}
+	check.Issues = append(check.Issues, issues...)
+
+	for _, r := range recordedAmounts {
+		if issues, err = referenceMismatches(ctx, tx, int32(userIDInt), r.kind, r.accountType, r.query); err != nil {
+			return nil, err
+		}
+		check.Issues = append(check.Issues, issues...)
+	}
+	return &check, nil
+}
+
+// TakeLedgerSnapshots snapshots the balance of every ledger account with
+// postings since its last snapshot, so balances need only sum what came
+// after. It returns how many snapshots were taken.
+func TakeLedgerSnapshots(ctx context.Context) (int, error) {
+	var taken int
+	err := sharedDB.GetDB().QueryRow(ctx,
+		"SELECT account_income_service.take_ledger_snapshots()",
+	).Scan(&taken)
+	if err != nil {
+		return 0, fmt.Errorf("failed to take ledger snapshots: %v", err)
+	}
+	return taken, nil
+}
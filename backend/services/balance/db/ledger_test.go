@@ -0,0 +1,332 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	goalDB "github.com/Aneesh-Hegde/expenseManager/services/goals/db"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+// These tests write to the database the DB_* variables name, which must
+// have every service's tables and migrations applied. The journal cannot be
+// cleaned up after them, so they only run when DB_TEST is set, against a
+// scratch database.
+func requireDB(t *testing.T) {
+	t.Helper()
+	if os.Getenv("DB_TEST") == "" {
+		t.Skip("DB_TEST is not set")
+	}
+}
+
+// requested is a legacy request amount, in whatever currency it is used in.
+func requested(amount float64) money.Amount {
+	a, _ := money.Requested(amount, nil)
+	return a
+}
+
+// newUser creates a user of its own for a test and returns its ID.
+func newUser(t *testing.T, ctx context.Context) string {
+	t.Helper()
+	name := fmt.Sprintf("ledger-test-%d", time.Now().UnixNano())
+	var userID int32
+	err := sharedDB.GetDB().QueryRow(ctx,
+		"INSERT INTO user_service.users (username, email) VALUES ($1, $2) RETURNING user_id",
+		name, name+"@example.com").Scan(&userID)
+	if err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	return strconv.Itoa(int(userID))
+}
+
+// newCashAccount creates a cash account holding amount in USD, which
+// spending without a named account is drawn from, and returns its account
+// ID and the ID of the income that opened it.
+func newCashAccount(t *testing.T, ctx context.Context, userID string, amount float64) (int32, int32) {
+	t.Helper()
+	accountID, err := balanceDB.CreateAccountWithIncome(ctx, userID,
+		balanceDB.AccountDetails{Name: "Wallet"}, "cash", money.FromFloat(amount, "USD"))
+	if err != nil {
+		t.Fatalf("creating account: %v", err)
+	}
+	var incomeID int32
+	err = sharedDB.GetDB().QueryRow(ctx,
+		"SELECT income_id FROM account_income_service.incomes WHERE account_id = $1",
+		accountID).Scan(&incomeID)
+	if err != nil {
+		t.Fatalf("finding opening income: %v", err)
+	}
+	return accountID, incomeID
+}
+
+func balanceOf(t *testing.T, ctx context.Context, userID string, accountID int32) float64 {
+	t.Helper()
+	id, _ := strconv.Atoi(userID)
+	balances, err := ledger.Balances(ctx, sharedDB.GetDB(), int32(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balances[accountID]
+}
+
+func checkLedger(t *testing.T, ctx context.Context, userID string) *balanceDB.LedgerCheck {
+	t.Helper()
+	check, err := balanceDB.CheckLedger(ctx, userID)
+	if err != nil {
+		t.Fatalf("CheckLedger: %v", err)
+	}
+	return check
+}
+
+// assetPostings sums what every entry for e's reference posted to asset
+// accounts.
+func assetPostings(t *testing.T, ctx context.Context, tx pgx.Tx, e ledger.Entry) float64 {
+	t.Helper()
+	var posted float64
+	err := tx.QueryRow(ctx, `
+        SELECT COALESCE(SUM(jp.amount), 0)::float8
+        FROM account_income_service.journal_entries je
+        JOIN account_income_service.journal_postings jp ON jp.entry_id = je.entry_id
+        JOIN account_income_service.ledger_accounts a ON a.ledger_account_id = jp.ledger_account_id
+        WHERE je.user_id = $1 AND je.reference_type = $2 AND je.reference_id = $3
+          AND a.account_type = 'asset'`,
+		e.UserID, e.ReferenceType, e.ReferenceID).Scan(&posted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return posted
+}
+
+func TestPostAndRestateBalance(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	id, _ := strconv.Atoi(userID)
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	entry := ledger.Entry{
+		UserID:        int32(id),
+		Kind:          "income",
+		ReferenceType: "income",
+		ReferenceID:   "restate-test",
+		Postings:      ledger.Move(ledger.IncomeAccount, ledger.BalanceAccount(1), money.FromFloat(100, "USD")),
+	}
+	if entryID, err := ledger.Post(ctx, tx, entry); err != nil || entryID == 0 {
+		t.Fatalf("Post() = %d, %v", entryID, err)
+	}
+
+	// Restating to what is already posted adds nothing.
+	if entryID, err := ledger.Restate(ctx, tx, entry); err != nil || entryID != 0 {
+		t.Fatalf("Restate() unchanged = %d, %v; want 0, nil", entryID, err)
+	}
+
+	entry.Postings = ledger.Move(ledger.IncomeAccount, ledger.BalanceAccount(1), money.FromFloat(80, "USD"))
+	if entryID, err := ledger.Restate(ctx, tx, entry); err != nil || entryID == 0 {
+		t.Fatalf("Restate() changed = %d, %v", entryID, err)
+	}
+	if posted := assetPostings(t, ctx, tx, entry); posted != 80 {
+		t.Errorf("restated postings come to %.2f, want 80", posted)
+	}
+
+	// No postings reverses the reference.
+	entry.Postings = nil
+	if _, err := ledger.Restate(ctx, tx, entry); err != nil {
+		t.Fatal(err)
+	}
+	if posted := assetPostings(t, ctx, tx, entry); posted != 0 {
+		t.Errorf("reversed postings come to %.2f, want 0", posted)
+	}
+}
+
+func TestPostJournalEntryRejectsUnbalancedPostings(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Called directly, past Entry.Validate.
+	_, err = tx.Exec(ctx, `
+        SELECT account_income_service.post_journal_entry($1, 'test', 'test', '1', NULL, NOW(),
+            '[{"type": "asset", "code": "1", "amount": "10.00", "currency": "USD"},
+              {"type": "income", "code": "income", "amount": "-9.99", "currency": "USD"}]'::jsonb)`,
+		userID)
+	if err == nil {
+		t.Fatal("post_journal_entry accepted postings that do not balance")
+	}
+}
+
+func TestBalancedEntryTriggerRejectsUnbalancedCommit(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var ledgerAccountID int32
+	err = tx.QueryRow(ctx, `
+        INSERT INTO account_income_service.ledger_accounts (user_id, account_type, code)
+        VALUES ($1, 'asset', 'trigger-test')
+        RETURNING ledger_account_id`,
+		userID).Scan(&ledgerAccountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entryID int64
+	err = tx.QueryRow(ctx, `
+        INSERT INTO account_income_service.journal_entries (user_id, kind, reference_type, reference_id)
+        VALUES ($1, 'test', 'test', 'trigger-test')
+        RETURNING entry_id`,
+		userID).Scan(&entryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.Exec(ctx, `
+        INSERT INTO account_income_service.journal_postings (entry_id, ledger_account_id, amount, currency)
+        VALUES ($1, $2, 10, 'USD')`,
+		entryID, ledgerAccountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The check is deferred to commit, so an entry's postings can be
+	// inserted one at a time.
+	if err := tx.Commit(ctx); err == nil {
+		t.Fatal("committed a journal entry that does not balance")
+	}
+}
+
+func TestCheckLedgerAfterGoalRefundAndIncomeChanges(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	accountID, incomeID := newCashAccount(t, ctx, userID, 1000)
+
+	goalID, err := goalDB.CreateGoal(ctx, userID, "Trip", "", 500, 0,
+		time.Now().AddDate(1, 0, 0), time.Now(), "Travel", "#336699", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := goalDB.ExecuteGoalUpdateTransaction(ctx, goalID, userID, 200, incomeID, "deposit", "", 200, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	product, err := productDB.InsertProduct(ctx, userID, productDB.Product{
+		ProductName: "Headphones",
+		Quantity:    3,
+		Price:       money.New(1000, "USD"),
+		DateAdded:   time.Now().AddDate(0, 0, -1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := productDB.CreateRefund(ctx, userID, productDB.RefundRequest{
+		ProductID: product.ProductID,
+		Amount:    requested(5),
+		BalanceID: incomeID,
+		Date:      time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restating the opening income must not count the goal or the refund
+	// a second time.
+	if _, _, _, err := balanceDB.UpdateIncome(ctx, incomeID, userID, requested(1200)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := balanceOf(t, ctx, userID, accountID), 1200.0-200-30+5; got != want {
+		t.Errorf("balance = %.2f, want %.2f", got, want)
+	}
+	if check := checkLedger(t, ctx, userID); len(check.Issues) != 0 {
+		t.Errorf("CheckLedger found %d issues: %+v", len(check.Issues), check.Issues)
+	}
+}
+
+func TestUpdateAccountBalanceLeavesIncomeAlone(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	accountID, incomeID := newCashAccount(t, ctx, userID, 100)
+
+	if _, _, _, err := balanceDB.UpdateAccountBalance(ctx, accountID, userID, requested(250)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := balanceDB.UpdateIncome(ctx, incomeID, userID, requested(120)); err != nil {
+		t.Fatal(err)
+	}
+	// The balance was set to 250, and the income then raised by 20.
+	if got := balanceOf(t, ctx, userID, accountID); got != 270 {
+		t.Errorf("balance = %.2f, want 270", got)
+	}
+}
+
+func TestCheckLedgerReportsStrayPostings(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	newCashAccount(t, ctx, userID, 100)
+	id, _ := strconv.Atoi(userID)
+
+	product, err := productDB.InsertProduct(ctx, userID, productDB.Product{
+		ProductName: "Coffee",
+		Quantity:    1,
+		Price:       money.New(400, "USD"),
+		DateAdded:   time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check := checkLedger(t, ctx, userID); len(check.Issues) != 0 {
+		t.Fatalf("CheckLedger found %d issues before the stray entry: %+v", len(check.Issues), check.Issues)
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	_, err = ledger.Post(ctx, tx, ledger.Entry{
+		UserID:        int32(id),
+		Kind:          "expense",
+		ReferenceType: "product",
+		ReferenceID:   strconv.Itoa(int(product.ProductID)),
+		Postings:      ledger.Move(ledger.AdjustmentAccount, ledger.ExpenseAccount(product.CategoryID), money.FromFloat(1, "USD")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	check := checkLedger(t, ctx, userID)
+	if len(check.Issues) != 1 {
+		t.Fatalf("CheckLedger found %d issues, want 1: %+v", len(check.Issues), check.Issues)
+	}
+	issue := check.Issues[0]
+	if issue.Kind != "product" || issue.Expected != 4 || issue.Actual != 5 {
+		t.Errorf("issue = %+v, want product posted at 5 against 4", issue)
+	}
+}
@@ -0,0 +1,627 @@
+-- Schema changes for account_income_service and transfer_service, applied in
+-- order on top of the existing tables, after the user and product services'
+-- migrations.
+
+-- Idempotency keys of internal transfers. A request that repeats a key gets
+-- the transfer the key first made; the parameters are kept to tell a retry
+-- from a different transfer reusing the key.
+CREATE TABLE IF NOT EXISTS transfer_service.transfer_requests (
+    user_id INT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    source_account_id INT NOT NULL,
+    target_account_id INT NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL,
+    transfer_id INT REFERENCES transfer_service.transfers (transfer_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+-- Double-entry journal, the source of truth for balances. Every income,
+-- expense, transfer, refund and goal contribution is an entry whose
+-- postings sum to zero per currency; a balance account's balance is the sum
+-- of the postings to its asset ledger account. Entries are never changed or
+-- removed: a change to what they record is posted as a further entry for
+-- the same reference that makes up the difference.
+--
+-- Ledger accounts are keyed by type and code: asset accounts by the balance
+-- account's account_id, expense accounts by category_id, goal accounts by
+-- goal id, and income and equity accounts by name.
+CREATE TABLE IF NOT EXISTS account_income_service.ledger_accounts (
+    ledger_account_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_type VARCHAR(20) NOT NULL CHECK (account_type IN ('asset', 'income', 'expense', 'equity', 'goal')),
+    code VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, account_type, code)
+);
+
+CREATE TABLE IF NOT EXISTS account_income_service.journal_entries (
+    entry_id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    reference_type VARCHAR(30) NOT NULL,
+    reference_id VARCHAR(64) NOT NULL,
+    description TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_reference
+    ON account_income_service.journal_entries (reference_type, reference_id);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_user
+    ON account_income_service.journal_entries (user_id, entry_id);
+
+CREATE TABLE IF NOT EXISTS account_income_service.journal_postings (
+    posting_id BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES account_income_service.journal_entries (entry_id),
+    ledger_account_id INT NOT NULL REFERENCES account_income_service.ledger_accounts (ledger_account_id),
+    amount NUMERIC(14, 2) NOT NULL CHECK (amount <> 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD'
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_postings_entry
+    ON account_income_service.journal_postings (entry_id);
+
+CREATE INDEX IF NOT EXISTS idx_journal_postings_account
+    ON account_income_service.journal_postings (ledger_account_id, entry_id);
+
+-- Balances of ledger accounts through an entry, so balances need only sum
+-- the postings made since.
+CREATE TABLE IF NOT EXISTS account_income_service.ledger_snapshots (
+    ledger_account_id INT NOT NULL REFERENCES account_income_service.ledger_accounts (ledger_account_id),
+    currency CHAR(3) NOT NULL,
+    through_entry_id BIGINT NOT NULL,
+    balance NUMERIC(14, 2) NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (ledger_account_id, currency, through_entry_id)
+);
+
+CREATE OR REPLACE FUNCTION account_income_service.journal_append_only() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only; post a correcting entry instead', TG_TABLE_NAME;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS journal_entries_append_only ON account_income_service.journal_entries;
+CREATE TRIGGER journal_entries_append_only
+    BEFORE UPDATE OR DELETE ON account_income_service.journal_entries
+    FOR EACH ROW EXECUTE FUNCTION account_income_service.journal_append_only();
+
+DROP TRIGGER IF EXISTS journal_postings_append_only ON account_income_service.journal_postings;
+CREATE TRIGGER journal_postings_append_only
+    BEFORE UPDATE OR DELETE ON account_income_service.journal_postings
+    FOR EACH ROW EXECUTE FUNCTION account_income_service.journal_append_only();
+
+-- Checked at commit, once all of an entry's postings are in.
+CREATE OR REPLACE FUNCTION account_income_service.journal_check_balanced() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM account_income_service.journal_postings WHERE entry_id = NEW.entry_id) THEN
+        RAISE EXCEPTION 'journal entry % has no postings', NEW.entry_id;
+    END IF;
+    IF EXISTS (
+        SELECT 1 FROM account_income_service.journal_postings
+        WHERE entry_id = NEW.entry_id
+        GROUP BY currency
+        HAVING SUM(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'journal entry % does not balance', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS journal_entries_balanced ON account_income_service.journal_entries;
+CREATE CONSTRAINT TRIGGER journal_entries_balanced
+    AFTER INSERT ON account_income_service.journal_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION account_income_service.journal_check_balanced();
+
+DROP TRIGGER IF EXISTS journal_postings_balanced ON account_income_service.journal_postings;
+CREATE CONSTRAINT TRIGGER journal_postings_balanced
+    AFTER INSERT ON account_income_service.journal_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION account_income_service.journal_check_balanced();
+
+-- Appends an entry. p_postings is a JSON array of objects with type, code,
+-- amount and optionally currency; postings to the same account are merged
+-- and ones that come to zero dropped. Returns NULL when nothing is left.
+CREATE OR REPLACE FUNCTION account_income_service.post_journal_entry(
+    p_user_id INT, p_kind VARCHAR, p_reference_type VARCHAR, p_reference_id VARCHAR,
+    p_description TEXT, p_occurred_at TIMESTAMPTZ, p_postings JSONB) RETURNS BIGINT
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    v_entry_id BIGINT;
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM jsonb_array_elements(p_postings) p
+        GROUP BY COALESCE(p->>'currency', 'USD')
+        HAVING SUM((p->>'amount')::numeric) <> 0
+    ) THEN
+        RAISE EXCEPTION 'postings for % % do not balance', p_reference_type, p_reference_id;
+    END IF;
+    IF NOT EXISTS (
+        SELECT 1 FROM jsonb_array_elements(p_postings) p
+        GROUP BY p->>'type', p->>'code', COALESCE(p->>'currency', 'USD')
+        HAVING SUM((p->>'amount')::numeric) <> 0
+    ) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO account_income_service.ledger_accounts (user_id, account_type, code)
+    SELECT DISTINCT p_user_id, p->>'type', p->>'code' FROM jsonb_array_elements(p_postings) p
+    ON CONFLICT (user_id, account_type, code) DO NOTHING;
+
+    INSERT INTO account_income_service.journal_entries
+        (user_id, kind, reference_type, reference_id, description, occurred_at)
+    VALUES (p_user_id, p_kind, p_reference_type, p_reference_id, p_description, COALESCE(p_occurred_at, CURRENT_TIMESTAMP))
+    RETURNING entry_id INTO v_entry_id;
+
+    INSERT INTO account_income_service.journal_postings (entry_id, ledger_account_id, amount, currency)
+    SELECT v_entry_id, a.ledger_account_id, SUM((p->>'amount')::numeric), COALESCE(p->>'currency', 'USD')
+    FROM jsonb_array_elements(p_postings) p
+    JOIN account_income_service.ledger_accounts a
+        ON a.user_id = p_user_id AND a.account_type = p->>'type' AND a.code = p->>'code'
+    GROUP BY a.ledger_account_id, COALESCE(p->>'currency', 'USD')
+    HAVING SUM((p->>'amount')::numeric) <> 0;
+
+    RETURN v_entry_id;
+END;
+$$;
+
+-- Brings what is posted for a reference, summed over all its entries, to
+-- p_postings by appending an entry for the difference. An empty array
+-- reverses the reference. Returns NULL when nothing changed.
+CREATE OR REPLACE FUNCTION account_income_service.restate_journal(
+    p_user_id INT, p_kind VARCHAR, p_reference_type VARCHAR, p_reference_id VARCHAR,
+    p_description TEXT, p_occurred_at TIMESTAMPTZ, p_postings JSONB) RETURNS BIGINT
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    v_difference JSONB;
+BEGIN
+    -- Restatements of one reference must see each other's entries.
+    PERFORM pg_advisory_xact_lock(hashtext('journal:' || p_reference_type || ':' || p_reference_id));
+
+    SELECT COALESCE(jsonb_agg(d), '[]'::jsonb) INTO v_difference
+    FROM (
+        SELECT p->>'type' AS type, p->>'code' AS code, COALESCE(p->>'currency', 'USD') AS currency,
+            (p->>'amount')::numeric AS amount
+        FROM jsonb_array_elements(p_postings) p
+        UNION ALL
+        SELECT a.account_type, a.code, jp.currency, -jp.amount
+        FROM account_income_service.journal_entries je
+        JOIN account_income_service.journal_postings jp ON jp.entry_id = je.entry_id
+        JOIN account_income_service.ledger_accounts a ON a.ledger_account_id = jp.ledger_account_id
+        WHERE je.user_id = p_user_id AND je.reference_type = p_reference_type
+          AND je.reference_id = p_reference_id
+    ) d;
+
+    RETURN account_income_service.post_journal_entry(p_user_id, p_kind, p_reference_type, p_reference_id,
+        p_description, p_occurred_at, v_difference);
+END;
+$$;
+
+-- Balances of the user's ledger accounts: the latest snapshot of each plus
+-- the postings made since.
+CREATE OR REPLACE FUNCTION account_income_service.ledger_balances(p_user_id INT)
+    RETURNS TABLE(ledger_account_id INT, account_type VARCHAR, code VARCHAR, currency CHAR(3), balance NUMERIC)
+    LANGUAGE sql STABLE
+    AS $$
+    WITH latest AS (
+        SELECT DISTINCT ON (s.ledger_account_id, s.currency)
+            s.ledger_account_id, s.currency, s.through_entry_id, s.balance
+        FROM account_income_service.ledger_snapshots s
+        JOIN account_income_service.ledger_accounts a ON a.ledger_account_id = s.ledger_account_id
+        WHERE a.user_id = p_user_id
+        ORDER BY s.ledger_account_id, s.currency, s.through_entry_id DESC
+    ),
+    recent AS (
+        SELECT jp.ledger_account_id, jp.currency, SUM(jp.amount) AS amount
+        FROM account_income_service.journal_postings jp
+        JOIN account_income_service.ledger_accounts a ON a.ledger_account_id = jp.ledger_account_id
+        LEFT JOIN latest l ON l.ledger_account_id = jp.ledger_account_id AND l.currency = jp.currency
+        WHERE a.user_id = p_user_id AND jp.entry_id > COALESCE(l.through_entry_id, 0)
+        GROUP BY jp.ledger_account_id, jp.currency
+    )
+    SELECT a.ledger_account_id, a.account_type, a.code, COALESCE(l.currency, r.currency),
+        COALESCE(l.balance, 0) + COALESCE(r.amount, 0)
+    FROM latest l
+    FULL JOIN recent r ON r.ledger_account_id = l.ledger_account_id AND r.currency = l.currency
+    JOIN account_income_service.ledger_accounts a
+        ON a.ledger_account_id = COALESCE(l.ledger_account_id, r.ledger_account_id);
+$$;
+
+-- Snapshots every ledger account that has had postings since its last
+-- snapshot. Snapshots stop short of the last hour's entries so that none
+-- still being committed is passed over. Returns how many were taken.
+CREATE OR REPLACE FUNCTION account_income_service.take_ledger_snapshots() RETURNS INT
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    v_taken INT;
+BEGIN
+    INSERT INTO account_income_service.ledger_snapshots (ledger_account_id, currency, through_entry_id, balance)
+    SELECT jp.ledger_account_id, jp.currency, c.through_entry_id, SUM(jp.amount)
+    FROM (
+        SELECT MAX(entry_id) AS through_entry_id FROM account_income_service.journal_entries
+        WHERE created_at < CURRENT_TIMESTAMP - interval '1 hour'
+    ) c
+    JOIN account_income_service.journal_postings jp ON jp.entry_id <= c.through_entry_id
+    GROUP BY jp.ledger_account_id, jp.currency, c.through_entry_id
+    HAVING MAX(jp.entry_id) > COALESCE((
+        SELECT MAX(s.through_entry_id) FROM account_income_service.ledger_snapshots s
+        WHERE s.ledger_account_id = jp.ledger_account_id AND s.currency = jp.currency), 0);
+    GET DIAGNOSTICS v_taken = ROW_COUNT;
+    RETURN v_taken;
+END;
+$$;
+
+-- Currencies. Accounts hold one currency; incomes, products and goals name
+-- their own, and each income and product records the rate it was converted
+-- to its user's base currency at (user_service.users.base_currency), which
+-- aggregates multiply by. Rates come from exchange_rates, filled by the
+-- balance service's rate providers.
+ALTER TABLE account_income_service.accounts
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE account_income_service.incomes
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(20, 10);
+-- Transfers between accounts in different currencies credit target_amount,
+-- amount converted at exchange_rate.
+ALTER TABLE transfer_service.transfers
+    ADD COLUMN IF NOT EXISTS target_amount NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(20, 10);
+
+-- Units of quote_currency one unit of base_currency bought on rate_date.
+CREATE TABLE IF NOT EXISTS account_income_service.exchange_rates (
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate_date DATE NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL,
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency, rate_date),
+    CHECK (base_currency <> quote_currency)
+);
+
+-- Decimal places of a currency's minor unit, as package money has them.
+CREATE OR REPLACE FUNCTION account_income_service.currency_exponent(p_currency CHAR(3)) RETURNS INT
+    LANGUAGE sql IMMUTABLE
+    AS $$
+    SELECT CASE
+        WHEN p_currency IN ('CLP', 'ISK', 'JPY', 'KRW', 'PYG', 'UGX', 'VND') THEN 0
+        WHEN p_currency IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 3
+        ELSE 2
+    END;
+$$;
+
+-- The latest rate on or before p_date quoted directly, either way round.
+CREATE OR REPLACE FUNCTION account_income_service.fx_direct_rate(
+    p_from CHAR(3), p_to CHAR(3), p_date DATE) RETURNS NUMERIC
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT rate FROM (
+        SELECT rate, rate_date FROM account_income_service.exchange_rates
+        WHERE base_currency = p_from AND quote_currency = p_to AND rate_date <= p_date
+        UNION ALL
+        SELECT 1 / rate, rate_date FROM account_income_service.exchange_rates
+        WHERE base_currency = p_to AND quote_currency = p_from AND rate_date <= p_date
+    ) r
+    ORDER BY rate_date DESC
+    LIMIT 1;
+$$;
+
+-- Units of p_to one unit of p_from bought on p_date: quoted directly, or
+-- crossed through USD. NULL when no rate is known.
+CREATE OR REPLACE FUNCTION account_income_service.fx_rate(
+    p_from CHAR(3), p_to CHAR(3), p_date DATE) RETURNS NUMERIC
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT CASE
+        WHEN p_from = p_to THEN 1
+        ELSE COALESCE(
+            account_income_service.fx_direct_rate(p_from, p_to, p_date),
+            CASE WHEN p_from <> 'USD' AND p_to <> 'USD' THEN
+                account_income_service.fx_direct_rate(p_from, 'USD', p_date)
+                    * account_income_service.fx_direct_rate('USD', p_to, p_date)
+            END)
+    END;
+$$;
+
+-- fx_rate, raising SQLSTATE FX001 when no rate is known so callers can tell
+-- a missing rate from other failures.
+CREATE OR REPLACE FUNCTION account_income_service.fx_rate_required(
+    p_from CHAR(3), p_to CHAR(3), p_date DATE) RETURNS NUMERIC
+    LANGUAGE plpgsql STABLE
+    AS $$
+DECLARE
+    v_rate NUMERIC := account_income_service.fx_rate(p_from, p_to, p_date);
+BEGIN
+    IF v_rate IS NULL THEN
+        RAISE EXCEPTION 'no exchange rate from % to % on or before %', p_from, p_to, p_date
+            USING ERRCODE = 'FX001';
+    END IF;
+    RETURN v_rate;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION account_income_service.base_currency(p_user_id INT) RETURNS CHAR(3)
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT COALESCE((SELECT base_currency FROM user_service.users WHERE user_id = p_user_id), 'USD');
+$$;
+
+-- amount converted from p_from to p_to at p_date's rate, rounded to p_to's
+-- minor unit.
+CREATE OR REPLACE FUNCTION account_income_service.fx_convert(
+    p_amount NUMERIC, p_from CHAR(3), p_to CHAR(3), p_date DATE) RETURNS NUMERIC
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT ROUND(p_amount * account_income_service.fx_rate_required(p_from, p_to, p_date),
+        account_income_service.currency_exponent(p_to));
+$$;
+
+-- Records the rate an income or product converts to its user's base
+-- currency at, as of its date. A row keeps its rate until its currency or
+-- date changes or the rate is cleared; rerate_user clears them. With no rate
+-- known yet the row is still written, with none recorded: recorded_rate
+-- looks one up when it is read.
+CREATE OR REPLACE FUNCTION account_income_service.trigger_record_exchange_rate() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.currency := upper(COALESCE(NULLIF(btrim(NEW.currency), ''), 'USD'));
+    IF TG_OP = 'UPDATE' AND NEW.exchange_rate IS NOT NULL
+            AND NEW.currency = OLD.currency AND NEW.date_added IS NOT DISTINCT FROM OLD.date_added THEN
+        RETURN NEW;
+    END IF;
+    NEW.exchange_rate := account_income_service.fx_rate(NEW.currency,
+        account_income_service.base_currency(NEW.user_id), COALESCE(NEW.date_added::date, CURRENT_DATE));
+    RETURN NEW;
+END;
+$$;
+
+-- The rate an income or product converts to its user's base currency at:
+-- the one recorded on it, else the rate as of its date now known. NULL while
+-- there is still none, leaving the row out of base-currency totals.
+CREATE OR REPLACE FUNCTION account_income_service.recorded_rate(
+    p_rate NUMERIC, p_currency CHAR(3), p_user_id INT, p_date DATE) RETURNS NUMERIC
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT COALESCE(p_rate, account_income_service.fx_rate(p_currency,
+        account_income_service.base_currency(p_user_id), COALESCE(p_date, CURRENT_DATE)));
+$$;
+
+-- An income is paid into its account, so it is in the account's currency.
+CREATE OR REPLACE FUNCTION account_income_service.trigger_income_currency() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF NEW.account_id IS NOT NULL THEN
+        SELECT currency INTO NEW.currency FROM account_income_service.accounts WHERE account_id = NEW.account_id;
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS incomes_currency ON account_income_service.incomes;
+CREATE TRIGGER incomes_currency
+    BEFORE INSERT OR UPDATE ON account_income_service.incomes
+    FOR EACH ROW EXECUTE FUNCTION account_income_service.trigger_income_currency();
+
+DROP TRIGGER IF EXISTS incomes_exchange_rate ON account_income_service.incomes;
+CREATE TRIGGER incomes_exchange_rate
+    BEFORE INSERT OR UPDATE ON account_income_service.incomes
+    FOR EACH ROW EXECUTE FUNCTION account_income_service.trigger_record_exchange_rate();
+
+DROP TRIGGER IF EXISTS products_exchange_rate ON product_category_service.products;
+CREATE TRIGGER products_exchange_rate
+    BEFORE INSERT OR UPDATE ON product_category_service.products
+    FOR EACH ROW EXECUTE FUNCTION account_income_service.trigger_record_exchange_rate();
+
+-- Re-records the rates of the user's incomes and products, after their base
+-- currency changes. Rows with no rate to the new base are left without one.
+CREATE OR REPLACE FUNCTION account_income_service.rerate_user(p_user_id INT) RETURNS VOID
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    UPDATE account_income_service.incomes SET exchange_rate = NULL WHERE user_id = p_user_id;
+    UPDATE product_category_service.products SET exchange_rate = NULL WHERE user_id = p_user_id;
+END;
+$$;
+
+-- The account spending without a named balance account is drawn from: the
+-- user's cash account, as get_user_balances has it, with its currency, or
+-- equity "unfunded", which takes whatever currency is spent.
+CREATE OR REPLACE FUNCTION account_income_service.journal_funding_account(p_user_id INT) RETURNS JSONB
+    LANGUAGE sql
+    AS $$
+    SELECT COALESCE(
+        (SELECT jsonb_build_object('type', 'asset', 'code', account_id::text, 'currency', currency)
+         FROM account_income_service.accounts
+         WHERE user_id = p_user_id AND lower(balance_source) = 'cash'
+         ORDER BY account_id
+         LIMIT 1),
+        jsonb_build_object('type', 'equity', 'code', 'unfunded'));
+$$;
+
+-- The postings that take p_amount in p_currency out of p_from into p_to.
+-- When p_from holds another currency the amount passes through equity "fx":
+-- p_from gives up the amount converted at p_date's rate, and "fx" takes the
+-- difference so each currency balances.
+CREATE OR REPLACE FUNCTION account_income_service.journal_exchange(
+    p_from JSONB, p_to JSONB, p_amount NUMERIC, p_currency CHAR(3), p_date DATE) RETURNS JSONB
+    LANGUAGE plpgsql STABLE
+    AS $$
+DECLARE
+    v_from_currency CHAR(3) := COALESCE(p_from->>'currency', p_currency);
+    v_converted NUMERIC;
+BEGIN
+    IF v_from_currency = p_currency THEN
+        RETURN jsonb_build_array(
+            jsonb_build_object('type', p_from->>'type', 'code', p_from->>'code', 'amount', -p_amount, 'currency', p_currency),
+            jsonb_build_object('type', p_to->>'type', 'code', p_to->>'code', 'amount', p_amount, 'currency', p_currency));
+    END IF;
+    v_converted := account_income_service.fx_convert(p_amount, p_currency, v_from_currency, p_date);
+    RETURN jsonb_build_array(
+        jsonb_build_object('type', p_from->>'type', 'code', p_from->>'code', 'amount', -v_converted, 'currency', v_from_currency),
+        jsonb_build_object('type', 'equity', 'code', 'fx', 'amount', v_converted, 'currency', v_from_currency),
+        jsonb_build_object('type', 'equity', 'code', 'fx', 'amount', -p_amount, 'currency', p_currency),
+        jsonb_build_object('type', p_to->>'type', 'code', p_to->>'code', 'amount', p_amount, 'currency', p_currency));
+END;
+$$;
+
+-- Everything recorded before currencies were is in USD, every user's base.
+-- Rows since left without a rate are in another currency and stay so.
+UPDATE account_income_service.incomes SET exchange_rate = 1
+WHERE exchange_rate IS NULL AND currency = account_income_service.base_currency(user_id);
+UPDATE transfer_service.transfers SET target_amount = amount, exchange_rate = 1 WHERE target_amount IS NULL;
+
+-- Account types. Credit cards and loans are liabilities: their balance is
+-- negated what is owed, so spending on a card takes it below zero and a
+-- payment towards it, a transfer in, brings it back up. Liabilities have no
+-- income row; what was owed when they were added is an opening entry.
+-- credit_limit and statement_day apply to credit cards; interest_rate, an
+-- annual percentage, to cards, loans and savings.
+ALTER TABLE account_income_service.accounts
+    ADD COLUMN IF NOT EXISTS account_type VARCHAR(20) NOT NULL DEFAULT 'cash',
+    ADD COLUMN IF NOT EXISTS credit_limit NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS statement_day SMALLINT,
+    ADD COLUMN IF NOT EXISTS interest_rate NUMERIC(7, 4);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'accounts_account_type_check') THEN
+        ALTER TABLE account_income_service.accounts
+            ADD CONSTRAINT accounts_account_type_check
+                CHECK (account_type IN ('cash', 'checking', 'savings', 'credit_card', 'loan', 'investment')),
+            ADD CONSTRAINT accounts_credit_limit_check CHECK (credit_limit >= 0),
+            ADD CONSTRAINT accounts_statement_day_check CHECK (statement_day BETWEEN 1 AND 31),
+            ADD CONSTRAINT accounts_interest_rate_check CHECK (interest_rate >= 0);
+    END IF;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION account_income_service.is_liability(p_account_type VARCHAR) RETURNS BOOLEAN
+    LANGUAGE sql IMMUTABLE
+    AS $$
+    SELECT p_account_type IN ('credit_card', 'loan');
+$$;
+
+-- Net worth history is computed from running totals of the journal; see
+-- GetNetWorth.
+DROP FUNCTION IF EXISTS account_income_service.balances_at(INT, DATE);
+
+-- Products can name the balance account that paid for them, as those
+-- imported from an account's statement do; they are posted from it rather
+-- than the funding account. A product whose account is gone falls back to
+-- the funding account.
+CREATE OR REPLACE FUNCTION account_income_service.journal_product_account(p_user_id INT, p_account_id INT) RETURNS JSONB
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT COALESCE(
+        (SELECT jsonb_build_object('type', 'asset', 'code', account_id::text, 'currency', currency)
+         FROM account_income_service.accounts
+         WHERE account_id = p_account_id AND user_id = p_user_id),
+        account_income_service.journal_funding_account(p_user_id));
+$$;
+
+-- Restates the expense a product records: its line total from the account
+-- that paid for it to its category, converted where the account holds
+-- another currency. The product service's products_journal trigger calls it
+-- on every write to a product. Earlier versions took fewer arguments.
+DROP FUNCTION IF EXISTS account_income_service.journal_product(INT, INT, TEXT, INT, NUMERIC, TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS account_income_service.journal_product(INT, INT, TEXT, INT, NUMERIC, CHAR, TIMESTAMPTZ);
+CREATE OR REPLACE FUNCTION account_income_service.journal_product(
+    p_user_id INT, p_product_id INT, p_name TEXT, p_category_id INT, p_total NUMERIC,
+    p_currency CHAR(3), p_date TIMESTAMPTZ, p_account_id INT) RETURNS BIGINT
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    v_postings JSONB := '[]'::jsonb;
+BEGIN
+    IF COALESCE(p_total, 0) <> 0 THEN
+        v_postings := account_income_service.journal_exchange(
+            account_income_service.journal_product_account(p_user_id, p_account_id),
+            jsonb_build_object('type', 'expense', 'code', COALESCE(p_category_id::text, 'uncategorized')),
+            p_total, p_currency, p_date::date);
+    END IF;
+    RETURN account_income_service.restate_journal(p_user_id, 'expense', 'product', p_product_id::text,
+        p_name, p_date, v_postings);
+END;
+$$;
+
+-- Backfill, once: the expenses, refunds, incomes and transfers recorded so far, then
+-- an opening entry per balance account that makes its journal balance equal
+-- the balance get_user_balances reported before the journal existed. It
+-- comes last, once every function it calls is defined, and reads the
+-- product service's tables, whose migrations are applied before these. Rows
+-- from before the journal predate currencies, so are all in USD.
+DO $$
+DECLARE
+    r RECORD;
+    b RECORD;
+    v_journal NUMERIC;
+BEGIN
+    IF EXISTS (SELECT 1 FROM account_income_service.journal_entries) THEN
+        RETURN;
+    END IF;
+
+    FOR r IN SELECT * FROM product_category_service.products ORDER BY product_id LOOP
+        PERFORM account_income_service.journal_product(r.user_id, r.product_id, r.product_name,
+            r.category_id, ROUND(COALESCE(r.quantity, 0) * r.price, 2), r.currency,
+            COALESCE(r.date_added, CURRENT_TIMESTAMP), r.account_id);
+    END LOOP;
+
+    FOR r IN
+        SELECT rf.refund_id, rf.user_id, rf.reason, rf.refund_date, rf.amount,
+            COALESCE((SELECT jsonb_build_object('type', 'asset', 'code', i.account_id::text)
+                      FROM account_income_service.incomes i
+                      WHERE i.income_id = rf.balance_id AND i.account_id IS NOT NULL),
+                     account_income_service.journal_funding_account(rf.user_id)) AS funding,
+            (SELECT jsonb_agg(jsonb_build_object('type', 'expense',
+                        'code', COALESCE(ri.category_id::text, p.category_id::text, 'uncategorized'), 'amount', -ri.amount))
+             FROM product_category_service.refund_items ri
+             JOIN product_category_service.products p ON p.product_id = ri.product_id
+             WHERE ri.refund_id = rf.refund_id) AS items
+        FROM product_category_service.refunds rf
+        ORDER BY rf.refund_id
+    LOOP
+        PERFORM account_income_service.restate_journal(r.user_id, 'refund', 'refund', r.refund_id::text,
+            r.reason, r.refund_date, COALESCE(r.items, '[]'::jsonb)
+                || jsonb_build_array(r.funding || jsonb_build_object('amount', r.amount)));
+    END LOOP;
+
+    FOR r IN SELECT * FROM account_income_service.incomes WHERE account_id IS NOT NULL ORDER BY income_id LOOP
+        PERFORM account_income_service.restate_journal(r.user_id, 'income', 'income', r.income_id::text,
+            r.description, COALESCE(r.date_added, CURRENT_TIMESTAMP), jsonb_build_array(
+                jsonb_build_object('type', 'asset', 'code', r.account_id::text, 'amount', r.amount),
+                jsonb_build_object('type', 'income', 'code', 'income', 'amount', -r.amount)));
+    END LOOP;
+
+    FOR r IN SELECT * FROM transfer_service.transfers ORDER BY transfer_id LOOP
+        PERFORM account_income_service.restate_journal(r.user_id, 'transfer', 'transfer', r.transfer_id::text,
+            r.description, COALESCE(r.date_added, CURRENT_TIMESTAMP), jsonb_build_array(
+                jsonb_build_object('type', 'asset', 'code', r.source_account_id::text, 'amount', -r.amount),
+                jsonb_build_object('type', 'asset', 'code', r.target_account_id::text, 'amount', r.amount)));
+    END LOOP;
+
+    FOR r IN SELECT DISTINCT user_id FROM account_income_service.accounts LOOP
+        FOR b IN SELECT * FROM account_income_service.get_user_balances(r.user_id) LOOP
+            SELECT COALESCE(SUM(lb.balance), 0) INTO v_journal
+            FROM account_income_service.ledger_balances(r.user_id) lb
+            WHERE lb.account_type = 'asset' AND lb.code = b.balance_id::text;
+            PERFORM account_income_service.post_journal_entry(r.user_id, 'opening', 'account', b.balance_id::text,
+                'Opening balance', CURRENT_TIMESTAMP, jsonb_build_array(
+                    jsonb_build_object('type', 'asset', 'code', b.balance_id::text, 'amount', b.balance_amount - v_journal),
+                    jsonb_build_object('type', 'equity', 'code', 'opening', 'amount', v_journal - b.balance_amount)));
+        END LOOP;
+    END LOOP;
+END;
+$$;
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+)
+
+// ErrInvalidPeriod is returned for net worth history that cannot be
+// reported.
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// Spacing of net worth history points accepted by GetNetWorth.
+var netWorthIntervals = map[string]string{
+	"day":   "1 day",
+	"week":  "1 week",
+	"month": "1 month",
+}
+
+// maxNetWorthPoints bounds the history one request may ask for.
+const maxNetWorthPoints = 400
+
+// NetWorthPoint is the user's net worth at the end of Date. Liabilities is
+// what credit cards and loans owe, as a positive amount; NetWorth is Assets
+// less Liabilities. Balances with no rate to the base currency on Date are
+// left out of the totals and listed in Unrated.
+type NetWorthPoint struct {
+	Date        time.Time
+	Assets      float64
+	Liabilities float64
+	NetWorth    float64
+	Unrated     []int32
+}
+
+// NetWorth is the user's net worth now, with the balances it is made of
+// and its history. Every total is in Currency, the user's base currency.
+type NetWorth struct {
+	Currency string
+	NetWorthPoint
+	Accounts []BalanceResult
+	History  []NetWorthPoint
+}
+
+// netWorthAt returns the user's net worth as of the end of each date from
+// from to to, interval apart, and at to itself. Each account's balance is a
+// running total of its postings by day, which holds from the day of its
+// last change until the next; it is converted to the base currency at each
+// date's rate.
+func netWorthAt(ctx context.Context, userID int32, from, to time.Time, interval string) ([]NetWorthPoint, error) {
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        WITH days AS (
+            SELECT generate_series($2::date, $3::date, $4::interval)::date AS day
+            UNION
+            SELECT $3::date
+        ), running AS (
+            SELECT a.account_id, a.account_type, a.currency, je.occurred_at::date AS day,
+                LEAD(je.occurred_at::date) OVER w AS until,
+                SUM(SUM(jp.amount)) OVER w AS balance
+            FROM account_income_service.accounts a
+            JOIN account_income_service.ledger_accounts la
+                ON la.user_id = a.user_id AND la.account_type = 'asset' AND la.code = a.account_id::text
+            JOIN account_income_service.journal_postings jp
+                ON jp.ledger_account_id = la.ledger_account_id AND jp.currency = a.currency
+            JOIN account_income_service.journal_entries je ON je.entry_id = jp.entry_id
+            WHERE a.user_id = $1 AND je.occurred_at < ($3::date + 1)::timestamptz
+            GROUP BY a.account_id, a.account_type, a.currency, je.occurred_at::date
+            WINDOW w AS (PARTITION BY a.account_id ORDER BY je.occurred_at::date)
+        ), balances AS (
+            SELECT d.day, r.account_id, r.account_type, r.balance,
+                CASE WHEN r.balance = 0 THEN 0
+                    ELSE r.balance * account_income_service.fx_rate(r.currency,
+                        account_income_service.base_currency($1), d.day) END AS base_balance
+            FROM days d
+            JOIN running r ON r.day <= d.day AND (r.until IS NULL OR r.until > d.day)
+        )
+        SELECT d.day,
+            COALESCE(SUM(b.base_balance) FILTER (WHERE NOT account_income_service.is_liability(b.account_type)), 0)::float8,
+            COALESCE(-SUM(b.base_balance) FILTER (WHERE account_income_service.is_liability(b.account_type)), 0)::float8,
+            COALESCE(array_agg(b.account_id ORDER BY b.account_id)
+                FILTER (WHERE b.balance <> 0 AND b.base_balance IS NULL), '{}')
+        FROM days d
+        LEFT JOIN balances b ON b.day = d.day
+        GROUP BY d.day
+        ORDER BY d.day`,
+		userID, from, to, interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query net worth: %w", err)
+	}
+	defer rows.Close()
+
+	var points []NetWorthPoint
+	for rows.Next() {
+		var p NetWorthPoint
+		if err := rows.Scan(&p.Date, &p.Assets, &p.Liabilities, &p.Unrated); err != nil {
+			return nil, fmt.Errorf("failed to scan net worth: %v", err)
+		}
+		p.NetWorth = p.Assets - p.Liabilities
+		points = append(points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating net worth: %w", err)
+	}
+	return points, nil
+}
+
+// GetNetWorth returns the user's net worth today and its history from from
+// to to, one point per interval ("day", "week" or "month"). Balances with
+// no rate to the base currency are left out of a point's totals and listed
+// in its Unrated.
+func GetNetWorth(ctx context.Context, userID string, from, to time.Time, interval string) (*NetWorth, error) {
+	userIDInt, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %v", err)
+	}
+	step, ok := netWorthIntervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("%w: interval must be day, week or month", ErrInvalidPeriod)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: the history ends before it starts", ErrInvalidPeriod)
+	}
+	points := int(to.Sub(from).Hours()/24) + 1
+	switch interval {
+	case "week":
+		points = points/7 + 1
+	case "month":
+		points = points/28 + 1
+	}
+	if points > maxNetWorthPoints {
+		return nil, fmt.Errorf("%w: at most %d points of history can be asked for", ErrInvalidPeriod, maxNetWorthPoints)
+	}
+
+	accounts, err := GetUserBalances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now()
+	current, err := netWorthAt(ctx, int32(userIDInt), today, today, step)
+	if err != nil {
+		return nil, err
+	}
+	history, err := netWorthAt(ctx, int32(userIDInt), from, to, step)
+	if err != nil {
+		return nil, err
+	}
+
+	worth := &NetWorth{Accounts: accounts, History: history}
+	if len(current) > 0 {
+		worth.NetWorthPoint = current[0]
+	}
+	err = sharedDB.GetDB().QueryRow(ctx,
+		"SELECT account_income_service.base_currency($1)", int32(userIDInt)).Scan(&worth.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base currency: %v", err)
+	}
+	return worth, nil
+}
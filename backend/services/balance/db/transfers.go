@@ -0,0 +1,340 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/fx"
+	"github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrInvalidTransfer     = errors.New("invalid transfer")
+	ErrAccountNotFound     = errors.New("balance account not found")
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrIdempotencyConflict = errors.New("idempotency key already used for a different transfer")
+)
+
+// maxTransferAmount is the largest amount the transfers table can hold.
+const maxTransferAmount = 99999999.99
+
+// TransferRequest asks to move Amount from one of the user's balance
+// accounts to another.
+type TransferRequest struct {
+	FromBalanceID int32
+	ToBalanceID   int32
+	// Amount is in the source account's currency; it must name no other.
+	Amount      money.Amount
+	Description string
+	// IdempotencyKey, when set, makes retries of the same request return
+	// the transfer it first made.
+	IdempotencyKey string
+	// AllowOverdraft lets the source account go below zero, or a credit
+	// card past its limit.
+	AllowOverdraft bool
+}
+
+// TransferRecord is a completed transfer with the balances it left behind,
+// FromBalance in FromCurrency and ToBalance in ToCurrency.
+type TransferRecord struct {
+	TransferResult
+	FromBalanceID int32
+	ToBalanceID   int32
+	Description   string
+	FromBalance   float64
+	ToBalance     float64
+	// Replayed is set when an earlier request with the same idempotency key
+	// made the transfer.
+	Replayed bool
+}
+
+// lockAccounts locks two accounts, checking both are the user's. They are
+// locked in ID order so concurrent transfers between the same pair cannot
+// deadlock.
+func lockAccounts(ctx context.Context, tx pgx.Tx, userID int32, from, to int32) error {
+	rows, err := tx.Query(ctx, `
+        SELECT account_id
+        FROM account_income_service.accounts
+        WHERE user_id = $1 AND account_id IN ($2, $3)
+        ORDER BY account_id
+        FOR UPDATE`,
+		userID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to lock accounts: %v", err)
+	}
+	defer rows.Close()
+
+	owned := map[int32]bool{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("failed to scan account: %v", err)
+		}
+		owned[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating accounts: %v", err)
+	}
+	for _, id := range []int32{from, to} {
+		if !owned[id] {
+			return fmt.Errorf("%w: %d", ErrAccountNotFound, id)
+		}
+	}
+	return nil
+}
+
+// getTransfer loads one of the user's transfers.
+func getTransfer(ctx context.Context, tx pgx.Tx, userID int32, transferID int32) (*TransferRecord, error) {
+	var record TransferRecord
+	var description *string
+	err := tx.QueryRow(ctx, `
+        SELECT t.transfer_id, t.user_id, sa.balance_source, ta.balance_source, t.amount::float8,
+            t.date_added, t.source_account_id, t.target_account_id, t.description,
+            sa.currency, ta.currency, COALESCE(t.target_amount, t.amount)::float8,
+            COALESCE(t.exchange_rate, 1)::float8, account_income_service.is_liability(ta.account_type)
+        FROM transfer_service.transfers t
+        JOIN account_income_service.accounts sa ON sa.account_id = t.source_account_id
+        JOIN account_income_service.accounts ta ON ta.account_id = t.target_account_id
+        WHERE t.user_id = $1 AND t.transfer_id = $2`,
+		userID, transferID).Scan(&record.TransferID, &record.UserID, &record.FromSource, &record.ToSource,
+		&record.Amount, &record.Date, &record.FromBalanceID, &record.ToBalanceID, &description,
+		&record.FromCurrency, &record.ToCurrency, &record.TargetAmount, &record.ExchangeRate, &record.Payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfer: %v", err)
+	}
+	if description != nil {
+		record.Description = *description
+	}
+	return &record, nil
+}
+
+// sourceCurrency returns the currency one of the user's accounts holds, for
+// the amount taken out of it.
+func sourceCurrency(ctx context.Context, tx pgx.Tx, userID int32, accountID int32) (string, error) {
+	var currency string
+	err := tx.QueryRow(ctx, `
+        SELECT currency FROM account_income_service.accounts
+        WHERE account_id = $1 AND user_id = $2`,
+		accountID, userID).Scan(&currency)
+	if err == pgx.ErrNoRows {
+		return "", fmt.Errorf("%w: %d", ErrAccountNotFound, accountID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read account currency: %v", err)
+	}
+	return currency, nil
+}
+
+// claimIdempotencyKey records the key for this request, for amount, the
+// request's amount in the source account's currency. If an earlier request
+// holds it, the earlier transfer's ID is returned instead; a request still
+// in flight with the key is waited for.
+func claimIdempotencyKey(ctx context.Context, tx pgx.Tx, userID int32, req TransferRequest, amount money.Money) (int32, error) {
+	tag, err := tx.Exec(ctx, `
+        INSERT INTO transfer_service.transfer_requests
+            (user_id, idempotency_key, source_account_id, target_account_id, amount)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id, idempotency_key) DO NOTHING`,
+		userID, req.IdempotencyKey, req.FromBalanceID, req.ToBalanceID, amount.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to record idempotency key: %v", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return 0, nil
+	}
+
+	var from, to int32
+	var claimed float64
+	var transferID *int32
+	err = tx.QueryRow(ctx, `
+        SELECT source_account_id, target_account_id, amount::float8, transfer_id
+        FROM transfer_service.transfer_requests
+        WHERE user_id = $1 AND idempotency_key = $2`,
+		userID, req.IdempotencyKey).Scan(&from, &to, &claimed, &transferID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up idempotency key: %v", err)
+	}
+	if from != req.FromBalanceID || to != req.ToBalanceID || money.FromFloat(claimed, amount.Currency).Minor != amount.Minor || transferID == nil {
+		return 0, ErrIdempotencyConflict
+	}
+	return *transferID, nil
+}
+
+// checkFunds reports whether an account of accountType holding balance, in
+// amount's currency, can pay amount out. Credit cards can also spend what is
+// left of their limit.
+func checkFunds(accountType string, balance float64, creditLimit *float64, amount money.Money) error {
+	available := balance
+	if accountType == AccountCreditCard && creditLimit != nil {
+		available += *creditLimit
+	}
+	if money.FromFloat(available, amount.Currency).Minor >= amount.Minor {
+		return nil
+	}
+	if accountType == AccountCreditCard {
+		return fmt.Errorf("%w: the card has %.2f of credit left", ErrInsufficientFunds, available)
+	}
+	return fmt.Errorf("%w: the account holds %.2f", ErrInsufficientFunds, available)
+}
+
+// transferKind returns whether a transfer into an account of toType is a
+// transfer or a payment towards what is owed, and its description, which
+// defaults to one naming the kind.
+func transferKind(toType, description string) (string, string) {
+	kind := "transfer"
+	if IsLiability(toType) {
+		kind = "payment"
+	}
+	if description == "" {
+		switch toType {
+		case AccountCreditCard:
+			description = "Credit card payment"
+		case AccountLoan:
+			description = "Loan payment"
+		default:
+			description = "Internal transfer"
+		}
+	}
+	return kind, description
+}
+
+// InternalTransfer moves money between two of the user's balance accounts.
+// The transfer and its journal entry are written in one transaction, and
+// the source must hold the amount, or a credit card have the credit left,
+// unless the request allows an overdraft. Amount is rounded to the source
+// account's currency; a target holding another currency is credited the
+// amount converted at today's rate. A transfer to a credit card or loan is
+// a payment towards what it owes.
+func InternalTransfer(ctx context.Context, userID string, req TransferRequest) (*TransferRecord, error) {
+	userIDInt, err := strconv.ParseInt(userID, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %v", err)
+	}
+	if req.FromBalanceID == req.ToBalanceID {
+		return nil, fmt.Errorf("%w: the accounts must differ", ErrInvalidTransfer)
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	fromCurrency, err := sourceCurrency(ctx, tx, int32(userIDInt), req.FromBalanceID)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := inAccountCurrency(req.Amount, fromCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Minor <= 0 || amount.Float64() > maxTransferAmount {
+		return nil, fmt.Errorf("%w: amount must be more than 0 and at most %.2f", ErrInvalidTransfer, maxTransferAmount)
+	}
+
+	if req.IdempotencyKey != "" {
+		transferID, err := claimIdempotencyKey(ctx, tx, int32(userIDInt), req, amount)
+		if err != nil {
+			return nil, err
+		}
+		if transferID != 0 {
+			record, err := getTransfer(ctx, tx, int32(userIDInt), transferID)
+			if err != nil {
+				return nil, err
+			}
+			balances, err := ledger.Balances(ctx, tx, int32(userIDInt))
+			if err != nil {
+				return nil, err
+			}
+			record.FromBalance, record.ToBalance = balances[record.FromBalanceID], balances[record.ToBalanceID]
+			record.Replayed = true
+			return record, nil
+		}
+	}
+
+	if err := lockAccounts(ctx, tx, int32(userIDInt), req.FromBalanceID, req.ToBalanceID); err != nil {
+		return nil, err
+	}
+	toCurrency, err := ledger.AccountCurrency(ctx, tx, int32(userIDInt), req.ToBalanceID)
+	if err != nil {
+		return nil, err
+	}
+	target, rate, err := fx.Convert(ctx, tx, amount, toCurrency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var fromType, toType string
+	var creditLimit *float64
+	err = tx.QueryRow(ctx, `
+        SELECT sa.account_type, sa.credit_limit::float8, ta.account_type
+        FROM account_income_service.accounts sa, account_income_service.accounts ta
+        WHERE sa.account_id = $1 AND ta.account_id = $2`,
+		req.FromBalanceID, req.ToBalanceID).Scan(&fromType, &creditLimit, &toType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account types: %v", err)
+	}
+
+	balances, err := ledger.Balances(ctx, tx, int32(userIDInt))
+	if err != nil {
+		return nil, err
+	}
+	if !req.AllowOverdraft {
+		if err := checkFunds(fromType, balances[req.FromBalanceID], creditLimit, amount); err != nil {
+			return nil, err
+		}
+	}
+
+	kind, description := transferKind(toType, req.Description)
+	var transferID int32
+	err = tx.QueryRow(ctx, `
+        INSERT INTO transfer_service.transfers
+            (user_id, amount, description, source_account_id, target_account_id, target_amount, exchange_rate)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING transfer_id`,
+		int32(userIDInt), amount.String(), description, req.FromBalanceID, req.ToBalanceID,
+		target.String(), rate).Scan(&transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %v", err)
+	}
+	if req.IdempotencyKey != "" {
+		_, err = tx.Exec(ctx, `
+            UPDATE transfer_service.transfer_requests SET transfer_id = $3
+            WHERE user_id = $1 AND idempotency_key = $2`,
+			int32(userIDInt), req.IdempotencyKey, transferID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record idempotency key: %v", err)
+		}
+	}
+
+	record, err := getTransfer(ctx, tx, int32(userIDInt), transferID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = ledger.Restate(ctx, tx, ledger.Entry{
+		UserID:        int32(userIDInt),
+		Kind:          kind,
+		ReferenceType: "transfer",
+		ReferenceID:   strconv.Itoa(int(transferID)),
+		Description:   description,
+		OccurredAt:    record.Date,
+		Postings:      ledger.Exchange(ledger.BalanceAccount(req.FromBalanceID), amount, ledger.BalanceAccount(req.ToBalanceID), target),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if balances, err = ledger.Balances(ctx, tx, int32(userIDInt)); err != nil {
+		return nil, err
+	}
+	record.FromBalance, record.ToBalance = balances[record.FromBalanceID], balances[record.ToBalanceID]
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer: %v", err)
+	}
+	return record, nil
+}
@@ -0,0 +1,51 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/grpc_money"
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+func TestTransferRetriesMatchInMinorUnits(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	from, _ := newCashAccount(t, ctx, userID, 100)
+	to, _ := newCashAccount(t, ctx, userID, 0)
+	key := fmt.Sprintf("retry-%d", time.Now().UnixNano())
+
+	transfer := func(amount money.Amount) (*balanceDB.TransferRecord, error) {
+		return balanceDB.InternalTransfer(ctx, userID, balanceDB.TransferRequest{
+			FromBalanceID: from, ToBalanceID: to, Amount: amount, IdempotencyKey: key})
+	}
+	first, err := transfer(requested(10.005))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The retry names the amount the first request was rounded to, exactly.
+	exact, err := money.Requested(0, &grpc_money.Money{Value: "10.01", Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, err := transfer(exact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !retry.Replayed || retry.TransferID != first.TransferID {
+		t.Errorf("retry made transfer %d (replayed %v), want transfer %d replayed", retry.TransferID, retry.Replayed, first.TransferID)
+	}
+	if got := balanceOf(t, ctx, userID, from); got != 89.99 {
+		t.Errorf("source balance = %.2f, want 89.99", got)
+	}
+
+	if _, err := transfer(requested(10.02)); !errors.Is(err, balanceDB.ErrIdempotencyConflict) {
+		t.Errorf("a retry for another amount = %v, want ErrIdempotencyConflict", err)
+	}
+}
@@ -15,7 +15,9 @@ import (
 	grpcMiddleware "github.com/Aneesh-Hegde/expenseManager/middleware"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
 	balanceHandler "github.com/Aneesh-Hegde/expenseManager/services/balance/balance"
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/fx"
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -81,6 +83,10 @@ func (s *BalanceService) UpdateBalance(ctx context.Context, req *balance.UpdateB
 	return balanceHandler.UpdateBalance(ctx, req)
 }
 
+func (s *BalanceService) InternalTransfer(ctx context.Context, req *balance.TransferFunds) (*balance.TransferFundsResponse, error) {
+	return balanceHandler.InternalTransfer(ctx, req)
+}
+
 func (s *BalanceService) GetTransfer(ctx context.Context, req *balance.GetTransferRequest) (*balance.GetTransferResponse, error) {
 	return balanceHandler.GetTransfer(ctx, req)
 }
@@ -97,6 +103,22 @@ func (s *BalanceService) UpdateIncome(ctx context.Context, req *balance.UpdateIn
 	return balanceHandler.UpdateIncome(ctx, req)
 }
 
+func (s *BalanceService) CheckLedger(ctx context.Context, req *balance.CheckLedgerRequest) (*balance.CheckLedgerResponse, error) {
+	return balanceHandler.CheckLedger(ctx, req)
+}
+
+func (s *BalanceService) GetExchangeRate(ctx context.Context, req *balance.GetExchangeRateRequest) (*balance.ExchangeRate, error) {
+	return balanceHandler.GetExchangeRate(ctx, req)
+}
+
+func (s *BalanceService) UpdateAccount(ctx context.Context, req *balance.UpdateAccountRequest) (*balance.UpdateAccountResponse, error) {
+	return balanceHandler.UpdateAccount(ctx, req)
+}
+
+func (s *BalanceService) GetNetWorth(ctx context.Context, req *balance.GetNetWorthRequest) (*balance.NetWorthResponse, error) {
+	return balanceHandler.GetNetWorth(ctx, req)
+}
+
 func authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	fmt.Println(info.FullMethod)
 	newCtx, err := grpcMiddleware.AuthInterceptor(ctx)
@@ -166,6 +188,71 @@ func startDBHealthMonitor() {
 	}()
 }
 
+// snapshotInterval is how often ledger snapshots are taken.
+const snapshotInterval = time.Hour
+
+// Periodically snapshots ledger balances so reading them need only sum the
+// postings made since
+func startSnapshotScheduler() {
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		taken, err := balanceDB.TakeLedgerSnapshots(ctx)
+		if err != nil {
+			log.Printf("Taking ledger snapshots failed: %v", err)
+			return
+		}
+		if taken > 0 {
+			log.Printf("Took %d ledger snapshots", taken)
+		}
+	}
+
+	ticker := time.NewTicker(snapshotInterval)
+	go func() {
+		defer ticker.Stop()
+		run()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
+
+// rateRefreshInterval is how often exchange rates are reloaded from the
+// configured providers.
+const rateRefreshInterval = 6 * time.Hour
+
+// Periodically loads exchange rates from the providers EXCHANGE_RATES_FILE
+// and EXCHANGE_RATES_URL configure. Without either, rates are only what is
+// already stored.
+func startRateScheduler() {
+	providers := fx.ProvidersFromEnv()
+	if len(providers) == 0 {
+		log.Println("No exchange rate provider configured")
+		return
+	}
+	run := func() {
+		for _, provider := range providers {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			stored, err := fx.Refresh(ctx, provider)
+			cancel()
+			if err != nil {
+				log.Printf("Refreshing exchange rates from %s failed: %v", provider.Name(), err)
+				continue
+			}
+			log.Printf("Stored %d exchange rates from %s", stored, provider.Name())
+		}
+	}
+
+	ticker := time.NewTicker(rateRefreshInterval)
+	go func() {
+		defer ticker.Stop()
+		run()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
+
 func startMetricServer() {
 
 	http.Handle("/metrics", promhttp.Handler())
@@ -226,6 +313,8 @@ func main() {
 
 	// Start background health monitoring
 	startDBHealthMonitor()
+	startSnapshotScheduler()
+	startRateScheduler()
 
 	listener, err := net.Listen("tcp", ":50055")
 	if err != nil {
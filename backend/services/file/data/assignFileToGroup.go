@@ -0,0 +1,57 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	files "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	"github.com/Aneesh-Hegde/expenseManager/redis"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AssignFileToGroup shares a receipt the caller paid for with one of their
+// groups, or takes it out of its group when group_id is 0.
+func AssignFileToGroup(ctx context.Context, req *files.AssignFileToGroupRequest) (*files.FileActionResponse, error) {
+	userId, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expense, err := fileDB.AssignFileToGroup(ctx, strconv.Itoa(userId), req.GetFileId(), req.GetGroupId(), req.GetSharedBy())
+	switch {
+	case errors.Is(err, fileDB.ErrFileNotFound), errors.Is(err, fileDB.ErrGroupNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, fileDB.ErrInvalidShare):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, fileDB.ErrReceiptEmpty):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	file, err := fileDB.GetFileByID(ctx, strconv.Itoa(userId), req.GetFileId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Cached products carry the old splits.
+	if err := redis.DeleteCachedProductData(userId, file.FileName); err != nil {
+		log.Printf("Warning: could not clear cache for %s: %v", file.FileName, err)
+	}
+
+	message := fmt.Sprintf("Removed %s from its group", file.FileName)
+	if expense != nil {
+		message = fmt.Sprintf("Shared %s (%.2f) between %d members", file.FileName,
+			float64(expense.Amount)/100, len(expense.Shares))
+	}
+	return &files.FileActionResponse{
+		Success: true,
+		Message: message,
+		File:    toFileMessage(*file),
+	}, nil
+}
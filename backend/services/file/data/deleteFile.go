@@ -0,0 +1,68 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	files "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	"github.com/Aneesh-Hegde/expenseManager/redis"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeleteFile removes a receipt, the products extracted from it, its cached
+// extraction and the stored object.
+func DeleteFile(ctx context.Context, req *files.DeleteFileRequest) (*files.FileActionResponse, error) {
+	userId, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fileDB.GetFileByID(ctx, strconv.Itoa(userId), req.GetFileId())
+	if errors.Is(err, fileDB.ErrFileNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deletedProducts, err := fileDB.DeleteFile(ctx, strconv.Itoa(userId), file.FileID)
+	if errors.Is(err, fileDB.ErrFileNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, fileDB.ErrFileRefunded) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := redis.DeleteCachedProductData(userId, file.FileName); err != nil {
+		log.Printf("Warning: could not clear cache for %s: %v", file.FileName, err)
+	}
+
+	// The row is gone at this point, so a failed removal only leaves an orphan
+	// for the scheduled CleanupAllOrphanedFiles to remove.
+	for _, key := range []*string{file.ObjectKey, file.ThumbnailKey, file.PreviewKey} {
+		if key == nil || *key == "" {
+			continue
+		}
+		if err := removeObject(ctx, *key); err != nil {
+			log.Printf("Warning: could not remove object %s: %v", *key, err)
+		}
+	}
+
+	log.Printf("Deleted file %d (%s) for user %d with %d products", file.FileID, file.FileName, userId, deletedProducts)
+	return &files.FileActionResponse{
+		Success: true,
+		Message: fmt.Sprintf("Deleted %s and %d products", file.FileName, deletedProducts),
+		File: &files.File{
+			Filename: file.FileName,
+			FileId:   file.FileID,
+		},
+	}, nil
+}
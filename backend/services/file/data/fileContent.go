@@ -0,0 +1,86 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+
+	files "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/encryption"
+	"github.com/minio/minio-go/v7"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetFileContent returns the decrypted bytes of one of the caller's files. It
+// backs the gateway's /files/:id/content route, which is how clients load
+// images when objects are encrypted at rest.
+func GetFileContent(ctx context.Context, req *files.GetFileContentRequest) (*files.FileContent, error) {
+	userId, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fileDB.GetFileByID(ctx, strconv.Itoa(userId), req.GetFileId())
+	if errors.Is(err, fileDB.ErrFileNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var key *string
+	switch req.GetVariant() {
+	case "":
+		key = file.ObjectKey
+	case "thumb":
+		key = file.ThumbnailKey
+	case "preview":
+		key = file.PreviewKey
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown variant %q", req.GetVariant())
+	}
+	// Files without variants fall back to the full image, as listings do.
+	if key == nil || *key == "" {
+		key = file.ObjectKey
+	}
+	if key == nil || *key == "" {
+		return nil, status.Error(codes.NotFound, "no stored object for this file")
+	}
+
+	if minioClient == nil {
+		return nil, status.Error(codes.Unavailable, "MinIO client not initialized")
+	}
+	obj, err := minioClient.GetObject(ctx, bucketName, *key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	info, err := obj.Stat()
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "object %s not found: %v", *key, err)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(obj); err != nil {
+		return nil, err
+	}
+
+	data := buf.Bytes()
+	if encryption.IsSealed(data) {
+		if encryption.KeyringInstance == nil {
+			return nil, status.Error(codes.FailedPrecondition, "file is encrypted but no keyring is configured")
+		}
+		data, err = encryption.KeyringInstance.Open(int64(userId), *key, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &files.FileContent{
+		ContentType: info.ContentType,
+		Data:        data,
+	}, nil
+}
@@ -2,69 +2,240 @@ package data
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	files "github.com/Aneesh-Hegde/expenseManager/grpc_file"
 	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/encryption"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var minioClient *minio.Client
 
+const bucketName = "test"
+
 type FileWithURL struct {
 	Filename string `json:"filename"`
 	ImageURL string `json:"image_url"`
 }
 
-func getFilesFromDBWithURLs(ctx context.Context, userId int) (*files.FileList, error) {
-	fileResults, err := fileDB.GetFilesWithURLs(ctx, strconv.Itoa(userId))
+// urlWorkers bounds how many files are presigned concurrently.
+const urlWorkers = 8
+
+// maxPageSize caps page_size so one request cannot ask for everything at once;
+// page_size 0 still returns all files for clients that predate paging.
+const maxPageSize = 200
+
+// fileCursor is the opaque next_cursor handed to clients. It records the sort
+// it was issued for so it cannot be replayed against a different ordering.
+type fileCursor struct {
+	Sort       string    `json:"s"`
+	UploadDate time.Time `json:"d"`
+	FileName   string    `json:"n"`
+	FileID     int32     `json:"i"`
+}
+
+func encodeCursor(sort string, last fileDB.FileMetadata) string {
+	raw, _ := json.Marshal(fileCursor{Sort: sort, UploadDate: last.UploadDate, FileName: last.FileName, FileID: last.FileID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor, sort string) (*fileDB.FileCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	var c fileCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if c.Sort != sort {
+		return nil, fmt.Errorf("cursor was issued for sort %q", c.Sort)
+	}
+	return &fileDB.FileCursor{UploadDate: c.UploadDate, FileName: c.FileName, FileID: c.FileID}, nil
+}
+
+// fileQueryFromRequest validates the list filters and turns them into a DB query.
+func fileQueryFromRequest(req *files.GetFileByUser) (fileDB.FileQuery, error) {
+	q := fileDB.FileQuery{
+		NameQuery: strings.TrimSpace(req.GetNameQuery()),
+		Sort:      req.GetSort(),
+	}
+	if q.Sort == "" {
+		q.Sort = fileDB.SortDateDesc
+	}
+	switch q.Sort {
+	case fileDB.SortDateDesc, fileDB.SortDateAsc, fileDB.SortNameAsc, fileDB.SortNameDesc:
+	default:
+		return q, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	if req.GetPageSize() < 0 {
+		return q, fmt.Errorf("page_size must not be negative")
+	}
+	q.Limit = int(req.GetPageSize())
+	if q.Limit > maxPageSize {
+		q.Limit = maxPageSize
+	}
+
+	if req.GetFromDate() != "" {
+		from, err := time.Parse("2006-01-02", req.GetFromDate())
+		if err != nil {
+			return q, fmt.Errorf("from_date must be YYYY-MM-DD")
+		}
+		q.From = &from
+	}
+	if req.GetToDate() != "" {
+		to, err := time.Parse("2006-01-02", req.GetToDate())
+		if err != nil {
+			return q, fmt.Errorf("to_date must be YYYY-MM-DD")
+		}
+		// Inclusive: everything before the start of the following day.
+		to = to.AddDate(0, 0, 1)
+		q.To = &to
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return q, fmt.Errorf("from_date must not be after to_date")
+	}
+
+	if req.GetCursor() != "" {
+		after, err := decodeCursor(req.GetCursor(), q.Sort)
+		if err != nil {
+			return q, err
+		}
+		q.After = after
+	}
+	return q, nil
+}
+
+func listFiles(ctx context.Context, userId int, req *files.GetFileByUser) (*files.FileList, error) {
+	q, err := fileQueryFromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	fileResults, total, err := fileDB.ListFiles(ctx, strconv.Itoa(userId), q)
 	if err != nil {
 		log.Printf("Error fetching files from DB for user %d: %v", userId, err)
 		return nil, err
 	}
 
-	var fileList files.FileList
-	bucketName := "test"
+	fileList := files.FileList{
+		Allfiles:   toFileMessages(fileResults),
+		TotalCount: int32(total),
+	}
+	if q.Limit > 0 && len(fileResults) == q.Limit {
+		fileList.NextCursor = encodeCursor(q.Sort, fileResults[len(fileResults)-1])
+	}
+
+	log.Printf("Retrieved %d of %d files for user %d", len(fileList.Allfiles), total, userId)
+	return &fileList, nil
+}
 
-	for _, result := range fileResults {
-		var imageURL string
+// toFileMessages presigns a page of files on a bounded worker pool, keeping
+// the input order.
+func toFileMessages(results []fileDB.FileMetadata) []*files.File {
+	messages := make([]*files.File, len(results))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
 
-		if result.ImageURL != nil && *result.ImageURL != "" {
-			objectKey := fmt.Sprintf("upload/user_%d/processed_%s", userId, result.FileName)
-			presignedURL, err := generatePresignedURL(bucketName, objectKey, 24*time.Hour)
-			if err != nil {
-				log.Printf("Error generating pre-signed URL for %s: %v", result.FileName, err)
-				imageURL = *result.ImageURL
-			} else {
-				imageURL = presignedURL
+	workers := urlWorkers
+	if len(results) < workers {
+		workers = len(results)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				messages[i] = toFileMessage(results[i])
 			}
+		}()
+	}
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return messages
+}
+
+// toFileMessage converts a metadata row into the gRPC message, presigning the
+// object key stored at upload time. Encrypted objects are useless to a browser,
+// so with encryption enabled the URLs point at the gateway instead.
+func toFileMessage(result fileDB.FileMetadata) *files.File {
+	if encryption.KeyringInstance != nil {
+		file := &files.File{
+			Filename:     result.FileName,
+			ImageUrl:     encryption.ContentURL(result.FileID, ""),
+			FileId:       result.FileID,
+			UploadDate:   result.UploadDate.Format(time.RFC3339),
+			ThumbnailUrl: encryption.ContentURL(result.FileID, "thumb"),
+			PreviewUrl:   encryption.ContentURL(result.FileID, "preview"),
+		}
+		if result.Width != nil && result.Height != nil {
+			file.Width, file.Height = *result.Width, *result.Height
+		}
+		if result.GroupID != nil {
+			file.GroupId = *result.GroupID
+		}
+		return file
+	}
+
+	var imageURL string
+	if result.ObjectKey != nil && *result.ObjectKey != "" {
+		presignedURL, err := generatePresignedURL(bucketName, *result.ObjectKey, 24*time.Hour)
+		if err != nil {
+			log.Printf("Error generating pre-signed URL for %s: %v", result.FileName, err)
 		} else {
-			objectKey := fmt.Sprintf("upload/user_%d/processed_%s", userId, result.FileName)
-			presignedURL, err := generatePresignedURL(bucketName, objectKey, 24*time.Hour)
-			if err != nil {
-				log.Printf("No URL available for file %s: %v", result.FileName, err)
-				imageURL = ""
-			} else {
-				imageURL = presignedURL
-			}
+			imageURL = presignedURL
 		}
+	} else {
+		log.Printf("No object key recorded for file %d (%s)", result.FileID, result.FileName)
+	}
 
-		fileList.Allfiles = append(fileList.Allfiles, &files.File{
-			Filename: result.FileName,
-			ImageUrl: imageURL,
-		})
+	file := &files.File{
+		Filename:     result.FileName,
+		ImageUrl:     imageURL,
+		FileId:       result.FileID,
+		UploadDate:   result.UploadDate.Format(time.RFC3339),
+		ThumbnailUrl: presignVariant(result.ThumbnailKey, imageURL),
+		PreviewUrl:   presignVariant(result.PreviewKey, imageURL),
+	}
+	if result.Width != nil && result.Height != nil {
+		file.Width, file.Height = *result.Width, *result.Height
 	}
+	if result.GroupID != nil {
+		file.GroupId = *result.GroupID
+	}
+	return file
+}
 
-	log.Printf("Retrieved %d files for user %d", len(fileList.Allfiles), userId)
-	return &fileList, nil
+// presignVariant returns a URL for a thumbnail or preview, or the full-size
+// image URL for files that have not been backfilled yet.
+func presignVariant(key *string, fallback string) string {
+	if key == nil || *key == "" {
+		return fallback
+	}
+	presignedURL, err := generatePresignedURL(bucketName, *key, 24*time.Hour)
+	if err != nil {
+		log.Printf("Error generating pre-signed URL for variant %s: %v", *key, err)
+		return fallback
+	}
+	return presignedURL
 }
 
 func generatePresignedURL(bucketName, objectKey string, expiry time.Duration) (string, error) {
@@ -75,12 +246,8 @@ func generatePresignedURL(bucketName, objectKey string, expiry time.Duration) (s
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	_, err := minioClient.StatObject(ctx, bucketName, objectKey, minio.StatObjectOptions{})
-	if err != nil {
-		log.Printf("Object %s not found in bucket %s: %v", objectKey, bucketName, err)
-		return "", fmt.Errorf("image not found: %s", objectKey)
-	}
-
+	// Presigning is a local signature; the key comes from file_metadata, so
+	// there is no need for a StatObject round-trip per file.
 	reqParams := make(url.Values)
 	reqParams.Set("response-cache-control", "max-age=3600")
 
@@ -119,29 +286,26 @@ func generatePresignedURLWithHeaders(bucketName, objectKey string, expiry time.D
 }
 
 func GetFiles(ctx context.Context, req *files.GetFileByUser) (*files.FileList, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("metadata not found in context")
+	userId, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	userIdStr := md.Get("user_id")
-	if len(userIdStr) == 0 {
-		return nil, fmt.Errorf("user ID not found in context")
-	}
+	log.Printf("Fetching files for user ID: %d", userId)
+	return listFiles(ctx, userId, req)
+}
 
-	userId, err := strconv.Atoi(userIdStr[0])
+// getUserID is auth.UserID as the numeric ID the file tables key on.
+func getUserID(ctx context.Context) (int, error) {
+	userID, err := auth.UserID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %v", err)
+		return 0, err
 	}
-
-	accessToken := md.Get("token")
-	if len(accessToken) > 0 {
-		headers := metadata.Pairs("token", accessToken[0])
-		grpc.SendHeader(ctx, headers)
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID: %v", err)
 	}
-
-	log.Printf("Fetching files for user ID: %d", userId)
-	return getFilesFromDBWithURLs(ctx, userId)
+	return id, nil
 }
 
 func InitMinIOForFiles(endpoint, accessKeyID, secretAccessKey string, useSSL bool) error {
@@ -170,17 +334,28 @@ func UpdateImageURLInDB(ctx context.Context, userId int, fileName, imageURL stri
 	return fileDB.UpdateImageURL(ctx, strconv.Itoa(userId), fileName, imageURL)
 }
 
+// orphanGracePeriod is how old an object with no file row must be before it
+// is removed as an orphan, so that uploads whose row is not written yet are
+// left alone.
+const orphanGracePeriod = time.Hour
+
+// CleanupOrphanedFiles removes the user's objects that no file row names,
+// such as those left behind when removing a deleted file's objects failed.
 func CleanupOrphanedFiles(ctx context.Context, userId int) error {
 	if minioClient == nil {
 		return fmt.Errorf("MinIO client not initialized")
 	}
 
-	dbFiles, err := fileDB.GetAllFileNames(ctx, strconv.Itoa(userId))
+	keys, err := fileDB.GetAllObjectKeys(ctx, strconv.Itoa(userId))
 	if err != nil {
 		return fmt.Errorf("failed to query database: %v", err)
 	}
 
-	bucketName := "test"
+	knownKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		knownKeys[key] = true
+	}
+
 	prefix := fmt.Sprintf("upload/user_%d/", userId)
 	objectCh := minioClient.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
 		Prefix:    prefix,
@@ -194,19 +369,8 @@ func CleanupOrphanedFiles(ctx context.Context, userId int) error {
 			continue
 		}
 
-		parts := strings.Split(object.Key, "/")
-		if len(parts) >= 3 {
-			minioFileName := strings.TrimPrefix(parts[2], "processed_")
-			found := false
-			for _, dbFile := range dbFiles {
-				if dbFile == minioFileName {
-					found = true
-					break
-				}
-			}
-			if !found {
-				orphanedFiles = append(orphanedFiles, object.Key)
-			}
+		if !knownKeys[object.Key] && time.Since(object.LastModified) > orphanGracePeriod {
+			orphanedFiles = append(orphanedFiles, object.Key)
 		}
 	}
 
@@ -222,3 +386,37 @@ func CleanupOrphanedFiles(ctx context.Context, userId int) error {
 	log.Printf("Cleanup completed for user %d. Removed %d orphaned files", userId, len(orphanedFiles))
 	return nil
 }
+
+// CleanupAllOrphanedFiles runs CleanupOrphanedFiles for every user with
+// objects stored, including users who no longer have any files.
+func CleanupAllOrphanedFiles(ctx context.Context) error {
+	if minioClient == nil {
+		return fmt.Errorf("MinIO client not initialized")
+	}
+
+	var userIDs []int
+	for object := range minioClient.ListObjects(ctx, bucketName, minio.ListObjectsOptions{Prefix: "upload/"}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list users' uploads: %v", object.Err)
+		}
+		// Listed without recursion, each user's folder is one entry.
+		var userId int
+		if _, err := fmt.Sscanf(object.Key, "upload/user_%d/", &userId); err == nil {
+			userIDs = append(userIDs, userId)
+		}
+	}
+
+	for _, userId := range userIDs {
+		if err := CleanupOrphanedFiles(ctx, userId); err != nil {
+			log.Printf("Orphan cleanup failed for user %d: %v", userId, err)
+		}
+	}
+	return nil
+}
+
+func removeObject(ctx context.Context, objectKey string) error {
+	if minioClient == nil {
+		return fmt.Errorf("MinIO client not initialized")
+	}
+	return minioClient.RemoveObject(ctx, bucketName, objectKey, minio.RemoveObjectOptions{})
+}
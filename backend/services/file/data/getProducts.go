@@ -7,6 +7,7 @@ import (
 
 	"github.com/Aneesh-Hegde/expenseManager/grpc"
 	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 )
 
 func GetFileProduct(ctx context.Context, filename string, userId string) (*grpc.GetTextResponse, error) {
@@ -19,7 +20,7 @@ func GetFileProduct(ctx context.Context, filename string, userId string) (*grpc.
 	}
 
 	var products []*grpc.Product
-	var totalAmount float32
+	totalAmount := money.Zero(money.DefaultCurrency)
 
 	for _, result := range productResults {
 		product := &grpc.Product{
@@ -27,20 +28,25 @@ func GetFileProduct(ctx context.Context, filename string, userId string) (*grpc.
 			ProductName: result.ProductName,
 			Quantity:    float32(result.Quantity),
 			Amount:      float32(result.Price),
+			AmountMoney: money.MessageOf(result.Price, money.DefaultCurrency),
 			Name:        result.FileName,
 			Category:    result.CategoryName,
 			Date:        result.DateAdded.Format("02/01/2006"),
 		}
 
-		totalAmount += float32(result.Quantity) * float32(result.Price)
+		totalAmount, err = totalAmount.Add(money.FromFloat(result.Price, money.DefaultCurrency).Mul(float64(result.Quantity)))
+		if err != nil {
+			return nil, err
+		}
 		products = append(products, product)
 	}
 
 	fmt.Println(products)
 
 	response := &grpc.GetTextResponse{
-		Products: products,
-		Total:    fmt.Sprintf("%.2f", totalAmount),
+		Products:   products,
+		Total:      totalAmount.String(),
+		TotalMoney: totalAmount.Message(),
 	}
 
 	return response, nil
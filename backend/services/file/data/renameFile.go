@@ -0,0 +1,64 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	files "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	"github.com/Aneesh-Hegde/expenseManager/redis"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RenameFile changes the name a receipt is listed under. The stored object is
+// not moved; only metadata, products and the cache key follow the new name.
+func RenameFile(ctx context.Context, req *files.RenameFileRequest) (*files.FileActionResponse, error) {
+	userId, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newName := strings.TrimSpace(req.GetNewFilename())
+	if newName == "" {
+		return nil, status.Error(codes.InvalidArgument, "new filename is required")
+	}
+	if strings.ContainsAny(newName, "/\\") {
+		return nil, status.Error(codes.InvalidArgument, "filename must not contain path separators")
+	}
+
+	file, err := fileDB.GetFileByID(ctx, strconv.Itoa(userId), req.GetFileId())
+	if errors.Is(err, fileDB.ErrFileNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = fileDB.RenameFile(ctx, strconv.Itoa(userId), file.FileID, newName)
+	switch {
+	case errors.Is(err, fileDB.ErrFileNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, fileDB.ErrFileNameTaken):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	// Cached products are keyed by file name; drop the old entry and let the
+	// next read repopulate it under the new one.
+	if err := redis.DeleteCachedProductData(userId, file.FileName); err != nil {
+		log.Printf("Warning: could not clear cache for %s: %v", file.FileName, err)
+	}
+
+	file.FileName = newName
+	return &files.FileActionResponse{
+		Success: true,
+		Message: fmt.Sprintf("Renamed file to %s", newName),
+		File:    toFileMessage(*file),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	files "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	"github.com/Aneesh-Hegde/expenseManager/redis"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResetFileExtraction discards the products extracted from a receipt and its
+// cached extraction. Nothing is extracted here: the next GetText call for the
+// receipt runs OCR on the stored object again.
+func ResetFileExtraction(ctx context.Context, req *files.ResetFileExtractionRequest) (*files.FileActionResponse, error) {
+	userId, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fileDB.GetFileByID(ctx, strconv.Itoa(userId), req.GetFileId())
+	if errors.Is(err, fileDB.ErrFileNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if file.ObjectKey == nil || *file.ObjectKey == "" {
+		return nil, status.Error(codes.FailedPrecondition, "no stored image to extract this file from again")
+	}
+
+	deletedProducts, err := fileDB.DeleteFileProducts(ctx, strconv.Itoa(userId), file.FileName)
+	if errors.Is(err, fileDB.ErrFileRefunded) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := redis.DeleteCachedProductData(userId, file.FileName); err != nil {
+		log.Printf("Warning: could not clear cache for %s: %v", file.FileName, err)
+	}
+
+	log.Printf("Reset extraction of file %d (%s), dropped %d products", file.FileID, file.FileName, deletedProducts)
+	return &files.FileActionResponse{
+		Success: true,
+		Message: fmt.Sprintf("Cleared %d extracted products; %s will be re-read on next open", deletedProducts, file.FileName),
+		File:    toFileMessage(*file),
+	}, nil
+}
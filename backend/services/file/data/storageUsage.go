@@ -0,0 +1,35 @@
+package data
+
+import (
+	"context"
+
+	files "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/validation"
+)
+
+// GetStorageUsage reports how much of their storage quota the caller has used,
+// together with the limits that apply to them.
+func GetStorageUsage(ctx context.Context, req *files.GetStorageUsageRequest) (*files.StorageUsage, error) {
+	userId, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := fileDB.GetUserLimits(ctx, int64(userId), validation.DefaultLimits())
+	if err != nil {
+		return nil, err
+	}
+	usage, err := fileDB.GetStorageUsage(ctx, int64(userId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &files.StorageUsage{
+		BytesUsed:    usage.BytesUsed,
+		FileCount:    usage.FileCount,
+		QuotaBytes:   limits.QuotaBytes,
+		QuotaFiles:   limits.QuotaFiles,
+		MaxFileBytes: limits.MaxFileBytes,
+	}, nil
+}
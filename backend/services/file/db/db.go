@@ -2,18 +2,51 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/validation"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
 	"strconv"
+	"strings"
 	"time"
 )
 
+var (
+	ErrFileNotFound  = errors.New("file not found")
+	ErrFileNameTaken = errors.New("a file with this name already exists")
+	ErrFileRefunded  = errors.New("the file has refunded products; delete their refunds first")
+)
+
 type FileMetadata struct {
 	FileID     int32
 	FileName   string
 	ImageURL   *string
+	ObjectKey  *string
 	UploadDate time.Time
 	UserID     int32
+
+	ThumbnailKey *string
+	PreviewKey   *string
+	Width        *int32
+	Height       *int32
+	SizeBytes    int64
+	ContentType  *string
+
+	// GroupID is the group the receipt is shared in, if any.
+	GroupID *int32
+}
+
+const fileMetadataColumns = `file_id, file_name, image_url, object_key, upload_date, user_id,
+        thumbnail_key, preview_key, image_width, image_height, size_bytes, content_type,
+        (SELECT ge.group_id FROM user_service.group_expenses ge WHERE ge.file_id = file_metadata.file_id)`
+
+func scanFileMetadata(row pgx.Row) (FileMetadata, error) {
+	var file FileMetadata
+	err := row.Scan(&file.FileID, &file.FileName, &file.ImageURL, &file.ObjectKey,
+		&file.UploadDate, &file.UserID, &file.ThumbnailKey, &file.PreviewKey,
+		&file.Width, &file.Height, &file.SizeBytes, &file.ContentType, &file.GroupID)
+	return file, err
 }
 
 type ProductResult struct {
@@ -35,34 +68,121 @@ func parseUserID(userID string) (int32, error) {
 	return int32(id), nil
 }
 
-func GetFilesWithURLs(ctx context.Context, userID string) ([]FileMetadata, error) {
+// File list orderings accepted by ListFiles.
+const (
+	SortDateDesc = "date_desc"
+	SortDateAsc  = "date_asc"
+	SortNameAsc  = "name_asc"
+	SortNameDesc = "name_desc"
+)
+
+// FileCursor identifies the last row of a page; the next page starts after it
+// in the query's sort order.
+type FileCursor struct {
+	UploadDate time.Time
+	FileName   string
+	FileID     int32
+}
+
+// FileQuery filters and pages a user's files. Zero values mean no filter;
+// Limit 0 returns every matching row.
+type FileQuery struct {
+	From      *time.Time
+	To        *time.Time
+	NameQuery string
+	Sort      string
+	Limit     int
+	After     *FileCursor
+}
+
+// ListFiles returns one page of a user's files together with the number of
+// files matching the filters across all pages.
+func ListFiles(ctx context.Context, userID string, q FileQuery) ([]FileMetadata, int, error) {
 	userIDInt, err := parseUserID(userID)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	rows, err := sharedDB.GetDB().Query(ctx, `
-        SELECT file_id, file_name, image_url, upload_date, user_id 
-        FROM file_management_service.file_metadata 
-        WHERE user_id = $1 ORDER BY upload_date DESC`,
-		userIDInt)
+	where := []string{"user_id = $1"}
+	args := []interface{}{userIDInt}
+	addArg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
 
+	if q.From != nil {
+		where = append(where, "upload_date >= "+addArg(*q.From))
+	}
+	if q.To != nil {
+		where = append(where, "upload_date < "+addArg(*q.To))
+	}
+	if q.NameQuery != "" {
+		where = append(where, "file_name ILIKE "+addArg("%"+escapeLike(q.NameQuery)+"%"))
+	}
+
+	var total int
+	err = sharedDB.GetDB().QueryRow(ctx, `
+        SELECT COUNT(*) FROM file_management_service.file_metadata
+        WHERE `+strings.Join(where, " AND "), args...).Scan(&total)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching files from DB: %v", err)
+		return nil, 0, fmt.Errorf("error counting files: %v", err)
+	}
+
+	var orderBy string
+	switch q.Sort {
+	case SortDateAsc:
+		orderBy = "upload_date ASC, file_id ASC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(upload_date, file_id) > (%s, %s)", addArg(q.After.UploadDate), addArg(q.After.FileID)))
+		}
+	case SortNameAsc:
+		orderBy = "lower(file_name) ASC, file_id ASC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(lower(file_name), file_id) > (lower(%s), %s)", addArg(q.After.FileName), addArg(q.After.FileID)))
+		}
+	case SortNameDesc:
+		orderBy = "lower(file_name) DESC, file_id DESC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(lower(file_name), file_id) < (lower(%s), %s)", addArg(q.After.FileName), addArg(q.After.FileID)))
+		}
+	case SortDateDesc, "":
+		orderBy = "upload_date DESC, file_id DESC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(upload_date, file_id) < (%s, %s)", addArg(q.After.UploadDate), addArg(q.After.FileID)))
+		}
+	default:
+		return nil, 0, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	query := `
+        SELECT ` + fileMetadataColumns + `
+        FROM file_management_service.file_metadata
+        WHERE ` + strings.Join(where, " AND ") + `
+        ORDER BY ` + orderBy
+	if q.Limit > 0 {
+		query += " LIMIT " + addArg(q.Limit)
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching files from DB: %v", err)
 	}
 	defer rows.Close()
 
 	var files []FileMetadata
 	for rows.Next() {
-		var file FileMetadata
-		if err := rows.Scan(&file.FileID, &file.FileName, &file.ImageURL,
-			&file.UploadDate, &file.UserID); err != nil {
-			return nil, fmt.Errorf("error scanning file row: %v", err)
+		file, err := scanFileMetadata(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error scanning file row: %v", err)
 		}
 		files = append(files, file)
 	}
 
-	return files, rows.Err()
+	return files, total, rows.Err()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func GetFileProducts(ctx context.Context, filename, userID string) ([]ProductResult, error) {
@@ -124,29 +244,461 @@ func UpdateImageURL(ctx context.Context, userID string, fileName, imageURL strin
 	return nil
 }
 
-func GetAllFileNames(ctx context.Context, userID string) ([]string, error) {
+func GetFileByID(ctx context.Context, userID string, fileID int32) (*FileMetadata, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := scanFileMetadata(sharedDB.GetDB().QueryRow(ctx, `
+        SELECT `+fileMetadataColumns+`
+        FROM file_management_service.file_metadata
+        WHERE user_id = $1 AND file_id = $2`,
+		userIDInt, fileID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching file %d: %v", fileID, err)
+	}
+
+	return &file, nil
+}
+
+// uniqueViolation is the SQLSTATE of a unique constraint violation.
+const uniqueViolation = "23505"
+
+// foreignKeyViolation is the SQLSTATE of a foreign key violation. Deleting
+// products raises it when they have refunds, which must be deleted first.
+const foreignKeyViolation = "23503"
+
+// refundedError turns a foreign key violation deleting products into
+// ErrFileRefunded.
+func refundedError(err error) error {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) && pgErr.SQLState() == foreignKeyViolation {
+		return ErrFileRefunded
+	}
+	return err
+}
+
+// FileNameTaken reports whether the user already has a file with this name.
+// Products, receipt text and annotations refer to their receipt by name, so
+// names are unique per user.
+func FileNameTaken(ctx context.Context, userID int64, fileName string) (bool, error) {
+	var taken bool
+	err := sharedDB.GetDB().QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM file_management_service.file_metadata
+            WHERE user_id = $1 AND file_name = $2)`,
+		userID, fileName).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check file name: %v", err)
+	}
+	return taken, nil
+}
+
+// InsertFileMetadata records a stored upload and returns its file_id. It
+// returns ErrFileNameTaken if another upload took the name first.
+func InsertFileMetadata(ctx context.Context, file FileMetadata) (int32, error) {
+	return insertFileMetadata(ctx, sharedDB.GetDB(), file)
+}
+
+// InsertFileWithinQuota records a stored upload like InsertFileMetadata, but
+// only if its stored size, variants included, still fits the user's quota.
+// The user's storage_quotas row stays locked from summing their usage to
+// recording the upload, so concurrent uploads are checked one after another.
+// It returns an error wrapping validation.ErrQuotaExceeded if the upload does
+// not fit.
+func InsertFileWithinQuota(ctx context.Context, file FileMetadata, limits validation.Limits) (int32, error) {
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Users without overrides have no row to lock, so they get one; its NULL
+	// limits keep the defaults.
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO file_management_service.storage_quotas (user_id) VALUES ($1)
+        ON CONFLICT (user_id) DO NOTHING`,
+		file.UserID); err != nil {
+		return 0, fmt.Errorf("failed to create storage quota: %v", err)
+	}
+	if _, err := tx.Exec(ctx, `
+        SELECT 1 FROM file_management_service.storage_quotas
+        WHERE user_id = $1 FOR UPDATE`,
+		file.UserID); err != nil {
+		return 0, fmt.Errorf("failed to lock storage quota: %v", err)
+	}
+
+	usage, err := storageUsage(ctx, tx, int64(file.UserID))
+	if err != nil {
+		return 0, err
+	}
+	if err := validation.CheckQuota(usage, limits, file.SizeBytes); err != nil {
+		return 0, err
+	}
+
+	fileID, err := insertFileMetadata(ctx, tx, file)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit file metadata: %v", err)
+	}
+	return fileID, nil
+}
+
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func insertFileMetadata(ctx context.Context, q queryRower, file FileMetadata) (int32, error) {
+	var fileID int32
+	err := q.QueryRow(ctx, `
+        INSERT INTO file_management_service.file_metadata
+            (user_id, file_name, image_url, object_key, upload_date, thumbnail_key, preview_key, image_width, image_height,
+             size_bytes, content_type)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING file_id`,
+		file.UserID, file.FileName, file.ImageURL, file.ObjectKey, file.UploadDate, file.ThumbnailKey, file.PreviewKey,
+		file.Width, file.Height, file.SizeBytes, file.ContentType).Scan(&fileID)
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) && pgErr.SQLState() == uniqueViolation {
+		return 0, ErrFileNameTaken
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error storing file metadata: %v", err)
+	}
+	return fileID, nil
+}
+
+// DeleteFile removes the file's metadata row together with every product that
+// was extracted from it. It returns the number of products removed.
+func DeleteFile(ctx context.Context, userID string, fileID int32) (int64, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var fileName string
+	err = tx.QueryRow(ctx, `
+        DELETE FROM file_management_service.file_metadata
+        WHERE user_id = $1 AND file_id = $2
+        RETURNING file_name`,
+		userIDInt, fileID).Scan(&fileName)
+	if err == pgx.ErrNoRows {
+		return 0, ErrFileNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete file metadata: %v", err)
+	}
+
+	result, err := tx.Exec(ctx, `
+        DELETE FROM product_category_service.products
+        WHERE user_id = $1 AND file_name = $2`,
+		userIDInt, fileName)
+	if err = refundedError(err); err == ErrFileRefunded {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete file products: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        DELETE FROM product_category_service.receipt_texts
+        WHERE user_id = $1 AND file_name = $2`,
+		userIDInt, fileName); err != nil {
+		return 0, fmt.Errorf("failed to delete receipt text: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        DELETE FROM product_category_service.receipt_annotations
+        WHERE user_id = $1 AND file_name = $2`,
+		userIDInt, fileName); err != nil {
+		return 0, fmt.Errorf("failed to delete receipt annotation: %v", err)
+	}
+
+	// The receipt no longer backs a group expense.
+	if _, err := receiptSharing.UnassignReceipt(ctx, tx, userIDInt, fileID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit file deletion: %v", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// RenameFile changes the display name of a file. Products, receipt text and
+// annotations, and refunds reference their receipt by name, so they are
+// moved along with it; the object key is left untouched.
+func RenameFile(ctx context.Context, userID string, fileID int32, newName string) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var taken bool
+	err = tx.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM file_management_service.file_metadata
+            WHERE user_id = $1 AND file_name = $2 AND file_id <> $3)`,
+		userIDInt, newName, fileID).Scan(&taken)
+	if err != nil {
+		return fmt.Errorf("failed to check file name: %v", err)
+	}
+	if taken {
+		return ErrFileNameTaken
+	}
+
+	var oldName string
+	err = tx.QueryRow(ctx, `
+        SELECT file_name FROM file_management_service.file_metadata
+        WHERE user_id = $1 AND file_id = $2 FOR UPDATE`,
+		userIDInt, fileID).Scan(&oldName)
+	if err == pgx.ErrNoRows {
+		return ErrFileNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch file %d: %v", fileID, err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE file_management_service.file_metadata
+        SET file_name = $1 WHERE user_id = $2 AND file_id = $3`,
+		newName, userIDInt, fileID); err != nil {
+		return fmt.Errorf("failed to rename file: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE product_category_service.products
+        SET file_name = $1 WHERE user_id = $2 AND file_name = $3`,
+		newName, userIDInt, oldName); err != nil {
+		return fmt.Errorf("failed to rename file products: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE product_category_service.receipt_texts
+        SET file_name = $1 WHERE user_id = $2 AND file_name = $3`,
+		newName, userIDInt, oldName); err != nil {
+		return fmt.Errorf("failed to rename receipt text: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE product_category_service.receipt_annotations
+        SET file_name = $1 WHERE user_id = $2 AND file_name = $3`,
+		newName, userIDInt, oldName); err != nil {
+		return fmt.Errorf("failed to rename receipt annotation: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE product_category_service.refunds
+        SET file_name = $1 WHERE user_id = $2 AND file_name = $3`,
+		newName, userIDInt, oldName); err != nil {
+		return fmt.Errorf("failed to rename receipt refunds: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit file rename: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteFileProducts drops the products extracted from a file so the receipt
+// can be run through extraction again.
+func DeleteFileProducts(ctx context.Context, userID string, fileName string) (int64, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+        DELETE FROM product_category_service.products
+        WHERE user_id = $1 AND file_name = $2`,
+		userIDInt, fileName)
+	if err = refundedError(err); err == ErrFileRefunded {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete file products: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit product deletion: %v", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func GetAllObjectKeys(ctx context.Context, userID string) ([]string, error) {
 	userIDInt, err := parseUserID(userID)
 	if err != nil {
 		return nil, err
 	}
 
 	rows, err := sharedDB.GetDB().Query(ctx, `
-        SELECT file_name FROM file_management_service.file_metadata WHERE user_id = $1`,
+        SELECT k FROM file_management_service.file_metadata,
+            unnest(ARRAY[object_key, thumbnail_key, preview_key]) AS k
+        WHERE user_id = $1 AND k IS NOT NULL`,
 		userIDInt)
-
 	if err != nil {
 		return nil, fmt.Errorf("failed to query database: %v", err)
 	}
 	defer rows.Close()
 
-	var fileNames []string
+	var keys []string
 	for rows.Next() {
-		var fileName string
-		if err := rows.Scan(&fileName); err != nil {
-			continue
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("error scanning object key: %v", err)
 		}
-		fileNames = append(fileNames, fileName)
+		keys = append(keys, key)
 	}
 
-	return fileNames, rows.Err()
+	return keys, rows.Err()
+}
+
+// GetFilesMissingVariants pages through stored images that have no thumbnail
+// or no recorded size yet, ordered by file_id and starting after afterID.
+func GetFilesMissingVariants(ctx context.Context, afterID int32, limit int) ([]FileMetadata, error) {
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT `+fileMetadataColumns+`
+        FROM file_management_service.file_metadata
+        WHERE (thumbnail_key IS NULL OR size_bytes = 0) AND object_key IS NOT NULL
+            AND content_type IS DISTINCT FROM 'application/pdf' AND file_id > $1
+        ORDER BY file_id LIMIT $2`,
+		afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching files without variants: %v", err)
+	}
+	defer rows.Close()
+
+	var files []FileMetadata
+	for rows.Next() {
+		file, err := scanFileMetadata(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning file row: %v", err)
+		}
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+func UpdateFileVariants(ctx context.Context, fileID int32, thumbnailKey, previewKey string, width, height int, sizeBytes int64) error {
+	_, err := sharedDB.GetDB().Exec(ctx, `
+        UPDATE file_management_service.file_metadata
+        SET thumbnail_key = $1, preview_key = $2, image_width = $3, image_height = $4, size_bytes = $5
+        WHERE file_id = $6`,
+		thumbnailKey, previewKey, width, height, sizeBytes, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to record image variants: %v", err)
+	}
+	return nil
+}
+
+// GetFilesWithoutSize pages through uploads recorded before sizes were
+// tracked, ordered by file_id and starting after afterID.
+func GetFilesWithoutSize(ctx context.Context, afterID int32, limit int) ([]FileMetadata, error) {
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT `+fileMetadataColumns+`
+        FROM file_management_service.file_metadata
+        WHERE size_bytes = 0 AND object_key IS NOT NULL AND file_id > $1
+        ORDER BY file_id LIMIT $2`,
+		afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching files without a size: %v", err)
+	}
+	defer rows.Close()
+
+	var files []FileMetadata
+	for rows.Next() {
+		file, err := scanFileMetadata(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning file row: %v", err)
+		}
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+func UpdateFileSize(ctx context.Context, fileID int32, sizeBytes int64) error {
+	_, err := sharedDB.GetDB().Exec(ctx, `
+        UPDATE file_management_service.file_metadata
+        SET size_bytes = $1 WHERE file_id = $2 AND size_bytes = 0`,
+		sizeBytes, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to record file size: %v", err)
+	}
+	return nil
+}
+
+// GetStorageUsage sums the stored size of every upload the user owns,
+// including generated variants.
+func GetStorageUsage(ctx context.Context, userID int64) (validation.Usage, error) {
+	return storageUsage(ctx, sharedDB.GetDB(), userID)
+}
+
+func storageUsage(ctx context.Context, q queryRower, userID int64) (validation.Usage, error) {
+	var usage validation.Usage
+	err := q.QueryRow(ctx, `
+        SELECT COALESCE(SUM(size_bytes), 0), COUNT(*)
+        FROM file_management_service.file_metadata
+        WHERE user_id = $1`,
+		userID).Scan(&usage.BytesUsed, &usage.FileCount)
+	if err != nil {
+		return usage, fmt.Errorf("failed to fetch storage usage: %v", err)
+	}
+	return usage, nil
+}
+
+// GetUserLimits applies any per-user overrides from storage_quotas on top of
+// the service defaults.
+func GetUserLimits(ctx context.Context, userID int64, defaults validation.Limits) (validation.Limits, error) {
+	var maxFileBytes, quotaBytes, quotaFiles *int64
+	err := sharedDB.GetDB().QueryRow(ctx, `
+        SELECT max_file_bytes, quota_bytes, quota_files
+        FROM file_management_service.storage_quotas
+        WHERE user_id = $1`,
+		userID).Scan(&maxFileBytes, &quotaBytes, &quotaFiles)
+	if err == pgx.ErrNoRows {
+		return defaults, nil
+	}
+	if err != nil {
+		return defaults, fmt.Errorf("failed to fetch storage quota: %v", err)
+	}
+
+	limits := defaults
+	if maxFileBytes != nil {
+		limits.MaxFileBytes = *maxFileBytes
+	}
+	if quotaBytes != nil {
+		limits.QuotaBytes = *quotaBytes
+	}
+	if quotaFiles != nil {
+		limits.QuotaFiles = *quotaFiles
+	}
+	return limits, nil
 }
@@ -0,0 +1,112 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/validation"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+// These tests write to the database the DB_* variables name, which must
+// have every service's tables and migrations applied, so they only run when
+// DB_TEST is set, against a scratch database.
+func requireDB(t *testing.T) {
+	t.Helper()
+	if os.Getenv("DB_TEST") == "" {
+		t.Skip("DB_TEST is not set")
+	}
+}
+
+// newUser creates a user of its own for a test and returns its ID.
+func newUser(t *testing.T, ctx context.Context) int32 {
+	t.Helper()
+	name := fmt.Sprintf("file-test-%d", time.Now().UnixNano())
+	var userID int32
+	err := sharedDB.GetDB().QueryRow(ctx,
+		"INSERT INTO user_service.users (username, email) VALUES ($1, $2) RETURNING user_id",
+		name, name+"@example.com").Scan(&userID)
+	if err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	return userID
+}
+
+func TestRenameFileMovesRefunds(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	user := strconv.Itoa(int(userID))
+	if _, err := balanceDB.CreateAccountWithIncome(ctx, user,
+		balanceDB.AccountDetails{Name: "Wallet"}, "cash", money.New(10000, "USD")); err != nil {
+		t.Fatalf("creating account: %v", err)
+	}
+
+	oldName := fmt.Sprintf("receipt-%d.jpg", time.Now().UnixNano())
+	fileID, err := fileDB.InsertFileMetadata(ctx, fileDB.FileMetadata{UserID: userID, FileName: oldName, UploadDate: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := productDB.InsertProduct(ctx, user, productDB.Product{ProductName: "Kettle", Quantity: 1, Price: money.New(3000, "USD"),
+		FileName: &oldName, DateAdded: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	amount, err := money.Requested(10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refund, err := productDB.CreateRefund(ctx, user, productDB.RefundRequest{FileName: oldName, Amount: amount, Date: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newName := "renamed-" + oldName
+	if err := fileDB.RenameFile(ctx, user, fileID, newName); err != nil {
+		t.Fatal(err)
+	}
+
+	refunds, err := productDB.ListRefunds(ctx, user, productDB.RefundQuery{FileName: newName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refunds) != 1 || refunds[0].RefundID != refund.RefundID {
+		t.Fatalf("refunds of the renamed receipt = %+v, want refund %d", refunds, refund.RefundID)
+	}
+	if refunds[0].FileName == nil || *refunds[0].FileName != newName {
+		t.Errorf("refund file name = %v, want %q", refunds[0].FileName, newName)
+	}
+	if refunds, err := productDB.ListRefunds(ctx, user, productDB.RefundQuery{FileName: oldName}); err != nil || len(refunds) != 0 {
+		t.Errorf("refunds under the old name = %+v, %v; want none", refunds, err)
+	}
+}
+
+func TestInsertFileWithinQuotaCountsStoredSize(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID := newUser(t, ctx)
+	limits := validation.Limits{QuotaBytes: 1000, QuotaFiles: 10}
+
+	insert := func(name string, size int64) error {
+		_, err := fileDB.InsertFileWithinQuota(ctx, fileDB.FileMetadata{UserID: userID, FileName: name,
+			UploadDate: time.Now(), SizeBytes: size}, limits)
+		return err
+	}
+	if err := insert("first.jpg", 600); err != nil {
+		t.Fatal(err)
+	}
+	if err := insert("second.jpg", 401); !errors.Is(err, validation.ErrQuotaExceeded) {
+		t.Errorf("an upload past the quota = %v, want ErrQuotaExceeded", err)
+	}
+	if err := insert("second.jpg", 400); err != nil {
+		t.Errorf("an upload filling the quota = %v, want nil", err)
+	}
+}
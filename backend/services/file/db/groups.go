@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrReceiptEmpty = errors.New("receipt has nothing to share")
+	// ErrGroupNotFound and ErrInvalidShare are what ReceiptSharing
+	// implementations wrap their errors in.
+	ErrGroupNotFound = errors.New("group not found")
+	ErrInvalidShare  = errors.New("invalid group share")
+)
+
+// GroupExpense is a receipt recorded as an expense of a group. Amounts are
+// in cents; Shares has each member's part of Amount.
+type GroupExpense struct {
+	GroupID int32
+	Amount  int64
+	Shares  map[int32]int64
+}
+
+// ReceiptSharing records receipts as group expenses and splits their
+// products, within the caller's transaction. The services that own groups
+// and products implement it, so this package does not depend on them; the
+// file service sets it with SetReceiptSharing before serving.
+type ReceiptSharing interface {
+	// AssignReceipt records the receipt as an expense the user paid in the
+	// group, shared equally by sharedBy or by every member, moving it from
+	// any group it was in.
+	AssignReceipt(ctx context.Context, tx pgx.Tx, userID, groupID, fileID int32, fileName string,
+		amount int64, date time.Time, sharedBy []int32) (*GroupExpense, error)
+	// UnassignReceipt removes the expense recorded from the receipt, if any,
+	// and reports whether there was one.
+	UnassignReceipt(ctx context.Context, tx pgx.Tx, userID, fileID int32) (bool, error)
+	// SplitReceipt splits every product on the receipt by percent between
+	// the user and other users, keyed by user ID. No percents makes the
+	// products whole again.
+	SplitReceipt(ctx context.Context, tx pgx.Tx, userID int32, fileName string, percents map[int32]float64) error
+}
+
+var receiptSharing ReceiptSharing
+
+// SetReceiptSharing sets how receipts are shared with groups.
+func SetReceiptSharing(sharing ReceiptSharing) {
+	receiptSharing = sharing
+}
+
+// AssignFileToGroup records one of the user's receipts as an expense they
+// paid in one of their groups, shared equally by sharedBy or by the whole
+// group, and splits the receipt's products so the other members' shares no
+// longer count as the user's spend. Any splits the receipt had are
+// replaced. Group 0 takes the receipt out of its group and makes its
+// products whole again; the returned expense is then nil.
+func AssignFileToGroup(ctx context.Context, userID string, fileID, groupID int32, sharedBy []int32) (*GroupExpense, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var fileName string
+	var uploadDate time.Time
+	err = tx.QueryRow(ctx, `
+        SELECT file_name, upload_date FROM file_management_service.file_metadata
+        WHERE user_id = $1 AND file_id = $2 FOR UPDATE`,
+		userIDInt, fileID).Scan(&fileName, &uploadDate)
+	if err == pgx.ErrNoRows {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch file %d: %v", fileID, err)
+	}
+
+	if groupID == 0 {
+		removed, err := receiptSharing.UnassignReceipt(ctx, tx, userIDInt, fileID)
+		if err != nil {
+			return nil, err
+		}
+		// Splits the user made themselves are only dropped when they came
+		// from the group.
+		if removed {
+			if err := receiptSharing.SplitReceipt(ctx, tx, userIDInt, fileName, nil); err != nil {
+				return nil, err
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit group assignment: %v", err)
+		}
+		return nil, nil
+	}
+
+	var total float64
+	var purchased *time.Time
+	err = tx.QueryRow(ctx, `
+        SELECT COALESCE(SUM(quantity * price), 0)::float8, MIN(date_added)
+        FROM product_category_service.products
+        WHERE user_id = $1 AND file_name = $2`,
+		userIDInt, fileName).Scan(&total, &purchased)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total receipt: %v", err)
+	}
+	amount := int64(math.Round(total * 100))
+	if amount <= 0 {
+		return nil, ErrReceiptEmpty
+	}
+	date := uploadDate
+	if purchased != nil {
+		date = *purchased
+	}
+
+	expense, err := receiptSharing.AssignReceipt(ctx, tx, userIDInt, groupID, fileID, fileName, amount, date, sharedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each member's share becomes a part of every product on the receipt;
+	// the user's own part stays theirs.
+	percents := map[int32]float64{}
+	shared := false
+	for person, share := range expense.Shares {
+		if share == 0 {
+			continue
+		}
+		percents[person] = float64(share) * 100 / float64(expense.Amount)
+		if person != userIDInt {
+			shared = true
+		}
+	}
+	if !shared {
+		percents = nil
+	}
+	if err := receiptSharing.SplitReceipt(ctx, tx, userIDInt, fileName, percents); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit group assignment: %v", err)
+	}
+	return expense, nil
+}
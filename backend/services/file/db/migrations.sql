@@ -0,0 +1,67 @@
+-- Schema changes for file_management_service, applied in order on top of the
+-- existing tables.
+
+-- Exact MinIO object key of each upload, so readers never have to guess it
+-- from the file name.
+ALTER TABLE file_management_service.file_metadata
+    ADD COLUMN IF NOT EXISTS object_key VARCHAR(512);
+
+-- Rows uploaded before object_key existed stored the full object URL in
+-- image_url as http://<endpoint>/<bucket>/<object key>.
+UPDATE file_management_service.file_metadata
+SET object_key = regexp_replace(image_url, '^https?://[^/]+/[^/]+/', '')
+WHERE object_key IS NULL AND image_url IS NOT NULL AND image_url <> '';
+
+CREATE INDEX IF NOT EXISTS idx_file_metadata_user_name
+    ON file_management_service.file_metadata (user_id, file_name);
+
+-- Downscaled copies for the gallery and the dimensions of the stored image.
+-- Rows without variants are filled in by running the file service with
+-- -backfill-thumbnails.
+ALTER TABLE file_management_service.file_metadata
+    ADD COLUMN IF NOT EXISTS thumbnail_key VARCHAR(512),
+    ADD COLUMN IF NOT EXISTS preview_key VARCHAR(512),
+    ADD COLUMN IF NOT EXISTS image_width INT,
+    ADD COLUMN IF NOT EXISTS image_height INT;
+
+-- Upload validation: stored size (original plus variants) and sniffed content
+-- type of each file, counted against the per-user quota.
+ALTER TABLE file_management_service.file_metadata
+    ADD COLUMN IF NOT EXISTS size_bytes BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS content_type VARCHAR(100);
+
+-- Per-user overrides of the UPLOAD_* defaults; NULL keeps the default.
+CREATE TABLE IF NOT EXISTS file_management_service.storage_quotas (
+    user_id INT PRIMARY KEY,
+    max_file_bytes BIGINT,
+    quota_bytes BIGINT,
+    quota_files BIGINT
+);
+
+-- Gallery paging: keyset order by date and trigram matching for name search.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_file_metadata_user_date
+    ON file_management_service.file_metadata (user_id, upload_date DESC, file_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_file_metadata_name_trgm
+    ON file_management_service.file_metadata USING gin (file_name gin_trgm_ops);
+
+-- Products, receipt text and annotations refer to their receipt by
+-- (user_id, file_name), so names are unique per user. Later uploads that
+-- reused a name get their file_id appended; the products recorded under the
+-- name stay with the first upload, as they cannot be told apart.
+UPDATE file_management_service.file_metadata f
+SET file_name = regexp_replace(f.file_name, '(\.[^.]*)?$', ' (' || f.file_id || ')\1')
+WHERE EXISTS (
+    SELECT 1 FROM file_management_service.file_metadata o
+    WHERE o.user_id = f.user_id AND o.file_name = f.file_name AND o.file_id < f.file_id);
+
+DROP INDEX IF EXISTS file_management_service.idx_file_metadata_user_name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_file_metadata_user_name
+    ON file_management_service.file_metadata (user_id, file_name);
+
+-- Uploads made before size_bytes existed were stored with 0 and so never
+-- counted against the quota. Their sizes live only in object storage; the
+-- file service reads them from there on start (BackfillStorageSizes).
@@ -0,0 +1,285 @@
+// Package encryption implements optional client-side envelope encryption for
+// stored receipts. Each user gets a random data key; data keys are wrapped
+// with a master key and kept in a local keyring file, so everything works
+// offline. SSE-C was not used because MinIO only accepts it over TLS and a
+// browser cannot attach the key headers to a presigned URL.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sealedMagic prefixes every encrypted object so plaintext objects written
+// before encryption was enabled can still be read.
+var sealedMagic = []byte("EMENC1\x00")
+
+var ErrNoDataKey = errors.New("no data key for user")
+
+// Keyring holds the master key and the wrapped per-user data keys.
+type Keyring struct {
+	masterKey []byte
+	path      string
+
+	mu       sync.Mutex
+	wrapped  map[string]string
+	dataKeys map[int64][]byte
+}
+
+type keyringFile struct {
+	Users map[string]string `json:"users"`
+}
+
+// KeyringInstance is nil when encryption is disabled.
+var KeyringInstance *Keyring
+
+// InitKeyring enables encryption when FILE_ENCRYPTION_MASTER_KEY_FILE is set.
+// The master key file holds 32 base64-encoded bytes and is created on first
+// use; the keyring defaults to keyring.json next to it. Losing either file
+// makes encrypted objects unreadable, so both belong in backups.
+func InitKeyring() error {
+	masterPath := os.Getenv("FILE_ENCRYPTION_MASTER_KEY_FILE")
+	if masterPath == "" {
+		log.Print("Object encryption disabled (FILE_ENCRYPTION_MASTER_KEY_FILE not set)")
+		return nil
+	}
+
+	keyringPath := os.Getenv("FILE_ENCRYPTION_KEYRING_FILE")
+	if keyringPath == "" {
+		keyringPath = filepath.Join(filepath.Dir(masterPath), "keyring.json")
+	}
+
+	keyring, err := LoadKeyring(masterPath, keyringPath)
+	if err != nil {
+		return err
+	}
+	KeyringInstance = keyring
+	log.Printf("Object encryption enabled, keyring at %s", keyringPath)
+	return nil
+}
+
+// LoadKeyring reads (or creates) the master key and loads the keyring file.
+func LoadKeyring(masterPath, keyringPath string) (*Keyring, error) {
+	masterKey, err := loadOrCreateMasterKey(masterPath)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Keyring{
+		masterKey: masterKey,
+		path:      keyringPath,
+		wrapped:   make(map[string]string),
+		dataKeys:  make(map[int64][]byte),
+	}
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func loadOrCreateMasterKey(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate master key: %v", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create key directory: %v", err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(key)
+		if err := os.WriteFile(path, []byte(encoded+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write master key: %v", err)
+		}
+		log.Printf("Generated new master key at %s", path)
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key: %v", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("master key in %s must be 32 base64-encoded bytes", path)
+	}
+	return key, nil
+}
+
+// reload re-reads the keyring file. The file service is the only writer; other
+// processes reload when they meet a user they have no key for yet.
+func (k *Keyring) reload() error {
+	raw, err := os.ReadFile(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read keyring: %v", err)
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return fmt.Errorf("failed to parse keyring: %v", err)
+	}
+	for user, wrapped := range file.Users {
+		k.wrapped[user] = wrapped
+	}
+	return nil
+}
+
+func (k *Keyring) save() error {
+	raw, err := json.MarshalIndent(keyringFile{Users: k.wrapped}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := k.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write keyring: %v", err)
+	}
+	return os.Rename(tmp, k.path)
+}
+
+// dataKey returns the user's data key, generating and persisting one if create
+// is set and none exists.
+func (k *Keyring) dataKey(userID int64, create bool) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, ok := k.dataKeys[userID]; ok {
+		return key, nil
+	}
+
+	user := strconv.FormatInt(userID, 10)
+	if _, ok := k.wrapped[user]; !ok {
+		if err := k.reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	if wrapped, ok := k.wrapped[user]; ok {
+		sealed, err := base64.StdEncoding.DecodeString(wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("corrupt data key for user %d: %v", userID, err)
+		}
+		key, err := open(k.masterKey, sealed, []byte("user:"+user))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap data key for user %d: %v", userID, err)
+		}
+		k.dataKeys[userID] = key
+		return key, nil
+	}
+
+	if !create {
+		return nil, fmt.Errorf("%w %d", ErrNoDataKey, userID)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %v", err)
+	}
+	sealed, err := seal(k.masterKey, key, []byte("user:"+user))
+	if err != nil {
+		return nil, err
+	}
+	k.wrapped[user] = base64.StdEncoding.EncodeToString(sealed)
+	if err := k.save(); err != nil {
+		delete(k.wrapped, user)
+		return nil, err
+	}
+	k.dataKeys[userID] = key
+	return key, nil
+}
+
+// Seal encrypts an object with the user's data key. The object key is bound
+// as associated data so ciphertexts cannot be swapped between objects.
+func (k *Keyring) Seal(userID int64, objectKey string, plaintext []byte) ([]byte, error) {
+	key, err := k.dataKey(userID, true)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := seal(key, plaintext, []byte(objectKey))
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), sealedMagic...), sealed...), nil
+}
+
+// Open decrypts an object written by Seal. Objects without the encryption
+// header are returned unchanged.
+func (k *Keyring) Open(userID int64, objectKey string, data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	key, err := k.dataKey(userID, false)
+	if err != nil {
+		return nil, err
+	}
+	return open(key, data[len(sealedMagic):], []byte(objectKey))
+}
+
+// IsSealed reports whether data was produced by Seal.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, sealedMagic)
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %v", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ContentURL is where clients fetch an encrypted file: the API gateway
+// decrypts it through the file service, since MinIO only holds ciphertext.
+func ContentURL(fileID int32, variant string) string {
+	base := os.Getenv("FILE_CONTENT_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8081/files"
+	}
+	url := fmt.Sprintf("%s/%d/content", strings.TrimSuffix(base, "/"), fileID)
+	if variant != "" {
+		url += "?variant=" + variant
+	}
+	return url
+}
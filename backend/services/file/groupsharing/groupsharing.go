@@ -0,0 +1,71 @@
+// Package groupsharing shares receipts with expense groups for the file
+// service, by recording them as group expenses in the user service's tables
+// and splitting their products in the product service's.
+package groupsharing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/splitting"
+	userDB "github.com/Aneesh-Hegde/expenseManager/services/user/db"
+	"github.com/jackc/pgx/v4"
+)
+
+// Receipts implements fileDB.ReceiptSharing.
+type Receipts struct{}
+
+var _ fileDB.ReceiptSharing = Receipts{}
+
+func (Receipts) AssignReceipt(ctx context.Context, tx pgx.Tx, userID, groupID, fileID int32, fileName string,
+	amount int64, date time.Time, sharedBy []int32) (*fileDB.GroupExpense, error) {
+	expense, err := userDB.AssignReceipt(ctx, tx, userID, groupID, fileID, fileName, amount, date, sharedBy)
+	if err != nil {
+		return nil, sharingError(err)
+	}
+	return &fileDB.GroupExpense{GroupID: expense.GroupID, Amount: expense.Amount, Shares: expense.Shares}, nil
+}
+
+func (Receipts) UnassignReceipt(ctx context.Context, tx pgx.Tx, userID, fileID int32) (bool, error) {
+	return userDB.UnassignReceipt(ctx, tx, userID, fileID)
+}
+
+func (Receipts) SplitReceipt(ctx context.Context, tx pgx.Tx, userID int32, fileName string, percents map[int32]float64) error {
+	people := make([]int32, 0, len(percents))
+	for person := range percents {
+		people = append(people, person)
+	}
+	sort.Slice(people, func(i, j int) bool { return people[i] < people[j] })
+
+	var allocations []splitting.Allocation
+	for _, person := range people {
+		percent := percents[person]
+		allocation := splitting.Allocation{Percent: &percent}
+		if person != userID {
+			member := person
+			allocation.ParticipantUserID = &member
+		}
+		allocations = append(allocations, allocation)
+	}
+	if _, err := productDB.SplitReceiptProducts(ctx, tx, userID, fileName, allocations); err != nil {
+		return sharingError(err)
+	}
+	return nil
+}
+
+// sharingError wraps the errors callers can act on in the file service's.
+func sharingError(err error) error {
+	switch {
+	case errors.Is(err, userDB.ErrGroupNotFound):
+		return fmt.Errorf("%w: %v", fileDB.ErrGroupNotFound, err)
+	case errors.Is(err, userDB.ErrNotGroupMember), errors.Is(err, userDB.ErrInvalidExpense),
+		errors.Is(err, productDB.ErrInvalidSplit):
+		return fmt.Errorf("%w: %v", fileDB.ErrInvalidShare, err)
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
@@ -15,6 +16,8 @@ import (
 	grpcMiddlware "github.com/Aneesh-Hegde/expenseManager/middleware"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
 	"github.com/Aneesh-Hegde/expenseManager/services/file/data"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/groupsharing"
 	"github.com/Aneesh-Hegde/expenseManager/services/file/utils"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
 	"github.com/joho/godotenv"
@@ -79,6 +82,30 @@ func (s *FileService) UploadFile(ctx context.Context, req *files.UploadFileReque
 	return s.fileLogic.UploadFile(ctx, req)
 }
 
+func (s *FileService) DeleteFile(ctx context.Context, req *files.DeleteFileRequest) (*files.FileActionResponse, error) {
+	return data.DeleteFile(ctx, req)
+}
+
+func (s *FileService) RenameFile(ctx context.Context, req *files.RenameFileRequest) (*files.FileActionResponse, error) {
+	return data.RenameFile(ctx, req)
+}
+
+func (s *FileService) ResetFileExtraction(ctx context.Context, req *files.ResetFileExtractionRequest) (*files.FileActionResponse, error) {
+	return data.ResetFileExtraction(ctx, req)
+}
+
+func (s *FileService) GetStorageUsage(ctx context.Context, req *files.GetStorageUsageRequest) (*files.StorageUsage, error) {
+	return data.GetStorageUsage(ctx, req)
+}
+
+func (s *FileService) GetFileContent(ctx context.Context, req *files.GetFileContentRequest) (*files.FileContent, error) {
+	return data.GetFileContent(ctx, req)
+}
+
+func (s *FileService) AssignFileToGroup(ctx context.Context, req *files.AssignFileToGroupRequest) (*files.FileActionResponse, error) {
+	return data.AssignFileToGroup(ctx, req)
+}
+
 // Authentication interceptor - all endpoints require authentication
 func authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	fmt.Println(info.FullMethod)
@@ -194,16 +221,66 @@ func startMetricsCleaner() {
 	}()
 }
 
+// orphanCleanupInterval is how often objects left behind by deleted files
+// are removed.
+const orphanCleanupInterval = 6 * time.Hour
+
+// Periodically removes stored objects no file row names, such as those of
+// deleted files whose removal failed.
+func startOrphanCleanup() {
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+		if err := data.CleanupAllOrphanedFiles(ctx); err != nil {
+			log.Printf("Orphaned file cleanup failed: %v", err)
+		}
+	}
+
+	ticker := time.NewTicker(orphanCleanupInterval)
+	go func() {
+		defer ticker.Stop()
+		run()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
+
+// runVariantBackfill is a one-off job for uploads made before thumbnails existed.
+func runVariantBackfill() {
+	if err := utils.InitMinIO(); err != nil {
+		log.Fatalf("Failed to initialize MinIO: %v", err)
+	}
+	sharedDB.InitDB()
+	defer sharedDB.CloseDB()
+
+	if err := utils.BackfillImageVariants(context.Background(), 50); err != nil {
+		log.Fatalf("Thumbnail backfill failed: %v", err)
+	}
+}
+
 func main() {
+	backfillVariants := flag.Bool("backfill-thumbnails", false, "generate thumbnails and previews for existing uploads, then exit")
+	flag.Parse()
+
 	if err := godotenv.Load(".env-dev"); err != nil {
 		log.Printf("Error loading .env-dev file: %v", err)
 	}
 
+	if *backfillVariants {
+		runVariantBackfill()
+		return
+	}
+
 	startMetricServer()
 
 	if err := utils.InitMinIO(); err != nil {
 		log.Fatalf("Failed to initialize MinIO: %v", err)
 	}
+	// Listing, presigning and deletes go through the data package's own client
+	if err := data.InitMinIOForFiles("localhost:9000", "minioadmin", "minioadmin", false); err != nil {
+		log.Fatalf("Failed to initialize MinIO for files: %v", err)
+	}
 	newFileLogic := utils.NewFileServiceServer()
 	fileService := &FileService{
 		fileLogic: newFileLogic,
@@ -212,9 +289,19 @@ func main() {
 	// Initialize database - will auto-reconnect when needed
 	sharedDB.InitDB()
 	redis.InitRedis()
+	fileDB.SetReceiptSharing(groupsharing.Receipts{})
 
 	// Start background health monitoring
 	startDBHealthMonitor()
+	startOrphanCleanup()
+
+	// Uploads from before sizes were tracked are stored with size 0 and
+	// would never count against the quota.
+	go func() {
+		if err := utils.BackfillStorageSizes(context.Background(), 100); err != nil {
+			log.Printf("Storage size backfill failed: %v", err)
+		}
+	}()
 
 	listener, err := net.Listen("tcp", ":50053")
 	if err != nil {
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/file/validation"
+)
+
+// Phone photos carry EXIF with GPS coordinates and device details. Uploads are
+// decoded and re-encoded by OpenCV, which already drops them (IMRead applies
+// the EXIF orientation first), but stripMetadata runs on every stored image so
+// no encoder or future pass-through path can leak them.
+func stripMetadata(contentType string, data []byte) []byte {
+	switch contentType {
+	case validation.ContentTypeJPEG:
+		return stripJPEGMetadata(data)
+	case validation.ContentTypePNG:
+		return stripPNGMetadata(data)
+	}
+	return data
+}
+
+// stripJPEGMetadata drops APP1 (EXIF/XMP), APP13 (IPTC) and comment segments.
+// Anything it cannot parse is returned untouched.
+func stripJPEGMetadata(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return data
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return data
+		}
+		marker := data[pos+1]
+		if marker == 0xDA {
+			// Start of scan: entropy-coded data follows to the end.
+			out.Write(data[pos:])
+			return out.Bytes()
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return data
+		}
+		if marker != 0xE1 && marker != 0xED && marker != 0xFE {
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+	return data
+}
+
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"iTXt": true,
+	"zTXt": true,
+	"tIME": true,
+}
+
+// stripPNGMetadata drops the EXIF, text and timestamp chunks.
+func stripPNGMetadata(data []byte) []byte {
+	const signatureLen = 8
+	if len(data) < signatureLen {
+		return data
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:signatureLen])
+	pos := signatureLen
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return data
+		}
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return data
+		}
+		if !pngMetadataChunks[string(data[pos+4:pos+8])] {
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+	return out.Bytes()
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -11,12 +12,16 @@ import (
 	"sync"
 	"time"
 
-	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
 	pb "github.com/Aneesh-Hegde/expenseManager/grpc_file"
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/encryption"
+	"github.com/Aneesh-Hegde/expenseManager/services/file/validation"
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"gocv.io/x/gocv"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MinIOClient struct {
@@ -35,9 +40,15 @@ type FileServiceServer struct {
 }
 
 type fileUploadState struct {
+	userId         int64
 	filePath       string
 	totalChunks    int
 	receivedChunks map[int]bool
+	bytesReceived  int64
+	contentType    string
+	limits         validation.Limits
+	// usage is what the user had stored when the upload started.
+	usage validation.Usage
 }
 
 var MinIOClientInstance *MinIOClient
@@ -70,6 +81,10 @@ func InitMinIO() error {
 		Endpoint:   endpoint,
 	}
 
+	if err := encryption.InitKeyring(); err != nil {
+		return fmt.Errorf("failed to load encryption keyring: %v", err)
+	}
+
 	log.Print("Testing MinIO connection...")
 	err = MinIOClientInstance.createBucketIfNotExists()
 	if err != nil {
@@ -117,37 +132,85 @@ func (m *MinIOClient) createBucketIfNotExists() error {
 			return fmt.Errorf("failed to create Bucket: %v", err)
 		}
 		log.Printf("Bucket '%s' created successfully", m.BucketName)
-
-		policy := fmt.Sprintf(`{
-			"Version": "2012-10-17",
-			"Statement": [
-				{
-					"Effect": "Allow",
-					"Principal": {"AWS": ["*"]},
-					"Action": ["s3:GetObject"],
-					"Resource": ["arn:aws:s3:::%s/*"]
-				}
-			]
-		}`, m.BucketName)
-
-		err = m.Client.SetBucketPolicy(ctx, m.BucketName, policy)
-		if err != nil {
-			log.Printf("Warning: Could not set Bucket policy: %v", err)
-		} else {
-			log.Print("Bucket policy set successfully")
-		}
 	} else {
 		log.Printf("Bucket '%s' already exists", m.BucketName)
 	}
+
+	// Receipts are only reachable through presigned URLs. Earlier versions
+	// installed a public-read policy, so remove whatever policy is left over.
+	policy, err := m.Client.GetBucketPolicy(ctx, m.BucketName)
+	if err != nil {
+		log.Printf("Warning: Could not read Bucket policy: %v", err)
+	} else if policy != "" {
+		if err := m.Client.SetBucketPolicy(ctx, m.BucketName, ""); err != nil {
+			return fmt.Errorf("failed to remove public Bucket policy: %v", err)
+		}
+		log.Printf("Removed Bucket policy from '%s'", m.BucketName)
+	}
 	return nil
 }
 
-func (m *MinIOClient) uploadProcessedImage(imageData []byte, filename string, userId int64) (string, error) {
+// putObject stores data for a user, sealing it with the user's data key when
+// encryption is enabled.
+func (m *MinIOClient) putObject(ctx context.Context, userId int64, objectKey string, data []byte, opts minio.PutObjectOptions) error {
+	if encryption.KeyringInstance != nil {
+		sealed, err := encryption.KeyringInstance.Seal(userId, objectKey, data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %v", objectKey, err)
+		}
+		data = sealed
+		opts.UserMetadata = map[string]string{"encryption": "envelope-v1"}
+	}
+	_, err := m.Client.PutObject(ctx, m.BucketName, objectKey, bytes.NewReader(data), int64(len(data)), opts)
+	return err
+}
+
+// ReadObject returns the plaintext of a stored object, decrypting it if it was
+// written with encryption enabled.
+func (m *MinIOClient) ReadObject(ctx context.Context, userId int64, objectKey string) ([]byte, error) {
+	obj, err := m.Client.GetObject(ctx, m.BucketName, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	var data bytes.Buffer
+	if _, err := data.ReadFrom(obj); err != nil {
+		return nil, err
+	}
+
+	if !encryption.IsSealed(data.Bytes()) {
+		return data.Bytes(), nil
+	}
+	if encryption.KeyringInstance == nil {
+		return nil, fmt.Errorf("object %s is encrypted but no keyring is configured", objectKey)
+	}
+	return encryption.KeyringInstance.Open(userId, objectKey, data.Bytes())
+}
+
+// accessURL is the URL returned to clients for a freshly stored file.
+func (m *MinIOClient) accessURL(ctx context.Context, fileID int32, objectKey string) string {
+	if encryption.KeyringInstance != nil {
+		return encryption.ContentURL(fileID, "")
+	}
+	presignedURL, err := m.Client.PresignedGetObject(ctx, m.BucketName, objectKey, 24*time.Hour, nil)
+	if err != nil {
+		log.Printf("Warning: Could not presign %s: %v", objectKey, err)
+		return ""
+	}
+	return presignedURL.String()
+}
+
+// uploadProcessedImage stores the image under a unique key in the user's folder
+// and returns its storage URL together with the object key that was used. The
+// key's extension follows the sniffed content type, not the name the user gave.
+// The storage URL is not publicly readable; clients get presigned URLs.
+func (m *MinIOClient) uploadProcessedImage(imageData []byte, filename string, contentType string, userId int64) (string, string, error) {
 	ctx := context.Background()
 
-	ext := filepath.Ext(filename)
+	ext := validation.Extension(contentType)
 	uniqueFilename := fmt.Sprintf("%s_%s%s",
-		strings.TrimSuffix(filename, ext),
+		strings.TrimSuffix(filename, filepath.Ext(filename)),
 		uuid.New().String()[:8],
 		ext)
 	
@@ -155,18 +218,17 @@ func (m *MinIOClient) uploadProcessedImage(imageData []byte, filename string, us
 
 	log.Printf("Uploading to MinIO - Object: %s", objectName)
 
-	reader := bytes.NewReader(imageData)
-	_, err := m.Client.PutObject(ctx, m.BucketName, objectName, reader, int64(len(imageData)), minio.PutObjectOptions{
-		ContentType: getContentType(ext),
+	err := m.putObject(ctx, userId, objectName, stripMetadata(contentType, imageData), minio.PutObjectOptions{
+		ContentType: contentType,
 	})
 	if err != nil {
 		log.Printf("ERROR: Failed to upload to MinIO: %v", err)
-		return "", err
+		return "", "", err
 	}
 
 	imageURL := fmt.Sprintf("http://%s/%s/%s", m.Endpoint, m.BucketName, objectName)
 	log.Printf("Image uploaded successfully to user folder: %s", imageURL)
-	return imageURL, nil
+	return imageURL, objectName, nil
 }
 
 func getContentType(ext string) string {
@@ -201,24 +263,32 @@ func (s *FileServiceServer) UploadFile(ctx context.Context, req *pb.UploadFileRe
 	
 	s.mu.Lock()
 	state, exists := s.uploadStates[fileKey]
+	s.mu.Unlock()
 	if !exists {
-		userUploadDir := filepath.Join(s.tempUploadDir, fmt.Sprintf("user_%d", userId))
-		if err := os.MkdirAll(userUploadDir, os.ModePerm); err != nil {
-			s.mu.Unlock()
-			log.Printf("ERROR: Error creating user upload directory %s: %v", userUploadDir, err)
-			return &pb.UploadFileResponse{
-				Success: false,
-				Message: fmt.Sprintf("Error creating user upload directory: %v", err),
-			}, nil
-		}
-		state = &fileUploadState{
-			filePath:       filepath.Join(userUploadDir, fmt.Sprintf("temp_%s", filename)),
-			totalChunks:    totalChunks,
-			receivedChunks: make(map[int]bool),
+		// The first chunk decides whether the upload is accepted at all: it must
+		// start with an allowed signature and the user must have room left.
+		newState, err := s.startUpload(ctx, userId, filename, chunkNumber, totalChunks, chunkData)
+		if err != nil {
+			return nil, err
 		}
-		s.uploadStates[fileKey] = state
+		s.mu.Lock()
+		s.uploadStates[fileKey] = newState
+		s.mu.Unlock()
+		state = newState
 	}
+
+	s.mu.Lock()
+	state.bytesReceived += int64(len(chunkData))
+	receivedBytes := state.bytesReceived
 	s.mu.Unlock()
+	if err := validation.CheckFileSize(receivedBytes, state.limits); err != nil {
+		s.abortUpload(fileKey, state)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.checkQuotaInFlight(state); err != nil {
+		s.abortUpload(fileKey, state)
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 
 	outFile, err := os.OpenFile(state.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err != nil {
@@ -253,90 +323,58 @@ func (s *FileServiceServer) UploadFile(ctx context.Context, req *pb.UploadFileRe
 	}
 	s.mu.Unlock()
 
-	var imageURL string
 	if allChunksReceived {
-		log.Printf("All chunks received for %s (user %d), processing image...", filename, userId)
+		defer os.Remove(state.filePath)
 
-		img := gocv.IMRead(state.filePath, gocv.IMReadColor)
-		if img.Empty() {
-			log.Printf("ERROR: Could not read image file from temporary path: %s", state.filePath)
-			os.Remove(state.filePath)
-			return &pb.UploadFileResponse{
-				Success: false,
-				Message: "Could not process image file",
-			}, nil
-		}
-		defer img.Close()
-		
-		log.Printf("Image loaded successfully, converting to grayscale...")
-
-		gray := gocv.NewMat()
-		defer gray.Close()
-		gocv.CvtColor(img, &gray, gocv.ColorBGRToGray)
-
-		processedImagePath := filepath.Join(filepath.Dir(state.filePath), fmt.Sprintf("processed_%s", filename))
-		if ok := gocv.IMWrite(processedImagePath, gray); !ok {
-			log.Printf("ERROR: Could not save processed image to: %s", processedImagePath)
-			os.Remove(state.filePath)
-			return &pb.UploadFileResponse{
-				Success: false,
-				Message: "Could not save processed image",
-			}, nil
-		}
-		
-		log.Printf("Processed image saved successfully")
+		log.Printf("All chunks received for %s (user %d), processing %s...", filename, userId, state.contentType)
 
-		processedImageData, err := os.ReadFile(processedImagePath)
+		stored, err := s.storeUpload(ctx, state, filename, userId)
 		if err != nil {
-			log.Printf("ERROR: Error reading processed image: %v", err)
-			os.Remove(state.filePath)
-			os.Remove(processedImagePath)
+			log.Printf("ERROR: %v", err)
 			return &pb.UploadFileResponse{
 				Success: false,
-				Message: fmt.Sprintf("Could not read processed image: %v", err),
+				Message: err.Error(),
 			}, nil
 		}
-		
-		log.Printf("Processed image data read successfully, size: %d bytes", len(processedImageData))
 
-		imageURL, err = s.minioClient.uploadProcessedImage(processedImageData, filename, userId)
-		if err != nil {
-			log.Printf("ERROR: Error uploading to MinIO: %v", err)
-			os.Remove(state.filePath)
-			os.Remove(processedImagePath)
-			return &pb.UploadFileResponse{
-				Success: false,
-				Message: fmt.Sprintf("Error uploading to MinIO: %v", err),
-			}, nil
-		}
-
-		log.Printf("Image uploaded to MinIO successfully: %s", imageURL)
-
-		fmt.Println("Database: ",sharedDB.GetDB())
-		query := "INSERT INTO file_management_service.file_metadata (user_id, file_name, image_url, upload_date) VALUES ($1, $2, $3, $4)"
-		_, err =sharedDB.GetDB().Query(context.Background(), query, userId, filename, imageURL, time.Now())
+		// The quota is checked again against what was actually stored, variants
+		// included, as other uploads may have completed in the meantime.
+		fileID, err := fileDB.InsertFileWithinQuota(ctx, fileDB.FileMetadata{
+			UserID:       int32(userId),
+			FileName:     filename,
+			ImageURL:     &stored.imageURL,
+			ObjectKey:    &stored.objectKey,
+			UploadDate:   time.Now(),
+			ThumbnailKey: stored.thumbnailKey,
+			PreviewKey:   stored.previewKey,
+			Width:        toInt32(stored.width),
+			Height:       toInt32(stored.height),
+			SizeBytes:    stored.sizeBytes,
+			ContentType:  &state.contentType,
+		}, state.limits)
 		if err != nil {
+			// Without a metadata row nothing refers to the objects.
+			s.minioClient.removeStored(ctx, stored)
+			if errors.Is(err, fileDB.ErrFileNameTaken) {
+				return nil, status.Error(codes.AlreadyExists, err.Error())
+			}
+			if errors.Is(err, validation.ErrQuotaExceeded) {
+				return nil, status.Error(codes.ResourceExhausted, err.Error())
+			}
 			log.Printf("ERROR: Error storing file metadata: %v", err)
-		} else {
-			log.Printf("File metadata uploaded to database successfully")
+			return nil, status.Error(codes.Internal, err.Error())
 		}
+		log.Printf("File metadata uploaded to database successfully")
 
-		log.Printf("Cleaning up temporary files...")
-		if err := os.Remove(state.filePath); err != nil {
-			log.Printf("Warning: Could not remove temp file %s: %v", state.filePath, err)
-		}
-		if err := os.Remove(processedImagePath); err != nil {
-			log.Printf("Warning: Could not remove processed file %s: %v", processedImagePath, err)
-		}
-		
 		log.Printf("Upload process completed successfully for user %d!", userId)
 
 		return &pb.UploadFileResponse{
 			Success:     true,
 			Message:     "File uploaded and processed successfully",
-			ImageUrl:    imageURL,
+			ImageUrl:    s.minioClient.accessURL(ctx, fileID, stored.objectKey),
 			UserId:      userId,
 			ChunkStatus: fmt.Sprintf("%d/%d", chunkNumber, totalChunks),
+			FileId:      fileID,
 		}, nil
 
 	}
@@ -349,3 +387,193 @@ func (s *FileServiceServer) UploadFile(ctx context.Context, req *pb.UploadFileRe
 		ChunkStatus: fmt.Sprintf("%d/%d", chunkNumber, totalChunks),
 	}, nil
 }
+
+// startUpload validates the opening chunk of a new upload and prepares its
+// temporary file.
+func (s *FileServiceServer) startUpload(ctx context.Context, userId int64, filename string, chunkNumber, totalChunks int, chunkData []byte) (*fileUploadState, error) {
+	if chunkNumber != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "upload of %s must start with chunk 1, got %d", filename, chunkNumber)
+	}
+	if totalChunks < 1 {
+		return nil, status.Error(codes.InvalidArgument, "total_chunks must be at least 1")
+	}
+
+	// Products and receipt text refer to their receipt by name, so a second
+	// upload under the same name would be mixed up with the first.
+	taken, err := fileDB.FileNameTaken(ctx, userId, filename)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if taken {
+		return nil, status.Error(codes.AlreadyExists, fileDB.ErrFileNameTaken.Error())
+	}
+
+	contentType, ok := validation.SniffContentType(chunkData)
+	if !ok {
+		log.Printf("Rejected upload %s for user %d: unrecognised file signature", filename, userId)
+		return nil, status.Error(codes.InvalidArgument, validation.ErrUnsupportedType.Error())
+	}
+
+	limits, err := fileDB.GetUserLimits(ctx, userId, validation.DefaultLimits())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	usage, err := fileDB.GetStorageUsage(ctx, userId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	userUploadDir := filepath.Join(s.tempUploadDir, fmt.Sprintf("user_%d", userId))
+	if err := os.MkdirAll(userUploadDir, os.ModePerm); err != nil {
+		log.Printf("ERROR: Error creating user upload directory %s: %v", userUploadDir, err)
+		return nil, status.Errorf(codes.Internal, "error creating user upload directory: %v", err)
+	}
+
+	return &fileUploadState{
+		userId:         userId,
+		filePath:       filepath.Join(userUploadDir, fmt.Sprintf("temp_%s", filepath.Base(filename))),
+		totalChunks:    totalChunks,
+		receivedChunks: make(map[int]bool),
+		contentType:    contentType,
+		limits:         limits,
+		usage:          usage,
+	}, nil
+}
+
+// checkQuotaInFlight checks an upload's bytes so far against the user's
+// quota, counting the usage they had when it started and every other upload
+// of theirs still in progress. Chunks carry no total size, so an upload that
+// cannot fit is turned away as soon as its chunks pass what is left.
+func (s *FileServiceServer) checkQuotaInFlight(state *fileUploadState) error {
+	s.mu.Lock()
+	usage := state.usage
+	for _, other := range s.uploadStates {
+		if other != state && other.userId == state.userId {
+			usage.BytesUsed += other.bytesReceived
+			usage.FileCount++
+		}
+	}
+	incoming := state.bytesReceived
+	s.mu.Unlock()
+	return validation.CheckQuota(usage, state.limits, incoming)
+}
+
+func (s *FileServiceServer) abortUpload(fileKey string, state *fileUploadState) {
+	s.mu.Lock()
+	delete(s.uploadStates, fileKey)
+	s.mu.Unlock()
+	os.Remove(state.filePath)
+}
+
+// removeStored deletes the objects of an upload that could not be recorded.
+func (m *MinIOClient) removeStored(ctx context.Context, stored *storedUpload) {
+	keys := []string{stored.objectKey}
+	for _, key := range []*string{stored.thumbnailKey, stored.previewKey} {
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	for _, key := range keys {
+		if err := m.Client.RemoveObject(ctx, m.BucketName, key, minio.RemoveObjectOptions{}); err != nil {
+			log.Printf("Warning: could not remove %s: %v", key, err)
+		}
+	}
+}
+
+func toInt32(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+type storedUpload struct {
+	imageURL     string
+	objectKey    string
+	thumbnailKey *string
+	previewKey   *string
+	width        *int
+	height       *int
+	sizeBytes    int64
+}
+
+// storeUpload turns a fully received temp file into a stored object. Images
+// are converted to grayscale and get gallery variants; PDFs are kept as-is.
+func (s *FileServiceServer) storeUpload(ctx context.Context, state *fileUploadState, filename string, userId int64) (*storedUpload, error) {
+	if state.contentType == validation.ContentTypePDF {
+		// PDFs are not image-processed, so they never carried camera EXIF;
+		// their document info is kept as uploaded.
+		data, err := os.ReadFile(state.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read uploaded document: %v", err)
+		}
+		imageURL, objectKey, err := s.minioClient.uploadProcessedImage(data, filename, state.contentType, userId)
+		if err != nil {
+			return nil, fmt.Errorf("error uploading to MinIO: %v", err)
+		}
+		return &storedUpload{imageURL: imageURL, objectKey: objectKey, sizeBytes: int64(len(data))}, nil
+	}
+
+	img := gocv.IMRead(state.filePath, gocv.IMReadColor)
+	if img.Empty() {
+		return nil, fmt.Errorf("could not process image file")
+	}
+	defer img.Close()
+	
+	log.Printf("Image loaded successfully, converting to grayscale...")
+
+	gray := gocv.NewMat()
+	defer gray.Close()
+	gocv.CvtColor(img, &gray, gocv.ColorBGRToGray)
+
+	// OpenCV cannot write GIFs, so those are stored as PNG after processing.
+	contentType := state.contentType
+	if contentType == validation.ContentTypeGIF {
+		contentType = validation.ContentTypePNG
+	}
+
+	processedImagePath := filepath.Join(filepath.Dir(state.filePath),
+		fmt.Sprintf("processed_%s%s", strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)), validation.Extension(contentType)))
+	if ok := gocv.IMWrite(processedImagePath, gray); !ok {
+		return nil, fmt.Errorf("could not save processed image")
+	}
+	defer os.Remove(processedImagePath)
+	
+	log.Printf("Processed image saved successfully")
+
+	processedImageData, err := os.ReadFile(processedImagePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read processed image: %v", err)
+	}
+	
+	log.Printf("Processed image data read successfully, size: %d bytes", len(processedImageData))
+
+	imageURL, objectKey, err := s.minioClient.uploadProcessedImage(processedImageData, filename, contentType, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading to MinIO: %v", err)
+	}
+
+	log.Printf("Image uploaded to MinIO successfully: %s", imageURL)
+
+	width, height := gray.Cols(), gray.Rows()
+	stored := &storedUpload{
+		imageURL:  imageURL,
+		objectKey: objectKey,
+		width:     &width,
+		height:    &height,
+		sizeBytes: int64(len(processedImageData)),
+	}
+
+	// Variants are a convenience for the gallery; listing falls back to the
+	// full image when they are missing, so a failure here is not fatal.
+	variants, err := s.minioClient.storeImageVariants(ctx, gray, objectKey, userId)
+	if err != nil {
+		log.Printf("Warning: Could not generate image variants for %s: %v", objectKey, err)
+	} else {
+		stored.thumbnailKey, stored.previewKey = &variants.ThumbnailKey, &variants.PreviewKey
+		stored.sizeBytes += variants.Bytes
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"path"
+	"strings"
+
+	fileDB "github.com/Aneesh-Hegde/expenseManager/services/file/db"
+	"github.com/minio/minio-go/v7"
+	"gocv.io/x/gocv"
+)
+
+// imageVariant describes a downscaled copy of an upload. Images are shrunk so
+// their longest side is at most MaxSide; smaller images are stored as-is.
+type imageVariant struct {
+	Name    string
+	MaxSide int
+}
+
+var (
+	thumbnailVariant = imageVariant{Name: "thumb", MaxSide: 320}
+	previewVariant   = imageVariant{Name: "preview", MaxSide: 1280}
+)
+
+// webpFileExt is not among gocv's predefined extensions but is supported by
+// OpenCV builds with libwebp.
+const webpFileExt gocv.FileExt = ".webp"
+
+// ImageVariants holds the storage keys and source dimensions recorded for an
+// upload's thumbnail and preview.
+type ImageVariants struct {
+	ThumbnailKey string
+	PreviewKey   string
+	Width        int
+	Height       int
+	Bytes        int64
+}
+
+// storeImageVariants encodes a thumbnail and a preview of img and uploads them
+// next to the original object, under thumb/ and preview/ sub-folders.
+func (m *MinIOClient) storeImageVariants(ctx context.Context, img gocv.Mat, objectKey string, userId int64) (*ImageVariants, error) {
+	if img.Empty() {
+		return nil, fmt.Errorf("cannot generate variants from an empty image")
+	}
+
+	variants := &ImageVariants{Width: img.Cols(), Height: img.Rows()}
+
+	thumbKey, thumbBytes, err := m.storeVariant(ctx, img, objectKey, userId, thumbnailVariant)
+	if err != nil {
+		return nil, err
+	}
+	variants.ThumbnailKey = thumbKey
+
+	previewKey, previewBytes, err := m.storeVariant(ctx, img, objectKey, userId, previewVariant)
+	if err != nil {
+		m.Client.RemoveObject(ctx, m.BucketName, thumbKey, minio.RemoveObjectOptions{})
+		return nil, err
+	}
+	variants.PreviewKey = previewKey
+	variants.Bytes = thumbBytes + previewBytes
+
+	return variants, nil
+}
+
+func (m *MinIOClient) storeVariant(ctx context.Context, img gocv.Mat, objectKey string, userId int64, variant imageVariant) (string, int64, error) {
+	resized := gocv.NewMat()
+	defer resized.Close()
+
+	src := img
+	if scale := variantScale(img.Cols(), img.Rows(), variant.MaxSide); scale < 1 {
+		if err := gocv.Resize(img, &resized, image.Point{}, scale, scale, gocv.InterpolationArea); err != nil {
+			return "", 0, fmt.Errorf("failed to resize %s: %v", variant.Name, err)
+		}
+		src = resized
+	}
+
+	data, ext, err := encodeVariant(src)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to encode %s: %v", variant.Name, err)
+	}
+
+	key := variantKey(objectKey, variant.Name, ext)
+	contentType := getContentType(ext)
+	data = stripMetadata(contentType, data)
+	err = m.putObject(ctx, userId, key, data, minio.PutObjectOptions{
+		ContentType:  contentType,
+		CacheControl: "private, max-age=86400",
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to upload %s: %v", variant.Name, err)
+	}
+
+	log.Printf("Stored %s variant %s (%d bytes)", variant.Name, key, len(data))
+	return key, int64(len(data)), nil
+}
+
+// encodeVariant prefers WebP and falls back to JPEG when OpenCV was built
+// without WebP support.
+func encodeVariant(img gocv.Mat) ([]byte, string, error) {
+	buf, err := gocv.IMEncodeWithParams(webpFileExt, img, []int{gocv.IMWriteWebpQuality, 80})
+	if err == nil && buf.Len() > 0 {
+		defer buf.Close()
+		return append([]byte(nil), buf.GetBytes()...), string(webpFileExt), nil
+	}
+	if buf != nil {
+		buf.Close()
+	}
+
+	buf, err = gocv.IMEncodeWithParams(gocv.JPEGFileExt, img, []int{gocv.IMWriteJpegQuality, 85})
+	if err != nil {
+		return nil, "", err
+	}
+	defer buf.Close()
+	return append([]byte(nil), buf.GetBytes()...), string(gocv.JPEGFileExt), nil
+}
+
+func variantScale(width, height, maxSide int) float64 {
+	longest := width
+	if height > longest {
+		longest = height
+	}
+	if longest <= maxSide || longest == 0 {
+		return 1
+	}
+	return float64(maxSide) / float64(longest)
+}
+
+// variantKey maps upload/user_1/receipt_ab12cd34.png to
+// upload/user_1/thumb/receipt_ab12cd34.webp.
+func variantKey(objectKey, variant, ext string) string {
+	dir, file := path.Split(objectKey)
+	base := strings.TrimSuffix(file, path.Ext(file))
+	return path.Join(dir, variant, base+ext)
+}
+
+// BackfillImageVariants generates thumbnails and previews for files uploaded
+// before variants existed, working through them in batches.
+func BackfillImageVariants(ctx context.Context, batchSize int) error {
+	if MinIOClientInstance == nil {
+		return fmt.Errorf("MinIO client not initialized")
+	}
+	m := MinIOClientInstance
+
+	var done, failed int
+	var lastID int32
+	for {
+		pending, err := fileDB.GetFilesMissingVariants(ctx, lastID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		for _, file := range pending {
+			lastID = file.FileID
+			if err := m.backfillFile(ctx, file); err != nil {
+				log.Printf("Backfill failed for file %d (%s): %v", file.FileID, *file.ObjectKey, err)
+				failed++
+				continue
+			}
+			done++
+		}
+	}
+
+	log.Printf("Image variant backfill finished: %d generated, %d failed", done, failed)
+	return nil
+}
+
+// BackfillStorageSizes records the stored size of uploads made before sizes
+// were tracked, so they count against the storage quota. Objects that no
+// longer exist are left at zero.
+func BackfillStorageSizes(ctx context.Context, batchSize int) error {
+	if MinIOClientInstance == nil {
+		return fmt.Errorf("MinIO client not initialized")
+	}
+	m := MinIOClientInstance
+
+	var done, failed int
+	var lastID int32
+	for {
+		pending, err := fileDB.GetFilesWithoutSize(ctx, lastID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		for _, file := range pending {
+			lastID = file.FileID
+			size, err := m.storedSize(ctx, file)
+			if err == nil {
+				err = fileDB.UpdateFileSize(ctx, file.FileID, size)
+			}
+			if err != nil {
+				log.Printf("Size backfill failed for file %d (%s): %v", file.FileID, *file.ObjectKey, err)
+				failed++
+				continue
+			}
+			done++
+		}
+	}
+
+	log.Printf("Storage size backfill finished: %d sized, %d failed", done, failed)
+	return nil
+}
+
+// storedSize sums the sizes of a file's object and its variants.
+func (m *MinIOClient) storedSize(ctx context.Context, file fileDB.FileMetadata) (int64, error) {
+	var size int64
+	for _, key := range []*string{file.ObjectKey, file.ThumbnailKey, file.PreviewKey} {
+		if key == nil {
+			continue
+		}
+		info, err := m.Client.StatObject(ctx, m.BucketName, *key, minio.StatObjectOptions{})
+		if err != nil {
+			return 0, err
+		}
+		size += info.Size
+	}
+	return size, nil
+}
+
+func (m *MinIOClient) backfillFile(ctx context.Context, file fileDB.FileMetadata) error {
+	data, err := m.ReadObject(ctx, int64(file.UserID), *file.ObjectKey)
+	if err != nil {
+		return err
+	}
+
+	img, err := gocv.IMDecode(data, gocv.IMReadColor)
+	if err != nil {
+		return err
+	}
+	defer img.Close()
+
+	variants, err := m.storeImageVariants(ctx, img, *file.ObjectKey, int64(file.UserID))
+	if err != nil {
+		return err
+	}
+
+	return fileDB.UpdateFileVariants(ctx, file.FileID, variants.ThumbnailKey, variants.PreviewKey,
+		variants.Width, variants.Height, int64(len(data))+variants.Bytes)
+}
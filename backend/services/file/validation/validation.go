@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported file type: only JPEG, PNG, GIF, WebP images and PDF documents are accepted")
+	ErrFileTooLarge    = errors.New("file exceeds the maximum upload size")
+	ErrQuotaExceeded   = errors.New("storage quota exceeded")
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeGIF  = "image/gif"
+	ContentTypeWebP = "image/webp"
+	ContentTypePDF  = "application/pdf"
+)
+
+// SniffLength is the number of leading bytes SniffContentType needs.
+const SniffLength = 12
+
+// SniffContentType identifies an upload by its magic bytes rather than its
+// file name. It returns false for anything outside the allowed set.
+func SniffContentType(head []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		return ContentTypeJPEG, true
+	case bytes.HasPrefix(head, []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}):
+		return ContentTypePNG, true
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return ContentTypeGIF, true
+	case len(head) >= 12 && bytes.Equal(head[0:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")):
+		return ContentTypeWebP, true
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return ContentTypePDF, true
+	}
+	return "", false
+}
+
+// Extension returns the canonical file extension for an allowed content type.
+func Extension(contentType string) string {
+	switch contentType {
+	case ContentTypeJPEG:
+		return ".jpg"
+	case ContentTypePNG:
+		return ".png"
+	case ContentTypeGIF:
+		return ".gif"
+	case ContentTypeWebP:
+		return ".webp"
+	case ContentTypePDF:
+		return ".pdf"
+	}
+	return ""
+}
+
+// Limits are the upload constraints applied to a single user.
+type Limits struct {
+	MaxFileBytes int64
+	QuotaBytes   int64
+	QuotaFiles   int64
+}
+
+// DefaultLimits reads the service-wide limits from the environment, falling
+// back to 10 MiB per file and 500 MiB / 1000 files per user.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxFileBytes: envInt64("UPLOAD_MAX_FILE_BYTES", 10<<20),
+		QuotaBytes:   envInt64("UPLOAD_USER_QUOTA_BYTES", 500<<20),
+		QuotaFiles:   envInt64("UPLOAD_USER_QUOTA_FILES", 1000),
+	}
+}
+
+// Usage is a user's current storage footprint.
+type Usage struct {
+	BytesUsed int64
+	FileCount int64
+}
+
+// CheckFileSize rejects uploads larger than the per-file limit.
+func CheckFileSize(size int64, limits Limits) error {
+	if limits.MaxFileBytes > 0 && size > limits.MaxFileBytes {
+		return fmt.Errorf("%w (%d bytes, limit %d)", ErrFileTooLarge, size, limits.MaxFileBytes)
+	}
+	return nil
+}
+
+// CheckQuota reports whether storing one more file of incomingBytes would push
+// the user past either quota.
+func CheckQuota(usage Usage, limits Limits, incomingBytes int64) error {
+	if limits.QuotaFiles > 0 && usage.FileCount+1 > limits.QuotaFiles {
+		return fmt.Errorf("%w: file limit of %d reached", ErrQuotaExceeded, limits.QuotaFiles)
+	}
+	if limits.QuotaBytes > 0 && usage.BytesUsed+incomingBytes > limits.QuotaBytes {
+		return fmt.Errorf("%w: %d of %d bytes used", ErrQuotaExceeded, usage.BytesUsed, limits.QuotaBytes)
+	}
+	return nil
+}
+
+func envInt64(name string, fallback int64) int64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		return fallback
+	}
+	return parsed
+}
@@ -6,6 +6,9 @@ import (
     "time"
     "strconv"
     sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+    "github.com/Aneesh-Hegde/expenseManager/shared/fx"
+    "github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+    "github.com/Aneesh-Hegde/expenseManager/shared/money"
     "github.com/jackc/pgx/v4"
 )
 
@@ -20,6 +23,12 @@ type GoalResult struct {
     CategoryID   string
     CategoryName string
     Hexcode      string
+    Currency     string
+    // The amounts in the user's BaseCurrency at today's rate; nil when no
+    // rate is known.
+    BaseCurrency      string
+    BaseTargetAmount  *float64
+    BaseCurrentAmount *float64
 }
 
 type GoalTransactionResult struct {
@@ -30,6 +39,7 @@ type GoalTransactionResult struct {
     TransactionType string
     CreatedAt       time.Time
     Notes           string
+    ExchangeRate    float64
 }
 
 type CategoryResult struct {
@@ -43,14 +53,18 @@ type CategoryResult struct {
 type GoalUpdateData struct {
     CurrentAmount float64
     GoalName      string
+    Currency      string
 }
 
+// BalanceData describes the balance account behind a balance ID.
+// CurrentBalance is what the journal has the account holding.
 type BalanceData struct {
     CurrentBalance float64
     Description    string
+    Currency       string
 }
 
-func CreateGoal(ctx context.Context, userID, name, description string, targetAmount, currentAmount float64, deadline time.Time, createdAt time.Time, categoryName, hexcode string) (string, error) {
+func CreateGoal(ctx context.Context, userID, name, description string, targetAmount, currentAmount float64, deadline time.Time, createdAt time.Time, categoryName, hexcode, currency string) (string, error) {
     userIDInt, err := strconv.ParseInt(userID, 10, 32)
     if err != nil {
         return "", fmt.Errorf("invalid user ID: %v", err)
@@ -92,8 +106,8 @@ func CreateGoal(ctx context.Context, userID, name, description string, targetAmo
     }
 
     goalInsertQuery := `
-        INSERT INTO goal_management_service.goals (id, user_id, name, target_amount, current_amount, deadline, description, created_at, category_id)
-        VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8)
+        INSERT INTO goal_management_service.goals (id, user_id, name, target_amount, current_amount, deadline, description, created_at, category_id, currency)
+        VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
         RETURNING id`
     
     var goalID string
@@ -106,6 +120,7 @@ func CreateGoal(ctx context.Context, userID, name, description string, targetAmo
         description,
         createdAt,
         categoryID,
+        money.Normalize(currency),
     ).Scan(&goalID)
     
     if err != nil {
@@ -137,9 +152,14 @@ func GetUserGoals(ctx context.Context, userID string) ([]GoalResult, error) {
             g.created_at,
             g.category_id,
             gc.name AS category_name,
-            gc.color AS hexcode
+            gc.color AS hexcode,
+            g.currency,
+            base.currency,
+            (g.target_amount * account_income_service.fx_rate(g.currency, base.currency, CURRENT_DATE))::float8,
+            (g.current_amount * account_income_service.fx_rate(g.currency, base.currency, CURRENT_DATE))::float8
         FROM goal_management_service.goals g
         JOIN goal_management_service.goal_categories gc ON g.category_id = gc.id
+        CROSS JOIN (SELECT account_income_service.base_currency($1) AS currency) base
         WHERE g.user_id = $1
         ORDER BY g.created_at DESC`
 
@@ -163,6 +183,10 @@ func GetUserGoals(ctx context.Context, userID string) ([]GoalResult, error) {
             &goal.CategoryID,
             &goal.CategoryName,
             &goal.Hexcode,
+            &goal.Currency,
+            &goal.BaseCurrency,
+            &goal.BaseTargetAmount,
+            &goal.BaseCurrentAmount,
         )
         if err != nil {
             return nil, fmt.Errorf("error scanning goal row: %v", err)
@@ -193,15 +217,15 @@ func DeleteGoal(ctx context.Context, goalID, userID string) (bool, error) {
     return cmdTag.RowsAffected() > 0, nil
 }
 
-func CreateNewGoal(ctx context.Context, userID, name, description string, targetAmount, currentAmount float64, deadline, categoryID string) (string, error) {
+func CreateNewGoal(ctx context.Context, userID, name, description string, targetAmount, currentAmount float64, deadline, categoryID, currency string) (string, error) {
     userIDInt, err := strconv.ParseInt(userID, 10, 32)
     if err != nil {
         return "", fmt.Errorf("invalid user ID: %v", err)
     }
 
     query := `
-        INSERT INTO goal_management_service.goals (id, user_id, name, description, target_amount, current_amount, deadline, created_at, category_id)
-        VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8)
+        INSERT INTO goal_management_service.goals (id, user_id, name, description, target_amount, current_amount, deadline, created_at, category_id, currency)
+        VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
         RETURNING id`
 
     var newID string
@@ -214,6 +238,7 @@ func CreateNewGoal(ctx context.Context, userID, name, description string, target
         deadline,
         time.Now(),
         categoryID,
+        money.Normalize(currency),
     ).Scan(&newID)
     
     if err != nil {
@@ -314,7 +339,8 @@ func GetGoalTransactions(ctx context.Context, goalID, userID string) ([]GoalTran
             gt.amount,
             gt.transaction_type,
             gt.created_at,
-            gt.notes
+            gt.notes,
+            gt.exchange_rate::float8
         FROM goal_management_service.goal_transactions gt
         INNER JOIN goal_management_service.goals g ON gt.goal_id = g.id
         WHERE gt.goal_id = $1 AND g.user_id = $2
@@ -337,6 +363,7 @@ func GetGoalTransactions(ctx context.Context, goalID, userID string) ([]GoalTran
             &transaction.TransactionType,
             &transaction.CreatedAt,
             &transaction.Notes,
+            &transaction.ExchangeRate,
         )
         if err != nil {
             return nil, fmt.Errorf("failed to scan transaction row: %w", err)
@@ -359,11 +386,11 @@ func GetGoalUpdateData(ctx context.Context, goalID, userID string) (*GoalUpdateD
 
     var data GoalUpdateData
     getCurrentQuery := `
-        SELECT current_amount, name
+        SELECT current_amount, name, currency
         FROM goal_management_service.goals
         WHERE id = $1 AND user_id = $2`
     
-    err = sharedDB.GetDB().QueryRow(ctx, getCurrentQuery, goalID, int32(userIDInt)).Scan(&data.CurrentAmount, &data.GoalName)
+    err = sharedDB.GetDB().QueryRow(ctx, getCurrentQuery, goalID, int32(userIDInt)).Scan(&data.CurrentAmount, &data.GoalName, &data.Currency)
     if err != nil {
         if err == pgx.ErrNoRows {
             return nil, fmt.Errorf("goal with ID '%s' not found or not owned by user", goalID)
@@ -401,11 +428,13 @@ func GetBalanceData(ctx context.Context, balanceID int32, userID string) (*Balan
 
     var data BalanceData
     getBalanceQuery := `
-        SELECT amount, description
-        FROM account_income_service.incomes
-        WHERE income_id = $1 AND user_id = $2`
+        SELECT COALESCE(b.balance, 0)::float8, i.description, i.currency
+        FROM account_income_service.incomes i
+        LEFT JOIN account_income_service.ledger_balances($2) b
+            ON b.account_type = 'asset' AND b.code = i.account_id::text AND b.currency = i.currency
+        WHERE i.income_id = $1 AND i.user_id = $2`
     
-    err = sharedDB.GetDB().QueryRow(ctx, getBalanceQuery, balanceID, int32(userIDInt)).Scan(&data.CurrentBalance, &data.Description)
+    err = sharedDB.GetDB().QueryRow(ctx, getBalanceQuery, balanceID, int32(userIDInt)).Scan(&data.CurrentBalance, &data.Description, &data.Currency)
     if err != nil {
         if err == pgx.ErrNoRows {
             return nil, fmt.Errorf("balance account not found or not owned by user")
@@ -429,26 +458,45 @@ func CreateGoalTransaction(ctx context.Context, goalID string, balanceID int32,
     return nil
 }
 
+// UpdateBalanceAccount moves balanceChange into the balance account as an
+// adjustment. Only the journal records it: the income row the balance ID
+// names keeps the amount it was paid in at.
 func UpdateBalanceAccount(ctx context.Context, balanceID int32, userID string, balanceChange float64) error {
     userIDInt, err := strconv.ParseInt(userID, 10, 32)
     if err != nil {
         return fmt.Errorf("invalid user ID: %v", err)
     }
 
-    updateBalanceQuery := `
-        UPDATE account_income_service.incomes
-        SET amount = amount + $1, last_updated = NOW()
-        WHERE income_id = $2 AND user_id = $3`
-    
-    _, err = sharedDB.GetDB().Exec(ctx, updateBalanceQuery, balanceChange, balanceID, int32(userIDInt))
+    tx, err := sharedDB.GetDB().Begin(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to start transaction: %w", err)
+    }
+    defer tx.Rollback(ctx)
+
+    account, currency, err := ledger.IncomeBalanceAccount(ctx, tx, int32(userIDInt), balanceID)
+    if err != nil {
+        return err
+    }
+    _, err = ledger.Post(ctx, tx, ledger.Entry{
+        UserID:        int32(userIDInt),
+        Kind:          "adjustment",
+        ReferenceType: "income",
+        ReferenceID:   strconv.Itoa(int(balanceID)),
+        Postings:      ledger.Move(ledger.AdjustmentAccount, account, money.FromFloat(balanceChange, currency)),
+    })
     if err != nil {
-        return fmt.Errorf("failed to update balance account: %w", err)
+        return err
     }
     
-    return nil
+    return tx.Commit(ctx)
 }
 
-func ExecuteGoalUpdateTransaction(ctx context.Context, goalID, userID string, amount float64, balanceID int32, transactionType, notes string, amountDiff float64) error {
+// ExecuteGoalUpdateTransaction sets the goal's amount and moves the
+// difference, amountDiff in the goal's currency, between the goal and the
+// balance account. exchangeRate converts it to the account's currency. The
+// move is posted to the journal only; the balance account's income rows are
+// left alone, as restating an income would otherwise count it again.
+func ExecuteGoalUpdateTransaction(ctx context.Context, goalID, userID string, amount float64, balanceID int32, transactionType, notes string, amountDiff, exchangeRate float64) error {
     userIDInt, err := strconv.ParseInt(userID, 10, 32)
     if err != nil {
         return fmt.Errorf("invalid user ID: %v", err)
@@ -469,26 +517,47 @@ func ExecuteGoalUpdateTransaction(ctx context.Context, goalID, userID string, am
     }
 
     if amountDiff != 0 && balanceID != 0 {
-        _, err = tx.Exec(ctx, `
-            INSERT INTO goal_management_service.goal_transactions (goal_id, balance_id, amount, transaction_type, notes)
-            VALUES ($1, $2, $3, $4, $5)`, goalID, balanceID, amountDiff, transactionType, notes)
+        var transactionID int32
+        err = tx.QueryRow(ctx, `
+            INSERT INTO goal_management_service.goal_transactions (goal_id, balance_id, amount, transaction_type, notes, exchange_rate)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id`, goalID, balanceID, amountDiff, transactionType, notes, exchangeRate).Scan(&transactionID)
         if err != nil {
             return fmt.Errorf("failed to create transaction record: %w", err)
         }
 
-        var balanceChange float64
+        var goalCurrency string
+        err = tx.QueryRow(ctx, `
+            SELECT currency FROM goal_management_service.goals
+            WHERE id = $1 AND user_id = $2`, goalID, int32(userIDInt)).Scan(&goalCurrency)
+        if err != nil {
+            return fmt.Errorf("failed to read goal currency: %w", err)
+        }
+        account, accountCurrency, err := ledger.IncomeBalanceAccount(ctx, tx, int32(userIDInt), balanceID)
+        if err != nil {
+            return err
+        }
+
+        var goalChange money.Money
         if transactionType == "deposit" {
-            balanceChange = -amountDiff
+            goalChange = money.FromFloat(-amountDiff, goalCurrency)
         } else {
-            balanceChange = amountDiff
+            goalChange = money.FromFloat(amountDiff, goalCurrency)
         }
-
-        _, err = tx.Exec(ctx, `
-            UPDATE account_income_service.incomes
-            SET amount = amount + $1, last_updated = NOW()
-            WHERE income_id = $2 AND user_id = $3`, balanceChange, balanceID, int32(userIDInt))
+        balanceChange := fx.At(goalChange, accountCurrency, exchangeRate)
+
+        // Money into the goal leaves the balance account, and comes back
+        // out of the goal on a withdrawal.
+        _, err = ledger.Restate(ctx, tx, ledger.Entry{
+            UserID:        int32(userIDInt),
+            Kind:          "goal_contribution",
+            ReferenceType: "goal_transaction",
+            ReferenceID:   strconv.Itoa(int(transactionID)),
+            Description:   notes,
+            Postings:      ledger.Exchange(ledger.GoalAccount(goalID), goalChange, account, balanceChange),
+        })
         if err != nil {
-            return fmt.Errorf("failed to update balance account: %w", err)
+            return err
         }
     }
 
@@ -0,0 +1,10 @@
+-- Schema changes for goal_management_service, applied in order on top of the
+-- existing tables.
+
+-- A goal's amounts are in its own currency. A contribution from a balance
+-- account in another currency moves amount into the goal and amount times
+-- exchange_rate out of the account.
+ALTER TABLE goal_management_service.goals
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE goal_management_service.goal_transactions
+    ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(20, 10) NOT NULL DEFAULT 1;
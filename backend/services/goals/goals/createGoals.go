@@ -46,7 +46,7 @@ func CreateGoals(ctx context.Context, req *goals.CreateGoalRequest) (*goals.Crea
 
     goalId, err := goalDB.CreateGoal(ctx, userId, req.Name, req.Description, 
         req.TargetAmount, req.CurrentAmount, deadline, createdAt, 
-        req.CategoryName, req.Hexacode)
+        req.CategoryName, req.Hexacode, req.Currency)
     if err != nil {
         return nil, err
     }
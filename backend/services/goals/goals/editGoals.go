@@ -47,7 +47,7 @@ func EditGoals(ctx context.Context, req *goals.EditGoalRequest) (*goals.EditResp
     if goal.GetId() == "" {
         // Create new goal
         newID, err := goalDB.CreateNewGoal(ctx, userId, goal.GetName(), goal.GetDescription(),
-            goal.GetTargetAmount(), goal.GetCurrentAmount(), goal.GetDeadline(), goal.GetCategoryId())
+            goal.GetTargetAmount(), goal.GetCurrentAmount(), goal.GetDeadline(), goal.GetCategoryId(), goal.GetCurrency())
         if err != nil {
             return nil, err
         }
@@ -48,6 +48,10 @@ func GetGoals(ctx context.Context, req *goals.GetGoalRequest) (*goals.GetGoalRes
             CategoryId:   result.CategoryID,
             CategoryName: result.CategoryName,
             Hexcode:      result.Hexcode,
+            Currency:     result.Currency,
+            BaseCurrency: result.BaseCurrency,
+            BaseTargetAmount:  result.BaseTargetAmount,
+            BaseCurrentAmount: result.BaseCurrentAmount,
         }
         userGoals = append(userGoals, goal)
     }
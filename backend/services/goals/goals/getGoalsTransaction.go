@@ -49,6 +49,7 @@ func GoalTransactions(ctx context.Context, req *goals.GetGoalTransactionsRequest
             TransactionType: result.TransactionType,
             CreatedAt:       result.CreatedAt.Format("2006-01-02 15:04:05"),
             Notes:           result.Notes,
+            ExchangeRate:    result.ExchangeRate,
         }
         transactions = append(transactions, transaction)
     }
@@ -3,10 +3,16 @@ package goals
 import (
     "context"
     "fmt"
+    "time"
     goalDB "github.com/Aneesh-Hegde/expenseManager/services/goals/db"
     goals "github.com/Aneesh-Hegde/expenseManager/grpc_goal"
+    sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+    "github.com/Aneesh-Hegde/expenseManager/shared/fx"
+    "github.com/Aneesh-Hegde/expenseManager/shared/money"
     "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
     "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
 )
 
 func UpdateGoals(ctx context.Context, req *goals.UpdateGoalRequest) (*goals.UpdateResponse, error) {
@@ -43,10 +49,20 @@ func UpdateGoals(ctx context.Context, req *goals.UpdateGoalRequest) (*goals.Upda
             return nil, err
         }
 
+        // The goal's amounts are in its currency, the account's in its own
+        exchangeRate, err := fx.Lookup(ctx, sharedDB.GetDB(), goalData.Currency, balanceData.Currency, time.Now())
+        if err != nil {
+            if fx.IsNoRate(err) {
+                return nil, status.Error(codes.FailedPrecondition, err.Error())
+            }
+            return nil, err
+        }
+        required := fx.At(money.FromFloat(amountDiff, goalData.Currency), balanceData.Currency, exchangeRate).Float64()
+
         // Check for sufficient funds if adding money to goal
-        if req.GetTransactionType() == "deposit" && balanceData.CurrentBalance < amountDiff {
+        if req.GetTransactionType() == "deposit" && balanceData.CurrentBalance < required {
             return nil, fmt.Errorf("Insufficient funds in account '%s'. Available: %.2f, Required: %.2f",
-                balanceData.Description, balanceData.CurrentBalance, amountDiff)
+                balanceData.Description, balanceData.CurrentBalance, required)
         }
 
         notes := req.GetNotes()
@@ -62,7 +78,7 @@ func UpdateGoals(ctx context.Context, req *goals.UpdateGoalRequest) (*goals.Upda
 
         // Execute the complete transaction
         err = goalDB.ExecuteGoalUpdateTransaction(ctx, req.GetId(), userId, req.GetAmount(), 
-            req.GetBalanceId(), req.GetTransactionType(), notes, amountDiff)
+            req.GetBalanceId(), req.GetTransactionType(), notes, amountDiff, exchangeRate)
         if err != nil {
             return nil, err
         }
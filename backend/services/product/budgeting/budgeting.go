@@ -0,0 +1,274 @@
+// Package budgeting works out budget periods, rollover and projected spend.
+// Dates are calendar days; times of day are ignored.
+package budgeting
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period lengths.
+const (
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+	PeriodCustom  = "custom"
+)
+
+// Rollover modes: what happens to the difference between a period's
+// allowance and its spend.
+const (
+	// RolloverNone starts every period from the budget amount.
+	RolloverNone = "none"
+	// RolloverUnspent carries money left over into the next period.
+	RolloverUnspent = "unspent"
+	// RolloverAll also carries overspending, shrinking the next period.
+	RolloverAll = "all"
+)
+
+// maxPeriods bounds how far back rollover is followed.
+const maxPeriods = 1000
+
+// Schedule is how a budget's periods repeat. Monthly periods start on the
+// day of the month of Start, or the last day of shorter months; weekly and
+// custom periods are 7 and Days days long from Start. A budget with an End
+// has no periods after that day.
+type Schedule struct {
+	Period string
+	Days   int
+	Start  time.Time
+	End    *time.Time
+}
+
+// Period is a span of days; End is exclusive.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Days is the number of days in the period.
+func (p Period) Days() int {
+	return daysBetween(p.Start, p.End)
+}
+
+// Contains reports whether t falls on one of the period's days.
+func (p Period) Contains(t time.Time) bool {
+	d := Date(t)
+	return !d.Before(p.Start) && d.Before(p.End)
+}
+
+// Date truncates t to midnight UTC of its calendar day.
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(Date(to).Sub(Date(from)).Hours() / 24)
+}
+
+// Validate checks a schedule and normalises its dates.
+func (s *Schedule) Validate() error {
+	s.Start = Date(s.Start)
+	switch s.Period {
+	case PeriodWeekly:
+		s.Days = 7
+	case PeriodMonthly:
+		s.Days = 0
+	case PeriodCustom:
+		if s.Days < 1 || s.Days > 366 {
+			return fmt.Errorf("custom periods must be between 1 and 366 days")
+		}
+	default:
+		return fmt.Errorf("period must be %s, %s or %s", PeriodWeekly, PeriodMonthly, PeriodCustom)
+	}
+	if s.End != nil {
+		end := Date(*s.End)
+		if end.Before(s.Start) {
+			return fmt.Errorf("end date must not be before start date")
+		}
+		s.End = &end
+	}
+	return nil
+}
+
+// ValidRollover reports whether mode is a known rollover mode.
+func ValidRollover(mode string) bool {
+	return mode == RolloverNone || mode == RolloverUnspent || mode == RolloverAll
+}
+
+// nth returns the index-th period, ignoring End.
+func (s Schedule) nth(index int) Period {
+	if s.Period == PeriodMonthly {
+		return Period{Start: addMonths(s.Start, index), End: addMonths(s.Start, index+1)}
+	}
+	start := s.Start.AddDate(0, 0, index*s.Days)
+	return Period{Start: start, End: start.AddDate(0, 0, s.Days)}
+}
+
+// addMonths moves start forward by months, keeping its day of the month where
+// the target month has it and using the month's last day otherwise.
+func addMonths(start time.Time, months int) time.Time {
+	first := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := start.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// index returns which period t falls in; negative before Start.
+func (s Schedule) index(t time.Time) int {
+	d := Date(t)
+	if d.Before(s.Start) {
+		return -1
+	}
+	if s.Period != PeriodMonthly {
+		return daysBetween(s.Start, d) / s.Days
+	}
+	i := (d.Year()-s.Start.Year())*12 + int(d.Month()) - int(s.Start.Month())
+	for i > 0 && s.nth(i).Start.After(d) {
+		i--
+	}
+	for !s.nth(i).End.After(d) {
+		i++
+	}
+	return i
+}
+
+// clip cuts a period short at the schedule's End.
+func (s Schedule) clip(p Period) (Period, bool) {
+	if s.End == nil {
+		return p, true
+	}
+	last := s.End.AddDate(0, 0, 1)
+	if !p.Start.Before(last) {
+		return Period{}, false
+	}
+	if p.End.After(last) {
+		p.End = last
+	}
+	return p, true
+}
+
+// PeriodAt returns the period containing t. Before Start it returns the first
+// period and false; after End, the last period and false.
+func (s Schedule) PeriodAt(t time.Time) (Period, bool) {
+	i := s.index(t)
+	if i < 0 {
+		p, _ := s.clip(s.nth(0))
+		return p, false
+	}
+	if p, ok := s.clip(s.nth(i)); ok {
+		return p, true
+	}
+	p, _ := s.clip(s.nth(s.index(*s.End)))
+	return p, false
+}
+
+// Through returns every period from the first up to and including target,
+// at most the last maxPeriods of them.
+func (s Schedule) Through(target Period) []Period {
+	last := s.index(target.Start)
+	first := 0
+	if last-first+1 > maxPeriods {
+		first = last - maxPeriods + 1
+	}
+	var periods []Period
+	for i := first; i <= last; i++ {
+		if p, ok := s.clip(s.nth(i)); ok {
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+// PeriodsContaining returns the distinct periods the given days fall in,
+// skipping days outside the schedule.
+func (s Schedule) PeriodsContaining(days []time.Time) []Period {
+	seen := map[int]bool{}
+	var periods []Period
+	for _, day := range days {
+		i := s.index(day)
+		if i < 0 || seen[i] {
+			continue
+		}
+		seen[i] = true
+		if p, ok := s.clip(s.nth(i)); ok {
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+// Status is where a budget stands in one period.
+type Status struct {
+	Period Period
+	// Limit is the budget amount; Rollover is what earlier periods carried
+	// in, so Available = Limit + Rollover.
+	Limit     float64
+	Rollover  float64
+	Available float64
+	Spent     float64
+	Remaining float64
+	// Projected is the spend expected by the end of the period if spending
+	// continues at the rate so far.
+	Projected     float64
+	DaysElapsed   int
+	DaysRemaining int
+	// DailyAllowance is what can still be spent per day, today included,
+	// without going over; 0 once the period is over or nothing is left.
+	DailyAllowance float64
+}
+
+// Evaluate computes the status of the last period in spent, carrying rollover
+// through the ones before it. spent holds each period's spend, oldest first;
+// today decides how far through the last period is.
+func Evaluate(amount float64, rollover string, periods []Period, spent []float64, today time.Time) Status {
+	carry := 0.0
+	for i := 0; i < len(periods)-1; i++ {
+		left := amount + carry - spent[i]
+		switch rollover {
+		case RolloverUnspent:
+			if left < 0 {
+				left = 0
+			}
+			carry = left
+		case RolloverAll:
+			carry = left
+		default:
+			carry = 0
+		}
+	}
+
+	last := len(periods) - 1
+	status := Status{
+		Period:    periods[last],
+		Limit:     amount,
+		Rollover:  carry,
+		Available: amount + carry,
+		Spent:     spent[last],
+	}
+	status.Remaining = status.Available - status.Spent
+
+	total := status.Period.Days()
+	today = Date(today)
+	switch {
+	case today.Before(status.Period.Start):
+		status.DaysRemaining = total
+		status.Projected = status.Spent
+		if status.Remaining > 0 {
+			status.DailyAllowance = status.Remaining / float64(total)
+		}
+	case !today.Before(status.Period.End):
+		status.DaysElapsed = total
+		status.Projected = status.Spent
+	default:
+		status.DaysElapsed = daysBetween(status.Period.Start, today) + 1
+		status.DaysRemaining = total - status.DaysElapsed
+		status.Projected = status.Spent / float64(status.DaysElapsed) * float64(total)
+		if status.Remaining > 0 {
+			status.DailyAllowance = status.Remaining / float64(status.DaysRemaining+1)
+		}
+	}
+	return status
+}
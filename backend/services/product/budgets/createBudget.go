@@ -0,0 +1,28 @@
+package budgets
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/budget"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// CreateBudget saves a new budget for the caller.
+func CreateBudget(ctx context.Context, req *budget.BudgetRequest) (*budget.Budget, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newBudget, err := fromBudgetMessage(req.GetBudget())
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := productDB.CreateBudget(ctx, userId, newBudget)
+	if err != nil {
+		return nil, budgetError(err)
+	}
+	return toBudgetMessage(created), nil
+}
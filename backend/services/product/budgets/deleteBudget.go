@@ -0,0 +1,23 @@
+package budgets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/budget"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// DeleteBudget removes one of the caller's budgets.
+func DeleteBudget(ctx context.Context, req *budget.DeleteBudgetRequest) (*budget.DeleteBudgetResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := productDB.DeleteBudget(ctx, userId, req.GetBudgetId()); err != nil {
+		return nil, budgetError(err)
+	}
+	return &budget.DeleteBudgetResponse{Message: fmt.Sprintf("Deleted budget %d", req.GetBudgetId())}, nil
+}
@@ -0,0 +1,63 @@
+package budgets
+
+import (
+	"context"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/budget"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetBudgetStatus reports spend against the caller's budgets in the period
+// containing the requested day.
+func GetBudgetStatus(ctx context.Context, req *budget.GetBudgetStatusRequest) (*budget.GetBudgetStatusResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	day := time.Now()
+	if req.GetDate() != "" {
+		day, err = time.Parse("2006-01-02", req.GetDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "date must be YYYY-MM-DD")
+		}
+	}
+
+	statuses, err := productDB.GetBudgetStatuses(ctx, userId, req.GetBudgetId(), day)
+	if err != nil {
+		return nil, budgetError(err)
+	}
+
+	resp := &budget.GetBudgetStatusResponse{}
+	for i := range statuses {
+		resp.Statuses = append(resp.Statuses, toStatusMessage(&statuses[i]))
+	}
+	return resp, nil
+}
+
+func toStatusMessage(s *productDB.BudgetStatus) *budget.BudgetStatus {
+	msg := &budget.BudgetStatus{
+		Budget:         toBudgetMessage(&s.Budget),
+		PeriodStart:    s.Period.Start.Format("2006-01-02"),
+		PeriodEnd:      s.Period.End.AddDate(0, 0, -1).Format("2006-01-02"),
+		InSchedule:     s.Current,
+		Limit:          s.Limit,
+		Rollover:       s.Rollover,
+		Available:      s.Available,
+		Spent:          s.Spent,
+		Remaining:      s.Remaining,
+		ProjectedSpend: s.Projected,
+		ProjectedOver:  s.Projected > s.Available,
+		DaysElapsed:    int32(s.DaysElapsed),
+		DaysRemaining:  int32(s.DaysRemaining),
+		DailyAllowance: s.DailyAllowance,
+	}
+	if s.Available > 0 {
+		msg.PercentUsed = s.Spent / s.Available * 100
+	}
+	return msg
+}
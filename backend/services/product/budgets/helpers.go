@@ -0,0 +1,115 @@
+package budgets
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/budget"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/budgeting"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultStart is where a budget without a start date begins: this month,
+// this week (from Monday) or today.
+func defaultStart(period string, today time.Time) time.Time {
+	today = budgeting.Date(today)
+	switch period {
+	case budgeting.PeriodMonthly:
+		return time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case budgeting.PeriodWeekly:
+		offset := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset)
+	}
+	return today
+}
+
+// fromBudgetMessage checks the parts of a budget the DB layer does not and
+// converts it.
+func fromBudgetMessage(msg *budget.Budget) (productDB.Budget, error) {
+	if msg == nil {
+		return productDB.Budget{}, status.Error(codes.InvalidArgument, "budget is required")
+	}
+	name := strings.TrimSpace(msg.GetName())
+	if name == "" {
+		return productDB.Budget{}, status.Error(codes.InvalidArgument, "budget name is required")
+	}
+	if len([]rune(name)) > 100 {
+		return productDB.Budget{}, status.Error(codes.InvalidArgument, "budget name must be at most 100 characters")
+	}
+
+	b := productDB.Budget{
+		BudgetID: msg.GetBudgetId(),
+		Name:     name,
+		Amount:   msg.GetAmount(),
+		Rollover: strings.ToLower(strings.TrimSpace(msg.GetRollover())),
+		Schedule: budgeting.Schedule{
+			Period: strings.ToLower(strings.TrimSpace(msg.GetPeriod())),
+			Days:   int(msg.GetPeriodDays()),
+		},
+	}
+	if b.Rollover == "" {
+		b.Rollover = budgeting.RolloverNone
+	}
+	if categoryID := msg.GetCategoryId(); categoryID != 0 {
+		b.CategoryID = &categoryID
+	}
+
+	if msg.GetStartDate() == "" {
+		b.Schedule.Start = defaultStart(b.Schedule.Period, time.Now())
+	} else {
+		start, err := time.Parse("2006-01-02", msg.GetStartDate())
+		if err != nil {
+			return productDB.Budget{}, status.Error(codes.InvalidArgument, "start_date must be YYYY-MM-DD")
+		}
+		b.Schedule.Start = start
+	}
+	if msg.GetEndDate() != "" {
+		end, err := time.Parse("2006-01-02", msg.GetEndDate())
+		if err != nil {
+			return productDB.Budget{}, status.Error(codes.InvalidArgument, "end_date must be YYYY-MM-DD")
+		}
+		b.Schedule.End = &end
+	}
+	return b, nil
+}
+
+func toBudgetMessage(b *productDB.Budget) *budget.Budget {
+	msg := &budget.Budget{
+		BudgetId:  b.BudgetID,
+		Name:      b.Name,
+		Amount:    b.Amount,
+		Period:    b.Schedule.Period,
+		StartDate: b.Schedule.Start.Format("2006-01-02"),
+		Rollover:  b.Rollover,
+	}
+	if b.CategoryID != nil {
+		msg.CategoryId = *b.CategoryID
+	}
+	if b.CategoryName != nil {
+		msg.CategoryName = *b.CategoryName
+	}
+	if b.Schedule.Period == budgeting.PeriodCustom {
+		msg.PeriodDays = int32(b.Schedule.Days)
+	}
+	if b.Schedule.End != nil {
+		msg.EndDate = b.Schedule.End.Format("2006-01-02")
+	}
+	return msg
+}
+
+// budgetError refuses budgets over categories that are archived.
+func budgetError(err error) error {
+	switch {
+	case errors.Is(err, productDB.ErrBudgetNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrInvalidBudget),
+		errors.Is(err, productDB.ErrCategoryNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, productDB.ErrCategoryArchived):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,28 @@
+package budgets
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/budget"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// ListBudgets returns the caller's budgets, overall budgets first.
+func ListBudgets(ctx context.Context, req *budget.ListBudgetsRequest) (*budget.BudgetList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	budgetList, err := productDB.ListBudgets(ctx, userId)
+	if err != nil {
+		return nil, budgetError(err)
+	}
+
+	resp := &budget.BudgetList{}
+	for i := range budgetList {
+		resp.Budgets = append(resp.Budgets, toBudgetMessage(&budgetList[i]))
+	}
+	return resp, nil
+}
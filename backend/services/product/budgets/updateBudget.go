@@ -0,0 +1,28 @@
+package budgets
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/budget"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// UpdateBudget replaces one of the caller's budgets with the one given.
+func UpdateBudget(ctx context.Context, req *budget.BudgetRequest) (*budget.Budget, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedBudget, err := fromBudgetMessage(req.GetBudget())
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := productDB.UpdateBudget(ctx, userId, updatedBudget)
+	if err != nil {
+		return nil, budgetError(err)
+	}
+	return toBudgetMessage(updated), nil
+}
@@ -0,0 +1,24 @@
+package categories
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// ArchiveCategory hides or restores a category. Archived categories keep their
+// products but are no longer offered or matched for new ones.
+func ArchiveCategory(ctx context.Context, req *category.ArchiveCategoryRequest) (*category.Category, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := productDB.ArchiveCategory(ctx, userId, req.GetCategoryId(), req.GetArchived())
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	return toCategoryMessage(archived), nil
+}
@@ -0,0 +1,66 @@
+package categories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/classifier"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// GetClassifierStats describes the caller's classifier.
+func GetClassifierStats(ctx context.Context, req *category.ClassifierStatsRequest) (*category.ClassifierStats, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := productDB.GetClassifierStats(ctx, userId)
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	return toClassifierStatsMessage(stats), nil
+}
+
+// RetrainClassifier rebuilds the caller's classifier from their corrections.
+// Corrections retrain it in the background anyway; this is for after merging
+// or archiving categories, or to wait for a retrain to finish.
+func RetrainClassifier(ctx context.Context, req *category.RetrainClassifierRequest) (*category.ClassifierStats, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := productDB.RetrainClassifier(ctx, userId)
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	return toClassifierStatsMessage(stats), nil
+}
+
+func toClassifierStatsMessage(stats *productDB.ClassifierStats) *category.ClassifierStats {
+	msg := &category.ClassifierStats{
+		Corrections:     int32(stats.Corrections),
+		MinExamples:     classifier.MinExamples,
+		AssignThreshold: classifier.AssignThreshold,
+	}
+	if stats.Model != nil {
+		msg.Trained = true
+		msg.TrainingExamples = int32(stats.Model.Documents)
+		msg.VocabularySize = int32(stats.Model.VocabularySize)
+		msg.Accuracy = stats.Model.Accuracy
+		msg.Active = stats.Model.Documents >= classifier.MinExamples
+	}
+	if stats.TrainedAt != nil {
+		msg.TrainedAt = stats.TrainedAt.Format(time.RFC3339)
+	}
+	for i := range stats.Classes {
+		msg.Classes = append(msg.Classes, &category.ClassStats{
+			Category: toCategoryMessage(&stats.Classes[i].Category),
+			Examples: int32(stats.Classes[i].Examples),
+		})
+	}
+	return msg
+}
@@ -0,0 +1,44 @@
+package categories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// CreateCategory adds a category, optionally under an existing parent.
+func CreateCategory(ctx context.Context, req *category.CreateCategoryRequest) (*category.Category, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := validateName(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateColor(req.GetColor()); err != nil {
+		return nil, err
+	}
+
+	newCategory := productDB.Category{Name: name}
+	if req.GetParentId() != 0 {
+		parentID := req.GetParentId()
+		newCategory.ParentID = &parentID
+	}
+	if color := req.GetColor(); color != "" {
+		newCategory.Color = &color
+	}
+	if icon := strings.TrimSpace(req.GetIcon()); icon != "" {
+		newCategory.Icon = &icon
+	}
+
+	created, err := productDB.CreateCategory(ctx, userId, newCategory)
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	return toCategoryMessage(created), nil
+}
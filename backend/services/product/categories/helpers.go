@@ -0,0 +1,68 @@
+package categories
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "category name is required")
+	}
+	if len([]rune(name)) > 100 {
+		return "", status.Error(codes.InvalidArgument, "category name must be at most 100 characters")
+	}
+	return name, nil
+}
+
+func validateColor(color string) error {
+	if color != "" && !colorPattern.MatchString(color) {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("color %q must be #RRGGBB", color))
+	}
+	return nil
+}
+
+func toCategoryMessage(c *productDB.Category) *category.Category {
+	msg := &category.Category{
+		CategoryId:   c.CategoryID,
+		Name:         c.Name,
+		Archived:     c.Archived,
+		ProductCount: c.ProductCount,
+	}
+	if c.ParentID != nil {
+		msg.ParentId = *c.ParentID
+	}
+	if c.Color != nil {
+		msg.Color = *c.Color
+	}
+	if c.Icon != nil {
+		msg.Icon = *c.Icon
+	}
+	return msg
+}
+
+// categoryError reports a name already in use as AlreadyExists and moves
+// that would nest a category under itself as InvalidArgument.
+func categoryError(err error) error {
+	switch {
+	case errors.Is(err, productDB.ErrCategoryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrCategoryNameTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, productDB.ErrCategoryCycle):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, productDB.ErrCategoryArchived):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
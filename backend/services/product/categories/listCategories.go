@@ -0,0 +1,29 @@
+package categories
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// ListCategories returns the caller's category tree, parents first. New users
+// get the default taxonomy on their first call.
+func ListCategories(ctx context.Context, req *category.ListCategoriesRequest) (*category.CategoryList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := productDB.ListCategories(ctx, userId, req.GetIncludeArchived())
+	if err != nil {
+		return nil, categoryError(err)
+	}
+
+	list := &category.CategoryList{}
+	for i := range results {
+		list.Categories = append(list.Categories, toCategoryMessage(&results[i]))
+	}
+	return list, nil
+}
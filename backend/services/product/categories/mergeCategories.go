@@ -0,0 +1,27 @@
+package categories
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// MergeCategories folds one category into another, for example a duplicate the
+// LLM invented into the category it should have used.
+func MergeCategories(ctx context.Context, req *category.MergeCategoriesRequest) (*category.MergeCategoriesResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target, moved, err := productDB.MergeCategories(ctx, userId, req.GetSourceId(), req.GetTargetId())
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	return &category.MergeCategoriesResponse{
+		Target:        toCategoryMessage(target),
+		MovedProducts: moved,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package categories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultSuggestions = 3
+	maxSuggestions     = 10
+)
+
+// SuggestCategory asks the caller's classifier which categories a product
+// belongs to. Callers without a trained classifier get no suggestions.
+func SuggestCategory(ctx context.Context, req *category.SuggestCategoryRequest) (*category.SuggestCategoryResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.GetProductName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "product name is required")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSuggestions
+	}
+	if limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+
+	suggestions, confident, err := productDB.SuggestCategories(ctx, userId, name, req.GetMerchant(), limit)
+	if err != nil {
+		return nil, categoryError(err)
+	}
+
+	resp := &category.SuggestCategoryResponse{Confident: confident}
+	for i := range suggestions {
+		resp.Suggestions = append(resp.Suggestions, &category.CategorySuggestion{
+			Category:   toCategoryMessage(&suggestions[i].Category),
+			Confidence: suggestions[i].Confidence,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,52 @@
+package categories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// UpdateCategory renames, recolours, re-icons or moves a category.
+func UpdateCategory(ctx context.Context, req *category.UpdateCategoryRequest) (*category.Category, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var update productDB.CategoryUpdate
+	if req.Name != nil {
+		name, err := validateName(req.GetName())
+		if err != nil {
+			return nil, err
+		}
+		update.Name = &name
+	}
+	if req.ParentId != nil {
+		if req.GetParentId() == 0 {
+			update.ClearParent = true
+		} else {
+			parentID := req.GetParentId()
+			update.ParentID = &parentID
+		}
+	}
+	if req.Color != nil {
+		if err := validateColor(req.GetColor()); err != nil {
+			return nil, err
+		}
+		color := req.GetColor()
+		update.Color = &color
+	}
+	if req.Icon != nil {
+		icon := strings.TrimSpace(req.GetIcon())
+		update.Icon = &icon
+	}
+
+	updated, err := productDB.UpdateCategory(ctx, userId, req.GetCategoryId(), update)
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	return toCategoryMessage(updated), nil
+}
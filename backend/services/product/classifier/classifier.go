@@ -0,0 +1,238 @@
+// Package classifier is a per-user multinomial naive Bayes model that learns
+// categories from product names and merchants. It is trained from the
+// categories users pick by hand and runs entirely in-process; the same
+// examples always produce the same model and predictions.
+package classifier
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MinExamples is how many examples a model needs before its predictions
+	// are used to assign categories.
+	MinExamples = 5
+	// AssignThreshold is the confidence a prediction needs to be assigned
+	// without asking the user.
+	AssignThreshold = 0.6
+	// merchantPrefix keeps merchant features apart from name tokens.
+	merchantPrefix = "m:"
+)
+
+// Example is one product the user categorised.
+type Example struct {
+	ProductName string
+	Merchant    string
+	CategoryID  int32
+}
+
+// Class holds the token counts of one category.
+type Class struct {
+	Documents  int            `json:"documents"`
+	TokenTotal int            `json:"token_total"`
+	Tokens     map[string]int `json:"tokens"`
+}
+
+// Model is a trained classifier. It is stored as JSON.
+type Model struct {
+	Classes        map[int32]*Class `json:"classes"`
+	Documents      int              `json:"documents"`
+	VocabularySize int              `json:"vocabulary_size"`
+	// Accuracy is the leave-one-out accuracy over the training examples, or 0
+	// when there were fewer than two.
+	Accuracy float64 `json:"accuracy"`
+}
+
+// Prediction is a category and the model's confidence in it, from 0 to 1.
+type Prediction struct {
+	CategoryID int32
+	Confidence float64
+}
+
+// Features turns a product into the tokens the model counts: the words of its
+// name and the merchant as a whole.
+func Features(productName, merchant string) []string {
+	var features []string
+	for _, word := range words(productName) {
+		// Lone letters and bare numbers (sizes, prices, codes) say nothing
+		// about the category.
+		if len([]rune(word)) < 2 || isNumber(word) {
+			continue
+		}
+		features = append(features, word)
+	}
+	if m := strings.Join(words(merchant), " "); m != "" {
+		features = append(features, merchantPrefix+m)
+	}
+	return features
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Train builds a model from examples. Examples without features are ignored.
+func Train(examples []Example) *Model {
+	m := &Model{Classes: map[int32]*Class{}}
+	vocabulary := map[string]bool{}
+	var featureSets [][]string
+	var labels []int32
+
+	for _, example := range examples {
+		features := Features(example.ProductName, example.Merchant)
+		if len(features) == 0 {
+			continue
+		}
+		class := m.Classes[example.CategoryID]
+		if class == nil {
+			class = &Class{Tokens: map[string]int{}}
+			m.Classes[example.CategoryID] = class
+		}
+		class.Documents++
+		for _, feature := range features {
+			class.Tokens[feature]++
+			class.TokenTotal++
+			vocabulary[feature] = true
+		}
+		m.Documents++
+		featureSets = append(featureSets, features)
+		labels = append(labels, example.CategoryID)
+	}
+	m.VocabularySize = len(vocabulary)
+
+	if m.Documents >= 2 {
+		correct := 0
+		for i, features := range featureSets {
+			predictions := m.predict(features, labels[i])
+			if len(predictions) > 0 && predictions[0].CategoryID == labels[i] {
+				correct++
+			}
+		}
+		m.Accuracy = float64(correct) / float64(m.Documents)
+	}
+	return m
+}
+
+// Predict ranks the categories a product may belong to, most likely first.
+// It returns nil when the model knows none of the product's features.
+func (m *Model) Predict(productName, merchant string) []Prediction {
+	if m == nil || m.Documents == 0 {
+		return nil
+	}
+	return m.predict(Features(productName, merchant), -1)
+}
+
+// Confident returns the best prediction if it is good enough to assign.
+func (m *Model) Confident(productName, merchant string) (Prediction, bool) {
+	if m == nil || m.Documents < MinExamples {
+		return Prediction{}, false
+	}
+	predictions := m.Predict(productName, merchant)
+	if len(predictions) == 0 || predictions[0].Confidence < AssignThreshold {
+		return Prediction{}, false
+	}
+	return predictions[0], true
+}
+
+// predict scores features against every class. If heldOut is a class ID, the
+// features are treated as one of that class's training examples and left out
+// of its counts, which gives the leave-one-out estimate.
+func (m *Model) predict(features []string, heldOut int32) []Prediction {
+	known := features[:0:0]
+	for _, feature := range features {
+		for _, class := range m.Classes {
+			if class.Tokens[feature] > 0 {
+				known = append(known, feature)
+				break
+			}
+		}
+	}
+	documents := m.Documents
+	if heldOut >= 0 {
+		documents--
+	}
+	if len(known) == 0 || documents <= 0 {
+		return nil
+	}
+
+	ids := make([]int32, 0, len(m.Classes))
+	for id := range m.Classes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	vocabulary := float64(m.VocabularySize)
+	classCount := float64(len(ids))
+	scores := make([]float64, 0, len(ids))
+	var kept []int32
+	for _, id := range ids {
+		class := m.Classes[id]
+		classDocuments := class.Documents
+		tokenTotal := class.TokenTotal
+		if id == heldOut {
+			classDocuments--
+			tokenTotal -= len(features)
+		}
+		if classDocuments <= 0 {
+			continue
+		}
+
+		// Laplace-smoothed log probabilities.
+		score := math.Log(float64(classDocuments+1) / (float64(documents) + classCount))
+		for _, feature := range known {
+			count := class.Tokens[feature]
+			if id == heldOut {
+				count -= occurrences(features, feature)
+			}
+			score += math.Log((float64(count) + 1) / (float64(tokenTotal) + vocabulary))
+		}
+		scores = append(scores, score)
+		kept = append(kept, id)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	best := math.Inf(-1)
+	for _, score := range scores {
+		best = math.Max(best, score)
+	}
+	var sum float64
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - best)
+		sum += scores[i]
+	}
+
+	predictions := make([]Prediction, len(kept))
+	for i, id := range kept {
+		predictions[i] = Prediction{CategoryID: id, Confidence: scores[i] / sum}
+	}
+	sort.SliceStable(predictions, func(i, j int) bool {
+		return predictions[i].Confidence > predictions[j].Confidence
+	})
+	return predictions
+}
+
+func occurrences(features []string, feature string) int {
+	n := 0
+	for _, f := range features {
+		if f == feature {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package classifier
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var examples = []Example{
+	{ProductName: "Whole Milk 1L", Merchant: "Corner Shop", CategoryID: 3},
+	{ProductName: "Sourdough Bread", Merchant: "Corner Shop", CategoryID: 3},
+	{ProductName: "Free Range Eggs x12", Merchant: "Corner Shop", CategoryID: 3},
+	{ProductName: "Unleaded Petrol", Merchant: "Fuel Stop", CategoryID: 7},
+	{ProductName: "Diesel", Merchant: "Fuel Stop", CategoryID: 7},
+	{ProductName: "Screen Wash", Merchant: "Fuel Stop", CategoryID: 7},
+	{ProductName: "Paracetamol 500mg", Merchant: "Pharmacy", CategoryID: 5},
+	{ProductName: "42", Merchant: "", CategoryID: 5},
+}
+
+func TestTrainIsDeterministic(t *testing.T) {
+	want := Train(examples)
+
+	reversed := make([]Example, len(examples))
+	for i, example := range examples {
+		reversed[len(examples)-1-i] = example
+	}
+	for name, input := range map[string][]Example{"same order": examples, "reversed": reversed} {
+		if got := Train(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Train = %+v, want %+v", name, got, want)
+		}
+	}
+
+	if want.Documents != 7 {
+		t.Errorf("Documents = %d, want 7 (examples without features are ignored)", want.Documents)
+	}
+}
+
+func TestPredictIsDeterministic(t *testing.T) {
+	model := Train(examples)
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := &Model{}
+	if err := json.Unmarshal(encoded, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, product := range []struct{ name, merchant string }{
+		{"Semi Skimmed Milk", "Corner Shop"},
+		{"Petrol", ""},
+		{"Milk", "Fuel Stop"},
+		{"unknown thing", ""},
+	} {
+		want := model.Predict(product.name, product.merchant)
+		for i := 0; i < 20; i++ {
+			if got := model.Predict(product.name, product.merchant); !reflect.DeepEqual(got, want) {
+				t.Fatalf("Predict(%q, %q) changed between calls: %v then %v", product.name, product.merchant, want, got)
+			}
+		}
+		if got := stored.Predict(product.name, product.merchant); !reflect.DeepEqual(got, want) {
+			t.Errorf("Predict(%q, %q) after JSON round trip = %v, want %v", product.name, product.merchant, got, want)
+		}
+	}
+}
+
+func TestPredictBreaksTiesByCategoryID(t *testing.T) {
+	model := Train([]Example{
+		{ProductName: "gift card", CategoryID: 9},
+		{ProductName: "gift card", CategoryID: 4},
+	})
+	for i := 0; i < 20; i++ {
+		predictions := model.Predict("gift card", "")
+		if len(predictions) != 2 || predictions[0].CategoryID != 4 || predictions[1].CategoryID != 9 {
+			t.Fatalf("Predict = %v, want category 4 then 9", predictions)
+		}
+	}
+}
+
+func TestConfident(t *testing.T) {
+	model := Train(examples)
+	prediction, ok := model.Confident("Skimmed Milk", "Corner Shop")
+	if !ok || prediction.CategoryID != 3 {
+		t.Errorf("Confident(milk) = %v, %v, want category 3", prediction, ok)
+	}
+	if _, ok := Train(examples[:3]).Confident("Milk", "Corner Shop"); ok {
+		t.Errorf("Confident with %d examples should wait for %d", 3, MinExamples)
+	}
+	if _, ok := (*Model)(nil).Confident("Milk", ""); ok {
+		t.Error("Confident on a nil model should be false")
+	}
+}
@@ -0,0 +1,317 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+)
+
+// Groupings accepted by the spending aggregates.
+const (
+	GroupByCategory = "category"
+	GroupByMerchant = "merchant"
+)
+
+// Time buckets, named as date_trunc names them. Weeks start on Monday.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// SpendingQuery selects the products to aggregate. To is exclusive; an empty
+// Interval totals each group over the whole range.
+type SpendingQuery struct {
+	From     time.Time
+	To       time.Time
+	GroupBy  string
+	Interval string
+	// TopLevel counts subcategories towards their root category.
+	TopLevel bool
+}
+
+// PeriodAmount is the amount that fell in the bucket starting at Start.
+type PeriodAmount struct {
+	Start  time.Time
+	Amount float64
+}
+
+// SpendingGroup is the spend of one category or merchant.
+type SpendingGroup struct {
+	// Key identifies the group across queries: the category ID, or the
+	// merchant name folded to lower case. It is "" for products without a
+	// category or merchant.
+	Key        string
+	CategoryID int32
+	Label      string
+	Total      float64
+	Items      int
+	Periods    []PeriodAmount
+}
+
+// GetSpending sums the user's spend per group, largest first. Split products
+// count with the parts the user keeps, each under its own category.
+func GetSpending(ctx context.Context, userID string, q SpendingQuery) ([]SpendingGroup, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var key, categoryID, label, joins string
+	switch q.GroupBy {
+	case GroupByMerchant:
+		key = "COALESCE(lower(btrim(t.merchant_name)), '')"
+		categoryID = "0"
+		label = "COALESCE(btrim(t.merchant_name), '')"
+		joins = `
+        LEFT JOIN product_category_service.receipt_texts t
+            ON t.user_id = p.user_id AND t.file_name = p.file_name`
+	case GroupByCategory:
+		key = "COALESCE(c.category_id::text, '')"
+		categoryID = "COALESCE(c.category_id, 0)"
+		label = "COALESCE(c.name, '')"
+		if q.TopLevel {
+			joins = `
+        LEFT JOIN roots r ON r.category_id = p.category_id
+        LEFT JOIN product_category_service.categories c
+            ON c.category_id = COALESCE(r.root_id, p.category_id)`
+		} else {
+			joins = `
+        LEFT JOIN product_category_service.categories c ON c.category_id = p.category_id`
+		}
+	default:
+		return nil, fmt.Errorf("unknown grouping %q", q.GroupBy)
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        WITH RECURSIVE roots AS (
+            SELECT category_id, category_id AS root_id
+            FROM product_category_service.categories
+            WHERE user_id = $1 AND parent_id IS NULL
+            UNION
+            SELECT c.category_id, r.root_id
+            FROM product_category_service.categories c
+            JOIN roots r ON c.parent_id = r.category_id
+        )
+        SELECT `+key+`, MAX(`+categoryID+`), MIN(`+label+`),
+            CASE WHEN $4::text = '' THEN NULL ELSE date_trunc($4::text, p.date_added)::date END,
+            SUM(p.amount), COUNT(DISTINCT p.product_id) FILTER (WHERE NOT p.refund)
+        FROM `+allocatedProducts+` p`+joins+`
+        WHERE p.user_id = $1 AND p.date_added >= $2::date AND p.date_added < $3::date
+        GROUP BY 1, 4
+        ORDER BY 1, 4`,
+		userIDInt, q.From, q.To, q.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("error summing spend: %v", err)
+	}
+	defer rows.Close()
+
+	var groups []SpendingGroup
+	index := map[string]int{}
+	for rows.Next() {
+		var row SpendingGroup
+		var period *time.Time
+		if err := rows.Scan(&row.Key, &row.CategoryID, &row.Label, &period, &row.Total, &row.Items); err != nil {
+			return nil, fmt.Errorf("error scanning spend: %v", err)
+		}
+		i, ok := index[row.Key]
+		if !ok {
+			i = len(groups)
+			index[row.Key] = i
+			groups = append(groups, SpendingGroup{Key: row.Key, CategoryID: row.CategoryID, Label: row.Label})
+		}
+		group := &groups[i]
+		// Merchants spelled differently over time are labelled consistently.
+		if row.Label < group.Label {
+			group.Label = row.Label
+		}
+		group.Total += row.Total
+		group.Items += row.Items
+		if period != nil {
+			group.Periods = append(group.Periods, PeriodAmount{Start: *period, Amount: row.Total})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Total > groups[j].Total
+	})
+	return groups, nil
+}
+
+// CashFlowPeriod is what came in, went out and moved between accounts in
+// one bucket.
+type CashFlowPeriod struct {
+	Start       time.Time
+	Income      float64
+	Expense     float64
+	Transferred float64
+}
+
+// GetCashFlow returns income, spend and transfers for every bucket between
+// from and to (exclusive), oldest first, including empty ones.
+func GetCashFlow(ctx context.Context, userID string, from, to time.Time, interval string) ([]CashFlowPeriod, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        WITH buckets AS (
+            SELECT generate_series(
+                date_trunc($4::text, $2::date::timestamp),
+                $3::date::timestamp - interval '1 day',
+                ('1 ' || $4::text)::interval)::date AS period
+        ),
+        expense AS (
+            SELECT date_trunc($4::text, p.date_added)::date AS period, SUM(p.amount) AS amount
+            FROM `+allocatedProducts+` p
+            WHERE p.user_id = $1 AND p.date_added >= $2::date AND p.date_added < $3::date
+            GROUP BY 1
+        ),
+        income AS (
+            SELECT date_trunc($4::text, i.date_added::timestamp)::date AS period, SUM(i.amount)::float8 AS amount
+            FROM account_income_service.incomes i
+            WHERE i.user_id = $1 AND i.date_added::timestamp >= $2::date AND i.date_added::timestamp < $3::date
+            GROUP BY 1
+        ),
+        moved AS (
+            SELECT date_trunc($4::text, tr.date_added::timestamp)::date AS period, SUM(tr.amount)::float8 AS amount
+            FROM transfer_service.transfers tr
+            WHERE tr.user_id = $1 AND tr.date_added::timestamp >= $2::date AND tr.date_added::timestamp < $3::date
+            GROUP BY 1
+        )
+        SELECT b.period, COALESCE(i.amount, 0), COALESCE(e.amount, 0), COALESCE(m.amount, 0)
+        FROM buckets b
+        LEFT JOIN income i ON i.period = b.period
+        LEFT JOIN expense e ON e.period = b.period
+        LEFT JOIN moved m ON m.period = b.period
+        ORDER BY b.period`,
+		userIDInt, from, to, interval)
+	if err != nil {
+		return nil, fmt.Errorf("error computing cash flow: %v", err)
+	}
+	defer rows.Close()
+
+	var periods []CashFlowPeriod
+	for rows.Next() {
+		var p CashFlowPeriod
+		if err := rows.Scan(&p.Start, &p.Income, &p.Expense, &p.Transferred); err != nil {
+			return nil, fmt.Errorf("error scanning cash flow: %v", err)
+		}
+		periods = append(periods, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return periods, nil
+}
+
+// GetIncomeTotal sums the user's income between from and to (exclusive).
+func GetIncomeTotal(ctx context.Context, userID string, from, to time.Time) (float64, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	err = sharedDB.GetDB().QueryRow(ctx, `
+        SELECT COALESCE(SUM(amount), 0)::float8
+        FROM account_income_service.incomes
+        WHERE user_id = $1 AND date_added::timestamp >= $2::date AND date_added::timestamp < $3::date`,
+		userIDInt, from, to).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("error summing income: %v", err)
+	}
+	return total, nil
+}
+
+// Orderings accepted by GetTopItems.
+const (
+	TopItemsByAmount = "amount"
+	TopItemsByCount  = "count"
+)
+
+// TopItem is everything bought under one name.
+type TopItem struct {
+	ProductName   string
+	Total         float64
+	Quantity      int64
+	Purchases     int
+	LastPurchased time.Time
+	Category      string
+}
+
+// TopItemsQuery selects and orders the items GetTopItems returns.
+type TopItemsQuery struct {
+	From       time.Time
+	To         time.Time
+	OrderBy    string
+	CategoryID int32
+	Limit      int
+}
+
+// GetTopItems returns the items the user spent most on, or bought most
+// often. Items are grouped by name, ignoring case and surrounding spaces.
+func GetTopItems(ctx context.Context, userID string, q TopItemsQuery) ([]TopItem, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := "2 DESC, 4 DESC"
+	if q.OrderBy == TopItemsByCount {
+		orderBy = "4 DESC, 2 DESC"
+	}
+
+	var categoryID *int32
+	if q.CategoryID != 0 {
+		categoryID = &q.CategoryID
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT MIN(btrim(p.product_name)), SUM(p.amount), COALESCE(ROUND(SUM(p.quantity * p.share)), 0)::bigint,
+            COUNT(DISTINCT p.product_id) FILTER (WHERE NOT p.refund),
+            COALESCE(MAX(p.date_added) FILTER (WHERE NOT p.refund), MAX(p.date_added)),
+            (array_agg(COALESCE(c.name, '') ORDER BY p.date_added DESC, p.product_id DESC, p.amount DESC))[1]
+        FROM `+allocatedProducts+` p
+        LEFT JOIN product_category_service.categories c ON c.category_id = p.category_id
+        WHERE p.user_id = $1 AND p.date_added >= $2::date AND p.date_added < $3::date
+          AND btrim(COALESCE(p.product_name, '')) <> ''
+          AND ($4::int IS NULL OR p.category_id IN (
+            WITH RECURSIVE tree AS (
+                SELECT category_id FROM product_category_service.categories
+                WHERE user_id = $1 AND category_id = $4
+                UNION
+                SELECT c.category_id FROM product_category_service.categories c
+                JOIN tree t ON c.parent_id = t.category_id OR c.merged_into = t.category_id
+            )
+            SELECT category_id FROM tree))
+        GROUP BY lower(btrim(p.product_name))
+        ORDER BY `+orderBy+`, 1
+        LIMIT $5`,
+		userIDInt, q.From, q.To, categoryID, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("error ranking items: %v", err)
+	}
+	defer rows.Close()
+
+	var items []TopItem
+	for rows.Next() {
+		var item TopItem
+		if err := rows.Scan(&item.ProductName, &item.Total, &item.Quantity, &item.Purchases,
+			&item.LastPurchased, &item.Category); err != nil {
+			return nil, fmt.Errorf("error scanning item: %v", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,470 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/budgeting"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrBudgetNotFound = errors.New("budget not found")
+	ErrInvalidBudget  = errors.New("invalid budget")
+)
+
+// Budget caps spending per period, overall or on a category and its
+// subcategories.
+type Budget struct {
+	BudgetID     int32
+	UserID       int32
+	Name         string
+	CategoryID   *int32
+	CategoryName *string
+	Amount       float64
+	Schedule     budgeting.Schedule
+	Rollover     string
+}
+
+const budgetColumns = `b.budget_id, b.user_id, b.name, b.category_id, c.name, b.amount::float8,
+        b.period, coalesce(b.period_days, 0), b.start_date, b.end_date, b.rollover`
+
+func scanBudget(row pgx.Row) (Budget, error) {
+	var budget Budget
+	err := row.Scan(&budget.BudgetID, &budget.UserID, &budget.Name, &budget.CategoryID, &budget.CategoryName,
+		&budget.Amount, &budget.Schedule.Period, &budget.Schedule.Days, &budget.Schedule.Start,
+		&budget.Schedule.End, &budget.Rollover)
+	return budget, err
+}
+
+const budgetFrom = `
+        FROM product_category_service.budgets b
+        LEFT JOIN product_category_service.categories c ON c.category_id = b.category_id`
+
+// BudgetStatus is a budget and where it stands in one period.
+type BudgetStatus struct {
+	Budget Budget
+	budgeting.Status
+	// Current is false when the requested date falls outside the budget's
+	// schedule and the nearest period was used instead.
+	Current bool
+}
+
+func listBudgets(ctx context.Context, tx pgx.Tx, userID int32, budgetID int32) ([]Budget, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT `+budgetColumns+budgetFrom+`
+        WHERE b.user_id = $1 AND ($2 = 0 OR b.budget_id = $2)
+        ORDER BY b.category_id NULLS FIRST, lower(b.name), b.budget_id`,
+		userID, budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing budgets: %v", err)
+	}
+	defer rows.Close()
+
+	var budgets []Budget
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning budget: %v", err)
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets, rows.Err()
+}
+
+// ListBudgets returns the user's budgets, overall budgets first.
+func ListBudgets(ctx context.Context, userID string) ([]Budget, error) {
+	var budgets []Budget
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		var err error
+		budgets, err = listBudgets(ctx, tx, userIDInt, 0)
+		return err
+	})
+	return budgets, err
+}
+
+func getBudget(ctx context.Context, tx pgx.Tx, userID int32, budgetID int32) (*Budget, error) {
+	budget, err := scanBudget(tx.QueryRow(ctx, `
+        SELECT `+budgetColumns+budgetFrom+`
+        WHERE b.user_id = $1 AND b.budget_id = $2`,
+		userID, budgetID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrBudgetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching budget: %v", err)
+	}
+	return &budget, nil
+}
+
+// checkBudget validates a budget before it is saved.
+func checkBudget(ctx context.Context, tx pgx.Tx, userID int32, budget *Budget) error {
+	if budget.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidBudget)
+	}
+	if err := budget.Schedule.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBudget, err)
+	}
+	if !budgeting.ValidRollover(budget.Rollover) {
+		return fmt.Errorf("%w: rollover must be %s, %s or %s", ErrInvalidBudget,
+			budgeting.RolloverNone, budgeting.RolloverUnspent, budgeting.RolloverAll)
+	}
+	if budget.CategoryID != nil {
+		return categoryExists(ctx, tx, userID, *budget.CategoryID)
+	}
+	return nil
+}
+
+func periodDays(schedule budgeting.Schedule) *int {
+	if schedule.Period != budgeting.PeriodCustom {
+		return nil
+	}
+	return &schedule.Days
+}
+
+// CreateBudget saves a new budget for the user.
+func CreateBudget(ctx context.Context, userID string, budget Budget) (*Budget, error) {
+	var created *Budget
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if err := checkBudget(ctx, tx, userIDInt, &budget); err != nil {
+			return err
+		}
+
+		var budgetID int32
+		err := tx.QueryRow(ctx, `
+            INSERT INTO product_category_service.budgets
+                (user_id, name, category_id, amount, period, period_days, start_date, end_date, rollover)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            RETURNING budget_id`,
+			userIDInt, budget.Name, budget.CategoryID, budget.Amount, budget.Schedule.Period,
+			periodDays(budget.Schedule), budget.Schedule.Start, budget.Schedule.End, budget.Rollover).Scan(&budgetID)
+		if err != nil {
+			return fmt.Errorf("error creating budget: %v", err)
+		}
+		created, err = getBudget(ctx, tx, userIDInt, budgetID)
+		if err != nil {
+			return err
+		}
+		_, err = storePeriodSpend(ctx, tx, *created, statusPeriods(*created, time.Now()))
+		return err
+	})
+	return created, err
+}
+
+// UpdateBudget replaces one of the user's budgets. Recorded spend is
+// recomputed, since the category or periods may have changed.
+func UpdateBudget(ctx context.Context, userID string, budget Budget) (*Budget, error) {
+	var updated *Budget
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if _, err := getBudget(ctx, tx, userIDInt, budget.BudgetID); err != nil {
+			return err
+		}
+		if err := checkBudget(ctx, tx, userIDInt, &budget); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, `
+            UPDATE product_category_service.budgets
+            SET name = $1, category_id = $2, amount = $3, period = $4, period_days = $5,
+                start_date = $6, end_date = $7, rollover = $8, updated_at = NOW()
+            WHERE user_id = $9 AND budget_id = $10`,
+			budget.Name, budget.CategoryID, budget.Amount, budget.Schedule.Period, periodDays(budget.Schedule),
+			budget.Schedule.Start, budget.Schedule.End, budget.Rollover, userIDInt, budget.BudgetID)
+		if err != nil {
+			return fmt.Errorf("error updating budget: %v", err)
+		}
+		_, err = tx.Exec(ctx, `
+            DELETE FROM product_category_service.budget_periods WHERE budget_id = $1`,
+			budget.BudgetID)
+		if err != nil {
+			return fmt.Errorf("error clearing budget spend: %v", err)
+		}
+		updated, err = getBudget(ctx, tx, userIDInt, budget.BudgetID)
+		if err != nil {
+			return err
+		}
+		_, err = storePeriodSpend(ctx, tx, *updated, statusPeriods(*updated, time.Now()))
+		return err
+	})
+	return updated, err
+}
+
+// DeleteBudget removes one of the user's budgets.
+func DeleteBudget(ctx context.Context, userID string, budgetID int32) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+	tag, err := sharedDB.GetDB().Exec(ctx, `
+        DELETE FROM product_category_service.budgets
+        WHERE user_id = $1 AND budget_id = $2`,
+		userIDInt, budgetID)
+	if err != nil {
+		return fmt.Errorf("error deleting budget: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// dailySpend sums the user's line totals per day over [from, to), counting
+// only the budget's category and its subcategories when it has one. Split
+// products count with the parts the user keeps, under their own categories.
+func dailySpend(ctx context.Context, tx pgx.Tx, userID int32, categoryID *int32, from, to time.Time) (map[time.Time]float64, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT p.date_added::date, COALESCE(SUM(p.amount), 0)::float8
+        FROM `+allocatedProducts+` p
+        WHERE p.user_id = $1 AND p.date_added >= $2 AND p.date_added < $3
+          AND ($4::int IS NULL OR p.category_id IN (
+            WITH RECURSIVE tree AS (
+                SELECT category_id FROM product_category_service.categories
+                WHERE user_id = $1 AND category_id = $4
+                UNION
+                SELECT c.category_id FROM product_category_service.categories c
+                JOIN tree t ON c.parent_id = t.category_id OR c.merged_into = t.category_id
+            )
+            SELECT category_id FROM tree))
+        GROUP BY 1`,
+		userID, from, to, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("error summing spend: %v", err)
+	}
+	defer rows.Close()
+
+	spend := map[time.Time]float64{}
+	for rows.Next() {
+		var day time.Time
+		var total float64
+		if err := rows.Scan(&day, &total); err != nil {
+			return nil, fmt.Errorf("error scanning spend: %v", err)
+		}
+		spend[budgeting.Date(day)] += total
+	}
+	return spend, rows.Err()
+}
+
+// periodSpend computes the spend of a budget's periods.
+func periodSpend(ctx context.Context, tx pgx.Tx, budget Budget, periods []budgeting.Period) (map[time.Time]float64, error) {
+	spent := map[time.Time]float64{}
+	if len(periods) == 0 {
+		return spent, nil
+	}
+
+	from, to := periods[0].Start, periods[0].End
+	for _, p := range periods[1:] {
+		if p.Start.Before(from) {
+			from = p.Start
+		}
+		if p.End.After(to) {
+			to = p.End
+		}
+	}
+	daily, err := dailySpend(ctx, tx, budget.UserID, budget.CategoryID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range periods {
+		total := 0.0
+		for day, amount := range daily {
+			if p.Contains(day) {
+				total += amount
+			}
+		}
+		spent[p.Start] = total
+	}
+	return spent, nil
+}
+
+// storePeriodSpend recomputes and records the spend of a budget's periods.
+func storePeriodSpend(ctx context.Context, tx pgx.Tx, budget Budget, periods []budgeting.Period) (map[time.Time]float64, error) {
+	spent, err := periodSpend(ctx, tx, budget, periods)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range periods {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO product_category_service.budget_periods (budget_id, period_start, period_end, spent, refreshed_at)
+            VALUES ($1, $2, $3, $4, NOW())
+            ON CONFLICT (budget_id, period_start) DO UPDATE
+            SET period_end = EXCLUDED.period_end, spent = EXCLUDED.spent, refreshed_at = EXCLUDED.refreshed_at`,
+			budget.BudgetID, p.Start, p.End, spent[p.Start])
+		if err != nil {
+			return nil, fmt.Errorf("error recording budget spend: %v", err)
+		}
+	}
+	return spent, nil
+}
+
+// RefreshBudgetSpend recomputes the spend of every budget period that
+// contains one of the given days. Paths that add, change or remove products
+// call it in the same transaction so budgets stay current.
+func RefreshBudgetSpend(ctx context.Context, tx pgx.Tx, userID int32, days []time.Time) error {
+	if len(days) == 0 {
+		return nil
+	}
+	budgets, err := listBudgets(ctx, tx, userID, 0)
+	if err != nil {
+		return err
+	}
+	for _, budget := range budgets {
+		periods := budget.Schedule.PeriodsContaining(days)
+		if _, err := storePeriodSpend(ctx, tx, budget, periods); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecomputeBudgetSpend records afresh the spend of all of the user's budgets
+// up to today. Used after changes that move many products between categories.
+func RecomputeBudgetSpend(ctx context.Context, tx pgx.Tx, userID int32) error {
+	_, err := tx.Exec(ctx, `
+        DELETE FROM product_category_service.budget_periods bp
+        USING product_category_service.budgets b
+        WHERE bp.budget_id = b.budget_id AND b.user_id = $1`,
+		userID)
+	if err != nil {
+		return fmt.Errorf("error clearing budget spend: %v", err)
+	}
+	budgets, err := listBudgets(ctx, tx, userID, 0)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, budget := range budgets {
+		if _, err := storePeriodSpend(ctx, tx, budget, statusPeriods(budget, now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusPeriods returns the periods whose spend the budget's status on day
+// depends on: every period so far when spend rolls over, else just the one
+// containing day.
+func statusPeriods(budget Budget, day time.Time) []budgeting.Period {
+	target, _ := budget.Schedule.PeriodAt(day)
+	periods := budget.Schedule.Through(target)
+	if budget.Rollover == budgeting.RolloverNone && len(periods) > 0 {
+		periods = periods[len(periods)-1:]
+	}
+	return periods
+}
+
+// GetBudgetStatuses returns the status of the user's budgets, or just one if
+// budgetID is set, in the period containing day. It only reads: periods with
+// no recorded spend are computed without being stored, and the write paths
+// record them once products land in them.
+func GetBudgetStatuses(ctx context.Context, userID string, budgetID int32, day time.Time) ([]BudgetStatus, error) {
+	var statuses []BudgetStatus
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		budgets, err := listBudgets(ctx, tx, userIDInt, budgetID)
+		if err != nil {
+			return err
+		}
+		if budgetID != 0 && len(budgets) == 0 {
+			return ErrBudgetNotFound
+		}
+
+		for _, budget := range budgets {
+			_, current := budget.Schedule.PeriodAt(day)
+			periods := statusPeriods(budget, day)
+
+			spent, err := recordedSpend(ctx, tx, budget.BudgetID, periods)
+			if err != nil {
+				return err
+			}
+			var missing []budgeting.Period
+			for _, p := range periods {
+				if _, ok := spent[p.Start]; !ok {
+					missing = append(missing, p)
+				}
+			}
+			computed, err := periodSpend(ctx, tx, budget, missing)
+			if err != nil {
+				return err
+			}
+			for start, total := range computed {
+				spent[start] = total
+			}
+
+			totals := make([]float64, len(periods))
+			for i, p := range periods {
+				totals[i] = spent[p.Start]
+			}
+			statuses = append(statuses, BudgetStatus{
+				Budget:  budget,
+				Status:  budgeting.Evaluate(budget.Amount, budget.Rollover, periods, totals, time.Now()),
+				Current: current,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// recordedSpend returns the stored spend of those periods that have any and
+// whose bounds still match the schedule.
+func recordedSpend(ctx context.Context, tx pgx.Tx, budgetID int32, periods []budgeting.Period) (map[time.Time]float64, error) {
+	spent := map[time.Time]float64{}
+	if len(periods) == 0 {
+		return spent, nil
+	}
+	rows, err := tx.Query(ctx, `
+        SELECT period_start, period_end, spent::float8
+        FROM product_category_service.budget_periods
+        WHERE budget_id = $1 AND period_start >= $2 AND period_start <= $3`,
+		budgetID, periods[0].Start, periods[len(periods)-1].Start)
+	if err != nil {
+		return nil, fmt.Errorf("error loading budget spend: %v", err)
+	}
+	defer rows.Close()
+
+	ends := map[time.Time]time.Time{}
+	for _, p := range periods {
+		ends[p.Start] = p.End
+	}
+	for rows.Next() {
+		var start, end time.Time
+		var total float64
+		if err := rows.Scan(&start, &end, &total); err != nil {
+			return nil, fmt.Errorf("error scanning budget spend: %v", err)
+		}
+		start, end = budgeting.Date(start), budgeting.Date(end)
+		if expected, ok := ends[start]; ok && expected.Equal(end) {
+			spent[start] = total
+		}
+	}
+	return spent, rows.Err()
+}
+
+// FileProductDates returns the distinct days the products of one of the
+// user's receipts are dated, for refreshing budgets around a change to it.
+func FileProductDates(ctx context.Context, tx pgx.Tx, userID int32, fileName string) ([]time.Time, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT DISTINCT date_added::date FROM product_category_service.products
+        WHERE user_id = $1 AND file_name = $2 AND date_added IS NOT NULL`,
+		userID, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching product dates: %v", err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("error scanning product date: %v", err)
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
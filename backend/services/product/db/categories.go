@@ -0,0 +1,598 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/taxonomy"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrCategoryNameTaken = errors.New("a category with this name already exists")
+	ErrCategoryCycle     = errors.New("a category cannot be placed under itself or its descendants")
+	ErrCategoryArchived  = errors.New("category is archived")
+)
+
+// Category is a node of a user's category tree.
+type Category struct {
+	CategoryID   int32
+	UserID       int32
+	Name         string
+	ParentID     *int32
+	Color        *string
+	Icon         *string
+	Archived     bool
+	MergedInto   *int32
+	ProductCount int32
+}
+
+const categoryColumns = `c.category_id, c.user_id, c.name, c.parent_id, c.color, c.icon, c.archived, c.merged_into`
+
+func scanCategory(row pgx.Row) (Category, error) {
+	var category Category
+	err := row.Scan(&category.CategoryID, &category.UserID, &category.Name, &category.ParentID,
+		&category.Color, &category.Icon, &category.Archived, &category.MergedInto)
+	return category, err
+}
+
+// EnsureUserCategories seeds the default taxonomy the first time a user's
+// categories are touched, and moves their products off the legacy global
+// categories onto it.
+func EnsureUserCategories(ctx context.Context, tx pgx.Tx, userID int32) error {
+	// Serialise seeding per user so two concurrent first requests do not both
+	// insert the defaults.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('product_category_service.categories'), $1)`, userID); err != nil {
+		return fmt.Errorf("error locking categories: %v", err)
+	}
+
+	var seeded bool
+	err := tx.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM product_category_service.categories WHERE user_id = $1)`,
+		userID).Scan(&seeded)
+	if err != nil {
+		return fmt.Errorf("error checking categories: %v", err)
+	}
+	if seeded {
+		return nil
+	}
+
+	for _, root := range taxonomy.Defaults {
+		var rootID int32
+		err := tx.QueryRow(ctx, `
+            INSERT INTO product_category_service.categories (user_id, name, color, icon)
+            VALUES ($1, $2, $3, $4) RETURNING category_id`,
+			userID, root.Name, root.Color, root.Icon).Scan(&rootID)
+		if err != nil {
+			return fmt.Errorf("error seeding category %s: %v", root.Name, err)
+		}
+		for _, child := range root.Children {
+			_, err := tx.Exec(ctx, `
+                INSERT INTO product_category_service.categories (user_id, name, parent_id, color, icon)
+                VALUES ($1, $2, $3, $4, $5)`,
+				userID, child, rootID, root.Color, root.Icon)
+			if err != nil {
+				return fmt.Errorf("error seeding category %s: %v", child, err)
+			}
+		}
+	}
+
+	return remapLegacyCategories(ctx, tx, userID)
+}
+
+func remapLegacyCategories(ctx context.Context, tx pgx.Tx, userID int32) error {
+	rows, err := tx.Query(ctx, `
+        SELECT DISTINCT c.category_id, c.name
+        FROM product_category_service.products p
+        JOIN product_category_service.categories c ON p.category_id = c.category_id
+        WHERE p.user_id = $1 AND c.user_id IS NULL`,
+		userID)
+	if err != nil {
+		return fmt.Errorf("error loading legacy categories: %v", err)
+	}
+	legacy := map[int32]string{}
+	for rows.Next() {
+		var id int32
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning legacy category: %v", err)
+		}
+		legacy[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(legacy) == 0 {
+		return nil
+	}
+
+	resolver, err := LoadCategoryResolver(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	for legacyID, name := range legacy {
+		target := resolver.Resolve(name)
+		_, err := tx.Exec(ctx, `
+            UPDATE product_category_service.products
+            SET category_id = $1, suggested_category = COALESCE(suggested_category, $2)
+            WHERE user_id = $3 AND category_id = $4`,
+			target.CategoryID, name, userID, legacyID)
+		if err != nil {
+			return fmt.Errorf("error remapping category %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// CategoryResolver maps free-form labels onto one user's categories.
+type CategoryResolver struct {
+	byID       map[int32]Category
+	byName     map[string]int32
+	byAlias    map[string]int32
+	fallbackID int32
+}
+
+// LoadCategoryResolver reads the user's categories and aliases. The user must
+// already have been seeded with EnsureUserCategories.
+func LoadCategoryResolver(ctx context.Context, tx pgx.Tx, userID int32) (*CategoryResolver, error) {
+	r := &CategoryResolver{
+		byID:    map[int32]Category{},
+		byName:  map[string]int32{},
+		byAlias: map[string]int32{},
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT `+categoryColumns+`
+        FROM product_category_service.categories c
+        WHERE c.user_id = $1`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading categories: %v", err)
+	}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning category: %v", err)
+		}
+		r.byID[category.CategoryID] = category
+		// A merged category's name may since have been reused; the live one wins.
+		if _, exists := r.byName[taxonomy.Normalize(category.Name)]; !exists || category.MergedInto == nil {
+			r.byName[taxonomy.Normalize(category.Name)] = category.CategoryID
+		}
+		if category.Name == taxonomy.Uncategorized {
+			r.fallbackID = category.CategoryID
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(ctx, `
+        SELECT alias, category_id FROM product_category_service.category_aliases
+        WHERE user_id = $1`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading category aliases: %v", err)
+	}
+	for rows.Next() {
+		var alias string
+		var id int32
+		if err := rows.Scan(&alias, &id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning category alias: %v", err)
+		}
+		r.byAlias[alias] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.fallbackID == 0 {
+		return nil, fmt.Errorf("user %d has no %s category", userID, taxonomy.Uncategorized)
+	}
+	return r, nil
+}
+
+// Resolve returns the category a label belongs to. Merged categories resolve
+// to their target and archived ones to their nearest active ancestor; labels
+// that match nothing go to Uncategorized.
+func (r *CategoryResolver) Resolve(label string) Category {
+	if category, ok := r.Lookup(label); ok {
+		return category
+	}
+	return r.byID[r.fallbackID]
+}
+
+// Lookup is Resolve without the fallback: it reports false for labels that
+// match none of the user's categories.
+func (r *CategoryResolver) Lookup(label string) (Category, bool) {
+	for _, candidate := range taxonomy.Candidates(label) {
+		if id, ok := r.byName[candidate]; ok {
+			return r.active(id), true
+		}
+		if id, ok := r.byAlias[candidate]; ok {
+			return r.active(id), true
+		}
+	}
+	return Category{}, false
+}
+
+// ByID returns the active category a stored category ID currently stands for.
+func (r *CategoryResolver) ByID(id int32) (Category, bool) {
+	if _, ok := r.byID[id]; !ok {
+		return Category{}, false
+	}
+	return r.active(id), true
+}
+
+func (r *CategoryResolver) active(id int32) Category {
+	seen := map[int32]bool{}
+	for !seen[id] {
+		seen[id] = true
+		category, ok := r.byID[id]
+		if !ok {
+			break
+		}
+		switch {
+		case category.MergedInto != nil:
+			id = *category.MergedInto
+		case category.Archived && category.ParentID != nil:
+			id = *category.ParentID
+		case category.Archived:
+			return r.byID[r.fallbackID]
+		default:
+			return category
+		}
+	}
+	return r.byID[r.fallbackID]
+}
+
+// withUserCategories runs fn in a transaction after making sure the user's
+// categories are seeded.
+func withUserCategories(ctx context.Context, userID string, fn func(tx pgx.Tx, userID int32) error) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := EnsureUserCategories(ctx, tx, userIDInt); err != nil {
+		return err
+	}
+	if err := fn(tx, userIDInt); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing categories: %v", err)
+	}
+	return nil
+}
+
+// ListCategories returns the user's categories with the number of products in
+// each, seeding the defaults on first use.
+func ListCategories(ctx context.Context, userID string, includeArchived bool) ([]Category, error) {
+	var categories []Category
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		rows, err := tx.Query(ctx, `
+            SELECT `+categoryColumns+`, COUNT(p.product_id)::int
+            FROM product_category_service.categories c
+            LEFT JOIN product_category_service.products p
+                ON p.category_id = c.category_id AND p.user_id = c.user_id
+            WHERE c.user_id = $1 AND c.merged_into IS NULL AND ($2 OR NOT c.archived)
+            GROUP BY c.category_id
+            ORDER BY c.parent_id NULLS FIRST, lower(c.name)`,
+			userIDInt, includeArchived)
+		if err != nil {
+			return fmt.Errorf("error listing categories: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var category Category
+			err := rows.Scan(&category.CategoryID, &category.UserID, &category.Name, &category.ParentID,
+				&category.Color, &category.Icon, &category.Archived, &category.MergedInto, &category.ProductCount)
+			if err != nil {
+				return fmt.Errorf("error scanning category: %v", err)
+			}
+			categories = append(categories, category)
+		}
+		return rows.Err()
+	})
+	return categories, err
+}
+
+func getCategory(ctx context.Context, tx pgx.Tx, userID int32, categoryID int32) (*Category, error) {
+	category, err := scanCategory(tx.QueryRow(ctx, `
+        SELECT `+categoryColumns+`
+        FROM product_category_service.categories c
+        WHERE c.user_id = $1 AND c.category_id = $2 AND c.merged_into IS NULL`,
+		userID, categoryID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching category: %v", err)
+	}
+	return &category, nil
+}
+
+func nameTaken(ctx context.Context, tx pgx.Tx, userID int32, name string, exceptID int32) error {
+	var taken bool
+	err := tx.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM product_category_service.categories
+            WHERE user_id = $1 AND lower(name) = lower($2) AND category_id <> $3 AND merged_into IS NULL)`,
+		userID, name, exceptID).Scan(&taken)
+	if err != nil {
+		return fmt.Errorf("error checking category name: %v", err)
+	}
+	if taken {
+		return ErrCategoryNameTaken
+	}
+	return nil
+}
+
+// checkParent verifies that parentID can hold categoryID without creating a
+// cycle. categoryID is 0 for a category that does not exist yet.
+func checkParent(ctx context.Context, tx pgx.Tx, userID int32, categoryID, parentID int32) error {
+	parent, err := getCategory(ctx, tx, userID, parentID)
+	if err != nil {
+		return err
+	}
+	if parent.Archived {
+		return ErrCategoryArchived
+	}
+	if categoryID == 0 {
+		return nil
+	}
+
+	var cycle bool
+	err = tx.QueryRow(ctx, `
+        WITH RECURSIVE ancestors AS (
+            SELECT category_id, parent_id FROM product_category_service.categories
+            WHERE category_id = $1 AND user_id = $3
+            UNION
+            SELECT c.category_id, c.parent_id FROM product_category_service.categories c
+            JOIN ancestors a ON c.category_id = a.parent_id
+        )
+        SELECT EXISTS (SELECT 1 FROM ancestors WHERE category_id = $2)`,
+		parentID, categoryID, userID).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("error checking category hierarchy: %v", err)
+	}
+	if cycle {
+		return ErrCategoryCycle
+	}
+	return nil
+}
+
+// CreateCategory adds a category to the user's tree.
+func CreateCategory(ctx context.Context, userID string, category Category) (*Category, error) {
+	var created *Category
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if err := nameTaken(ctx, tx, userIDInt, category.Name, 0); err != nil {
+			return err
+		}
+		if category.ParentID != nil {
+			if err := checkParent(ctx, tx, userIDInt, 0, *category.ParentID); err != nil {
+				return err
+			}
+		}
+
+		var id int32
+		err := tx.QueryRow(ctx, `
+            INSERT INTO product_category_service.categories (user_id, name, parent_id, color, icon)
+            VALUES ($1, $2, $3, $4, $5) RETURNING category_id`,
+			userIDInt, category.Name, category.ParentID, category.Color, category.Icon).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error creating category: %v", err)
+		}
+		created, err = getCategory(ctx, tx, userIDInt, id)
+		return err
+	})
+	return created, err
+}
+
+// CategoryUpdate lists the fields to change; nil fields are left alone.
+// ClearParent moves the category to the top level.
+type CategoryUpdate struct {
+	Name        *string
+	ParentID    *int32
+	ClearParent bool
+	Color       *string
+	Icon        *string
+}
+
+// UpdateCategory renames, recolours or moves a category. A rename keeps the old
+// name as an alias so extracted labels using it still resolve here. The
+// fallback category is found by name, so it cannot be renamed.
+func UpdateCategory(ctx context.Context, userID string, categoryID int32, update CategoryUpdate) (*Category, error) {
+	var updated *Category
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		current, err := getCategory(ctx, tx, userIDInt, categoryID)
+		if err != nil {
+			return err
+		}
+
+		next := *current
+		if update.Name != nil && *update.Name != current.Name {
+			if current.Name == taxonomy.Uncategorized {
+				return fmt.Errorf("%w: %s cannot be renamed", ErrCategoryArchived, taxonomy.Uncategorized)
+			}
+			if err := nameTaken(ctx, tx, userIDInt, *update.Name, categoryID); err != nil {
+				return err
+			}
+			if err := addAlias(ctx, tx, userIDInt, current.Name, categoryID); err != nil {
+				return err
+			}
+			next.Name = *update.Name
+		}
+		if update.ClearParent {
+			next.ParentID = nil
+		} else if update.ParentID != nil {
+			if err := checkParent(ctx, tx, userIDInt, categoryID, *update.ParentID); err != nil {
+				return err
+			}
+			next.ParentID = update.ParentID
+		}
+		if update.Color != nil {
+			next.Color = update.Color
+		}
+		if update.Icon != nil {
+			next.Icon = update.Icon
+		}
+
+		_, err = tx.Exec(ctx, `
+            UPDATE product_category_service.categories
+            SET name = $1, parent_id = $2, color = $3, icon = $4
+            WHERE user_id = $5 AND category_id = $6`,
+			next.Name, next.ParentID, next.Color, next.Icon, userIDInt, categoryID)
+		if err != nil {
+			return fmt.Errorf("error updating category: %v", err)
+		}
+		// Moving a category changes which budgets its products count towards.
+		if !equalIDs(current.ParentID, next.ParentID) {
+			if err := RecomputeBudgetSpend(ctx, tx, userIDInt); err != nil {
+				return err
+			}
+		}
+		updated, err = getCategory(ctx, tx, userIDInt, categoryID)
+		return err
+	})
+	return updated, err
+}
+
+func equalIDs(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func addAlias(ctx context.Context, tx pgx.Tx, userID int32, alias string, categoryID int32) error {
+	normalized := taxonomy.Normalize(alias)
+	if normalized == "" {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+        INSERT INTO product_category_service.category_aliases (user_id, alias, category_id)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, alias) DO UPDATE SET category_id = EXCLUDED.category_id`,
+		userID, normalized, categoryID)
+	if err != nil {
+		return fmt.Errorf("error saving category alias: %v", err)
+	}
+	return nil
+}
+
+// ArchiveCategory hides a category from pickers and from label mapping, or
+// restores it. Its products keep pointing at it.
+func ArchiveCategory(ctx context.Context, userID string, categoryID int32, archived bool) (*Category, error) {
+	var category *Category
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		current, err := getCategory(ctx, tx, userIDInt, categoryID)
+		if err != nil {
+			return err
+		}
+		if current.Name == taxonomy.Uncategorized && archived {
+			return fmt.Errorf("%w: %s cannot be archived", ErrCategoryArchived, taxonomy.Uncategorized)
+		}
+
+		_, err = tx.Exec(ctx, `
+            UPDATE product_category_service.categories SET archived = $1
+            WHERE user_id = $2 AND category_id = $3`,
+			archived, userIDInt, categoryID)
+		if err != nil {
+			return fmt.Errorf("error archiving category: %v", err)
+		}
+		category, err = getCategory(ctx, tx, userIDInt, categoryID)
+		return err
+	})
+	return category, err
+}
+
+// MergeCategories moves every product and child of source into target. The
+// source row is kept, marked as merged, so its name keeps resolving to target.
+func MergeCategories(ctx context.Context, userID string, sourceID, targetID int32) (*Category, int64, error) {
+	var target *Category
+	var moved int64
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if sourceID == targetID {
+			return fmt.Errorf("%w: cannot merge a category into itself", ErrCategoryCycle)
+		}
+		source, err := getCategory(ctx, tx, userIDInt, sourceID)
+		if err != nil {
+			return err
+		}
+		if source.Name == taxonomy.Uncategorized {
+			return fmt.Errorf("%w: %s cannot be merged away", ErrCategoryArchived, taxonomy.Uncategorized)
+		}
+		if err := checkParent(ctx, tx, userIDInt, sourceID, targetID); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx, `
+            UPDATE product_category_service.products SET category_id = $1
+            WHERE user_id = $2 AND category_id = $3`,
+			targetID, userIDInt, sourceID)
+		if err != nil {
+			return fmt.Errorf("error moving products: %v", err)
+		}
+		moved = result.RowsAffected()
+
+		if _, err := tx.Exec(ctx, `
+            UPDATE product_category_service.product_splits s SET category_id = $1
+            FROM product_category_service.products p
+            WHERE p.product_id = s.product_id AND p.user_id = $2 AND s.category_id = $3`,
+			targetID, userIDInt, sourceID); err != nil {
+			return fmt.Errorf("error moving product splits: %v", err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+            UPDATE product_category_service.categories SET parent_id = $1
+            WHERE user_id = $2 AND parent_id = $3`,
+			targetID, userIDInt, sourceID); err != nil {
+			return fmt.Errorf("error moving child categories: %v", err)
+		}
+		if _, err := tx.Exec(ctx, `
+            UPDATE product_category_service.category_aliases SET category_id = $1
+            WHERE user_id = $2 AND category_id = $3`,
+			targetID, userIDInt, sourceID); err != nil {
+			return fmt.Errorf("error moving category aliases: %v", err)
+		}
+		if err := addAlias(ctx, tx, userIDInt, source.Name, targetID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+            UPDATE product_category_service.budgets SET category_id = $1
+            WHERE user_id = $2 AND category_id = $3`,
+			targetID, userIDInt, sourceID); err != nil {
+			return fmt.Errorf("error moving budgets: %v", err)
+		}
+		if err := RecomputeBudgetSpend(ctx, tx, userIDInt); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+            UPDATE product_category_service.categories SET merged_into = $1, archived = TRUE
+            WHERE user_id = $2 AND category_id = $3`,
+			targetID, userIDInt, sourceID); err != nil {
+			return fmt.Errorf("error marking category merged: %v", err)
+		}
+
+		target, err = getCategory(ctx, tx, userIDInt, targetID)
+		return err
+	})
+	return target, moved, err
+}
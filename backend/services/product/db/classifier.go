@@ -0,0 +1,347 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/classifier"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+// maxTrainingExamples caps how many of a user's latest corrections a model is
+// trained on, which keeps each retrain cheap.
+const maxTrainingExamples = 5000
+
+// retrainTimeout bounds one background retrain.
+const retrainTimeout = time.Minute
+
+// retrains tracks the users whose model is being retrained in the background.
+// A user's entry is true when another correction arrived during the retrain,
+// so it runs once more when done.
+var retrains = struct {
+	sync.Mutex
+	again map[int32]bool
+}{again: map[int32]bool{}}
+
+// ClassifierStats describes a user's trained model.
+type ClassifierStats struct {
+	Model     *classifier.Model
+	TrainedAt *time.Time
+	// Corrections counts every recorded correction, including ones the model
+	// no longer trains on.
+	Corrections int
+	Classes     []ClassStats
+}
+
+// ClassStats is how many training examples the model has for a category.
+type ClassStats struct {
+	Category Category
+	Examples int
+}
+
+// recordCorrection remembers that the user moved a product to another
+// category by hand. The caller retrains the model with scheduleRetrain once
+// the correction has committed.
+func recordCorrection(ctx context.Context, tx pgx.Tx, userID int32, before, after *Product) error {
+	merchant, err := receiptMerchant(ctx, tx, userID, after.FileName)
+	if err != nil {
+		return err
+	}
+	var merchantName *string
+	if merchant != "" {
+		merchantName = &merchant
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO product_category_service.category_corrections
+            (user_id, product_id, product_name, merchant_name, from_category_id, to_category_id)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+		userID, after.ProductID, after.ProductName, merchantName, before.CategoryID, after.CategoryID)
+	if err != nil {
+		return fmt.Errorf("error recording correction: %v", err)
+	}
+	return nil
+}
+
+// scheduleRetrain retrains the user's model in the background, so saving a
+// correction does not wait on training. Corrections that arrive while a
+// retrain runs share the next one.
+func scheduleRetrain(userID int32) {
+	retrains.Lock()
+	defer retrains.Unlock()
+	if _, running := retrains.again[userID]; running {
+		retrains.again[userID] = true
+		return
+	}
+	retrains.again[userID] = false
+
+	go func() {
+		for {
+			if err := retrain(userID); err != nil {
+				log.Printf("Retraining classifier for user %d failed: %v", userID, err)
+			}
+			retrains.Lock()
+			if !retrains.again[userID] {
+				delete(retrains.again, userID)
+				retrains.Unlock()
+				return
+			}
+			retrains.again[userID] = false
+			retrains.Unlock()
+		}
+	}()
+}
+
+func retrain(userID int32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), retrainTimeout)
+	defer cancel()
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := trainClassifier(ctx, tx, userID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing classifier: %v", err)
+	}
+	return nil
+}
+
+// trainClassifier retrains the user's model from their latest correction of
+// each product and stores it.
+func trainClassifier(ctx context.Context, tx pgx.Tx, userID int32) (*classifier.Model, error) {
+	resolver, err := LoadCategoryResolver(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT product_name, coalesce(merchant_name, ''), to_category_id
+        FROM (
+            SELECT DISTINCT ON (coalesce(product_id, -correction_id))
+                correction_id, product_name, merchant_name, to_category_id
+            FROM product_category_service.category_corrections
+            WHERE user_id = $1
+            ORDER BY coalesce(product_id, -correction_id), correction_id DESC
+        ) latest
+        ORDER BY correction_id DESC
+        LIMIT $2`,
+		userID, maxTrainingExamples)
+	if err != nil {
+		return nil, fmt.Errorf("error loading corrections: %v", err)
+	}
+	var examples []classifier.Example
+	for rows.Next() {
+		var example classifier.Example
+		if err := rows.Scan(&example.ProductName, &example.Merchant, &example.CategoryID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning correction: %v", err)
+		}
+		// Train on the category the correction's target stands for today,
+		// following merges and archives.
+		category, ok := resolver.ByID(example.CategoryID)
+		if !ok {
+			continue
+		}
+		example.CategoryID = category.CategoryID
+		examples = append(examples, example)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	// Oldest first, so the model does not depend on how the query breaks ties.
+	for i, j := 0, len(examples)-1; i < j; i, j = i+1, j-1 {
+		examples[i], examples[j] = examples[j], examples[i]
+	}
+	model := classifier.Train(examples)
+
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding classifier: %v", err)
+	}
+	_, err = tx.Exec(ctx, `
+        INSERT INTO product_category_service.classifier_models (user_id, model, trained_at)
+        VALUES ($1, $2::jsonb, NOW())
+        ON CONFLICT (user_id) DO UPDATE SET model = EXCLUDED.model, trained_at = EXCLUDED.trained_at`,
+		userID, string(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("error saving classifier: %v", err)
+	}
+	return model, nil
+}
+
+// LoadClassifier returns the user's trained model, or nil if they have none.
+func LoadClassifier(ctx context.Context, tx pgx.Tx, userID int32) (*classifier.Model, error) {
+	model, _, err := loadClassifier(ctx, tx, userID)
+	return model, err
+}
+
+func loadClassifier(ctx context.Context, tx pgx.Tx, userID int32) (*classifier.Model, *time.Time, error) {
+	var encoded string
+	var trainedAt time.Time
+	err := tx.QueryRow(ctx, `
+        SELECT model::text, trained_at FROM product_category_service.classifier_models
+        WHERE user_id = $1`,
+		userID).Scan(&encoded, &trainedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading classifier: %v", err)
+	}
+
+	model := &classifier.Model{}
+	if err := json.Unmarshal([]byte(encoded), model); err != nil {
+		return nil, nil, fmt.Errorf("error decoding classifier: %v", err)
+	}
+	return model, &trainedAt, nil
+}
+
+// AssignPredictedCategory files a product under the model's prediction when
+// the model is confident, returning whether it did. Categories picked by hand
+// are left alone.
+func AssignPredictedCategory(model *classifier.Model, resolver *CategoryResolver, product *Product, merchant string) bool {
+	if product.CategoryIsManual() {
+		return false
+	}
+	prediction, ok := model.Confident(product.ProductName, merchant)
+	if !ok {
+		return false
+	}
+	category, ok := resolver.ByID(prediction.CategoryID)
+	if !ok {
+		return false
+	}
+	source := CategorySourceClassifier
+	product.CategoryID = category.CategoryID
+	product.CategoryName = category.Name
+	product.CategorySource = &source
+	return true
+}
+
+// CategorySuggestion is a category the user's model predicts for a product.
+type CategorySuggestion struct {
+	Category   Category
+	Confidence float64
+}
+
+// SuggestCategories returns up to limit categories for a product, most likely
+// first, and whether the first is confident enough to be assigned
+// automatically. Users without a trained model get none.
+func SuggestCategories(ctx context.Context, userID string, productName, merchant string, limit int) ([]CategorySuggestion, bool, error) {
+	var suggestions []CategorySuggestion
+	var confident bool
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		model, err := LoadClassifier(ctx, tx, userIDInt)
+		if err != nil || model == nil {
+			return err
+		}
+		resolver, err := LoadCategoryResolver(ctx, tx, userIDInt)
+		if err != nil {
+			return err
+		}
+
+		_, confident = model.Confident(productName, merchant)
+		seen := map[int32]bool{}
+		for _, prediction := range model.Predict(productName, merchant) {
+			category, ok := resolver.ByID(prediction.CategoryID)
+			if !ok || seen[category.CategoryID] {
+				continue
+			}
+			seen[category.CategoryID] = true
+			suggestions = append(suggestions, CategorySuggestion{Category: category, Confidence: prediction.Confidence})
+			if len(suggestions) == limit {
+				break
+			}
+		}
+		return nil
+	})
+	return suggestions, confident, err
+}
+
+// GetClassifierStats describes the user's model. The model is nil if it has
+// never been trained.
+func GetClassifierStats(ctx context.Context, userID string) (*ClassifierStats, error) {
+	stats := &ClassifierStats{}
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		var err error
+		stats.Model, stats.TrainedAt, err = loadClassifier(ctx, tx, userIDInt)
+		if err != nil {
+			return err
+		}
+		return classifierDetails(ctx, tx, userIDInt, stats)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// RetrainClassifier rebuilds the user's model from their corrections.
+func RetrainClassifier(ctx context.Context, userID string) (*ClassifierStats, error) {
+	stats := &ClassifierStats{}
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if _, err := trainClassifier(ctx, tx, userIDInt); err != nil {
+			return err
+		}
+		var err error
+		stats.Model, stats.TrainedAt, err = loadClassifier(ctx, tx, userIDInt)
+		if err != nil {
+			return err
+		}
+		return classifierDetails(ctx, tx, userIDInt, stats)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// classifierDetails fills in the correction count and the categories the
+// model knows.
+func classifierDetails(ctx context.Context, tx pgx.Tx, userID int32, stats *ClassifierStats) error {
+	err := tx.QueryRow(ctx, `
+        SELECT COUNT(*) FROM product_category_service.category_corrections WHERE user_id = $1`,
+		userID).Scan(&stats.Corrections)
+	if err != nil {
+		return fmt.Errorf("error counting corrections: %v", err)
+	}
+	if stats.Model == nil {
+		return nil
+	}
+
+	resolver, err := LoadCategoryResolver(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	// Categories merged since training count towards their target.
+	examples := map[int32]int{}
+	for id, class := range stats.Model.Classes {
+		if category, ok := resolver.ByID(id); ok {
+			examples[category.CategoryID] += class.Documents
+		}
+	}
+	for id, n := range examples {
+		category, _ := resolver.ByID(id)
+		stats.Classes = append(stats.Classes, ClassStats{Category: category, Examples: n})
+	}
+	sort.Slice(stats.Classes, func(i, j int) bool {
+		if stats.Classes[i].Examples != stats.Classes[j].Examples {
+			return stats.Classes[i].Examples > stats.Classes[j].Examples
+		}
+		return stats.Classes[i].Category.CategoryID < stats.Classes[j].Category.CategoryID
+	})
+	return nil
+}
@@ -2,19 +2,24 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/classifier"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/taxonomy"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
 	"strconv"
+	"strings"
 	"time"
 )
 
-type ProductResult struct {
-	ProductName  string
-	Quantity     int32
-	DateAdded    time.Time
-	Price        float64
-	CategoryName string
-}
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrCategoryNotFound = errors.New("category not found")
+)
 
 func parseUserID(userID string) (int32, error) {
 	id, err := strconv.ParseInt(userID, 10, 32)
@@ -24,37 +29,697 @@ func parseUserID(userID string) (int32, error) {
 	return int32(id), nil
 }
 
-func GetUserProducts(ctx context.Context, userID string) ([]ProductResult, error) {
+// Product is a single stored line item, in the currency of its unit Price.
+type Product struct {
+	ProductID    int32
+	UserID       int32
+	CategoryID   int32
+	CategoryName string
+	ProductName  string
+	Quantity     int32
+	Price        money.Money
+	FileName     *string
+	Description  *string
+	DateAdded    time.Time
+	LineTotal    float64
+	Tags         []string
+	Notes        *string
+	// CategorySource records who chose the category; see the CategorySource
+	// constants. NULL for products stored before it was tracked.
+	CategorySource *string
+	// ItemID is the canonical item the product is a purchase of; NULL until
+	// AssignItems links it.
+	ItemID *int32
+	// CustomFields holds the product's own custom field values by key; see
+	// package fields for how values are typed.
+	CustomFields map[string]interface{}
+	// Split is set when the product's cost is split; see GetProductSplits.
+	Split bool
+	// ExchangeRate is what one unit of Price's currency was worth in the user's
+	// base currency on DateAdded; nil while no rate is known.
+	ExchangeRate *float64
+	// AccountID is the balance account that paid for the product; nil
+	// means the user's funding account.
+	AccountID *int32
+}
+
+// Values of products.category_source.
+const (
+	CategorySourceExtraction = "extraction"
+	CategorySourceRule       = "rule"
+	CategorySourceManual     = "manual"
+	CategorySourceClassifier = "classifier"
+)
+
+// CategoryIsManual reports whether the user picked the product's category by
+// hand, in which case rules leave it alone.
+func (p *Product) CategoryIsManual() bool {
+	return p.CategorySource != nil && *p.CategorySource == CategorySourceManual
+}
+
+const productColumns = `p.product_id, p.user_id, p.category_id, c.name, p.product_name, p.quantity,
+        p.price, p.file_name, p.description, p.date_added, (p.quantity * p.price)::float8,
+        p.tags, p.notes, p.category_source, p.item_id, p.custom_fields::text,
+        EXISTS (SELECT 1 FROM product_category_service.product_splits s WHERE s.product_id = p.product_id),
+        p.currency, account_income_service.recorded_rate(p.exchange_rate, p.currency, p.user_id, p.date_added::date)::float8, p.account_id`
+
+// scanProduct scans productColumns followed by any extra columns into extra.
+func scanProduct(row pgx.Row, extra ...interface{}) (Product, error) {
+	var product Product
+	var customFields, currency string
+	var price float64
+	dest := []interface{}{&product.ProductID, &product.UserID, &product.CategoryID, &product.CategoryName,
+		&product.ProductName, &product.Quantity, &price, &product.FileName,
+		&product.Description, &product.DateAdded, &product.LineTotal,
+		&product.Tags, &product.Notes, &product.CategorySource, &product.ItemID, &customFields,
+		&product.Split, &currency, &product.ExchangeRate, &product.AccountID}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return product, err
+	}
+	product.Price = money.FromFloat(price, currency)
+	var err error
+	product.CustomFields, err = decodeCustomFields(customFields)
+	return product, err
+}
+
+// GetProduct returns one of the user's products.
+func GetProduct(ctx context.Context, q pgxQuerier, userID string, productID int32) (*Product, error) {
 	userIDInt, err := parseUserID(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := sharedDB.GetDB().Query(ctx, `
-        SELECT p.product_name AS product_name, p.quantity, p.date_added, p.price, c.name AS category_name 
-        FROM product_category_service.products p 
-        INNER JOIN product_category_service.categories c ON p.category_id = c.category_id 
-        WHERE p.user_id = $1`,
-		userIDInt)
+	product, err := scanProduct(q.QueryRow(ctx, `
+        SELECT `+productColumns+`
+        FROM product_category_service.products p
+        JOIN product_category_service.categories c ON p.category_id = c.category_id
+        WHERE p.user_id = $1 AND p.product_id = $2`,
+		userIDInt, productID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching product: %v", err)
+	}
+	return &product, nil
+}
+
+// pgxQuerier is satisfied by both the pool and a transaction.
+type pgxQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Pool returns the shared pool for reads outside a transaction.
+func Pool() pgxQuerier {
+	return sharedDB.GetDB()
+}
+
+// categoryExists checks that a category belongs to the user and can take new
+// products.
+func categoryExists(ctx context.Context, tx pgx.Tx, userID int32, categoryID int32) error {
+	var archived bool
+	err := tx.QueryRow(ctx, `
+        SELECT archived FROM product_category_service.categories
+        WHERE category_id = $1 AND user_id = $2 AND merged_into IS NULL`,
+		categoryID, userID).Scan(&archived)
+	if err == pgx.ErrNoRows {
+		return ErrCategoryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error checking category: %v", err)
+	}
+	if archived {
+		return ErrCategoryArchived
+	}
+	return nil
+}
+
+// InsertProduct stores a manually entered product and returns it as saved.
+func InsertProduct(ctx context.Context, userID string, product Product) (*Product, error) {
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	saved, err := insertProduct(ctx, tx, userID, product)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing product: %v", err)
+	}
+	return saved, nil
+}
+
+// insertProduct stores a product the way InsertProduct does, within tx.
+func insertProduct(ctx context.Context, tx pgx.Tx, userID string, product Product) (*Product, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := newProductWriter(ctx, tx, userIDInt)
+	if err != nil {
+		return nil, err
+	}
+	productID, err := writer.insert(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	if err := AssignItems(ctx, tx, userIDInt); err != nil {
+		return nil, err
+	}
+
+	saved, err := GetProduct(ctx, tx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userIDInt, []time.Time{saved.DateAdded}); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// productWriter stores a user's products within tx, loading their
+// categories and rules once however many it stores, and their model once
+// one is needed.
+type productWriter struct {
+	tx      pgx.Tx
+	userID  int32
+	ruleSet *RuleSet
+	model   *classifier.Model
+}
+
+func newProductWriter(ctx context.Context, tx pgx.Tx, userID int32) (*productWriter, error) {
+	if err := EnsureUserCategories(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	ruleSet, err := LoadRuleSet(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &productWriter{tx: tx, userID: userID, ruleSet: ruleSet}, nil
+}
+
+// insert categorises product and stores it, returning its ID. It neither
+// links it to items nor refreshes budget spend; callers do so once for all
+// they insert.
+func (w *productWriter) insert(ctx context.Context, product Product) (int32, error) {
+	merchant, err := receiptMerchant(ctx, w.tx, w.userID, product.FileName)
+	if err != nil {
+		return 0, err
+	}
+	if product.CategoryID == 0 {
+		// No category chosen: the user's model and then their rules decide,
+		// otherwise Uncategorized.
+		product.CategoryID = w.ruleSet.resolver.Resolve(taxonomy.Uncategorized).CategoryID
+		product.CategorySource = nil
+		if w.model == nil {
+			if w.model, err = LoadClassifier(ctx, w.tx, w.userID); err != nil {
+				return 0, err
+			}
+		}
+		AssignPredictedCategory(w.model, w.ruleSet.resolver, &product, merchant)
+	} else if err := categoryExists(ctx, w.tx, w.userID, product.CategoryID); err != nil {
+		return 0, err
+	}
+	if category, ok := w.ruleSet.resolver.ByID(product.CategoryID); ok {
+		product.CategoryName = category.Name
+	}
+	product.LineTotal = product.Price.Mul(float64(product.Quantity)).Float64()
+	w.ruleSet.ApplyTo(&product, merchant)
+	customFields, err := checkCustomFields(ctx, w.tx, w.userID, product.CustomFields)
+	if err != nil {
+		return 0, err
+	}
+
+	var productID int32
+	err = w.tx.QueryRow(ctx, `
+        INSERT INTO product_category_service.products
+            (user_id, category_id, product_name, quantity, price, file_name, description, date_added,
+             tags, notes, category_source, custom_fields, currency, account_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::jsonb, $13, $14)
+        RETURNING product_id`,
+		w.userID, product.CategoryID, product.ProductName, product.Quantity, product.Price.String(),
+		product.FileName, product.Description, product.DateAdded,
+		nonNilTags(product.Tags), product.Notes, product.CategorySource, customFields,
+		money.Normalize(product.Price.Currency), product.AccountID).Scan(&productID)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting product: %w", err)
+	}
+	return productID, nil
+}
+
+// UpdateProduct overwrites a product owned by the user. It returns the product
+// as it was before and after the change so callers can invalidate both files.
+// A refunded product cannot be changed to cost less than was refunded or to
+// another currency; see checkRefundedUpdate.
+func UpdateProduct(ctx context.Context, userID string, product Product) (*Product, *Product, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := GetProduct(ctx, tx, userID, product.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkRefundedUpdate(ctx, tx, userIDInt, product); err != nil {
+		return nil, nil, err
+	}
+	if err := EnsureUserCategories(ctx, tx, userIDInt); err != nil {
+		return nil, nil, err
+	}
+	if product.CategoryID != before.CategoryID {
+		if err := categoryExists(ctx, tx, userIDInt, product.CategoryID); err != nil {
+			return nil, nil, err
+		}
+	}
+	ruleSet, err := LoadRuleSet(ctx, tx, userIDInt)
+	if err != nil {
+		return nil, nil, err
+	}
+	merchant, err := receiptMerchant(ctx, tx, userIDInt, product.FileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if category, ok := ruleSet.resolver.ByID(product.CategoryID); ok {
+		product.CategoryName = category.Name
+	}
+	product.LineTotal = product.Price.Mul(float64(product.Quantity)).Float64()
+	ruleSet.ApplyTo(&product, merchant)
+	customFields, err := checkCustomFields(ctx, tx, userIDInt, product.CustomFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE product_category_service.products
+        SET category_id = $1, product_name = $2, quantity = $3,
+            item_id = CASE WHEN product_name IS NOT DISTINCT FROM $2 THEN item_id END, price = $4, description = $5, date_added = $6,
+            tags = $7, notes = $8, category_source = $9, custom_fields = $12::jsonb, currency = $13
+        WHERE user_id = $10 AND product_id = $11`,
+		product.CategoryID, product.ProductName, product.Quantity, product.Price.String(),
+		product.Description, product.DateAdded, nonNilTags(product.Tags), product.Notes,
+		product.CategorySource, userIDInt, product.ProductID, customFields,
+		money.Normalize(product.Price.Currency))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating product: %w", err)
+	}
+	if err := AssignItems(ctx, tx, userIDInt); err != nil {
+		return nil, nil, err
+	}
+
+	after, err := GetProduct(ctx, tx, userID, product.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	corrected := after.CategoryIsManual() && after.CategoryID != before.CategoryID
+	if corrected {
+		if err := recordCorrection(ctx, tx, userIDInt, before, after); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userIDInt, []time.Time{before.DateAdded, after.DateAdded}); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("error committing product: %v", err)
+	}
+	if corrected {
+		scheduleRetrain(userIDInt)
+	}
+	return before, after, nil
+}
+
+// DeleteProduct removes a product owned by the user and returns what was deleted.
+func DeleteProduct(ctx context.Context, userID string, productID int32) (*Product, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := GetProduct(ctx, tx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        DELETE FROM product_category_service.products
+        WHERE user_id = $1 AND product_id = $2`,
+		userIDInt, productID)
+	if err = refundedError(err); err == ErrProductRefunded {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error deleting product: %v", err)
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userIDInt, []time.Time{deleted.DateAdded}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing delete: %v", err)
+	}
+	return deleted, nil
+}
+
+// GetFileTotal recomputes the total of a receipt from its stored products,
+// with the currency it is in: the products' own when they share one, else
+// the user's base currency.
+func GetFileTotal(ctx context.Context, userID string, fileName string) (float64, string, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var total float64
+	var currency string
+	err = sharedDB.GetDB().QueryRow(ctx, `
+        SELECT CASE WHEN COUNT(DISTINCT currency) <= 1 THEN COALESCE(SUM(quantity * price), 0)
+                ELSE SUM(quantity * price * account_income_service.recorded_rate(exchange_rate, currency, user_id, date_added::date)) END::float8,
+            CASE WHEN COUNT(DISTINCT currency) = 1 THEN MIN(currency)
+                ELSE account_income_service.base_currency($1) END
+        FROM product_category_service.products
+        WHERE user_id = $1 AND file_name = $2`,
+		userIDInt, fileName).Scan(&total, &currency)
+	if err != nil {
+		return 0, "", fmt.Errorf("error computing file total: %v", err)
+	}
+	return total, currency, nil
+}
+
+// Product list orderings accepted by ListProducts.
+const (
+	SortDateDesc   = "date_desc"
+	SortDateAsc    = "date_asc"
+	SortAmountDesc = "amount_desc"
+	SortAmountAsc  = "amount_asc"
+	SortNameAsc    = "name_asc"
+)
+
+// ProductCursor identifies the last row of a page.
+type ProductCursor struct {
+	DateAdded   time.Time
+	Amount      float64
+	ProductName string
+	ProductID   int32
+}
+
+// ProductQuery filters and pages a user's products. Zero values mean no
+// filter; Limit 0 returns every match.
+type ProductQuery struct {
+	From        *time.Time
+	To          *time.Time
+	CategoryIDs []int32
+	MinAmount   *float64
+	MaxAmount   *float64
+	FileName    string
+	NameQuery   string
+	// Tags must all be on the product or its receipt, ignoring case.
+	Tags []string
+	// CustomFields must all match the product's effective values: its own,
+	// falling back to its receipt's.
+	CustomFields map[string]interface{}
+	Sort         string
+	Limit        int
+	After        *ProductCursor
+}
+
+// ProductPage is one page of products plus aggregates over every match.
+// TotalAmount is what the matches cost the user, counting only their own
+// parts of split products and net of refunds, in their base currency,
+// BaseCurrency.
+type ProductPage struct {
+	Products     []Product
+	TotalCount   int
+	TotalAmount  float64
+	BaseCurrency string
+}
+
+const lineTotal = "(p.quantity * p.price)::float8"
+
+// ListProducts returns one page of the user's products matching q.
+func ListProducts(ctx context.Context, userID string, q ProductQuery) (*ProductPage, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
 
+	where := []string{"p.user_id = $1"}
+	args := []interface{}{userIDInt}
+	addArg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if q.From != nil {
+		where = append(where, "p.date_added >= "+addArg(*q.From))
+	}
+	if q.To != nil {
+		where = append(where, "p.date_added < "+addArg(*q.To))
+	}
+	if len(q.CategoryIDs) > 0 {
+		// A parent category matches everything filed under its subcategories.
+		where = append(where, `p.category_id IN (
+            WITH RECURSIVE tree AS (
+                SELECT category_id FROM product_category_service.categories
+                WHERE user_id = $1 AND category_id = ANY(`+addArg(q.CategoryIDs)+`)
+                UNION
+                SELECT c.category_id FROM product_category_service.categories c
+                JOIN tree t ON c.parent_id = t.category_id OR c.merged_into = t.category_id
+            )
+            SELECT category_id FROM tree)`)
+	}
+	if q.MinAmount != nil {
+		where = append(where, lineTotal+" >= "+addArg(*q.MinAmount))
+	}
+	if q.MaxAmount != nil {
+		where = append(where, lineTotal+" <= "+addArg(*q.MaxAmount))
+	}
+	if q.FileName != "" {
+		where = append(where, "p.file_name = "+addArg(q.FileName))
+	}
+	if q.NameQuery != "" {
+		where = append(where, "p.product_name ILIKE "+addArg("%"+escapeLike(q.NameQuery)+"%"))
+	}
+	if len(q.Tags) > 0 {
+		tags := make([]string, len(q.Tags))
+		for i, tag := range q.Tags {
+			tags[i] = strings.ToLower(tag)
+		}
+		where = append(where, `ARRAY(
+            SELECT lower(tag) FROM unnest(p.tags || COALESCE((
+                SELECT ra.tags FROM product_category_service.receipt_annotations ra
+                WHERE ra.user_id = p.user_id AND ra.file_name = p.file_name), '{}')) tag
+            ) @> `+addArg(tags)+`::text[]`)
+	}
+	if len(q.CustomFields) > 0 {
+		encoded, err := json.Marshal(q.CustomFields)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding custom field filter: %v", err)
+		}
+		where = append(where, `(COALESCE((
+                SELECT ra.custom_fields FROM product_category_service.receipt_annotations ra
+                WHERE ra.user_id = p.user_id AND ra.file_name = p.file_name), '{}'::jsonb)
+            || p.custom_fields) @> `+addArg(string(encoded))+`::jsonb`)
+	}
+
+	page := &ProductPage{}
+	err = sharedDB.GetDB().QueryRow(ctx, `
+        SELECT COUNT(*), COALESCE(SUM(a.amount), 0)::float8,
+            account_income_service.base_currency($1)
+        FROM product_category_service.products p
+        LEFT JOIN LATERAL (
+            SELECT SUM(ap.amount) AS amount FROM `+allocatedProducts+` ap
+            WHERE ap.product_id = p.product_id
+        ) a ON TRUE
+        WHERE `+strings.Join(where, " AND "), args...).Scan(&page.TotalCount, &page.TotalAmount, &page.BaseCurrency)
 	if err != nil {
-		return nil, fmt.Errorf("no data found: %v", err)
+		return nil, fmt.Errorf("error counting products: %v", err)
+	}
+
+	var orderBy string
+	switch q.Sort {
+	case SortDateAsc:
+		orderBy = "p.date_added ASC, p.product_id ASC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(p.date_added, p.product_id) > (%s, %s)", addArg(q.After.DateAdded), addArg(q.After.ProductID)))
+		}
+	case SortAmountDesc:
+		orderBy = lineTotal + " DESC, p.product_id DESC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(%s, p.product_id) < (%s, %s)", lineTotal, addArg(q.After.Amount), addArg(q.After.ProductID)))
+		}
+	case SortAmountAsc:
+		orderBy = lineTotal + " ASC, p.product_id ASC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(%s, p.product_id) > (%s, %s)", lineTotal, addArg(q.After.Amount), addArg(q.After.ProductID)))
+		}
+	case SortNameAsc:
+		orderBy = "lower(p.product_name) ASC, p.product_id ASC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(lower(p.product_name), p.product_id) > (lower(%s), %s)", addArg(q.After.ProductName), addArg(q.After.ProductID)))
+		}
+	case SortDateDesc, "":
+		orderBy = "p.date_added DESC, p.product_id DESC"
+		if q.After != nil {
+			where = append(where, fmt.Sprintf("(p.date_added, p.product_id) < (%s, %s)", addArg(q.After.DateAdded), addArg(q.After.ProductID)))
+		}
+	default:
+		return nil, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	query := `
+        SELECT ` + productColumns + `
+        FROM product_category_service.products p
+        JOIN product_category_service.categories c ON p.category_id = c.category_id
+        WHERE ` + strings.Join(where, " AND ") + `
+        ORDER BY ` + orderBy
+	if q.Limit > 0 {
+		query += " LIMIT " + addArg(q.Limit)
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching products: %v", err)
 	}
 	defer rows.Close()
 
-	var products []ProductResult
 	for rows.Next() {
-		var product ProductResult
-		if err := rows.Scan(&product.ProductName, &product.Quantity, &product.DateAdded,
-			&product.Price, &product.CategoryName); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error scanning product row: %v", err)
 		}
-		products = append(products, product)
+		page.Products = append(page.Products, product)
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error during row iteration: %v", err)
 	}
 
-	return products, nil
+	return page, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// fuzzyThreshold is the minimum trigram similarity for a name or merchant to
+// match without a full-text hit; it lets "MLK 2%" find "Milk 2%".
+const fuzzyThreshold = 0.3
+
+// ts_headline marks matches with these control characters rather than HTML,
+// so that the OCR and user text around them can be escaped first.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// highlightHTML escapes a ts_headline result and turns its match markers into
+// <mark> tags.
+func highlightHTML(s string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(s))
+}
+
+// SearchResult is a product matched by SearchProducts.
+type SearchResult struct {
+	Product
+	MerchantName           *string
+	Rank                   float64
+	HighlightedName        string
+	HighlightedDescription string
+	TextSnippet            string
+	MatchedFields          []string
+}
+
+// SearchProducts ranks the user's products against a free-text query, combining
+// full-text matches over names, descriptions, merchants and receipt text with
+// trigram similarity on names and merchants.
+func SearchProducts(ctx context.Context, userID string, query string, from, to *time.Time, limit int) ([]SearchResult, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        WITH q AS (
+            SELECT websearch_to_tsquery('simple', $2) AS tsq, lower($2) AS raw
+        ), candidates AS (
+            SELECT * FROM (
+            SELECT p.product_id, r.merchant_name, r.raw_text,
+                ts_rank_cd(p.search_vector || coalesce(r.search_vector, ''::tsvector), q.tsq) AS text_rank,
+                GREATEST(similarity(q.raw, lower(p.product_name)), word_similarity(q.raw, lower(p.product_name)),
+                         coalesce(word_similarity(q.raw, lower(r.merchant_name)), 0)) AS fuzzy_rank
+            FROM product_category_service.products p
+            LEFT JOIN product_category_service.receipt_texts r
+                ON r.user_id = p.user_id AND r.file_name = p.file_name
+            CROSS JOIN q
+            WHERE p.user_id = $1
+              AND ($3::timestamp IS NULL OR p.date_added >= $3)
+              AND ($4::timestamp IS NULL OR p.date_added < $4)
+              AND ((p.search_vector || coalesce(r.search_vector, ''::tsvector)) @@ q.tsq
+                   OR similarity(q.raw, lower(p.product_name)) >= $5
+                   OR word_similarity(q.raw, lower(p.product_name)) >= $5
+                   OR word_similarity(q.raw, lower(r.merchant_name)) >= $5)
+            ) scored
+            ORDER BY text_rank + fuzzy_rank DESC, product_id DESC
+            LIMIT $6
+        )
+        SELECT `+productColumns+`, m.merchant_name, (m.text_rank + m.fuzzy_rank)::float8,
+            ts_headline('simple', p.product_name, q.tsq, $7 || ', HighlightAll=true'),
+            ts_headline('simple', coalesce(p.description, ''), q.tsq, $7 || ', HighlightAll=true'),
+            CASE WHEN m.raw_text IS NOT NULL AND to_tsvector('simple', m.raw_text) @@ q.tsq
+                 THEN ts_headline('simple', m.raw_text, q.tsq, $7 || ', MaxFragments=2, MaxWords=12, MinWords=4')
+                 ELSE '' END,
+            array_remove(ARRAY[
+                CASE WHEN to_tsvector('simple', p.product_name) @@ q.tsq
+                       OR GREATEST(similarity(q.raw, lower(p.product_name)), word_similarity(q.raw, lower(p.product_name))) >= $5
+                     THEN 'name' END,
+                CASE WHEN to_tsvector('simple', coalesce(p.description, '')) @@ q.tsq THEN 'description' END,
+                CASE WHEN to_tsvector('simple', coalesce(m.merchant_name, '')) @@ q.tsq
+                       OR coalesce(word_similarity(q.raw, lower(m.merchant_name)), 0) >= $5
+                     THEN 'merchant' END,
+                CASE WHEN to_tsvector('simple', coalesce(m.raw_text, '')) @@ q.tsq THEN 'receipt_text' END
+            ], NULL)
+        FROM candidates m
+        JOIN product_category_service.products p ON p.product_id = m.product_id
+        JOIN product_category_service.categories c ON p.category_id = c.category_id
+        CROSS JOIN q
+        ORDER BY m.text_rank + m.fuzzy_rank DESC, p.product_id DESC`,
+		userIDInt, query, from, to, fuzzyThreshold, limit,
+		fmt.Sprintf(`StartSel="%s", StopSel="%s"`, highlightStart, highlightStop))
+	if err != nil {
+		return nil, fmt.Errorf("error searching products: %v", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		product, err := scanProduct(rows, &r.MerchantName, &r.Rank, &r.HighlightedName,
+			&r.HighlightedDescription, &r.TextSnippet, &r.MatchedFields)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning search result: %v", err)
+		}
+		r.HighlightedName = highlightHTML(r.HighlightedName)
+		r.HighlightedDescription = highlightHTML(r.HighlightedDescription)
+		r.TextSnippet = highlightHTML(r.TextSnippet)
+		r.Product = product
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return results, nil
 }
@@ -0,0 +1,467 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/fields"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrFieldNotFound   = errors.New("custom field not found")
+	ErrInvalidField    = errors.New("invalid custom field")
+	ErrReceiptNotFound = errors.New("receipt not found")
+)
+
+// CustomField is one of a user's custom field definitions.
+type CustomField struct {
+	FieldID int32
+	fields.Definition
+}
+
+const customFieldColumns = `field_id, key, label, field_type, options`
+
+func scanCustomField(row pgx.Row) (CustomField, error) {
+	var field CustomField
+	err := row.Scan(&field.FieldID, &field.Key, &field.Label, &field.Type, &field.Options)
+	return field, err
+}
+
+func listCustomFields(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}, userID int32) ([]CustomField, error) {
+	rows, err := q.Query(ctx, `
+        SELECT `+customFieldColumns+` FROM product_category_service.custom_fields
+        WHERE user_id = $1
+        ORDER BY lower(label), field_id`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading custom fields: %v", err)
+	}
+	defer rows.Close()
+
+	var list []CustomField
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning custom field: %v", err)
+		}
+		list = append(list, field)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return list, nil
+}
+
+// ListCustomFields returns the user's custom field definitions by label.
+func ListCustomFields(ctx context.Context, userID string) ([]CustomField, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return listCustomFields(ctx, sharedDB.GetDB(), userIDInt)
+}
+
+// fieldDefinitions returns the user's definitions keyed by field key.
+func fieldDefinitions(ctx context.Context, tx pgx.Tx, userID int32) (map[string]fields.Definition, error) {
+	list, err := listCustomFields(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]fields.Definition, len(list))
+	for _, field := range list {
+		defs[field.Key] = field.Definition
+	}
+	return defs, nil
+}
+
+// checkCustomFields validates values against the user's definitions and
+// encodes them for a JSONB column.
+func checkCustomFields(ctx context.Context, tx pgx.Tx, userID int32, values map[string]interface{}) (string, error) {
+	checked := map[string]interface{}{}
+	if len(values) > 0 {
+		defs, err := fieldDefinitions(ctx, tx, userID)
+		if err != nil {
+			return "", err
+		}
+		if checked, err = fields.CheckAll(defs, values); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidField, err)
+		}
+	}
+	encoded, err := json.Marshal(checked)
+	if err != nil {
+		return "", fmt.Errorf("error encoding custom fields: %v", err)
+	}
+	return string(encoded), nil
+}
+
+func decodeCustomFields(raw string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if raw == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, fmt.Errorf("error decoding custom fields: %v", err)
+	}
+	return values, nil
+}
+
+// CreateCustomField adds a custom field definition for the user.
+func CreateCustomField(ctx context.Context, userID string, def fields.Definition) (*CustomField, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := def.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidField, err)
+	}
+
+	field, err := scanCustomField(sharedDB.GetDB().QueryRow(ctx, `
+        INSERT INTO product_category_service.custom_fields (user_id, key, label, field_type, options)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id, key) DO NOTHING
+        RETURNING `+customFieldColumns,
+		userIDInt, def.Key, def.Label, def.Type, append([]string{}, def.Options...)))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%w: a field with key %s already exists", ErrInvalidField, def.Key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating custom field: %v", err)
+	}
+	return &field, nil
+}
+
+// UpdateCustomField changes a field's label and, for select fields, its
+// options. Its key and type are fixed once values have been stored under
+// them; removing an option clears it from the values that used it.
+func UpdateCustomField(ctx context.Context, userID string, fieldID int32, label string, options []string) (*CustomField, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := scanCustomField(tx.QueryRow(ctx, `
+        SELECT `+customFieldColumns+` FROM product_category_service.custom_fields
+        WHERE user_id = $1 AND field_id = $2
+        FOR UPDATE`,
+		userIDInt, fieldID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrFieldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching custom field: %v", err)
+	}
+
+	def := current.Definition
+	def.Label = label
+	if def.Type == fields.TypeSelect {
+		def.Options = options
+	}
+	if err := def.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidField, err)
+	}
+
+	field, err := scanCustomField(tx.QueryRow(ctx, `
+        UPDATE product_category_service.custom_fields SET label = $1, options = $2
+        WHERE field_id = $3
+        RETURNING `+customFieldColumns,
+		def.Label, append([]string{}, def.Options...), fieldID))
+	if err != nil {
+		return nil, fmt.Errorf("error updating custom field: %v", err)
+	}
+
+	if def.Type == fields.TypeSelect {
+		// Values are stored in the option's spelling, so a renamed option
+		// is a removed one.
+		for _, table := range []string{"products", "receipt_annotations"} {
+			_, err := tx.Exec(ctx, `
+                UPDATE product_category_service.`+table+` SET custom_fields = custom_fields - $1::text
+                WHERE user_id = $2 AND custom_fields ? $1::text
+                  AND NOT (custom_fields ->> $1::text) = ANY($3)`,
+				def.Key, userIDInt, def.Options)
+			if err != nil {
+				return nil, fmt.Errorf("error clearing removed options: %v", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing custom field: %v", err)
+	}
+	return &field, nil
+}
+
+// DeleteCustomField removes a field definition and its values.
+func DeleteCustomField(ctx context.Context, userID string, fieldID int32) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var key string
+	err = tx.QueryRow(ctx, `
+        DELETE FROM product_category_service.custom_fields
+        WHERE user_id = $1 AND field_id = $2
+        RETURNING key`,
+		userIDInt, fieldID).Scan(&key)
+	if err == pgx.ErrNoRows {
+		return ErrFieldNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting custom field: %v", err)
+	}
+
+	for _, table := range []string{"products", "receipt_annotations"} {
+		_, err := tx.Exec(ctx, `
+            UPDATE product_category_service.`+table+` SET custom_fields = custom_fields - $1::text
+            WHERE user_id = $2 AND custom_fields ? $1::text`,
+			key, userIDInt)
+		if err != nil {
+			return fmt.Errorf("error clearing custom field values: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing custom field: %v", err)
+	}
+	return nil
+}
+
+// ReceiptAnnotation is what the user noted about a whole receipt. Products
+// on the receipt match its tags in filters and inherit its custom field
+// values unless they set their own.
+type ReceiptAnnotation struct {
+	FileName     string
+	Tags         []string
+	Notes        *string
+	CustomFields map[string]interface{}
+	UpdatedAt    *time.Time
+}
+
+// ReceiptAnnotationUpdate changes the parts of an annotation that are set.
+// CustomFields values are set and ClearFields removed.
+type ReceiptAnnotationUpdate struct {
+	Tags         *[]string
+	Notes        *string
+	CustomFields map[string]interface{}
+	ClearFields  []string
+}
+
+func receiptExists(ctx context.Context, tx pgx.Tx, userID int32, fileName string) error {
+	var exists bool
+	err := tx.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM file_management_service.file_metadata WHERE user_id = $1 AND file_name = $2)`,
+		userID, fileName).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error checking receipt: %v", err)
+	}
+	if !exists {
+		return ErrReceiptNotFound
+	}
+	return nil
+}
+
+func getReceiptAnnotation(ctx context.Context, tx pgx.Tx, userID int32, fileName string) (*ReceiptAnnotation, error) {
+	annotation := &ReceiptAnnotation{FileName: fileName, Tags: []string{}, CustomFields: map[string]interface{}{}}
+	var raw string
+	err := tx.QueryRow(ctx, `
+        SELECT tags, notes, custom_fields::text, updated_at
+        FROM product_category_service.receipt_annotations
+        WHERE user_id = $1 AND file_name = $2`,
+		userID, fileName).Scan(&annotation.Tags, &annotation.Notes, &raw, &annotation.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return annotation, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching receipt annotation: %v", err)
+	}
+	annotation.CustomFields, err = decodeCustomFields(raw)
+	return annotation, err
+}
+
+// GetReceiptAnnotation returns the annotation of one of the user's receipts,
+// empty if it has none.
+func GetReceiptAnnotation(ctx context.Context, userID string, fileName string) (*ReceiptAnnotation, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := receiptExists(ctx, tx, userIDInt, fileName); err != nil {
+		return nil, err
+	}
+	return getReceiptAnnotation(ctx, tx, userIDInt, fileName)
+}
+
+// UpdateReceiptAnnotation changes the annotation of one of the user's
+// receipts and returns it.
+func UpdateReceiptAnnotation(ctx context.Context, userID string, fileName string, update ReceiptAnnotationUpdate) (*ReceiptAnnotation, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := receiptExists(ctx, tx, userIDInt, fileName); err != nil {
+		return nil, err
+	}
+	annotation, err := getReceiptAnnotation(ctx, tx, userIDInt, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Tags != nil {
+		annotation.Tags = *update.Tags
+	}
+	if update.Notes != nil {
+		annotation.Notes = update.Notes
+		if *update.Notes == "" {
+			annotation.Notes = nil
+		}
+	}
+	for _, key := range update.ClearFields {
+		delete(annotation.CustomFields, key)
+	}
+	for key, value := range update.CustomFields {
+		annotation.CustomFields[key] = value
+	}
+	encoded, err := checkCustomFields(ctx, tx, userIDInt, annotation.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO product_category_service.receipt_annotations
+            (user_id, file_name, tags, notes, custom_fields, updated_at)
+        VALUES ($1, $2, $3, $4, $5::jsonb, NOW())
+        ON CONFLICT (user_id, file_name) DO UPDATE
+        SET tags = EXCLUDED.tags, notes = EXCLUDED.notes, custom_fields = EXCLUDED.custom_fields,
+            updated_at = EXCLUDED.updated_at`,
+		userIDInt, fileName, nonNilTags(annotation.Tags), annotation.Notes, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error saving receipt annotation: %v", err)
+	}
+
+	annotation, err = getReceiptAnnotation(ctx, tx, userIDInt, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing receipt annotation: %v", err)
+	}
+	return annotation, nil
+}
+
+// ExportRow is a product with what it is exported alongside.
+type ExportRow struct {
+	Product
+	Merchant string
+	Receipt  *ReceiptAnnotation
+}
+
+// ExportProducts returns one page of the user's products matching q, with
+// their receipts' merchants and annotations, the user's custom fields and how
+// many products match in all.
+func ExportProducts(ctx context.Context, userID string, q ProductQuery) ([]ExportRow, []CustomField, int, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	page, err := ListProducts(ctx, userID, q)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	customFields, err := listCustomFields(ctx, sharedDB.GetDB(), userIDInt)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var fileNames []string
+	seen := map[string]bool{}
+	for _, p := range page.Products {
+		if p.FileName != nil && *p.FileName != "" && !seen[*p.FileName] {
+			seen[*p.FileName] = true
+			fileNames = append(fileNames, *p.FileName)
+		}
+	}
+
+	merchants := map[string]string{}
+	receipts := map[string]*ReceiptAnnotation{}
+	if len(fileNames) > 0 {
+		rows, err := sharedDB.GetDB().Query(ctx, `
+            SELECT f.name, t.merchant_name, a.tags, a.notes, a.custom_fields::text
+            FROM unnest($2::text[]) AS f(name)
+            LEFT JOIN product_category_service.receipt_texts t
+                ON t.user_id = $1 AND t.file_name = f.name
+            LEFT JOIN product_category_service.receipt_annotations a
+                ON a.user_id = $1 AND a.file_name = f.name`,
+			userIDInt, fileNames)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("error loading receipts: %v", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			var merchant, notes, raw *string
+			var tags []string
+			if err := rows.Scan(&name, &merchant, &tags, &notes, &raw); err != nil {
+				return nil, nil, 0, fmt.Errorf("error scanning receipt: %v", err)
+			}
+			if merchant != nil {
+				merchants[name] = *merchant
+			}
+			if raw != nil {
+				values, err := decodeCustomFields(*raw)
+				if err != nil {
+					return nil, nil, 0, err
+				}
+				receipts[name] = &ReceiptAnnotation{FileName: name, Tags: tags, Notes: notes, CustomFields: values}
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, nil, 0, fmt.Errorf("error during row iteration: %v", err)
+		}
+	}
+
+	exported := make([]ExportRow, 0, len(page.Products))
+	for _, p := range page.Products {
+		row := ExportRow{Product: p}
+		if p.FileName != nil {
+			row.Merchant = merchants[*p.FileName]
+			row.Receipt = receipts[*p.FileName]
+		}
+		exported = append(exported, row)
+	}
+	return exported, customFields, page.TotalCount, nil
+}
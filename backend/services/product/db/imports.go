@@ -0,0 +1,924 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/statements"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrImportNotFound  = errors.New("import not found")
+	ErrMappingNotFound = errors.New("import mapping not found")
+	ErrInvalidImport   = errors.New("invalid import")
+	// ErrImportStatus is returned for a batch that has moved past the step
+	// asked for, such as committing one twice.
+	ErrImportStatus = errors.New("import cannot do that in its current state")
+)
+
+// ImportTag is added to every product recorded from an imported statement.
+const ImportTag = "imported"
+
+// Values of import_batches.status.
+const (
+	ImportPreview   = "preview"
+	ImportCommitted = "committed"
+	ImportUndone    = "undone"
+)
+
+// Values of import_rows.status. New rows are imported on commit unless
+// skipped; duplicates only when asked for; unsupported rows never are.
+const (
+	RowNew         = "new"
+	RowDuplicate   = "duplicate"
+	RowUnsupported = "unsupported"
+	RowImported    = "imported"
+	RowSkipped     = "skipped"
+)
+
+// Values of import_rows.duplicate_type: what a duplicate row matched.
+const (
+	DuplicateProduct = "product"
+	DuplicateIncome  = "income"
+	DuplicateImport  = "import"
+)
+
+// duplicateWindow is how many days apart a row and an existing entry may
+// be dated and still match; banks post card payments a few days late.
+const duplicateWindow = 3
+
+// minPayeeSimilarity is the trigram similarity between a row's payee and
+// an existing entry's name above which they are taken to be the same.
+const minPayeeSimilarity = 0.4
+
+// maxImportBatches bounds ListImportBatches.
+const maxImportBatches = 200
+
+// ImportMapping is a saved way of reading one bank's CSV exports.
+type ImportMapping struct {
+	MappingID int32
+	Name      string
+	statements.Mapping
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+const mappingColumns = `mapping_id, name, delimiter, has_header, skip_lines, date_column,
+        COALESCE(amount_column, ''), COALESCE(debit_column, ''), COALESCE(credit_column, ''),
+        COALESCE(payee_column, ''), COALESCE(memo_column, ''), COALESCE(id_column, ''),
+        COALESCE(date_format, ''), negate_amounts, decimal_comma, created_at, updated_at`
+
+func scanMapping(row pgx.Row) (ImportMapping, error) {
+	var m ImportMapping
+	err := row.Scan(&m.MappingID, &m.Name, &m.Delimiter, &m.HasHeader, &m.SkipLines, &m.DateColumn,
+		&m.AmountColumn, &m.DebitColumn, &m.CreditColumn, &m.PayeeColumn, &m.MemoColumn, &m.IDColumn,
+		&m.DateFormat, &m.NegateAmounts, &m.DecimalComma, &m.CreatedAt, &m.UpdatedAt)
+	return m, err
+}
+
+func getImportMapping(ctx context.Context, q pgxQuerier, userID int32, mappingID int32) (*ImportMapping, error) {
+	m, err := scanMapping(q.QueryRow(ctx, `
+        SELECT `+mappingColumns+`
+        FROM product_category_service.import_mappings
+        WHERE user_id = $1 AND mapping_id = $2`,
+		userID, mappingID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrMappingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching import mapping: %v", err)
+	}
+	return &m, nil
+}
+
+// ListImportMappings returns the user's saved CSV mappings by name.
+func ListImportMappings(ctx context.Context, userID string) ([]ImportMapping, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT `+mappingColumns+`
+        FROM product_category_service.import_mappings
+        WHERE user_id = $1
+        ORDER BY lower(name), mapping_id`,
+		userIDInt)
+	if err != nil {
+		return nil, fmt.Errorf("error listing import mappings: %v", err)
+	}
+	defer rows.Close()
+
+	var mappings []ImportMapping
+	for rows.Next() {
+		m, err := scanMapping(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning import mapping: %v", err)
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, rows.Err()
+}
+
+// SaveImportMapping creates a mapping, or replaces the one MappingID names.
+// Names are unique per user.
+func SaveImportMapping(ctx context.Context, userID string, m ImportMapping) (*ImportMapping, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(m.Name) == "" {
+		return nil, fmt.Errorf("%w: the mapping needs a name", ErrInvalidImport)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if m.Delimiter == "" {
+		m.Delimiter = ","
+	}
+	optional := func(value string) *string {
+		if value = strings.TrimSpace(value); value == "" {
+			return nil
+		}
+		return &value
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var taken bool
+	err = tx.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM product_category_service.import_mappings
+                       WHERE user_id = $1 AND lower(name) = lower($2) AND mapping_id <> $3)`,
+		userIDInt, m.Name, m.MappingID).Scan(&taken)
+	if err != nil {
+		return nil, fmt.Errorf("error checking import mapping name: %v", err)
+	}
+	if taken {
+		return nil, fmt.Errorf("%w: a mapping named %q already exists", ErrInvalidImport, m.Name)
+	}
+
+	args := []interface{}{userIDInt, m.Name, m.Delimiter, m.HasHeader, m.SkipLines, strings.TrimSpace(m.DateColumn),
+		optional(m.AmountColumn), optional(m.DebitColumn), optional(m.CreditColumn), optional(m.PayeeColumn),
+		optional(m.MemoColumn), optional(m.IDColumn), optional(m.DateFormat), m.NegateAmounts, m.DecimalComma}
+	if m.MappingID == 0 {
+		err = tx.QueryRow(ctx, `
+            INSERT INTO product_category_service.import_mappings
+                (user_id, name, delimiter, has_header, skip_lines, date_column, amount_column, debit_column,
+                 credit_column, payee_column, memo_column, id_column, date_format, negate_amounts, decimal_comma)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+            RETURNING mapping_id`,
+			args...).Scan(&m.MappingID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating import mapping: %v", err)
+		}
+	} else {
+		tag, err := tx.Exec(ctx, `
+            UPDATE product_category_service.import_mappings
+            SET name = $2, delimiter = $3, has_header = $4, skip_lines = $5, date_column = $6,
+                amount_column = $7, debit_column = $8, credit_column = $9, payee_column = $10,
+                memo_column = $11, id_column = $12, date_format = $13, negate_amounts = $14,
+                decimal_comma = $15, updated_at = CURRENT_TIMESTAMP
+            WHERE user_id = $1 AND mapping_id = $16`,
+			append(args, m.MappingID)...)
+		if err != nil {
+			return nil, fmt.Errorf("error updating import mapping: %v", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, ErrMappingNotFound
+		}
+	}
+
+	saved, err := getImportMapping(ctx, tx, userIDInt, m.MappingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing import mapping: %v", err)
+	}
+	return saved, nil
+}
+
+// DeleteImportMapping removes one of the user's mappings. Batches read with
+// it keep their rows.
+func DeleteImportMapping(ctx context.Context, userID string, mappingID int32) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+	tag, err := sharedDB.GetDB().Exec(ctx, `
+        DELETE FROM product_category_service.import_mappings
+        WHERE user_id = $1 AND mapping_id = $2`,
+		userIDInt, mappingID)
+	if err != nil {
+		return fmt.Errorf("error deleting import mapping: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMappingNotFound
+	}
+	return nil
+}
+
+// ImportBatch is one statement file imported into a balance account, with
+// how many of its rows are in each status.
+type ImportBatch struct {
+	BatchID     int32
+	AccountID   int32
+	AccountName string
+	Format      string
+	FileName    *string
+	MappingID   *int32
+	// Currency is the account's, which every row is in.
+	Currency    string
+	Status      string
+	CreatedAt   time.Time
+	CommittedAt *time.Time
+	UndoneAt    *time.Time
+	Counts      map[string]int
+	// Rows is filled in by GetImportBatch and the calls that return it.
+	Rows []ImportRow
+}
+
+// ImportRow is one transaction of a batch. Amount is positive for money in
+// and negative for money out.
+type ImportRow struct {
+	RowNumber     int32
+	Line          int32
+	Date          time.Time
+	Amount        float64
+	Payee         *string
+	Memo          *string
+	ExternalID    *string
+	Status        string
+	DuplicateType *string
+	DuplicateID   *int32
+	Note          *string
+	ProductID     *int32
+	IncomeID      *int32
+}
+
+// ImportRequest is a statement to preview an import of. CSV files are read
+// with the saved mapping MappingID names or, when it is 0, with Mapping.
+// Format is detected from FileName and Data when empty.
+type ImportRequest struct {
+	AccountID int32
+	Format    string
+	FileName  string
+	Data      []byte
+	MappingID int32
+	Mapping   *statements.Mapping
+}
+
+// importAccount is the balance account a batch is imported into.
+type importAccount struct {
+	name      string
+	currency  string
+	liability bool
+}
+
+func getImportAccount(ctx context.Context, tx pgx.Tx, userID int32, accountID int32) (*importAccount, error) {
+	var account importAccount
+	err := tx.QueryRow(ctx, `
+        SELECT account_name, currency, account_income_service.is_liability(account_type)
+        FROM account_income_service.accounts
+        WHERE account_id = $1 AND user_id = $2`,
+		accountID, userID).Scan(&account.name, &account.currency, &account.liability)
+	if err == pgx.ErrNoRows {
+		return nil, ErrBalanceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching balance account: %v", err)
+	}
+	return &account, nil
+}
+
+const batchColumns = `b.batch_id, b.account_id, COALESCE(a.account_name, ''), b.format, b.file_name,
+        b.mapping_id, b.currency, b.status, b.created_at, b.committed_at, b.undone_at,
+        (SELECT COALESCE(jsonb_object_agg(s.status, s.n), '{}'::jsonb)::text
+         FROM (SELECT r.status, COUNT(*) AS n FROM product_category_service.import_rows r
+               WHERE r.batch_id = b.batch_id GROUP BY r.status) s)`
+
+const batchFrom = `
+        FROM product_category_service.import_batches b
+        LEFT JOIN account_income_service.accounts a ON a.account_id = b.account_id`
+
+func scanBatch(row pgx.Row) (ImportBatch, error) {
+	var b ImportBatch
+	var counts string
+	err := row.Scan(&b.BatchID, &b.AccountID, &b.AccountName, &b.Format, &b.FileName, &b.MappingID,
+		&b.Currency, &b.Status, &b.CreatedAt, &b.CommittedAt, &b.UndoneAt, &counts)
+	if err != nil {
+		return b, err
+	}
+	b.Counts = map[string]int{}
+	if err := json.Unmarshal([]byte(counts), &b.Counts); err != nil {
+		return b, fmt.Errorf("error decoding import counts: %v", err)
+	}
+	return b, nil
+}
+
+// getImportBatch fetches one of the user's batches with its rows, locking
+// the batch when asked.
+func getImportBatch(ctx context.Context, tx pgx.Tx, userID int32, batchID int32, lock bool) (*ImportBatch, error) {
+	query := `
+        SELECT ` + batchColumns + batchFrom + `
+        WHERE b.user_id = $1 AND b.batch_id = $2`
+	if lock {
+		query += " FOR UPDATE OF b"
+	}
+	batch, err := scanBatch(tx.QueryRow(ctx, query, userID, batchID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrImportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching import: %v", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT row_number, line, transaction_date, amount::float8, payee, memo, external_id, status,
+            duplicate_type, duplicate_id, note, product_id, income_id
+        FROM product_category_service.import_rows
+        WHERE batch_id = $1
+        ORDER BY row_number`,
+		batchID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching import rows: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r ImportRow
+		if err := rows.Scan(&r.RowNumber, &r.Line, &r.Date, &r.Amount, &r.Payee, &r.Memo, &r.ExternalID,
+			&r.Status, &r.DuplicateType, &r.DuplicateID, &r.Note, &r.ProductID, &r.IncomeID); err != nil {
+			return nil, fmt.Errorf("error scanning import row: %v", err)
+		}
+		batch.Rows = append(batch.Rows, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return &batch, nil
+}
+
+// GetImportBatch returns one of the user's batches with its rows.
+func GetImportBatch(ctx context.Context, userID string, batchID int32) (*ImportBatch, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	return getImportBatch(ctx, tx, userIDInt, batchID, false)
+}
+
+// ListImportBatches returns the user's batches without their rows, newest
+// first, for one account when accountID is set.
+func ListImportBatches(ctx context.Context, userID string, accountID int32) ([]ImportBatch, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT `+batchColumns+batchFrom+`
+        WHERE b.user_id = $1 AND ($2 = 0 OR b.account_id = $2)
+        ORDER BY b.created_at DESC, b.batch_id DESC
+        LIMIT $3`,
+		userIDInt, accountID, maxImportBatches)
+	if err != nil {
+		return nil, fmt.Errorf("error listing imports: %v", err)
+	}
+	defer rows.Close()
+
+	var batches []ImportBatch
+	for rows.Next() {
+		b, err := scanBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning import: %v", err)
+		}
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
+
+// duplicateMatch is an existing entry a statement row matched.
+type duplicateMatch struct {
+	kind string
+	id   int32
+	note string
+}
+
+// findDuplicate looks for an entry the row already records: a transaction
+// with the same bank ID in a committed import into the account, or else,
+// for money out, a product or receipt and, for money in, an income into
+// the account, for the same amount within duplicateWindow days whose name
+// is like the payee. claimed holds entries earlier rows matched, so two
+// identical charges on one statement need two entries to be duplicates.
+func findDuplicate(ctx context.Context, tx pgx.Tx, userID int32, accountID int32, currency string, t statements.Transaction,
+	amount money.Money, claimed map[duplicateMatch]bool) (*duplicateMatch, error) {
+	if t.ID != "" {
+		var batchID int32
+		err := tx.QueryRow(ctx, `
+            SELECT b.batch_id
+            FROM product_category_service.import_rows r
+            JOIN product_category_service.import_batches b ON b.batch_id = r.batch_id
+            WHERE b.user_id = $1 AND b.account_id = $2 AND b.status = 'committed'
+              AND r.status = 'imported' AND r.external_id = $3
+            LIMIT 1`,
+			userID, accountID, t.ID).Scan(&batchID)
+		if err == nil {
+			return &duplicateMatch{DuplicateImport, batchID, fmt.Sprintf("already imported in batch %d", batchID)}, nil
+		}
+		if err != pgx.ErrNoRows {
+			return nil, fmt.Errorf("error checking earlier imports: %v", err)
+		}
+	}
+
+	payee := strings.ToLower(strings.TrimSpace(t.Payee))
+	var query string
+	kind := DuplicateProduct
+	if amount.Minor < 0 {
+		// Receipts are matched on their total: the bank sees one charge
+		// for all of their products.
+		query = `
+            SELECT MIN(p.product_id), MIN(p.date_added)::date, MIN(p.product_name)
+            FROM product_category_service.products p
+            LEFT JOIN product_category_service.receipt_texts rt
+                ON rt.user_id = p.user_id AND rt.file_name = p.file_name
+            WHERE p.user_id = $1 AND p.currency = $2
+              AND p.date_added::date BETWEEN $3::date - $5::int AND $3::date + $5::int
+              AND (p.account_id IS NULL OR p.account_id = $6)
+            GROUP BY COALESCE(p.file_name, 'product:' || p.product_id)
+            HAVING ROUND(SUM(p.quantity * p.price), 2) = $4
+               AND ($7 = '' OR GREATEST(
+                       MAX(word_similarity(lower(p.product_name), $7)),
+                       MAX(word_similarity(lower(COALESCE(p.description, '')), $7)),
+                       MAX(word_similarity(lower(COALESCE(rt.merchant_name, '')), $7))) >= $8)
+            ORDER BY ABS(MIN(p.date_added)::date - $3::date), 1
+            LIMIT 10`
+	} else {
+		kind = DuplicateIncome
+		query = `
+            SELECT i.income_id, i.date_added::date, COALESCE(i.description, '')
+            FROM account_income_service.incomes i
+            WHERE i.user_id = $1 AND i.currency = $2
+              AND i.date_added::date BETWEEN $3::date - $5::int AND $3::date + $5::int
+              AND i.amount = $4 AND i.account_id = $6
+              AND ($7 = '' OR word_similarity(lower(COALESCE(i.description, '')), $7) >= $8)
+            ORDER BY ABS(i.date_added::date - $3::date), 1
+            LIMIT 10`
+	}
+	magnitude := amount
+	if magnitude.Minor < 0 {
+		magnitude.Minor = -magnitude.Minor
+	}
+	rows, err := tx.Query(ctx, query, userID, currency, t.Date, magnitude.String(), duplicateWindow,
+		accountID, payee, minPayeeSimilarity)
+	if err != nil {
+		return nil, fmt.Errorf("error finding duplicates: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int32
+		var date time.Time
+		var name string
+		if err := rows.Scan(&id, &date, &name); err != nil {
+			return nil, fmt.Errorf("error scanning duplicate: %v", err)
+		}
+		match := duplicateMatch{kind: kind, id: id}
+		if claimed[match] {
+			continue
+		}
+		claimed[match] = true
+		match.note = fmt.Sprintf("matches %s %q on %s", kind, name, date.Format("2006-01-02"))
+		return &match, nil
+	}
+	return nil, rows.Err()
+}
+
+// unsupportedReason says why a statement row for amount cannot be imported
+// into an account, which is a credit card or loan if liability is set, or
+// returns "" if it can.
+func unsupportedReason(amount money.Money, liability bool) string {
+	switch {
+	case amount.IsZero():
+		return "the amount is zero"
+	case amount.Minor > 0 && liability:
+		return "money into a credit card or loan is recorded as a transfer or refund"
+	}
+	return ""
+}
+
+// PreviewImport reads a statement and stores it as a batch in preview,
+// marking each row new, a duplicate of an existing entry, or unsupported.
+// Nothing is recorded until the batch is committed.
+func PreviewImport(ctx context.Context, userID string, req ImportRequest) (*ImportBatch, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Data) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImport)
+	}
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "qfx" {
+		format = statements.FormatOFX
+	}
+	if format == "" {
+		if format = statements.DetectFormat(req.FileName, req.Data); format == "" {
+			return nil, fmt.Errorf("%w: the file's format could not be worked out; name it", ErrInvalidImport)
+		}
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	account, err := getImportAccount(ctx, tx, userIDInt, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	var mapping statements.Mapping
+	var mappingID *int32
+	if format == statements.FormatCSV {
+		switch {
+		case req.MappingID != 0:
+			saved, err := getImportMapping(ctx, tx, userIDInt, req.MappingID)
+			if err != nil {
+				return nil, err
+			}
+			mapping, mappingID = saved.Mapping, &saved.MappingID
+		case req.Mapping != nil:
+			mapping = *req.Mapping
+		default:
+			return nil, fmt.Errorf("%w: CSV files need a mapping", ErrInvalidImport)
+		}
+	} else if req.Mapping != nil {
+		mapping.DateFormat = req.Mapping.DateFormat
+	}
+
+	statement, err := statements.Parse(format, req.Data, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if len(statement.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: the file has no transactions", ErrInvalidImport)
+	}
+	if statement.Currency != "" && money.Normalize(statement.Currency) != account.currency {
+		return nil, fmt.Errorf("%w: the file is in %s but the account holds %s",
+			ErrInvalidImport, money.Normalize(statement.Currency), account.currency)
+	}
+
+	var fileName *string
+	if name := strings.TrimSpace(req.FileName); name != "" {
+		fileName = &name
+	}
+	var batchID int32
+	err = tx.QueryRow(ctx, `
+        INSERT INTO product_category_service.import_batches
+            (user_id, account_id, format, file_name, mapping_id, currency)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING batch_id`,
+		userIDInt, req.AccountID, format, fileName, mappingID, account.currency).Scan(&batchID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating import: %v", err)
+	}
+
+	claimed := map[duplicateMatch]bool{}
+	seen := map[string]int{}
+	for i, t := range statement.Transactions {
+		amount := money.FromFloat(t.Amount, account.currency)
+		status := RowNew
+		var match *duplicateMatch
+		var note *string
+		reason := unsupportedReason(amount, account.liability)
+		switch {
+		case reason != "":
+			status, note = RowUnsupported, &reason
+		case t.ID != "" && seen[t.ID] != 0:
+			status = RowDuplicate
+			match = &duplicateMatch{DuplicateImport, batchID, fmt.Sprintf("repeats line %d", seen[t.ID])}
+		default:
+			if match, err = findDuplicate(ctx, tx, userIDInt, req.AccountID, account.currency, t, amount, claimed); err != nil {
+				return nil, err
+			}
+			if match != nil {
+				status = RowDuplicate
+			}
+		}
+		if t.ID != "" && seen[t.ID] == 0 {
+			seen[t.ID] = t.Line
+		}
+
+		var duplicateType *string
+		var duplicateID *int32
+		if match != nil {
+			duplicateType, duplicateID, note = &match.kind, &match.id, &match.note
+		}
+		_, err := tx.Exec(ctx, `
+            INSERT INTO product_category_service.import_rows
+                (batch_id, row_number, line, transaction_date, amount, payee, memo, external_id,
+                 status, duplicate_type, duplicate_id, note)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			batchID, i+1, t.Line, t.Date, amount.String(), optionalString(truncate(t.Payee, 255)),
+			optionalString(t.Memo), optionalString(truncate(t.ID, 255)), status, duplicateType, duplicateID, note)
+		if err != nil {
+			return nil, fmt.Errorf("error storing import row: %v", err)
+		}
+	}
+
+	batch, err := getImportBatch(ctx, tx, userIDInt, batchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing import preview: %v", err)
+	}
+	return batch, nil
+}
+
+// CommitImport records a previewed batch: money out as products paid from
+// the account and money in as incomes into it. New rows are recorded
+// unless listed in skip; duplicates only when listed in include. Imported
+// products are categorised as products added by hand are, with the user's
+// rules and model loaded once for the batch, but are not linked to items:
+// a statement line names a merchant, not what was bought.
+func CommitImport(ctx context.Context, userID string, batchID int32, include, skip []int32) (*ImportBatch, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch, err := getImportBatch(ctx, tx, userIDInt, batchID, true)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != ImportPreview {
+		return nil, fmt.Errorf("%w: import %d is %s", ErrImportStatus, batchID, batch.Status)
+	}
+	account, err := getImportAccount(ctx, tx, userIDInt, batch.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := newProductWriter(ctx, tx, userIDInt)
+	if err != nil {
+		return nil, err
+	}
+	var spent []time.Time
+
+	for _, row := range batch.Rows {
+		if row.Status == RowUnsupported {
+			continue
+		}
+		if !recordRow(row, include, skip) {
+			if _, err := tx.Exec(ctx, `
+                UPDATE product_category_service.import_rows SET status = 'skipped'
+                WHERE batch_id = $1 AND row_number = $2`,
+				batchID, row.RowNumber); err != nil {
+				return nil, fmt.Errorf("error skipping import row: %v", err)
+			}
+			continue
+		}
+
+		name := importedName(row)
+		if row.Amount < 0 {
+			productID, err := writer.insert(ctx, Product{
+				ProductName: name,
+				Quantity:    1,
+				Price:       money.FromFloat(-row.Amount, batch.Currency),
+				Description: row.Memo,
+				DateAdded:   row.Date,
+				Tags:        []string{ImportTag},
+				AccountID:   &batch.AccountID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			spent = append(spent, row.Date)
+			_, err = tx.Exec(ctx, `
+                UPDATE product_category_service.import_rows SET status = 'imported', product_id = $3
+                WHERE batch_id = $1 AND row_number = $2`,
+				batchID, row.RowNumber, productID)
+			if err != nil {
+				return nil, fmt.Errorf("error linking import row: %v", err)
+			}
+			continue
+		}
+
+		if account.liability {
+			return nil, fmt.Errorf("%w: the account has become a credit card or loan, which take no incomes", ErrImportStatus)
+		}
+		incomeID, err := insertImportedIncome(ctx, tx, userIDInt, batch.AccountID, name, row)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(ctx, `
+            UPDATE product_category_service.import_rows SET status = 'imported', income_id = $3
+            WHERE batch_id = $1 AND row_number = $2`,
+			batchID, row.RowNumber, incomeID)
+		if err != nil {
+			return nil, fmt.Errorf("error linking import row: %v", err)
+		}
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userIDInt, spent); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE product_category_service.import_batches
+        SET status = 'committed', committed_at = CURRENT_TIMESTAMP
+        WHERE batch_id = $1`,
+		batchID); err != nil {
+		return nil, fmt.Errorf("error committing import: %v", err)
+	}
+	committed, err := getImportBatch(ctx, tx, userIDInt, batchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing import: %v", err)
+	}
+	return committed, nil
+}
+
+// recordRow reports whether committing records a previewed row: new rows
+// unless listed in skip, duplicates only when listed in include.
+func recordRow(row ImportRow, include, skip []int32) bool {
+	listed := func(ids []int32) bool {
+		for _, id := range ids {
+			if id == row.RowNumber {
+				return true
+			}
+		}
+		return false
+	}
+	return (row.Status == RowNew && !listed(skip)) || (row.Status == RowDuplicate && listed(include))
+}
+
+// importedName is the name an imported row is recorded under: its payee,
+// else its memo.
+func importedName(row ImportRow) string {
+	if row.Payee != nil {
+		return *row.Payee
+	}
+	if row.Memo != nil {
+		return truncate(*row.Memo, 255)
+	}
+	return "Imported transaction"
+}
+
+// insertImportedIncome records money in as an income into the account and
+// posts it as the balance service posts incomes.
+func insertImportedIncome(ctx context.Context, tx pgx.Tx, userID int32, accountID int32, name string, row ImportRow) (int32, error) {
+	var incomeID int32
+	var currency string
+	err := tx.QueryRow(ctx, `
+        INSERT INTO account_income_service.incomes (user_id, amount, description, date_added, account_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING income_id, currency`,
+		userID, row.Amount, name, row.Date, accountID).Scan(&incomeID, &currency)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting income: %w", err)
+	}
+	_, err = ledger.Restate(ctx, tx, ledger.Entry{
+		UserID:        userID,
+		Kind:          "income",
+		ReferenceType: "income",
+		ReferenceID:   strconv.Itoa(int(incomeID)),
+		Description:   name,
+		OccurredAt:    row.Date,
+		Postings:      ledger.Move(ledger.IncomeAccount, ledger.BalanceAccount(accountID), money.FromFloat(row.Amount, currency)),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incomeID, nil
+}
+
+// UndoImport deletes the products and incomes a committed batch recorded,
+// including any the user has since changed, and marks it undone. Undoing a
+// batch still in preview discards it.
+func UndoImport(ctx context.Context, userID string, batchID int32) (*ImportBatch, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch, err := getImportBatch(ctx, tx, userIDInt, batchID, true)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == ImportUndone {
+		return nil, fmt.Errorf("%w: import %d is already undone", ErrImportStatus, batchID)
+	}
+
+	if batch.Status == ImportCommitted {
+		tag, err := tx.Exec(ctx, `
+            DELETE FROM product_category_service.products
+            WHERE user_id = $1 AND product_id IN (
+                SELECT product_id FROM product_category_service.import_rows
+                WHERE batch_id = $2 AND product_id IS NOT NULL)`,
+			userIDInt, batchID)
+		if err = refundedError(err); err == ErrProductRefunded {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error deleting imported products: %v", err)
+		}
+		if tag.RowsAffected() > 0 {
+			if err := RecomputeBudgetSpend(ctx, tx, userIDInt); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, row := range batch.Rows {
+			if row.IncomeID == nil {
+				continue
+			}
+			if _, err := tx.Exec(ctx, `
+                DELETE FROM account_income_service.incomes
+                WHERE user_id = $1 AND income_id = $2`,
+				userIDInt, *row.IncomeID); err != nil {
+				return nil, fmt.Errorf("error deleting imported income: %v", err)
+			}
+			// Restating without postings reverses the income's entries.
+			if _, err := ledger.Restate(ctx, tx, ledger.Entry{
+				UserID:        userIDInt,
+				Kind:          "income",
+				ReferenceType: "income",
+				ReferenceID:   strconv.Itoa(int(*row.IncomeID)),
+				Description:   "Import undone",
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `
+            UPDATE product_category_service.import_rows SET product_id = NULL, income_id = NULL
+            WHERE batch_id = $1`,
+			batchID); err != nil {
+			return nil, fmt.Errorf("error unlinking import rows: %v", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE product_category_service.import_batches
+        SET status = 'undone', undone_at = CURRENT_TIMESTAMP
+        WHERE batch_id = $1`,
+		batchID); err != nil {
+		return nil, fmt.Errorf("error undoing import: %v", err)
+	}
+	undone, err := getImportBatch(ctx, tx, userIDInt, batchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing undo: %v", err)
+	}
+	return undone, nil
+}
+
+// optionalString is nil for blank text.
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// truncate shortens value to at most n characters.
+func truncate(value string, n int) string {
+	if runes := []rune(value); len(runes) > n {
+		return string(runes[:n])
+	}
+	return value
+}
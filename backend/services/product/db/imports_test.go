@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	balanceDB "github.com/Aneesh-Hegde/expenseManager/services/balance/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/statements"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+// Tests that write to the database the DB_* variables name, which must have
+// every service's tables and migrations applied, only run when DB_TEST is
+// set, against a scratch database.
+func requireDB(t *testing.T) {
+	t.Helper()
+	if os.Getenv("DB_TEST") == "" {
+		t.Skip("DB_TEST is not set")
+	}
+}
+
+// newUser creates a user of its own for a test, with a USD checking account
+// holding 500, and returns their IDs.
+func newUser(t *testing.T, ctx context.Context) (int32, int32) {
+	t.Helper()
+	name := fmt.Sprintf("product-test-%d", time.Now().UnixNano())
+	var userID int32
+	err := sharedDB.GetDB().QueryRow(ctx,
+		"INSERT INTO user_service.users (username, email) VALUES ($1, $2) RETURNING user_id",
+		name, name+"@example.com").Scan(&userID)
+	if err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	accountID, err := balanceDB.CreateAccountWithIncome(ctx, fmt.Sprint(userID),
+		balanceDB.AccountDetails{Name: "Checking"}, "cash", money.New(50000, "USD"))
+	if err != nil {
+		t.Fatalf("creating account: %v", err)
+	}
+	return userID, accountID
+}
+
+func TestFindDuplicateClaimsEachMatchOnce(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID, accountID := newUser(t, ctx)
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Two identical charges, as a statement with the same coffee twice in a
+	// day would show.
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	writer, err := newProductWriter(ctx, tx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var products []int32
+	for i := 0; i < 2; i++ {
+		productID, err := writer.insert(ctx, Product{ProductName: "Coffee", Quantity: 1, Price: money.New(350, "USD"),
+			DateAdded: date, AccountID: &accountID})
+		if err != nil {
+			t.Fatalf("inserting product: %v", err)
+		}
+		products = append(products, productID)
+	}
+
+	find := func(claimed map[duplicateMatch]bool, amount float64, payee string) *duplicateMatch {
+		t.Helper()
+		row := statements.Transaction{Date: date.AddDate(0, 0, 1), Amount: amount, Payee: payee}
+		match, err := findDuplicate(ctx, tx, userID, accountID, "USD", row, money.FromFloat(amount, "USD"), claimed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return match
+	}
+
+	claimed := map[duplicateMatch]bool{}
+	for i, want := range products {
+		match := find(claimed, -3.5, "COFFEE")
+		if match == nil || match.kind != DuplicateProduct || match.id != want {
+			t.Fatalf("row %d matched %+v, want product %d", i+1, match, want)
+		}
+	}
+	if match := find(claimed, -3.5, "COFFEE"); match != nil {
+		t.Errorf("a third charge matched %+v after both products were claimed", match)
+	}
+
+	// Another statement claims nothing yet, so it matches them again.
+	if match := find(map[duplicateMatch]bool{}, -3.5, "coffee"); match == nil || match.id != products[0] {
+		t.Errorf("a new statement matched %+v, want product %d", match, products[0])
+	}
+	if match := find(map[duplicateMatch]bool{}, -3.5, "Gym membership"); match != nil {
+		t.Errorf("a charge to another payee matched %+v", match)
+	}
+	if match := find(map[duplicateMatch]bool{}, -4, "coffee"); match != nil {
+		t.Errorf("a charge for another amount matched %+v", match)
+	}
+
+	// Money in matches the income that opened the account, once.
+	claimed = map[duplicateMatch]bool{}
+	if match := find(claimed, 500, ""); match == nil || match.kind != DuplicateIncome {
+		t.Fatalf("a deposit matched %+v, want the opening income", match)
+	}
+	if match := find(claimed, 500, ""); match != nil {
+		t.Errorf("a second deposit matched %+v after the income was claimed", match)
+	}
+}
+
+func TestUnsupportedReason(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    money.Money
+		liability bool
+		want      bool
+	}{
+		{name: "money out", amount: money.New(-350, "USD")},
+		{name: "money in", amount: money.New(350, "USD")},
+		{name: "money out of a card", amount: money.New(-350, "USD"), liability: true},
+		{name: "money into a card", amount: money.New(350, "USD"), liability: true, want: true},
+		{name: "zero", amount: money.Zero("USD"), want: true},
+	}
+	for _, tt := range tests {
+		if got := unsupportedReason(tt.amount, tt.liability); (got != "") != tt.want {
+			t.Errorf("%s: unsupportedReason() = %q, want unsupported %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordRow(t *testing.T) {
+	include, skip := []int32{2, 3}, []int32{1, 4}
+	tests := []struct {
+		row  ImportRow
+		want bool
+	}{
+		{ImportRow{RowNumber: 1, Status: RowNew}, false},
+		{ImportRow{RowNumber: 2, Status: RowNew}, true},
+		{ImportRow{RowNumber: 3, Status: RowDuplicate}, true},
+		{ImportRow{RowNumber: 4, Status: RowDuplicate}, false},
+		{ImportRow{RowNumber: 2, Status: RowUnsupported}, false},
+	}
+	for _, tt := range tests {
+		if got := recordRow(tt.row, include, skip); got != tt.want {
+			t.Errorf("recordRow(row %d %s) = %v, want %v", tt.row.RowNumber, tt.row.Status, got, tt.want)
+		}
+	}
+}
+
+func TestImportedName(t *testing.T) {
+	payee, memo := "Corner Cafe", strings.Repeat("é", 300)
+	tests := []struct {
+		name string
+		row  ImportRow
+		want string
+	}{
+		{name: "payee over memo", row: ImportRow{Payee: &payee, Memo: &memo}, want: payee},
+		{name: "memo cut to 255 characters", row: ImportRow{Memo: &memo}, want: strings.Repeat("é", 255)},
+		{name: "neither", want: "Imported transaction"},
+	}
+	for _, tt := range tests {
+		if got := importedName(tt.row); got != tt.want {
+			t.Errorf("%s: importedName() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,534 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/pricing"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrItemNotFound = errors.New("item not found")
+	ErrInvalidMerge = errors.New("invalid merge")
+)
+
+// Item is a canonical item: every product bought under names that fold to
+// the same key, plus any items the user merged into it.
+type Item struct {
+	ItemID          int32
+	Name            string
+	Purchases       int
+	LatestUnitPrice float64
+	LastPurchased   *time.Time
+}
+
+// AssignItems links the user's products that have no canonical item yet to
+// one, creating items for names not seen before. Products are unlinked when
+// they are renamed, so this also catches up after edits.
+func AssignItems(ctx context.Context, tx pgx.Tx, userID int32) error {
+	rows, err := tx.Query(ctx, `
+        SELECT product_id, product_name FROM product_category_service.products
+        WHERE user_id = $1 AND item_id IS NULL AND btrim(COALESCE(product_name, '')) <> ''
+        ORDER BY date_added, product_id`,
+		userID)
+	if err != nil {
+		return fmt.Errorf("error loading unlinked products: %v", err)
+	}
+	byKey := map[string][]int32{}
+	names := map[string]string{}
+	var keys []string
+	for rows.Next() {
+		var productID int32
+		var name string
+		if err := rows.Scan(&productID, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning product: %v", err)
+		}
+		key := pricing.ItemKey(name)
+		if key == "" {
+			continue
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+			names[key] = pricing.DisplayName(name)
+		}
+		byKey[key] = append(byKey[key], productID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	for _, key := range keys {
+		// Keys of merged items keep pointing at the item they were merged
+		// into, so later purchases land there too.
+		var itemID int32
+		var err error
+		// A concurrent upload may create the same item between the insert
+		// skipping it and the select looking for it; the second attempt
+		// sees it.
+		for attempt := 0; attempt < 2; attempt++ {
+			err = tx.QueryRow(ctx, `
+            WITH created AS (
+                INSERT INTO product_category_service.canonical_items (user_id, name, normalized_key)
+                VALUES ($1, $2, $3)
+                ON CONFLICT (user_id, normalized_key) DO NOTHING
+                RETURNING item_id
+            )
+            SELECT item_id FROM created
+            UNION ALL
+            SELECT COALESCE(merged_into, item_id) FROM product_category_service.canonical_items
+            WHERE user_id = $1 AND normalized_key = $3`,
+				userID, names[key], key).Scan(&itemID)
+			if err != pgx.ErrNoRows {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("error creating item %s: %v", key, err)
+		}
+		_, err = tx.Exec(ctx, `
+            UPDATE product_category_service.products SET item_id = $1
+            WHERE user_id = $2 AND product_id = ANY($3)`,
+			itemID, userID, byKey[key])
+		if err != nil {
+			return fmt.Errorf("error linking products to item: %v", err)
+		}
+	}
+	return nil
+}
+
+// withUserItems runs fn in a transaction after bringing the user's item links
+// up to date.
+func withUserItems(ctx context.Context, userID string, fn func(tx pgx.Tx, userID int32) error) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := AssignItems(ctx, tx, userIDInt); err != nil {
+		return err
+	}
+	if err := fn(tx, userIDInt); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	return nil
+}
+
+const itemColumns = `i.item_id, i.name,
+            (SELECT COUNT(*) FROM product_category_service.products p WHERE p.item_id = i.item_id),
+            COALESCE(latest.price, 0)::float8, latest.date_added`
+
+const itemLatestJoin = `
+        LEFT JOIN LATERAL (
+            SELECT p.price, p.date_added FROM product_category_service.products p
+            WHERE p.item_id = i.item_id
+            ORDER BY p.date_added DESC, p.product_id DESC
+            LIMIT 1
+        ) latest ON TRUE`
+
+func scanItem(row pgx.Row) (Item, error) {
+	var item Item
+	err := row.Scan(&item.ItemID, &item.Name, &item.Purchases, &item.LatestUnitPrice, &item.LastPurchased)
+	return item, err
+}
+
+// ListItems returns the user's items whose name contains query, most
+// recently bought first.
+func ListItems(ctx context.Context, userID string, query string, limit int) ([]Item, error) {
+	var items []Item
+	err := withUserItems(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		rows, err := tx.Query(ctx, `
+            SELECT `+itemColumns+`
+            FROM product_category_service.canonical_items i`+itemLatestJoin+`
+            WHERE i.user_id = $1 AND i.merged_into IS NULL
+              AND ($2 = '' OR i.name ILIKE '%' || $2 || '%')
+            ORDER BY latest.date_added DESC NULLS LAST, i.item_id DESC
+            LIMIT $3`,
+			userIDInt, escapeLike(query), limit)
+		if err != nil {
+			return fmt.Errorf("error listing items: %v", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			item, err := scanItem(rows)
+			if err != nil {
+				return fmt.Errorf("error scanning item: %v", err)
+			}
+			items = append(items, item)
+		}
+		return rows.Err()
+	})
+	return items, err
+}
+
+func getItem(ctx context.Context, tx pgx.Tx, userID int32, itemID int32) (*Item, error) {
+	item, err := scanItem(tx.QueryRow(ctx, `
+        SELECT `+itemColumns+`
+        FROM product_category_service.canonical_items i`+itemLatestJoin+`
+        WHERE i.user_id = $1 AND i.item_id = $2 AND i.merged_into IS NULL`,
+		userID, itemID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching item: %v", err)
+	}
+	return &item, nil
+}
+
+// RenameItem changes the name an item is shown under. Products keep their
+// own names.
+func RenameItem(ctx context.Context, userID string, itemID int32, name string) (*Item, error) {
+	var item *Item
+	err := withUserItems(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		tag, err := tx.Exec(ctx, `
+            UPDATE product_category_service.canonical_items SET name = $1
+            WHERE user_id = $2 AND item_id = $3 AND merged_into IS NULL`,
+			name, userIDInt, itemID)
+		if err != nil {
+			return fmt.Errorf("error renaming item: %v", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrItemNotFound
+		}
+		item, err = getItem(ctx, tx, userIDInt, itemID)
+		return err
+	})
+	return item, err
+}
+
+// MergeSuggestion is a pair of items whose names look like the same thing.
+type MergeSuggestion struct {
+	Item       Item
+	Other      Item
+	Similarity float64
+}
+
+// minMergeSimilarity is the trigram similarity of item keys above which two
+// items are suggested for merging.
+const minMergeSimilarity = 0.5
+
+// SuggestItemMerges returns pairs of the user's items that are probably the
+// same thing, most similar first, leaving out pairs the user dismissed.
+func SuggestItemMerges(ctx context.Context, userID string, limit int) ([]MergeSuggestion, error) {
+	var suggestions []MergeSuggestion
+	err := withUserItems(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		rows, err := tx.Query(ctx, `
+            SELECT a.item_id, b.item_id, similarity(a.normalized_key, b.normalized_key)::float8
+            FROM product_category_service.canonical_items a
+            JOIN product_category_service.canonical_items b
+                ON b.user_id = a.user_id AND b.item_id > a.item_id AND b.merged_into IS NULL
+               AND a.normalized_key % b.normalized_key
+            WHERE a.user_id = $1 AND a.merged_into IS NULL
+              AND similarity(a.normalized_key, b.normalized_key) >= $2
+              AND NOT EXISTS (
+                SELECT 1 FROM product_category_service.item_merge_dismissals d
+                WHERE d.item_id = a.item_id AND d.other_item_id = b.item_id)
+            ORDER BY 3 DESC, a.item_id, b.item_id
+            LIMIT $3`,
+			userIDInt, minMergeSimilarity, limit)
+		if err != nil {
+			return fmt.Errorf("error finding similar items: %v", err)
+		}
+		type pair struct {
+			a, b       int32
+			similarity float64
+		}
+		var pairs []pair
+		for rows.Next() {
+			var p pair
+			if err := rows.Scan(&p.a, &p.b, &p.similarity); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning similar items: %v", err)
+			}
+			pairs = append(pairs, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error during row iteration: %v", err)
+		}
+
+		for _, p := range pairs {
+			a, err := getItem(ctx, tx, userIDInt, p.a)
+			if err != nil {
+				return err
+			}
+			b, err := getItem(ctx, tx, userIDInt, p.b)
+			if err != nil {
+				return err
+			}
+			suggestions = append(suggestions, MergeSuggestion{Item: *a, Other: *b, Similarity: p.similarity})
+		}
+		return nil
+	})
+	return suggestions, err
+}
+
+// MergeItems folds the source items into the target: their purchases move to
+// it and products bought later under their names are linked to it. It
+// returns the target and how many products moved.
+func MergeItems(ctx context.Context, userID string, targetID int32, sourceIDs []int32) (*Item, int64, error) {
+	var item *Item
+	var moved int64
+	err := withUserItems(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		for _, id := range sourceIDs {
+			if id == targetID {
+				return fmt.Errorf("%w: an item cannot be merged into itself", ErrInvalidMerge)
+			}
+		}
+		var found int
+		err := tx.QueryRow(ctx, `
+            SELECT COUNT(*) FROM product_category_service.canonical_items
+            WHERE user_id = $1 AND merged_into IS NULL AND (item_id = $2 OR item_id = ANY($3))
+            `,
+			userIDInt, targetID, sourceIDs).Scan(&found)
+		if err != nil {
+			return fmt.Errorf("error checking items: %v", err)
+		}
+		if found != len(uniqueIDs(sourceIDs))+1 {
+			return ErrItemNotFound
+		}
+
+		tag, err := tx.Exec(ctx, `
+            UPDATE product_category_service.products SET item_id = $1
+            WHERE user_id = $2 AND item_id = ANY($3)`,
+			targetID, userIDInt, sourceIDs)
+		if err != nil {
+			return fmt.Errorf("error moving purchases: %v", err)
+		}
+		moved = tag.RowsAffected()
+
+		// Items merged into a source earlier now point straight at the target.
+		_, err = tx.Exec(ctx, `
+            UPDATE product_category_service.canonical_items SET merged_into = $1
+            WHERE user_id = $2 AND (item_id = ANY($3) OR merged_into = ANY($3))`,
+			targetID, userIDInt, sourceIDs)
+		if err != nil {
+			return fmt.Errorf("error merging items: %v", err)
+		}
+
+		item, err = getItem(ctx, tx, userIDInt, targetID)
+		return err
+	})
+	return item, moved, err
+}
+
+func uniqueIDs(ids []int32) []int32 {
+	seen := map[int32]bool{}
+	var unique []int32
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// DismissItemMerge stops two items from being suggested for merging.
+func DismissItemMerge(ctx context.Context, userID string, itemID, otherItemID int32) error {
+	if itemID > otherItemID {
+		itemID, otherItemID = otherItemID, itemID
+	}
+	return withUserItems(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if itemID == otherItemID {
+			return fmt.Errorf("%w: an item cannot be compared with itself", ErrInvalidMerge)
+		}
+		if _, err := getItem(ctx, tx, userIDInt, itemID); err != nil {
+			return err
+		}
+		if _, err := getItem(ctx, tx, userIDInt, otherItemID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+            INSERT INTO product_category_service.item_merge_dismissals (user_id, item_id, other_item_id)
+            VALUES ($1, $2, $3)
+            ON CONFLICT DO NOTHING`,
+			userIDInt, itemID, otherItemID)
+		if err != nil {
+			return fmt.Errorf("error dismissing suggestion: %v", err)
+		}
+		return nil
+	})
+}
+
+// PricePoint is one purchase of an item.
+type PricePoint struct {
+	pricing.Point
+	ProductID   int32
+	ProductName string
+	FileName    *string
+}
+
+// GetPriceHistory returns an item and its purchases between from and to
+// (exclusive; nil for unbounded), oldest first. A non-empty merchant keeps
+// only purchases from it, ignoring case.
+func GetPriceHistory(ctx context.Context, userID string, itemID int32, merchant string, from, to *time.Time) (*Item, []PricePoint, error) {
+	var item *Item
+	var points []PricePoint
+	err := withUserItems(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		var err error
+		if item, err = getItem(ctx, tx, userIDInt, itemID); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+            SELECT p.product_id, p.product_name, p.file_name, p.date_added, p.price::float8, p.quantity,
+                COALESCE(btrim(t.merchant_name), '')
+            FROM product_category_service.products p
+            LEFT JOIN product_category_service.receipt_texts t
+                ON t.user_id = p.user_id AND t.file_name = p.file_name
+            WHERE p.user_id = $1 AND p.item_id = $2
+              AND ($3 = '' OR lower(btrim(t.merchant_name)) = lower(btrim($3)))
+              AND ($4::timestamp IS NULL OR p.date_added >= $4)
+              AND ($5::timestamp IS NULL OR p.date_added < $5)
+            ORDER BY p.date_added, p.product_id`,
+			userIDInt, itemID, merchant, from, to)
+		if err != nil {
+			return fmt.Errorf("error loading price history: %v", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var point PricePoint
+			if err := rows.Scan(&point.ProductID, &point.ProductName, &point.FileName, &point.Date,
+				&point.UnitPrice, &point.Quantity, &point.Merchant); err != nil {
+				return fmt.Errorf("error scanning price: %v", err)
+			}
+			points = append(points, point)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, points, nil
+}
+
+// PriceInsight compares an item's latest price with the purchase before it,
+// counting each day's purchases once at their average unit price.
+type PriceInsight struct {
+	Item             Item
+	Merchant         string
+	LatestDate       time.Time
+	LatestPrice      float64
+	PreviousDate     time.Time
+	PreviousPrice    float64
+	PreviousMerchant string
+	ChangePercent    float64
+}
+
+// PriceInsightQuery selects the items GetPriceInsights compares.
+type PriceInsightQuery struct {
+	// Since keeps items whose latest purchase is on or after it.
+	Since      time.Time
+	CategoryID int32
+	// ByMerchant compares only purchases from the same merchant.
+	ByMerchant bool
+}
+
+// GetPriceInsights compares the latest price of every item bought since
+// q.Since with the previous purchase, largest change first.
+func GetPriceInsights(ctx context.Context, userID string, q PriceInsightQuery) ([]PriceInsight, error) {
+	var categoryID *int32
+	if q.CategoryID != 0 {
+		categoryID = &q.CategoryID
+	}
+	merchantKey := "''::text"
+	if q.ByMerchant {
+		merchantKey = "COALESCE(lower(btrim(t.merchant_name)), '')"
+	}
+
+	var insights []PriceInsight
+	err := withUserItems(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		rows, err := tx.Query(ctx, `
+            WITH daily AS (
+                SELECT p.item_id, p.date_added::date AS day, `+merchantKey+` AS merchant_key,
+                    MIN(COALESCE(btrim(t.merchant_name), '')) AS merchant,
+                    (SUM(p.quantity * p.price) / SUM(p.quantity))::float8 AS unit_price
+                FROM product_category_service.products p
+                LEFT JOIN product_category_service.receipt_texts t
+                    ON t.user_id = p.user_id AND t.file_name = p.file_name
+                WHERE p.user_id = $1 AND p.item_id IS NOT NULL AND p.quantity > 0 AND p.price > 0
+                  AND ($3::int IS NULL OR p.category_id IN (
+                    WITH RECURSIVE tree AS (
+                        SELECT category_id FROM product_category_service.categories
+                        WHERE user_id = $1 AND category_id = $3
+                        UNION
+                        SELECT c.category_id FROM product_category_service.categories c
+                        JOIN tree t ON c.parent_id = t.category_id OR c.merged_into = t.category_id
+                    )
+                    SELECT category_id FROM tree))
+                GROUP BY 1, 2, 3
+            ),
+            ranked AS (
+                SELECT d.*, ROW_NUMBER() OVER (PARTITION BY d.item_id, d.merchant_key ORDER BY d.day DESC) AS recency
+                FROM daily d
+            )
+            SELECT l.item_id, l.merchant, l.day, l.unit_price, prev.day, prev.unit_price, prev.merchant
+            FROM ranked l
+            JOIN ranked prev ON prev.item_id = l.item_id AND prev.merchant_key = l.merchant_key AND prev.recency = 2
+            WHERE l.recency = 1 AND l.day >= $2::date`,
+			userIDInt, q.Since, categoryID)
+		if err != nil {
+			return fmt.Errorf("error comparing prices: %v", err)
+		}
+		for rows.Next() {
+			var insight PriceInsight
+			if err := rows.Scan(&insight.Item.ItemID, &insight.Merchant, &insight.LatestDate, &insight.LatestPrice,
+				&insight.PreviousDate, &insight.PreviousPrice, &insight.PreviousMerchant); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning price change: %v", err)
+			}
+			if change := pricing.Change(insight.PreviousPrice, insight.LatestPrice); change != nil {
+				insight.ChangePercent = *change
+			}
+			insights = append(insights, insight)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error during row iteration: %v", err)
+		}
+
+		for i := range insights {
+			item, err := getItem(ctx, tx, userIDInt, insights[i].Item.ItemID)
+			if err != nil {
+				return err
+			}
+			insights[i].Item = *item
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(insights, func(i, j int) bool {
+		a, b := insights[i].ChangePercent, insights[j].ChangePercent
+		if a < 0 {
+			a = -a
+		}
+		if b < 0 {
+			b = -b
+		}
+		if a != b {
+			return a > b
+		}
+		return insights[i].Item.Name < insights[j].Item.Name
+	})
+	return insights, nil
+}
@@ -0,0 +1,500 @@
+-- Schema changes for product_category_service, applied in order on top of the
+-- existing tables, after the user service's migrations and before the
+-- balance service's.
+
+-- Product listing: keyset order by date and trigram matching for name search.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_products_user_date
+    ON product_category_service.products (user_id, date_added DESC, product_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_products_user_file
+    ON product_category_service.products (user_id, file_name);
+
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm
+    ON product_category_service.products USING gin (product_name gin_trgm_ops);
+
+-- Expense search. Receipts are full of abbreviations and OCR noise, so the
+-- 'simple' configuration is used: stemming English words does not help there,
+-- and trigram similarity covers misspellings.
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(product_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search
+    ON product_category_service.products USING gin (search_vector);
+
+-- Raw OCR output of each receipt, kept so it can be searched and re-parsed.
+CREATE TABLE IF NOT EXISTS product_category_service.receipt_texts (
+    user_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    merchant_name VARCHAR(255),
+    raw_text TEXT NOT NULL,
+    extracted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(merchant_name, '')), 'A') ||
+        setweight(to_tsvector('simple', raw_text), 'C')
+    ) STORED,
+    PRIMARY KEY (user_id, file_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_receipt_texts_search
+    ON product_category_service.receipt_texts USING gin (search_vector);
+
+CREATE INDEX IF NOT EXISTS idx_receipt_texts_merchant_trgm
+    ON product_category_service.receipt_texts USING gin (merchant_name gin_trgm_ops);
+
+-- Per-user category trees. Rows with a NULL user_id are the legacy global
+-- categories; each user's products are moved off them the first time the
+-- user's categories are seeded.
+ALTER TABLE product_category_service.categories
+    ADD COLUMN IF NOT EXISTS user_id INT,
+    ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES product_category_service.categories (category_id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS color VARCHAR(7),
+    ADD COLUMN IF NOT EXISTS icon VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS merged_into INT REFERENCES product_category_service.categories (category_id),
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_user_name
+    ON product_category_service.categories (user_id, lower(name))
+    WHERE user_id IS NOT NULL AND merged_into IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent
+    ON product_category_service.categories (parent_id);
+
+-- Old names of renamed or merged categories, normalised, so labels using them
+-- still resolve.
+CREATE TABLE IF NOT EXISTS product_category_service.category_aliases (
+    user_id INT NOT NULL,
+    alias VARCHAR(100) NOT NULL,
+    category_id INT NOT NULL REFERENCES product_category_service.categories (category_id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, alias)
+);
+
+-- The label extraction suggested, kept when it is mapped onto the taxonomy.
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS suggested_category VARCHAR(100);
+
+-- Who chose each product's category, so that rules never override a
+-- category the user picked by hand.
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS category_source VARCHAR(20);
+
+-- Auto-categorisation rules, evaluated in ascending priority.
+CREATE TABLE IF NOT EXISTS product_category_service.category_rules (
+    rule_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    priority INT NOT NULL DEFAULT 100,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    match_all BOOLEAN NOT NULL DEFAULT TRUE,
+    conditions JSONB NOT NULL,
+    set_category_id INT REFERENCES product_category_service.categories (category_id) ON DELETE SET NULL,
+    stop_processing BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_rules_user
+    ON product_category_service.category_rules (user_id, priority);
+
+-- Categories users picked by hand in place of the one a product had; the
+-- per-user classifier is trained on the latest one per product.
+CREATE TABLE IF NOT EXISTS product_category_service.category_corrections (
+    correction_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    product_id INT,
+    product_name VARCHAR(255) NOT NULL,
+    merchant_name VARCHAR(255),
+    from_category_id INT,
+    to_category_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_corrections_user
+    ON product_category_service.category_corrections (user_id, product_id, correction_id);
+
+-- Each user's trained classifier.
+CREATE TABLE IF NOT EXISTS product_category_service.classifier_models (
+    user_id INT PRIMARY KEY,
+    model JSONB NOT NULL,
+    trained_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Spending limits per period, overall (category_id NULL) or per category.
+CREATE TABLE IF NOT EXISTS product_category_service.budgets (
+    budget_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    category_id INT REFERENCES product_category_service.categories (category_id) ON DELETE CASCADE,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    period VARCHAR(10) NOT NULL,
+    period_days INT,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    rollover VARCHAR(10) NOT NULL DEFAULT 'none',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_user
+    ON product_category_service.budgets (user_id);
+
+-- Spend recorded against each budget period, kept current by the paths that
+-- write products. Statuses compute periods with no row without storing them.
+CREATE TABLE IF NOT EXISTS product_category_service.budget_periods (
+    budget_id INT NOT NULL REFERENCES product_category_service.budgets (budget_id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    spent NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (budget_id, period_start)
+);
+
+-- Products deleted outside the product service, such as with their receipt,
+-- take the recorded spend of the periods they fell in with them.
+CREATE OR REPLACE FUNCTION product_category_service.forget_deleted_spend() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM product_category_service.budget_periods bp
+    USING product_category_service.budgets b, deleted d
+    WHERE bp.budget_id = b.budget_id AND b.user_id = d.user_id
+      AND d.date_added::date >= bp.period_start AND d.date_added::date < bp.period_end;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_forget_spend ON product_category_service.products;
+CREATE TRIGGER products_forget_spend
+    AFTER DELETE ON product_category_service.products
+    REFERENCING OLD TABLE AS deleted
+    FOR EACH STATEMENT EXECUTE FUNCTION product_category_service.forget_deleted_spend();
+
+-- Canonical items: product names folded to a key (see pricing.ItemKey) so the
+-- same thing bought under slightly different names is tracked as one item.
+-- Merged items keep their key and point at the item they were merged into.
+CREATE TABLE IF NOT EXISTS product_category_service.canonical_items (
+    item_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    normalized_key VARCHAR(255) NOT NULL,
+    merged_into INT REFERENCES product_category_service.canonical_items (item_id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, normalized_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_canonical_items_key_trgm
+    ON product_category_service.canonical_items USING gin (normalized_key gin_trgm_ops);
+
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS item_id INT REFERENCES product_category_service.canonical_items (item_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_item
+    ON product_category_service.products (item_id, date_added);
+
+CREATE INDEX IF NOT EXISTS idx_products_unlinked
+    ON product_category_service.products (user_id) WHERE item_id IS NULL;
+
+-- Merge suggestions the user turned down, stored with item_id < other_item_id.
+CREATE TABLE IF NOT EXISTS product_category_service.item_merge_dismissals (
+    user_id INT NOT NULL,
+    item_id INT NOT NULL REFERENCES product_category_service.canonical_items (item_id) ON DELETE CASCADE,
+    other_item_id INT NOT NULL REFERENCES product_category_service.canonical_items (item_id) ON DELETE CASCADE,
+    dismissed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, other_item_id),
+    CHECK (item_id < other_item_id)
+);
+
+-- Free-form tags and notes on products, and the tags and notes rules add.
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS notes TEXT;
+
+ALTER TABLE product_category_service.category_rules
+    ADD COLUMN IF NOT EXISTS add_tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS set_notes TEXT;
+
+-- Typed custom fields users define once and fill in on products and receipts.
+-- Values are stored by key in JSONB; see package fields.
+CREATE TABLE IF NOT EXISTS product_category_service.custom_fields (
+    field_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    key VARCHAR(40) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    field_type VARCHAR(10) NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, key)
+);
+
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_products_custom_fields
+    ON product_category_service.products USING gin (custom_fields jsonb_path_ops);
+
+-- Tags, notes and custom field values of whole receipts. Products inherit the
+-- receipt's field values unless they set their own.
+CREATE TABLE IF NOT EXISTS product_category_service.receipt_annotations (
+    user_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    notes TEXT,
+    custom_fields JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, file_name)
+);
+
+-- Splits of a product's cost across categories and people, as shares of its
+-- line total. Parts with a participant belong to someone else and are left
+-- out of the user's spend.
+CREATE TABLE IF NOT EXISTS product_category_service.product_splits (
+    split_id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES product_category_service.products (product_id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    category_id INT REFERENCES product_category_service.categories (category_id),
+    share NUMERIC(12, 10) NOT NULL CHECK (share > 0 AND share <= 1),
+    participant VARCHAR(100),
+    participant_user_id INT,
+    note TEXT,
+    UNIQUE (product_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_splits_category
+    ON product_category_service.product_splits (category_id);
+
+-- Expenses that repeat on a schedule, such as rent and subscriptions. The
+-- scheduler turns each occurrence into a product once it falls due;
+-- occurrences counts how many it has handled and next_due is NULL once the
+-- schedule has ended.
+CREATE TABLE IF NOT EXISTS product_category_service.recurring_expenses (
+    recurring_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    merchant VARCHAR(255),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    category_id INT REFERENCES product_category_service.categories (category_id) ON DELETE SET NULL,
+    frequency VARCHAR(10) NOT NULL,
+    interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    notes TEXT,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    occurrences INT NOT NULL DEFAULT 0,
+    next_due DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_expenses_user
+    ON product_category_service.recurring_expenses (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_expenses_due
+    ON product_category_service.recurring_expenses (next_due) WHERE NOT paused;
+
+-- One row per occurrence handled, so none is generated twice. The product
+-- is NULL once the user deletes it; the occurrence is not generated again.
+CREATE TABLE IF NOT EXISTS product_category_service.recurring_occurrences (
+    recurring_id INT NOT NULL REFERENCES product_category_service.recurring_expenses (recurring_id) ON DELETE CASCADE,
+    due_date DATE NOT NULL,
+    product_id INT REFERENCES product_category_service.products (product_id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (recurring_id, due_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_occurrences_product
+    ON product_category_service.recurring_occurrences (product_id);
+
+-- Suggested subscriptions the user turned down, by folded merchant name and
+-- amount.
+CREATE TABLE IF NOT EXISTS product_category_service.subscription_dismissals (
+    user_id INT NOT NULL,
+    merchant_key VARCHAR(255) NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL,
+    dismissed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, merchant_key, amount)
+);
+
+-- Money given back for purchases: returned items, partial refunds and whole
+-- reversals of a product or a receipt. The products themselves are left as
+-- bought. balance_id is the balance account the refund was credited to, if
+-- any; product_id is NULL for receipt refunds.
+CREATE TABLE IF NOT EXISTS product_category_service.refunds (
+    refund_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    product_id INT REFERENCES product_category_service.products (product_id) ON DELETE SET NULL,
+    file_name VARCHAR(255),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    balance_id INT,
+    reason TEXT,
+    refund_date DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_user_date
+    ON product_category_service.refunds (user_id, refund_date);
+
+-- How a refund is spread over the products it covers. Aggregates count each
+-- row as negative spend under its product's category. A refunded product
+-- cannot be deleted until its refunds are: the refund's journal entry and
+-- the balance account it credited would otherwise be left standing.
+CREATE TABLE IF NOT EXISTS product_category_service.refund_items (
+    refund_id INT NOT NULL REFERENCES product_category_service.refunds (refund_id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES product_category_service.products (product_id) ON DELETE RESTRICT,
+    quantity INT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    PRIMARY KEY (refund_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_items_product
+    ON product_category_service.refund_items (product_id);
+
+-- category_id is the product's category when the refund was made. The
+-- refund's journal entry takes the item back off that category's expenses,
+-- and the ledger check compares against it.
+ALTER TABLE product_category_service.refund_items
+    ADD COLUMN IF NOT EXISTS category_id INT;
+
+UPDATE product_category_service.refund_items ri
+SET category_id = p.category_id
+FROM product_category_service.products p
+WHERE p.product_id = ri.product_id AND ri.category_id IS NULL AND p.category_id IS NOT NULL;
+
+ALTER TABLE product_category_service.refund_items
+    DROP CONSTRAINT IF EXISTS refund_items_product_id_fkey,
+    ADD CONSTRAINT refund_items_product_id_fkey FOREIGN KEY (product_id)
+        REFERENCES product_category_service.products (product_id) ON DELETE RESTRICT;
+
+-- A product's price is in currency. exchange_rate is what one unit of it
+-- was worth in the user's base currency on the product's date; aggregates
+-- multiply by it. It is recorded by a trigger the balance service's
+-- migrations define, since products have several writers.
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(20, 10);
+
+-- Everything recorded before currencies were is in USD, every user's base.
+-- Rows since left without a rate are in another currency and stay so.
+UPDATE product_category_service.products p SET exchange_rate = 1
+WHERE p.exchange_rate IS NULL AND p.currency = COALESCE(
+    (SELECT u.base_currency FROM user_service.users u WHERE u.user_id = p.user_id), 'USD');
+
+-- Refunds are in the currency of the products they cover.
+ALTER TABLE product_category_service.refunds
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- The balance account that paid for a product, when it is known, as it is
+-- for products imported from an account's statement; NULL products are
+-- paid from the funding account. The products_journal trigger below posts
+-- them from it.
+ALTER TABLE product_category_service.products
+    ADD COLUMN IF NOT EXISTS account_id INT;
+
+-- Saved ways of reading one bank's CSV exports. Columns are header names,
+-- or 1-based positions for files without a header; a mapping has either an
+-- amount column or debit and credit columns.
+CREATE TABLE IF NOT EXISTS product_category_service.import_mappings (
+    mapping_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    delimiter VARCHAR(1) NOT NULL DEFAULT ',',
+    has_header BOOLEAN NOT NULL DEFAULT TRUE,
+    skip_lines INT NOT NULL DEFAULT 0 CHECK (skip_lines BETWEEN 0 AND 100),
+    date_column VARCHAR(100) NOT NULL,
+    amount_column VARCHAR(100),
+    debit_column VARCHAR(100),
+    credit_column VARCHAR(100),
+    payee_column VARCHAR(100),
+    memo_column VARCHAR(100),
+    id_column VARCHAR(100),
+    date_format VARCHAR(30),
+    negate_amounts BOOLEAN NOT NULL DEFAULT FALSE,
+    decimal_comma BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- A statement file imported into a balance account. Batches are previewed
+-- first, then committed, which records their new rows, and can be undone,
+-- which deletes what they recorded.
+CREATE TABLE IF NOT EXISTS product_category_service.import_batches (
+    batch_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    account_id INT NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'ofx', 'qif')),
+    file_name VARCHAR(255),
+    mapping_id INT REFERENCES product_category_service.import_mappings (mapping_id) ON DELETE SET NULL,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'preview' CHECK (status IN ('preview', 'committed', 'undone')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    committed_at TIMESTAMP,
+    undone_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_batches_user
+    ON product_category_service.import_batches (user_id, created_at);
+
+-- The transactions of a batch, in file order. Money out is recorded as a
+-- product and money in as an income; duplicate_type and duplicate_id name
+-- the entry a duplicate matched, and note says why a row is not imported.
+CREATE TABLE IF NOT EXISTS product_category_service.import_rows (
+    batch_id INT NOT NULL REFERENCES product_category_service.import_batches (batch_id) ON DELETE CASCADE,
+    row_number INT NOT NULL,
+    line INT NOT NULL,
+    transaction_date DATE NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL,
+    payee VARCHAR(255),
+    memo TEXT,
+    external_id VARCHAR(255),
+    status VARCHAR(12) NOT NULL
+        CHECK (status IN ('new', 'duplicate', 'unsupported', 'imported', 'skipped')),
+    duplicate_type VARCHAR(10) CHECK (duplicate_type IN ('product', 'income', 'import')),
+    duplicate_id INT,
+    note TEXT,
+    product_id INT REFERENCES product_category_service.products (product_id) ON DELETE SET NULL,
+    income_id INT,
+    PRIMARY KEY (batch_id, row_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_rows_external
+    ON product_category_service.import_rows (external_id) WHERE external_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_import_rows_product
+    ON product_category_service.import_rows (product_id);
+
+-- Products are written by several services, so the expenses they record are
+-- posted to the journal here rather than by each writer. journal_product is
+-- the balance service's, defined by its migrations, which are applied after
+-- these and before products are written again. The trigger used to be
+-- defined with the journal; that version is dropped.
+CREATE OR REPLACE FUNCTION product_category_service.trigger_journal_product() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM account_income_service.journal_product(OLD.user_id, OLD.product_id, OLD.product_name,
+            OLD.category_id, 0, OLD.currency, CURRENT_TIMESTAMP, OLD.account_id);
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.price IS NOT DISTINCT FROM OLD.price
+            AND NEW.quantity IS NOT DISTINCT FROM OLD.quantity
+            AND NEW.category_id IS NOT DISTINCT FROM OLD.category_id
+            AND NEW.currency IS NOT DISTINCT FROM OLD.currency
+            AND NEW.account_id IS NOT DISTINCT FROM OLD.account_id THEN
+        RETURN NULL;
+    END IF;
+    PERFORM account_income_service.journal_product(NEW.user_id, NEW.product_id, NEW.product_name,
+        NEW.category_id, ROUND(COALESCE(NEW.quantity, 0) * NEW.price, account_income_service.currency_exponent(NEW.currency)),
+        NEW.currency, COALESCE(NEW.date_added, CURRENT_TIMESTAMP), NEW.account_id);
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS products_journal ON product_category_service.products;
+CREATE TRIGGER products_journal
+    AFTER INSERT OR UPDATE OR DELETE ON product_category_service.products
+    FOR EACH ROW EXECUTE FUNCTION product_category_service.trigger_journal_product();
+
+DROP FUNCTION IF EXISTS account_income_service.trigger_journal_product();
@@ -0,0 +1,591 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/recurrence"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrRecurringNotFound = errors.New("recurring expense not found")
+	ErrInvalidRecurring  = errors.New("invalid recurring expense")
+)
+
+// RecurringTag is added to every product recorded from a recurring expense.
+const RecurringTag = "recurring"
+
+// maxCatchUp bounds how many occurrences are recorded for one expense at a
+// time; the scheduler picks up the rest on its next run.
+const maxCatchUp = 100
+
+// maxBackfillDays is how far back occurrences are recorded, whether for an
+// expense that starts in the past or after the scheduler has not run for a
+// while. Older ones are skipped rather than flooding the user's history.
+const maxBackfillDays = 90
+
+// RecurringExpense is an expense that repeats on a schedule.
+type RecurringExpense struct {
+	RecurringID  int32
+	UserID       int32
+	Name         string
+	Merchant     *string
+	Amount       float64
+	CategoryID   *int32
+	CategoryName *string
+	Schedule     recurrence.Schedule
+	Notes        *string
+	Paused       bool
+	// Occurrences counts the occurrences handled so far; the next one is
+	// Schedule.Occurrence(Occurrences). NextDue is nil once the schedule
+	// has ended.
+	Occurrences int
+	NextDue     *time.Time
+	LastDue     *time.Time
+	CreatedAt   time.Time
+}
+
+const recurringColumns = `r.recurring_id, r.user_id, r.name, r.merchant, r.amount::float8, r.category_id,
+        c.name, r.frequency, r.interval_count, r.start_date, r.end_date, r.notes, r.paused,
+        r.occurrences, r.next_due,
+        (SELECT MAX(o.due_date) FROM product_category_service.recurring_occurrences o
+         WHERE o.recurring_id = r.recurring_id),
+        r.created_at`
+
+const recurringFrom = `
+        FROM product_category_service.recurring_expenses r
+        LEFT JOIN product_category_service.categories c ON c.category_id = r.category_id`
+
+func scanRecurring(row pgx.Row) (RecurringExpense, error) {
+	var r RecurringExpense
+	err := row.Scan(&r.RecurringID, &r.UserID, &r.Name, &r.Merchant, &r.Amount, &r.CategoryID,
+		&r.CategoryName, &r.Schedule.Frequency, &r.Schedule.Interval, &r.Schedule.Start,
+		&r.Schedule.End, &r.Notes, &r.Paused, &r.Occurrences, &r.NextDue, &r.LastDue, &r.CreatedAt)
+	return r, err
+}
+
+// getRecurring fetches one of the user's recurring expenses, locking it when
+// asked.
+func getRecurring(ctx context.Context, tx pgx.Tx, userID int32, recurringID int32, lock bool) (*RecurringExpense, error) {
+	query := `
+        SELECT ` + recurringColumns + recurringFrom + `
+        WHERE r.user_id = $1 AND r.recurring_id = $2`
+	if lock {
+		query += " FOR UPDATE OF r"
+	}
+	r, err := scanRecurring(tx.QueryRow(ctx, query, userID, recurringID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrRecurringNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching recurring expense: %v", err)
+	}
+	return &r, nil
+}
+
+// ListRecurringExpenses returns the user's recurring expenses, next due
+// first and ended ones last.
+func ListRecurringExpenses(ctx context.Context, userID string) ([]RecurringExpense, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT `+recurringColumns+recurringFrom+`
+        WHERE r.user_id = $1
+        ORDER BY r.next_due NULLS LAST, lower(r.name), r.recurring_id`,
+		userIDInt)
+	if err != nil {
+		return nil, fmt.Errorf("error listing recurring expenses: %v", err)
+	}
+	defer rows.Close()
+
+	var list []RecurringExpense
+	for rows.Next() {
+		r, err := scanRecurring(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning recurring expense: %v", err)
+		}
+		list = append(list, r)
+	}
+	return list, rows.Err()
+}
+
+// checkRecurring validates a recurring expense before it is saved.
+func checkRecurring(ctx context.Context, tx pgx.Tx, userID int32, r *RecurringExpense) error {
+	if r.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidRecurring)
+	}
+	if err := r.Schedule.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurring, err)
+	}
+	r.Schedule.Start = recurrence.Day(r.Schedule.Start)
+	if r.Schedule.End != nil {
+		end := recurrence.Day(*r.Schedule.End)
+		r.Schedule.End = &end
+	}
+	if r.CategoryID != nil {
+		return categoryExists(ctx, tx, userID, *r.CategoryID)
+	}
+	return nil
+}
+
+// position returns how many of schedule's occurrences fall on or before
+// after, which is where the schedule continues from.
+func position(schedule recurrence.Schedule, after *time.Time) int {
+	if after == nil {
+		return 0
+	}
+	n := 0
+	for {
+		date, ok := schedule.Occurrence(n)
+		if !ok || date.After(*after) {
+			return n
+		}
+		n++
+	}
+}
+
+// nextDue is the date of the n-th occurrence, or nil when the schedule has
+// ended by then.
+func nextDue(schedule recurrence.Schedule, n int) *time.Time {
+	date, ok := schedule.Occurrence(n)
+	if !ok {
+		return nil
+	}
+	return &date
+}
+
+// recordOccurrences records the expense's occurrences due on or before
+// through, back to maxBackfillDays before it, as products and moves the
+// schedule on past them. It returns how many products were recorded.
+func recordOccurrences(ctx context.Context, tx pgx.Tx, r *RecurringExpense, through time.Time) (int, error) {
+	if r.Paused {
+		return 0, nil
+	}
+	cutoff := recurrence.Day(through).AddDate(0, 0, -maxBackfillDays-1)
+	skipped := 0
+	if n := position(r.Schedule, &cutoff); n > r.Occurrences {
+		skipped = n - r.Occurrences
+	}
+	due := r.Schedule.Due(r.Occurrences+skipped, through, maxCatchUp)
+	if len(due) == 0 && skipped == 0 {
+		return 0, nil
+	}
+
+	userID := strconv.Itoa(int(r.UserID))
+	recorded := 0
+	for _, date := range due {
+		tag, err := tx.Exec(ctx, `
+            INSERT INTO product_category_service.recurring_occurrences (recurring_id, due_date)
+            VALUES ($1, $2)
+            ON CONFLICT DO NOTHING`,
+			r.RecurringID, date)
+		if err != nil {
+			return 0, fmt.Errorf("error recording occurrence: %v", err)
+		}
+		// Already recorded under an earlier version of the schedule.
+		if tag.RowsAffected() == 0 {
+			continue
+		}
+
+		product := Product{
+			ProductName: r.Name,
+			Quantity:    1,
+			// Recurring expenses are kept in the default currency.
+			Price:       money.FromFloat(r.Amount, money.DefaultCurrency),
+			Description: r.Merchant,
+			DateAdded:   date,
+			Tags:        []string{RecurringTag},
+			Notes:       r.Notes,
+		}
+		if r.CategoryID != nil {
+			product.CategoryID = *r.CategoryID
+			source := CategorySourceManual
+			product.CategorySource = &source
+		}
+		saved, err := insertProduct(ctx, tx, userID, product)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(ctx, `
+            UPDATE product_category_service.recurring_occurrences
+            SET product_id = $1 WHERE recurring_id = $2 AND due_date = $3`,
+			saved.ProductID, r.RecurringID, date); err != nil {
+			return 0, fmt.Errorf("error linking occurrence: %v", err)
+		}
+		recorded++
+	}
+
+	r.Occurrences += skipped + len(due)
+	r.NextDue = nextDue(r.Schedule, r.Occurrences)
+	if _, err := tx.Exec(ctx, `
+        UPDATE product_category_service.recurring_expenses
+        SET occurrences = $1, next_due = $2
+        WHERE recurring_id = $3`,
+		r.Occurrences, r.NextDue, r.RecurringID); err != nil {
+		return 0, fmt.Errorf("error advancing recurring expense: %v", err)
+	}
+	return recorded, nil
+}
+
+// CreateRecurringExpense saves a new recurring expense for the user and
+// records the occurrences already due, back to maxBackfillDays ago.
+func CreateRecurringExpense(ctx context.Context, userID string, r RecurringExpense) (*RecurringExpense, error) {
+	var created *RecurringExpense
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if err := checkRecurring(ctx, tx, userIDInt, &r); err != nil {
+			return err
+		}
+
+		var recurringID int32
+		err := tx.QueryRow(ctx, `
+            INSERT INTO product_category_service.recurring_expenses
+                (user_id, name, merchant, amount, category_id, frequency, interval_count,
+                 start_date, end_date, notes, paused, next_due)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+            RETURNING recurring_id`,
+			userIDInt, r.Name, r.Merchant, r.Amount, r.CategoryID, r.Schedule.Frequency, r.Schedule.Interval,
+			r.Schedule.Start, r.Schedule.End, r.Notes, r.Paused, nextDue(r.Schedule, 0)).Scan(&recurringID)
+		if err != nil {
+			return fmt.Errorf("error creating recurring expense: %v", err)
+		}
+
+		saved, err := getRecurring(ctx, tx, userIDInt, recurringID, true)
+		if err != nil {
+			return err
+		}
+		if _, err := recordOccurrences(ctx, tx, saved, recurrence.Day(time.Now().UTC())); err != nil {
+			return err
+		}
+		created, err = getRecurring(ctx, tx, userIDInt, recurringID, false)
+		return err
+	})
+	return created, err
+}
+
+// UpdateRecurringExpense replaces one of the user's recurring expenses.
+// Occurrences already recorded are kept; the new schedule continues after
+// the latest of them, or from today when a paused expense is resumed.
+func UpdateRecurringExpense(ctx context.Context, userID string, r RecurringExpense) (*RecurringExpense, error) {
+	var updated *RecurringExpense
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		before, err := getRecurring(ctx, tx, userIDInt, r.RecurringID, true)
+		if err != nil {
+			return err
+		}
+		if err := checkRecurring(ctx, tx, userIDInt, &r); err != nil {
+			return err
+		}
+
+		after := before.LastDue
+		today := recurrence.Day(time.Now().UTC())
+		if before.Paused && !r.Paused {
+			yesterday := today.AddDate(0, 0, -1)
+			if after == nil || after.Before(yesterday) {
+				after = &yesterday
+			}
+		}
+		occurrences := position(r.Schedule, after)
+
+		_, err = tx.Exec(ctx, `
+            UPDATE product_category_service.recurring_expenses
+            SET name = $1, merchant = $2, amount = $3, category_id = $4, frequency = $5,
+                interval_count = $6, start_date = $7, end_date = $8, notes = $9, paused = $10,
+                occurrences = $11, next_due = $12
+            WHERE user_id = $13 AND recurring_id = $14`,
+			r.Name, r.Merchant, r.Amount, r.CategoryID, r.Schedule.Frequency, r.Schedule.Interval,
+			r.Schedule.Start, r.Schedule.End, r.Notes, r.Paused, occurrences,
+			nextDue(r.Schedule, occurrences), userIDInt, r.RecurringID)
+		if err != nil {
+			return fmt.Errorf("error updating recurring expense: %v", err)
+		}
+
+		saved, err := getRecurring(ctx, tx, userIDInt, r.RecurringID, false)
+		if err != nil {
+			return err
+		}
+		if _, err := recordOccurrences(ctx, tx, saved, today); err != nil {
+			return err
+		}
+		updated, err = getRecurring(ctx, tx, userIDInt, r.RecurringID, false)
+		return err
+	})
+	return updated, err
+}
+
+// DeleteRecurringExpense removes one of the user's recurring expenses. The
+// products it recorded are kept unless deleteProducts is set. It returns
+// how many products were deleted.
+func DeleteRecurringExpense(ctx context.Context, userID string, recurringID int32, deleteProducts bool) (int64, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := getRecurring(ctx, tx, userIDInt, recurringID, true); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	if deleteProducts {
+		tag, err := tx.Exec(ctx, `
+            DELETE FROM product_category_service.products
+            WHERE user_id = $1 AND product_id IN (
+                SELECT product_id FROM product_category_service.recurring_occurrences
+                WHERE recurring_id = $2)`,
+			userIDInt, recurringID)
+		if err = refundedError(err); err == ErrProductRefunded {
+			return 0, err
+		}
+		if err != nil {
+			return 0, fmt.Errorf("error deleting recorded products: %v", err)
+		}
+		deleted = tag.RowsAffected()
+		if deleted > 0 {
+			if err := RecomputeBudgetSpend(ctx, tx, userIDInt); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+        DELETE FROM product_category_service.recurring_expenses
+        WHERE user_id = $1 AND recurring_id = $2`,
+		userIDInt, recurringID); err != nil {
+		return 0, fmt.Errorf("error deleting recurring expense: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing delete: %v", err)
+	}
+	return deleted, nil
+}
+
+// UpcomingExpense is one future occurrence of a recurring expense.
+type UpcomingExpense struct {
+	RecurringID int32
+	Name        string
+	Amount      float64
+	Date        time.Time
+}
+
+// GetUpcomingExpenses lists the occurrences of the user's active recurring
+// expenses due on or before through, soonest first.
+func GetUpcomingExpenses(ctx context.Context, userID string, through time.Time) ([]UpcomingExpense, error) {
+	list, err := ListRecurringExpenses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var upcoming []UpcomingExpense
+	for _, r := range list {
+		if r.Paused || r.NextDue == nil {
+			continue
+		}
+		for _, date := range r.Schedule.Due(r.Occurrences, through, 400) {
+			upcoming = append(upcoming, UpcomingExpense{
+				RecurringID: r.RecurringID,
+				Name:        r.Name,
+				Amount:      r.Amount,
+				Date:        date,
+			})
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].Date.Before(upcoming[j].Date) })
+	return upcoming, nil
+}
+
+// RecordDueRecurring records every occurrence of every user's recurring
+// expenses due on or before now. Each expense is handled in its own
+// transaction and locked while it is, so several service instances can
+// run it at once. It returns how many products were recorded.
+func RecordDueRecurring(ctx context.Context, now time.Time) (int, error) {
+	today := recurrence.Day(now)
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT recurring_id, user_id FROM product_category_service.recurring_expenses
+        WHERE NOT paused AND next_due <= $1
+        ORDER BY next_due
+        LIMIT 500`,
+		today)
+	if err != nil {
+		return 0, fmt.Errorf("error finding due recurring expenses: %v", err)
+	}
+	type due struct{ recurringID, userID int32 }
+	var pending []due
+	for rows.Next() {
+		var d due
+		if err := rows.Scan(&d.recurringID, &d.userID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("error scanning due recurring expense: %v", err)
+		}
+		pending = append(pending, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	recorded := 0
+	for _, d := range pending {
+		n, err := recordDue(ctx, d.userID, d.recurringID, today)
+		if err != nil {
+			log.Printf("Recording recurring expense %d failed: %v", d.recurringID, err)
+			continue
+		}
+		recorded += n
+	}
+	return recorded, nil
+}
+
+func recordDue(ctx context.Context, userID, recurringID int32, today time.Time) (int, error) {
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	r, err := scanRecurring(tx.QueryRow(ctx, `
+        SELECT `+recurringColumns+recurringFrom+`
+        WHERE r.user_id = $1 AND r.recurring_id = $2
+        FOR UPDATE OF r SKIP LOCKED`,
+		userID, recurringID))
+	if err == pgx.ErrNoRows {
+		// Deleted, or another instance is recording it.
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error fetching recurring expense: %v", err)
+	}
+	n, err := recordOccurrences(ctx, tx, &r, today)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing occurrences: %v", err)
+	}
+	return n, nil
+}
+
+// DetectSubscriptions looks through the user's purchases since since for
+// charges that recur, leaving out any the user dismissed or already tracks
+// as a recurring expense. Receipts count as one charge from their
+// merchant; products entered without one count under their own name.
+func DetectSubscriptions(ctx context.Context, userID string, since, now time.Time) ([]recurrence.Candidate, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT COALESCE(NULLIF(btrim(t.merchant_name), ''), p.product_name),
+            SUM(p.quantity * p.price * account_income_service.recorded_rate(p.exchange_rate, p.currency, p.user_id, p.date_added::date))::float8, MIN(p.date_added)
+        FROM product_category_service.products p
+        LEFT JOIN product_category_service.receipt_texts t
+            ON t.user_id = p.user_id AND t.file_name = p.file_name
+        WHERE p.user_id = $1 AND p.date_added >= $2
+            AND NOT EXISTS (
+                SELECT 1 FROM product_category_service.recurring_occurrences o
+                WHERE o.product_id = p.product_id)
+        GROUP BY CASE WHEN NULLIF(btrim(t.merchant_name), '') IS NULL
+                THEN 'product:' || p.product_id ELSE 'file:' || p.file_name END, 1`,
+		userIDInt, since)
+	if err != nil {
+		return nil, fmt.Errorf("error loading charges: %v", err)
+	}
+	var charges []recurrence.Charge
+	for rows.Next() {
+		var c recurrence.Charge
+		if err := rows.Scan(&c.Merchant, &c.Amount, &c.Date); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning charge: %v", err)
+		}
+		charges = append(charges, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	// Charges already tracked or dismissed, by folded merchant name.
+	known := map[string][]float64{}
+	rows, err = sharedDB.GetDB().Query(ctx, `
+        SELECT COALESCE(NULLIF(btrim(merchant), ''), name), amount::float8
+        FROM product_category_service.recurring_expenses WHERE user_id = $1
+        UNION ALL
+        SELECT merchant_key, amount::float8
+        FROM product_category_service.subscription_dismissals WHERE user_id = $1`,
+		userIDInt)
+	if err != nil {
+		return nil, fmt.Errorf("error loading known subscriptions: %v", err)
+	}
+	for rows.Next() {
+		var merchant string
+		var amount float64
+		if err := rows.Scan(&merchant, &amount); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning known subscription: %v", err)
+		}
+		key := recurrence.MerchantKey(merchant)
+		known[key] = append(known[key], amount)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	var candidates []recurrence.Candidate
+	for _, c := range recurrence.Detect(charges, now) {
+		if !knownCharge(known[c.Key], c.Amount) {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+func knownCharge(amounts []float64, amount float64) bool {
+	for _, a := range amounts {
+		if math.Abs(a-amount) <= a*recurrence.AmountTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// DismissSubscription stops charges from merchant for about amount being
+// suggested as a subscription.
+func DismissSubscription(ctx context.Context, userID string, merchant string, amount float64) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+	key := recurrence.MerchantKey(merchant)
+	if key == "" || amount <= 0 {
+		return fmt.Errorf("%w: a merchant and a positive amount are required", ErrInvalidRecurring)
+	}
+
+	_, err = sharedDB.GetDB().Exec(ctx, `
+        INSERT INTO product_category_service.subscription_dismissals (user_id, merchant_key, amount)
+        VALUES ($1, $2, round($3::numeric, 2))
+        ON CONFLICT DO NOTHING`,
+		userIDInt, key, amount)
+	if err != nil {
+		return fmt.Errorf("error dismissing subscription: %v", err)
+	}
+	return nil
+}
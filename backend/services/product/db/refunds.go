@@ -0,0 +1,590 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/budgeting"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/splitting"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/fx"
+	"github.com/Aneesh-Hegde/expenseManager/shared/ledger"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrRefundNotFound  = errors.New("refund not found")
+	ErrInvalidRefund   = errors.New("invalid refund")
+	ErrBalanceNotFound = errors.New("balance account not found")
+	ErrProductRefunded = errors.New("refunded products cannot be deleted, cost less than was refunded or change currency until their refunds are deleted")
+)
+
+// foreignKeyViolation is the SQLSTATE of a foreign key violation, raised
+// when a product with refund items is deleted.
+const foreignKeyViolation = "23503"
+
+// refundedError turns the violation deleting a refunded product raises into
+// ErrProductRefunded.
+func refundedError(err error) error {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) && pgErr.SQLState() == foreignKeyViolation {
+		return ErrProductRefunded
+	}
+	return err
+}
+
+// checkRefundedUpdate locks a product about to be updated to product and
+// returns ErrProductRefunded when its refunds would no longer fit it: when
+// they returned more units or money than it would then have, or it would
+// change currency.
+func checkRefundedUpdate(ctx context.Context, tx pgx.Tx, userID int32, product Product) error {
+	var currency string
+	var refunded bool
+	var quantity int32
+	var amount float64
+	err := tx.QueryRow(ctx, `
+        SELECT p.currency,
+            EXISTS (SELECT 1 FROM product_category_service.refund_items ri WHERE ri.product_id = p.product_id),
+            (SELECT COALESCE(SUM(ri.quantity), 0) FROM product_category_service.refund_items ri
+             WHERE ri.product_id = p.product_id)::int,
+            (SELECT COALESCE(SUM(ri.amount), 0) FROM product_category_service.refund_items ri
+             WHERE ri.product_id = p.product_id)::float8
+        FROM product_category_service.products p
+        WHERE p.user_id = $1 AND p.product_id = $2
+        FOR UPDATE OF p`,
+		userID, product.ProductID).Scan(&currency, &refunded, &quantity, &amount)
+	if err == pgx.ErrNoRows {
+		return ErrProductNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error checking product refunds: %v", err)
+	}
+	if !refunded {
+		return nil
+	}
+	if money.Normalize(product.Price.Currency) != currency {
+		return ErrProductRefunded
+	}
+	total := product.Price.Mul(float64(product.Quantity))
+	if product.Quantity < quantity || total.Minor < money.FromFloat(amount, currency).Minor {
+		return ErrProductRefunded
+	}
+	return nil
+}
+
+// Refund is money given back for one product or a whole receipt.
+type Refund struct {
+	RefundID int32
+	UserID   int32
+	// ProductID is nil for receipt refunds.
+	ProductID *int32
+	FileName  *string
+	// Amount is in the currency of the products refunded.
+	Amount money.Money
+	// BaseAmount is Amount in the user's base currency, BaseCurrency, at
+	// the rates recorded on the products.
+	BaseAmount   float64
+	BaseCurrency string
+	BalanceID    *int32
+	Reason       *string
+	Date         time.Time
+	Items        []RefundItem
+	CreatedAt    time.Time
+}
+
+// RefundItem is the part of a refund counted against one product.
+// CategoryID is the product's category when the refund was made, which the
+// refund's journal entry takes the item back off.
+type RefundItem struct {
+	ProductID    int32
+	ProductName  string
+	CategoryID   int32
+	CategoryName string
+	// Quantity is how many units were returned; 0 when the money came back
+	// without them.
+	Quantity int32
+	Amount   money.Money
+}
+
+// RefundRequest asks for a refund of a product, or of every product on the
+// receipt FileName when ProductID is 0. With neither Quantity nor Amount
+// set, everything not yet refunded is reversed. Amount is rounded to the
+// products' currency, and must name no other.
+type RefundRequest struct {
+	ProductID int32
+	FileName  string
+	Quantity  int32
+	Amount    money.Amount
+	BalanceID int32
+	Reason    *string
+	Date      time.Time
+}
+
+// RefundQuery selects refunds to list. To is exclusive.
+type RefundQuery struct {
+	ProductID int32
+	FileName  string
+	From      *time.Time
+	To        *time.Time
+}
+
+// refundable is what is left to refund of one product, in the minor units
+// of its currency.
+type refundable struct {
+	productID int32
+	price     money.Money
+	dateAdded time.Time
+	quantity  int32
+	left      int64
+}
+
+// loadRefundable locks the products a refund covers and works out what is
+// left of each after earlier refunds.
+func loadRefundable(ctx context.Context, tx pgx.Tx, userID int32, req RefundRequest) ([]refundable, *string, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT p.product_id, p.file_name, p.currency, p.price::float8, p.date_added,
+            p.quantity - (SELECT COALESCE(SUM(ri.quantity), 0) FROM product_category_service.refund_items ri
+                          WHERE ri.product_id = p.product_id)::int,
+            (p.quantity * p.price - (SELECT COALESCE(SUM(ri.amount), 0) FROM product_category_service.refund_items ri
+                                     WHERE ri.product_id = p.product_id))::float8
+        FROM product_category_service.products p
+        WHERE p.user_id = $1 AND (p.product_id = $2 OR ($2 = 0 AND p.file_name = $3))
+        ORDER BY p.product_id
+        FOR UPDATE OF p`,
+		userID, req.ProductID, req.FileName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading refunded products: %v", err)
+	}
+	defer rows.Close()
+
+	var products []refundable
+	var fileName *string
+	for rows.Next() {
+		var p refundable
+		var currency string
+		var price, left float64
+		if err := rows.Scan(&p.productID, &fileName, &currency, &price, &p.dateAdded, &p.quantity, &left); err != nil {
+			return nil, nil, fmt.Errorf("error scanning refunded product: %v", err)
+		}
+		p.price = money.FromFloat(price, currency)
+		p.left = money.FromFloat(left, currency).Minor
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return products, fileName, nil
+}
+
+// planRefund spreads a refund of amount, in the products' currency, over
+// the products it covers. A product refund defaults to the price of the
+// units returned; a partial receipt refund is shared out in proportion to
+// what is left of each product.
+func planRefund(products []refundable, req RefundRequest, amount money.Money) ([]RefundItem, error) {
+	if req.Quantity < 0 || amount.Minor < 0 {
+		return nil, fmt.Errorf("%w: quantity and amount must not be negative", ErrInvalidRefund)
+	}
+	reverse := req.Quantity == 0 && amount.IsZero()
+	currency := amount.Currency
+	of := func(minor int64) money.Money { return money.New(minor, currency) }
+
+	if req.ProductID != 0 {
+		p := products[0]
+		if req.Quantity > p.quantity {
+			return nil, fmt.Errorf("%w: only %d units are left to return", ErrInvalidRefund, p.quantity)
+		}
+		item := RefundItem{ProductID: p.productID, Quantity: req.Quantity}
+		minor := amount.Minor
+		switch {
+		case reverse:
+			item.Quantity, minor = p.quantity, p.left
+		case minor == 0:
+			minor = p.price.Mul(float64(req.Quantity)).Minor
+			if minor > p.left {
+				minor = p.left
+			}
+		}
+		if p.left <= 0 {
+			return nil, fmt.Errorf("%w: the product has been refunded in full", ErrInvalidRefund)
+		}
+		if minor > p.left {
+			return nil, fmt.Errorf("%w: at most %s is left to refund", ErrInvalidRefund, of(p.left))
+		}
+		if minor <= 0 {
+			return nil, fmt.Errorf("%w: the amount must be at least %s", ErrInvalidRefund, of(1))
+		}
+		item.Amount = of(minor)
+		return []RefundItem{item}, nil
+	}
+
+	if req.Quantity != 0 {
+		return nil, fmt.Errorf("%w: quantity applies to product refunds only", ErrInvalidRefund)
+	}
+	var total int64
+	for _, p := range products {
+		if p.left > 0 {
+			total += p.left
+		}
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("%w: the receipt has nothing left to refund", ErrInvalidRefund)
+	}
+
+	var items []RefundItem
+	if reverse {
+		for _, p := range products {
+			if p.left > 0 {
+				items = append(items, RefundItem{ProductID: p.productID, Quantity: p.quantity, Amount: of(p.left)})
+			}
+		}
+		return items, nil
+	}
+
+	if amount.Minor > total {
+		return nil, fmt.Errorf("%w: at most %s is left to refund", ErrInvalidRefund, of(total))
+	}
+	if amount.Minor <= 0 {
+		return nil, fmt.Errorf("%w: the amount must be at least %s", ErrInvalidRefund, of(1))
+	}
+	shares := make([]float64, len(products))
+	for i, p := range products {
+		if p.left > 0 {
+			shares[i] = float64(p.left) / float64(total)
+		}
+	}
+	for i, minor := range splitting.Parts(amount.Minor, shares) {
+		if minor > 0 {
+			items = append(items, RefundItem{ProductID: products[i].productID, Amount: of(minor)})
+		}
+	}
+	return items, nil
+}
+
+// journalRefund restates a refund's journal entry: each item taken back off
+// its category's expenses and paid into the balance account credited, or
+// the account spending is drawn from when none was. The entry is the only
+// record of the credit: the income row the balance ID names is left as it
+// is, so restating that income does not count the refund again. Refunds to
+// a balance account are in its currency; the funding account's may differ,
+// in which case the items are exchanged at the refund date's rate.
+func journalRefund(ctx context.Context, tx pgx.Tx, refund Refund) error {
+	funding, currency, err := ledger.FundingAccount(ctx, tx, refund.UserID)
+	if err != nil {
+		return err
+	}
+	if refund.BalanceID != nil {
+		if funding, currency, err = ledger.IncomeBalanceAccount(ctx, tx, refund.UserID, *refund.BalanceID); err != nil {
+			return err
+		}
+	}
+	if currency == "" {
+		currency = refund.Amount.Currency
+	}
+	rate, err := fx.Lookup(ctx, tx, refund.Amount.Currency, currency, refund.Date)
+	if err != nil {
+		return err
+	}
+
+	entry := ledger.Entry{
+		UserID:        refund.UserID,
+		Kind:          "refund",
+		ReferenceType: "refund",
+		ReferenceID:   strconv.Itoa(int(refund.RefundID)),
+		OccurredAt:    refund.Date,
+	}
+	if refund.Reason != nil {
+		entry.Description = *refund.Reason
+	}
+	for _, item := range refund.Items {
+		entry.Postings = append(entry.Postings, ledger.Exchange(
+			ledger.ExpenseAccount(item.CategoryID), item.Amount, funding, fx.At(item.Amount, currency, rate))...)
+	}
+	_, err = ledger.Restate(ctx, tx, entry)
+	return err
+}
+
+// balanceCurrency returns the currency of the balance account with the
+// given income ID, or ErrBalanceNotFound.
+func balanceCurrency(ctx context.Context, tx pgx.Tx, userID int32, balanceID int32) (string, error) {
+	var currency string
+	err := tx.QueryRow(ctx, `
+        SELECT a.currency
+        FROM account_income_service.incomes i
+        JOIN account_income_service.accounts a ON a.account_id = i.account_id
+        WHERE i.income_id = $1 AND i.user_id = $2`,
+		balanceID, userID).Scan(&currency)
+	if err == pgx.ErrNoRows {
+		return "", ErrBalanceNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("error finding balance account: %v", err)
+	}
+	return currency, nil
+}
+
+// CreateRefund records a refund against one of the user's products or
+// receipts and credits the balance account it names through the journal.
+// Refunds never take a product below nothing: the units returned and the
+// amount are checked against what earlier refunds left.
+func CreateRefund(ctx context.Context, userID string, req RefundRequest) (*Refund, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ProductID == 0 && req.FileName == "" {
+		return nil, fmt.Errorf("%w: a product or receipt is required", ErrInvalidRefund)
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	products, fileName, err := loadRefundable(ctx, tx, userIDInt, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		if req.ProductID != 0 {
+			return nil, ErrProductNotFound
+		}
+		if err := receiptExists(ctx, tx, userIDInt, req.FileName); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: the receipt has nothing to refund", ErrInvalidRefund)
+	}
+	currency := products[0].price.Currency
+	for _, p := range products {
+		if p.price.Currency != currency {
+			return nil, fmt.Errorf("%w: the receipt's products are in more than one currency", ErrInvalidRefund)
+		}
+	}
+	amount, err := req.Amount.In(currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: the products are in %s", ErrInvalidRefund, currency)
+	}
+	items, err := planRefund(products, req, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	date := budgeting.Date(req.Date)
+	for _, p := range products {
+		if date.Before(budgeting.Date(p.dateAdded)) {
+			return nil, fmt.Errorf("%w: the refund is dated before the purchase", ErrInvalidRefund)
+		}
+	}
+	total := money.Zero(currency)
+	for _, item := range items {
+		total.Minor += item.Amount.Minor
+	}
+
+	var productID, balanceID *int32
+	if req.ProductID != 0 {
+		productID = &req.ProductID
+	}
+	if req.BalanceID != 0 {
+		balanceID = &req.BalanceID
+		accountCurrency, err := balanceCurrency(ctx, tx, userIDInt, req.BalanceID)
+		if err != nil {
+			return nil, err
+		}
+		if accountCurrency != currency {
+			return nil, fmt.Errorf("%w: the refund is in %s but the balance account holds %s",
+				ErrInvalidRefund, currency, accountCurrency)
+		}
+	}
+
+	var refundID int32
+	err = tx.QueryRow(ctx, `
+        INSERT INTO product_category_service.refunds
+            (user_id, product_id, file_name, amount, currency, balance_id, reason, refund_date)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING refund_id`,
+		userIDInt, productID, fileName, total.String(), currency, balanceID, req.Reason, date).Scan(&refundID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating refund: %v", err)
+	}
+	for _, item := range items {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO product_category_service.refund_items (refund_id, product_id, quantity, amount, category_id)
+            SELECT $1, product_id, $3, $4, category_id
+            FROM product_category_service.products WHERE product_id = $2`,
+			refundID, item.ProductID, item.Quantity, item.Amount.String())
+		if err != nil {
+			return nil, fmt.Errorf("error creating refund item: %v", err)
+		}
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userIDInt, []time.Time{date}); err != nil {
+		return nil, err
+	}
+
+	refunds, err := listRefunds(ctx, tx, userIDInt, refundID, RefundQuery{}, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := journalRefund(ctx, tx, refunds[0]); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing refund: %v", err)
+	}
+	return &refunds[0], nil
+}
+
+// listRefunds returns the user's refunds matching q, or just one if
+// refundID is set, newest first with their items. lock locks the refunds
+// returned.
+func listRefunds(ctx context.Context, tx pgx.Tx, userID int32, refundID int32, q RefundQuery, lock bool) ([]Refund, error) {
+	query := `
+        SELECT r.refund_id, r.user_id, r.product_id, r.file_name, r.amount::float8, r.currency,
+            (SELECT COALESCE(SUM(ri.amount * account_income_service.recorded_rate(p.exchange_rate, p.currency, p.user_id, p.date_added::date)), 0)
+             FROM product_category_service.refund_items ri
+             JOIN product_category_service.products p ON p.product_id = ri.product_id
+             WHERE ri.refund_id = r.refund_id)::float8,
+            account_income_service.base_currency(r.user_id), r.balance_id,
+            r.reason, r.refund_date, r.created_at
+        FROM product_category_service.refunds r
+        WHERE r.user_id = $1 AND ($2 = 0 OR r.refund_id = $2)
+          AND ($3 = 0 OR r.product_id = $3 OR EXISTS (
+            SELECT 1 FROM product_category_service.refund_items ri
+            WHERE ri.refund_id = r.refund_id AND ri.product_id = $3))
+          AND ($4 = '' OR r.file_name = $4)
+          AND ($5::date IS NULL OR r.refund_date >= $5::date)
+          AND ($6::date IS NULL OR r.refund_date < $6::date)
+        ORDER BY r.refund_date DESC, r.refund_id DESC`
+	if lock {
+		query += " FOR UPDATE"
+	}
+	rows, err := tx.Query(ctx, query, userID, refundID, q.ProductID, q.FileName, q.From, q.To)
+	if err != nil {
+		return nil, fmt.Errorf("error listing refunds: %v", err)
+	}
+	var refunds []Refund
+	index := map[int32]int{}
+	for rows.Next() {
+		var r Refund
+		var amount float64
+		var currency string
+		if err := rows.Scan(&r.RefundID, &r.UserID, &r.ProductID, &r.FileName, &amount, &currency, &r.BaseAmount, &r.BaseCurrency, &r.BalanceID,
+			&r.Reason, &r.Date, &r.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning refund: %v", err)
+		}
+		r.Amount = money.FromFloat(amount, currency)
+		index[r.RefundID] = len(refunds)
+		refunds = append(refunds, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	if refundID != 0 && len(refunds) == 0 {
+		return nil, ErrRefundNotFound
+	}
+	if len(refunds) == 0 {
+		return refunds, nil
+	}
+
+	ids := make([]int32, 0, len(refunds))
+	for _, r := range refunds {
+		ids = append(ids, r.RefundID)
+	}
+	rows, err = tx.Query(ctx, `
+        SELECT ri.refund_id, ri.product_id, COALESCE(p.product_name, ''), COALESCE(ri.category_id, 0),
+            COALESCE(c.name, ''), ri.quantity, ri.amount::float8
+        FROM product_category_service.refund_items ri
+        JOIN product_category_service.products p ON p.product_id = ri.product_id
+        LEFT JOIN product_category_service.categories c ON c.category_id = ri.category_id
+        WHERE ri.refund_id = ANY($1)
+        ORDER BY ri.refund_id, ri.product_id`,
+		ids)
+	if err != nil {
+		return nil, fmt.Errorf("error loading refund items: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int32
+		var item RefundItem
+		var amount float64
+		if err := rows.Scan(&id, &item.ProductID, &item.ProductName, &item.CategoryID,
+			&item.CategoryName, &item.Quantity, &amount); err != nil {
+			return nil, fmt.Errorf("error scanning refund item: %v", err)
+		}
+		r := &refunds[index[id]]
+		item.Amount = money.FromFloat(amount, r.Amount.Currency)
+		r.Items = append(r.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return refunds, nil
+}
+
+// ListRefunds returns the user's refunds matching q, newest first.
+func ListRefunds(ctx context.Context, userID string, q RefundQuery) ([]Refund, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	return listRefunds(ctx, tx, userIDInt, 0, q, false)
+}
+
+// DeleteRefund removes one of the user's refunds, reversing its journal
+// entry so its amount comes back off the balance account it credited, and
+// returns what was deleted.
+func DeleteRefund(ctx context.Context, userID string, refundID int32) (*Refund, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	refunds, err := listRefunds(ctx, tx, userIDInt, refundID, RefundQuery{}, true)
+	if err != nil {
+		return nil, err
+	}
+	deleted := refunds[0]
+
+	_, err = tx.Exec(ctx, `
+        DELETE FROM product_category_service.refunds
+        WHERE user_id = $1 AND refund_id = $2`,
+		userIDInt, refundID)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting refund: %v", err)
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userIDInt, []time.Time{deleted.Date}); err != nil {
+		return nil, err
+	}
+	_, err = ledger.Restate(ctx, tx, ledger.Entry{
+		UserID:        userIDInt,
+		Kind:          "refund",
+		ReferenceType: "refund",
+		ReferenceID:   strconv.Itoa(int(refundID)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing delete: %v", err)
+	}
+	return &deleted, nil
+}
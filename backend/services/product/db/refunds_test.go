@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+func TestUpdateProductKeepsRefundsCovered(t *testing.T) {
+	requireDB(t)
+	ctx := context.Background()
+	userID, _ := newUser(t, ctx)
+	user := fmt.Sprint(userID)
+
+	saved, err := InsertProduct(ctx, user, Product{ProductName: "Headphones", Quantity: 2, Price: money.New(5000, "USD"),
+		DateAdded: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateRefund(ctx, user, RefundRequest{ProductID: saved.ProductID, Quantity: 1, Date: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		change  func(p *Product)
+		wantErr error
+	}{
+		{name: "below what was refunded", change: func(p *Product) { p.Price = money.New(4999, "USD"); p.Quantity = 1 }, wantErr: ErrProductRefunded},
+		{name: "another currency", change: func(p *Product) { p.Price.Currency = "EUR" }, wantErr: ErrProductRefunded},
+		{name: "down to what was refunded", change: func(p *Product) { p.Quantity = 1 }},
+		{name: "renamed", change: func(p *Product) { p.ProductName = "Wireless headphones" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, err := GetProduct(ctx, Pool(), user, saved.ProductID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated := *current
+			tt.change(&updated)
+			_, _, err = UpdateProduct(ctx, user, updated)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("UpdateProduct() = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProduct() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
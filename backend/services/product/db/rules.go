@@ -0,0 +1,418 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/ruleengine"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrRuleNotFound = errors.New("rule not found")
+	ErrInvalidRule  = errors.New("invalid rule")
+)
+
+const ruleColumns = `r.rule_id, r.name, r.priority, r.enabled, r.match_all, r.conditions::text,
+        r.set_category_id, r.add_tags, r.set_notes, r.stop_processing`
+
+func scanRule(row pgx.Row) (ruleengine.Rule, error) {
+	var rule ruleengine.Rule
+	var conditions string
+	err := row.Scan(&rule.RuleID, &rule.Name, &rule.Priority, &rule.Enabled, &rule.MatchAll, &conditions,
+		&rule.SetCategoryID, &rule.AddTags, &rule.SetNotes, &rule.StopProcessing)
+	if err != nil {
+		return rule, err
+	}
+	if err := json.Unmarshal([]byte(conditions), &rule.Conditions); err != nil {
+		return rule, fmt.Errorf("error decoding conditions of rule %d: %v", rule.RuleID, err)
+	}
+	return rule, nil
+}
+
+// RuleSet is a user's enabled rules, compiled and in evaluation order, along
+// with the categories their actions refer to.
+type RuleSet struct {
+	rules    []ruleengine.Rule
+	resolver *CategoryResolver
+}
+
+// LoadRuleSet reads the user's enabled rules. The user must already have been
+// seeded with EnsureUserCategories.
+func LoadRuleSet(ctx context.Context, tx pgx.Tx, userID int32) (*RuleSet, error) {
+	resolver, err := LoadCategoryResolver(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT `+ruleColumns+`
+        FROM product_category_service.category_rules r
+        WHERE r.user_id = $1 AND r.enabled`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading rules: %v", err)
+	}
+	defer rows.Close()
+
+	set := &RuleSet{resolver: resolver}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning rule: %v", err)
+		}
+		// Rules are validated when saved; one that no longer compiles is
+		// skipped rather than blocking every upload.
+		if err := ruleengine.Compile(&rule); err != nil {
+			log.Printf("Warning: skipping rule %d of user %d: %v", rule.RuleID, userID, err)
+			continue
+		}
+		set.rules = append(set.rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	ruleengine.Sort(set.rules)
+	return set, nil
+}
+
+// Resolver returns the category resolver the rule set was loaded with.
+func (s *RuleSet) Resolver() *CategoryResolver {
+	return s.resolver
+}
+
+// ApplyTo runs the rules over a product and updates it in place, returning the
+// IDs of the rules that matched. Categories picked by hand are kept, tags are
+// merged and notes only fill an empty field.
+func (s *RuleSet) ApplyTo(product *Product, merchant string) []int32 {
+	return s.applyTo(product, merchant, false)
+}
+
+func (s *RuleSet) applyTo(product *Product, merchant string, overrideManual bool) []int32 {
+	subject := ruleengine.Subject{
+		ProductName: product.ProductName,
+		Category:    product.CategoryName,
+		Merchant:    merchant,
+		Amount:      product.LineTotal,
+		Quantity:    float64(product.Quantity),
+	}
+	if product.Description != nil {
+		subject.Description = *product.Description
+	}
+	if product.FileName != nil {
+		subject.FileName = *product.FileName
+	}
+
+	outcome := ruleengine.Apply(s.rules, subject)
+	if outcome.CategoryID != nil && (overrideManual || !product.CategoryIsManual()) {
+		if category, ok := s.resolver.ByID(*outcome.CategoryID); ok {
+			source := CategorySourceRule
+			product.CategoryID = category.CategoryID
+			product.CategoryName = category.Name
+			product.CategorySource = &source
+		}
+	}
+	product.Tags = ruleengine.MergeTags(product.Tags, outcome.Tags)
+	if outcome.Notes != nil && (product.Notes == nil || *product.Notes == "") {
+		notes := *outcome.Notes
+		product.Notes = &notes
+	}
+	return outcome.RuleIDs
+}
+
+// receiptMerchant returns the merchant recognised on a receipt, if any.
+func receiptMerchant(ctx context.Context, tx pgx.Tx, userID int32, fileName *string) (string, error) {
+	if fileName == nil || *fileName == "" {
+		return "", nil
+	}
+	var merchant *string
+	err := tx.QueryRow(ctx, `
+        SELECT merchant_name FROM product_category_service.receipt_texts
+        WHERE user_id = $1 AND file_name = $2`,
+		userID, *fileName).Scan(&merchant)
+	if err == pgx.ErrNoRows || (err == nil && merchant == nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching merchant: %v", err)
+	}
+	return *merchant, nil
+}
+
+// ReceiptMerchant returns the merchant recognised on one of the user's
+// receipts, or "" if none was.
+func ReceiptMerchant(ctx context.Context, tx pgx.Tx, userID int32, fileName string) (string, error) {
+	return receiptMerchant(ctx, tx, userID, &fileName)
+}
+
+// nonNilTags keeps empty tag lists from being stored as NULL.
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// ListRules returns the user's rules in evaluation order.
+func ListRules(ctx context.Context, userID string) ([]ruleengine.Rule, error) {
+	var ruleList []ruleengine.Rule
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		rows, err := tx.Query(ctx, `
+            SELECT `+ruleColumns+`
+            FROM product_category_service.category_rules r
+            WHERE r.user_id = $1
+            ORDER BY r.priority, r.rule_id`,
+			userIDInt)
+		if err != nil {
+			return fmt.Errorf("error listing rules: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			rule, err := scanRule(rows)
+			if err != nil {
+				return fmt.Errorf("error scanning rule: %v", err)
+			}
+			ruleList = append(ruleList, rule)
+		}
+		return rows.Err()
+	})
+	return ruleList, err
+}
+
+func getRule(ctx context.Context, tx pgx.Tx, userID int32, ruleID int32) (*ruleengine.Rule, error) {
+	rule, err := scanRule(tx.QueryRow(ctx, `
+        SELECT `+ruleColumns+`
+        FROM product_category_service.category_rules r
+        WHERE r.user_id = $1 AND r.rule_id = $2`,
+		userID, ruleID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrRuleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching rule: %v", err)
+	}
+	return &rule, nil
+}
+
+// checkRule validates a rule before it is saved and encodes its conditions.
+func checkRule(ctx context.Context, tx pgx.Tx, userID int32, rule *ruleengine.Rule) (string, error) {
+	if err := ruleengine.Compile(rule); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	if rule.SetCategoryID != nil {
+		if err := categoryExists(ctx, tx, userID, *rule.SetCategoryID); err != nil {
+			return "", err
+		}
+	}
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return "", fmt.Errorf("error encoding conditions: %v", err)
+	}
+	return string(conditions), nil
+}
+
+// CreateRule saves a new rule for the user.
+func CreateRule(ctx context.Context, userID string, rule ruleengine.Rule) (*ruleengine.Rule, error) {
+	var created *ruleengine.Rule
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		rule.AddTags = ruleengine.MergeTags(nil, rule.AddTags)
+		conditions, err := checkRule(ctx, tx, userIDInt, &rule)
+		if err != nil {
+			return err
+		}
+
+		var ruleID int32
+		err = tx.QueryRow(ctx, `
+            INSERT INTO product_category_service.category_rules
+                (user_id, name, priority, enabled, match_all, conditions, set_category_id, add_tags, set_notes, stop_processing)
+            VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7, $8, $9, $10)
+            RETURNING rule_id`,
+			userIDInt, rule.Name, rule.Priority, rule.Enabled, rule.MatchAll, conditions,
+			rule.SetCategoryID, nonNilTags(rule.AddTags), rule.SetNotes, rule.StopProcessing).Scan(&ruleID)
+		if err != nil {
+			return fmt.Errorf("error creating rule: %v", err)
+		}
+		created, err = getRule(ctx, tx, userIDInt, ruleID)
+		return err
+	})
+	return created, err
+}
+
+// UpdateRule replaces one of the user's rules.
+func UpdateRule(ctx context.Context, userID string, rule ruleengine.Rule) (*ruleengine.Rule, error) {
+	var updated *ruleengine.Rule
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		if _, err := getRule(ctx, tx, userIDInt, rule.RuleID); err != nil {
+			return err
+		}
+		rule.AddTags = ruleengine.MergeTags(nil, rule.AddTags)
+		conditions, err := checkRule(ctx, tx, userIDInt, &rule)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+            UPDATE product_category_service.category_rules
+            SET name = $1, priority = $2, enabled = $3, match_all = $4, conditions = $5::jsonb,
+                set_category_id = $6, add_tags = $7, set_notes = $8, stop_processing = $9, updated_at = NOW()
+            WHERE user_id = $10 AND rule_id = $11`,
+			rule.Name, rule.Priority, rule.Enabled, rule.MatchAll, conditions,
+			rule.SetCategoryID, nonNilTags(rule.AddTags), rule.SetNotes, rule.StopProcessing,
+			userIDInt, rule.RuleID)
+		if err != nil {
+			return fmt.Errorf("error updating rule: %v", err)
+		}
+		updated, err = getRule(ctx, tx, userIDInt, rule.RuleID)
+		return err
+	})
+	return updated, err
+}
+
+// DeleteRule removes one of the user's rules. Products it already changed
+// keep their category, tags and notes.
+func DeleteRule(ctx context.Context, userID string, ruleID int32) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+	tag, err := sharedDB.GetDB().Exec(ctx, `
+        DELETE FROM product_category_service.category_rules
+        WHERE user_id = $1 AND rule_id = $2`,
+		userIDInt, ruleID)
+	if err != nil {
+		return fmt.Errorf("error deleting rule: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// ReapplyOptions selects the products ReapplyRules runs over.
+type ReapplyOptions struct {
+	From *time.Time
+	To   *time.Time
+	// IncludeManual lets rules replace categories the user picked by hand.
+	IncludeManual bool
+	// DryRun computes the changes without saving them.
+	DryRun bool
+}
+
+// RuleChange is a product ReapplyRules changed, or would change.
+type RuleChange struct {
+	Before  Product
+	After   Product
+	RuleIDs []int32
+}
+
+// ReapplyResult summarises a ReapplyRules run.
+type ReapplyResult struct {
+	Scanned int
+	Changes []RuleChange
+}
+
+// ReapplyRules runs the user's current rules over their stored products.
+func ReapplyRules(ctx context.Context, userID string, opts ReapplyOptions) (*ReapplyResult, error) {
+	result := &ReapplyResult{}
+	err := withUserCategories(ctx, userID, func(tx pgx.Tx, userIDInt int32) error {
+		ruleSet, err := LoadRuleSet(ctx, tx, userIDInt)
+		if err != nil {
+			return err
+		}
+		if len(ruleSet.rules) == 0 {
+			return nil
+		}
+
+		rows, err := tx.Query(ctx, `
+            SELECT `+productColumns+`, coalesce(r.merchant_name, '')
+            FROM product_category_service.products p
+            JOIN product_category_service.categories c ON p.category_id = c.category_id
+            LEFT JOIN product_category_service.receipt_texts r
+                ON r.user_id = p.user_id AND r.file_name = p.file_name
+            WHERE p.user_id = $1
+              AND ($2::timestamp IS NULL OR p.date_added >= $2)
+              AND ($3::timestamp IS NULL OR p.date_added < $3)
+            ORDER BY p.date_added, p.product_id
+            FOR UPDATE OF p`,
+			userIDInt, opts.From, opts.To)
+		if err != nil {
+			return fmt.Errorf("error fetching products: %v", err)
+		}
+		type candidate struct {
+			product  Product
+			merchant string
+		}
+		var candidates []candidate
+		for rows.Next() {
+			var merchant string
+			product, err := scanProduct(rows, &merchant)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning product row: %v", err)
+			}
+			candidates = append(candidates, candidate{product, merchant})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error during row iteration: %v", err)
+		}
+
+		result.Scanned = len(candidates)
+		for _, c := range candidates {
+			after := c.product
+			after.Tags = append([]string(nil), c.product.Tags...)
+			ruleIDs := ruleSet.applyTo(&after, c.merchant, opts.IncludeManual)
+			if !productChanged(c.product, after) {
+				continue
+			}
+			result.Changes = append(result.Changes, RuleChange{Before: c.product, After: after, RuleIDs: ruleIDs})
+
+			if opts.DryRun {
+				continue
+			}
+			_, err := tx.Exec(ctx, `
+                UPDATE product_category_service.products
+                SET category_id = $1, tags = $2, notes = $3, category_source = $4
+                WHERE user_id = $5 AND product_id = $6`,
+				after.CategoryID, nonNilTags(after.Tags), after.Notes, after.CategorySource,
+				userIDInt, after.ProductID)
+			if err != nil {
+				return fmt.Errorf("error updating product %d: %v", after.ProductID, err)
+			}
+		}
+		if !opts.DryRun && len(result.Changes) > 0 {
+			return RecomputeBudgetSpend(ctx, tx, userIDInt)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func productChanged(before, after Product) bool {
+	if before.CategoryID != after.CategoryID || len(before.Tags) != len(after.Tags) {
+		return true
+	}
+	for i := range before.Tags {
+		if before.Tags[i] != after.Tags[i] {
+			return true
+		}
+	}
+	beforeNotes, afterNotes := "", ""
+	if before.Notes != nil {
+		beforeNotes = *before.Notes
+	}
+	if after.Notes != nil {
+		afterNotes = *after.Notes
+	}
+	return strings.TrimSpace(beforeNotes) != strings.TrimSpace(afterNotes)
+}
@@ -0,0 +1,315 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/product/splitting"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrInvalidSplit = errors.New("invalid split")
+
+// allocatedProducts stands in for the products table in aggregates. It has
+// a row per product, or per part the user keeps of a split product, with
+// that part's category and amount. Parts that belong to someone else are
+// left out: they are not the user's spend. Refunds add rows of their own,
+// dated the day of the refund, with the units returned and the amount
+// negated and split the same way as the product; refund is set on them.
+// Amounts are in the user's base currency, at the rate recorded on the
+// product; products with no rate known have a NULL amount, which sums skip.
+const allocatedProducts = `(
+        SELECT p.product_id, p.user_id, p.product_name, p.quantity, p.file_name, p.date_added,
+            COALESCE(s.category_id, p.category_id) AS category_id,
+            ((p.quantity * p.price) * COALESCE(s.share, 1) * account_income_service.recorded_rate(p.exchange_rate, p.currency, p.user_id, p.date_added::date))::float8 AS amount,
+            COALESCE(s.share, 1)::float8 AS share,
+            FALSE AS refund
+        FROM product_category_service.products p
+        LEFT JOIN product_category_service.product_splits s ON s.product_id = p.product_id
+        WHERE s.split_id IS NULL OR (s.participant IS NULL AND s.participant_user_id IS NULL)
+        UNION ALL
+        SELECT p.product_id, p.user_id, p.product_name, -ri.quantity, p.file_name, r.refund_date::timestamp,
+            COALESCE(s.category_id, p.category_id),
+            (-ri.amount * COALESCE(s.share, 1) * account_income_service.recorded_rate(p.exchange_rate, p.currency, p.user_id, p.date_added::date))::float8,
+            COALESCE(s.share, 1)::float8,
+            TRUE
+        FROM product_category_service.refund_items ri
+        JOIN product_category_service.refunds r ON r.refund_id = ri.refund_id
+        JOIN product_category_service.products p ON p.product_id = ri.product_id
+        LEFT JOIN product_category_service.product_splits s ON s.product_id = p.product_id
+        WHERE s.split_id IS NULL OR (s.participant IS NULL AND s.participant_user_id IS NULL)
+    )`
+
+// ProductSplit is one stored part of a split product.
+type ProductSplit struct {
+	SplitID int32
+	// CategoryID is nil for parts filed under the product's own category;
+	// CategoryName is filled in either way.
+	CategoryID        *int32
+	CategoryName      string
+	Share             float64
+	Participant       *string
+	ParticipantUserID *int32
+	Note              *string
+}
+
+// Shared reports whether the part belongs to someone other than the user.
+func (s ProductSplit) Shared() bool {
+	return s.Participant != nil || s.ParticipantUserID != nil
+}
+
+func loadSplits(ctx context.Context, tx pgx.Tx, productID int32) ([]ProductSplit, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT s.split_id, s.category_id, COALESCE(c.name, ''), s.share::float8,
+            s.participant, s.participant_user_id, s.note
+        FROM product_category_service.product_splits s
+        JOIN product_category_service.products p ON p.product_id = s.product_id
+        LEFT JOIN product_category_service.categories c ON c.category_id = COALESCE(s.category_id, p.category_id)
+        WHERE s.product_id = $1
+        ORDER BY s.position`,
+		productID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading splits: %v", err)
+	}
+	defer rows.Close()
+
+	var splits []ProductSplit
+	for rows.Next() {
+		var s ProductSplit
+		if err := rows.Scan(&s.SplitID, &s.CategoryID, &s.CategoryName, &s.Share,
+			&s.Participant, &s.ParticipantUserID, &s.Note); err != nil {
+			return nil, fmt.Errorf("error scanning split: %v", err)
+		}
+		splits = append(splits, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return splits, nil
+}
+
+// GetProductSplits returns one of the user's products with its splits, in
+// the order they were given. Products that are not split have none.
+func GetProductSplits(ctx context.Context, userID string, productID int32) (*Product, []ProductSplit, error) {
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	product, err := GetProduct(ctx, tx, userID, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	splits, err := loadSplits(ctx, tx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return product, splits, nil
+}
+
+// checkAllocations checks the categories and participants allocations refer
+// to, filling in the names of participants given only as users. Participants
+// given as users must share a group with the user, so usernames are only
+// ever revealed to people the user already splits expenses with.
+func checkAllocations(ctx context.Context, tx pgx.Tx, userID int32, allocations []splitting.Allocation) error {
+	for i := range allocations {
+		a := &allocations[i]
+		a.Participant = strings.TrimSpace(a.Participant)
+		a.Note = strings.TrimSpace(a.Note)
+		if a.CategoryID != nil {
+			if err := categoryExists(ctx, tx, userID, *a.CategoryID); err != nil {
+				return err
+			}
+		}
+		if a.ParticipantUserID == nil {
+			continue
+		}
+		if *a.ParticipantUserID == userID {
+			return fmt.Errorf("%w: leave the participant empty for your own share", ErrInvalidSplit)
+		}
+		var username string
+		err := tx.QueryRow(ctx, `
+            SELECT u.username FROM user_service.users u
+            WHERE u.user_id = $2 AND EXISTS (
+                SELECT 1 FROM user_service.group_members mine
+                JOIN user_service.group_members theirs ON theirs.group_id = mine.group_id
+                WHERE mine.user_id = $1 AND theirs.user_id = u.user_id)`,
+			userID, *a.ParticipantUserID).Scan(&username)
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("%w: user %d is not in any of your groups", ErrInvalidSplit, *a.ParticipantUserID)
+		}
+		if err != nil {
+			return fmt.Errorf("error checking participant: %v", err)
+		}
+		if a.Participant == "" {
+			a.Participant = username
+		}
+	}
+	return nil
+}
+
+// storeSplits replaces a product's splits with allocations at shares.
+func storeSplits(ctx context.Context, tx pgx.Tx, productID int32, allocations []splitting.Allocation, shares []float64) error {
+	if _, err := tx.Exec(ctx, `
+        DELETE FROM product_category_service.product_splits WHERE product_id = $1`,
+		productID); err != nil {
+		return fmt.Errorf("error clearing splits: %v", err)
+	}
+	for i, a := range allocations {
+		var participant, note *string
+		if a.Participant != "" {
+			participant = &allocations[i].Participant
+		}
+		if a.Note != "" {
+			note = &allocations[i].Note
+		}
+		_, err := tx.Exec(ctx, `
+            INSERT INTO product_category_service.product_splits
+                (product_id, position, category_id, share, participant, participant_user_id, note)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			productID, i, a.CategoryID, shares[i], participant, a.ParticipantUserID, note)
+		if err != nil {
+			return fmt.Errorf("error storing split: %v", err)
+		}
+	}
+	return nil
+}
+
+// SetProductSplits replaces the splits of one of the user's products. No
+// allocations removes them, leaving the product whole again.
+func SetProductSplits(ctx context.Context, userID string, productID int32, allocations []splitting.Allocation) (*Product, []ProductSplit, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	product, err := GetProduct(ctx, tx, userID, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkAllocations(ctx, tx, userIDInt, allocations); err != nil {
+		return nil, nil, err
+	}
+	shares, err := splitting.Resolve(product.LineTotal, allocations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSplit, err)
+	}
+	if err := storeSplits(ctx, tx, productID, allocations, shares); err != nil {
+		return nil, nil, err
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userIDInt, []time.Time{product.DateAdded}); err != nil {
+		return nil, nil, err
+	}
+
+	splits, err := loadSplits(ctx, tx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("error committing splits: %v", err)
+	}
+	return product, splits, nil
+}
+
+// SplitReceipt splits every product on one of the user's receipts the same
+// way. Amounts are taken as parts of the receipt's total, so each product
+// is split in the same proportions; products with nothing to split are
+// left alone. No allocations removes the receipt's splits. It returns how
+// many products were split.
+func SplitReceipt(ctx context.Context, userID string, fileName string, allocations []splitting.Allocation) (int, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	split, err := SplitReceiptProducts(ctx, tx, userIDInt, fileName, allocations)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing splits: %v", err)
+	}
+	return split, nil
+}
+
+// SplitReceiptProducts is SplitReceipt within the caller's transaction, for
+// services that split a receipt as part of a larger change. No allocations
+// makes every product on the receipt whole again.
+func SplitReceiptProducts(ctx context.Context, tx pgx.Tx, userID int32, fileName string, allocations []splitting.Allocation) (int, error) {
+	if err := receiptExists(ctx, tx, userID, fileName); err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.Query(ctx, `
+        SELECT product_id, date_added, (quantity * price)::float8
+        FROM product_category_service.products
+        WHERE user_id = $1 AND file_name = $2
+        FOR UPDATE`,
+		userID, fileName)
+	if err != nil {
+		return 0, fmt.Errorf("error loading receipt products: %v", err)
+	}
+	var productIDs []int32
+	var dates []time.Time
+	var total float64
+	for rows.Next() {
+		var id int32
+		var date time.Time
+		var lineTotal float64
+		if err := rows.Scan(&id, &date, &lineTotal); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("error scanning receipt product: %v", err)
+		}
+		// Clearing splits covers every product, whatever its total.
+		if lineTotal > 0 || len(allocations) == 0 {
+			productIDs = append(productIDs, id)
+			dates = append(dates, date)
+			total += lineTotal
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error during row iteration: %v", err)
+	}
+	if len(productIDs) == 0 {
+		if len(allocations) == 0 {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%w: the receipt has nothing to split", ErrInvalidSplit)
+	}
+
+	var shares []float64
+	if len(allocations) > 0 {
+		if err := checkAllocations(ctx, tx, userID, allocations); err != nil {
+			return 0, err
+		}
+		if shares, err = splitting.Resolve(total, allocations); err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidSplit, err)
+		}
+	}
+	for _, id := range productIDs {
+		if err := storeSplits(ctx, tx, id, allocations, shares); err != nil {
+			return 0, err
+		}
+	}
+	if err := RefreshBudgetSpend(ctx, tx, userID, dates); err != nil {
+		return 0, err
+	}
+	return len(productIDs), nil
+}
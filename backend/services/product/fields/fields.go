@@ -0,0 +1,160 @@
+// Package fields defines the typed custom fields users attach to products
+// and receipts, and checks values against them. Values are kept as JSON
+// would hold them: text, select and date values are strings, numbers are
+// float64 and booleans are bool.
+package fields
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field types.
+const (
+	TypeText    = "text"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeDate    = "date"
+	TypeSelect  = "select"
+)
+
+const (
+	MaxLabelLength = 100
+	MaxTextLength  = 500
+	MaxOptions     = 50
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// Definition describes one custom field.
+type Definition struct {
+	// Key names the field in stored values and filters: lower case letters,
+	// digits and underscores, starting with a letter.
+	Key     string
+	Label   string
+	Type    string
+	Options []string
+}
+
+// Validate checks a definition and normalises it.
+func (d *Definition) Validate() error {
+	d.Key = strings.ToLower(strings.TrimSpace(d.Key))
+	if !keyPattern.MatchString(d.Key) {
+		return fmt.Errorf("key must start with a letter and contain at most 40 lower case letters, digits and underscores")
+	}
+	d.Label = strings.TrimSpace(d.Label)
+	if d.Label == "" {
+		d.Label = d.Key
+	}
+	if len([]rune(d.Label)) > MaxLabelLength {
+		return fmt.Errorf("label must be at most %d characters", MaxLabelLength)
+	}
+
+	switch d.Type {
+	case TypeText, TypeNumber, TypeBoolean, TypeDate:
+		if len(d.Options) > 0 {
+			return fmt.Errorf("only select fields have options")
+		}
+	case TypeSelect:
+		var options []string
+		seen := map[string]bool{}
+		for _, option := range d.Options {
+			option = strings.TrimSpace(option)
+			if option == "" || seen[strings.ToLower(option)] {
+				continue
+			}
+			if len([]rune(option)) > MaxLabelLength {
+				return fmt.Errorf("options must be at most %d characters", MaxLabelLength)
+			}
+			seen[strings.ToLower(option)] = true
+			options = append(options, option)
+		}
+		if len(options) == 0 {
+			return fmt.Errorf("select fields need at least one option")
+		}
+		if len(options) > MaxOptions {
+			return fmt.Errorf("select fields have at most %d options", MaxOptions)
+		}
+		d.Options = options
+	default:
+		return fmt.Errorf("type must be %s, %s, %s, %s or %s", TypeText, TypeNumber, TypeBoolean, TypeDate, TypeSelect)
+	}
+	return nil
+}
+
+// Check validates a value for the field and returns it normalised: text is
+// trimmed, dates become YYYY-MM-DD and select values take the option's
+// spelling.
+func (d Definition) Check(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case TypeNumber:
+		if n, ok := value.(float64); ok {
+			return n, nil
+		}
+	case TypeBoolean:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case TypeText:
+		if s, ok := value.(string); ok {
+			s = strings.TrimSpace(s)
+			if len([]rune(s)) > MaxTextLength {
+				return nil, fmt.Errorf("%s must be at most %d characters", d.Key, MaxTextLength)
+			}
+			return s, nil
+		}
+	case TypeDate:
+		if s, ok := value.(string); ok {
+			date, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a YYYY-MM-DD date", d.Key)
+			}
+			return date.Format("2006-01-02"), nil
+		}
+	case TypeSelect:
+		if s, ok := value.(string); ok {
+			for _, option := range d.Options {
+				if strings.EqualFold(option, strings.TrimSpace(s)) {
+					return option, nil
+				}
+			}
+			return nil, fmt.Errorf("%s must be one of %s", d.Key, strings.Join(d.Options, ", "))
+		}
+	}
+	return nil, fmt.Errorf("%s takes a %s value", d.Key, d.Type)
+}
+
+// CheckAll validates values against the definitions, keyed by field key.
+func CheckAll(defs map[string]Definition, values map[string]interface{}) (map[string]interface{}, error) {
+	checked := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		def, ok := defs[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field %q", key)
+		}
+		v, err := def.Check(value)
+		if err != nil {
+			return nil, err
+		}
+		checked[key] = v
+	}
+	return checked, nil
+}
+
+// Format renders a value for export.
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
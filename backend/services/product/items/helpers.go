@@ -0,0 +1,35 @@
+package items
+
+import (
+	"errors"
+
+	"github.com/Aneesh-Hegde/expenseManager/item"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toItemMessage(i *productDB.Item) *item.Item {
+	msg := &item.Item{
+		ItemId:          i.ItemID,
+		Name:            i.Name,
+		Purchases:       int32(i.Purchases),
+		LatestUnitPrice: i.LatestUnitPrice,
+	}
+	if i.LastPurchased != nil {
+		msg.LastPurchased = i.LastPurchased.Format("2006-01-02")
+	}
+	return msg
+}
+
+// itemError reports merges the items cannot take as InvalidArgument.
+func itemError(err error) error {
+	switch {
+	case errors.Is(err, productDB.ErrItemNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrInvalidMerge),
+		errors.Is(err, productDB.ErrCategoryNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package items
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/item"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+const (
+	defaultItems = 50
+	maxItems     = 200
+)
+
+// ListItems returns the caller's canonical items, most recently bought
+// first.
+func ListItems(ctx context.Context, req *item.ListItemsRequest) (*item.ItemList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultItems
+	}
+	if limit > maxItems {
+		limit = maxItems
+	}
+
+	items, err := productDB.ListItems(ctx, userId, strings.TrimSpace(req.GetQuery()), limit)
+	if err != nil {
+		return nil, itemError(err)
+	}
+
+	resp := &item.ItemList{}
+	for i := range items {
+		resp.Items = append(resp.Items, toItemMessage(&items[i]))
+	}
+	return resp, nil
+}
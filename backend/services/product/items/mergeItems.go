@@ -0,0 +1,90 @@
+package items
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/item"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultSuggestions = 20
+	maxSuggestions     = 100
+	maxMergeSources    = 50
+)
+
+// SuggestItemMerges lists pairs of the caller's items whose names suggest
+// they are the same thing.
+func SuggestItemMerges(ctx context.Context, req *item.SuggestItemMergesRequest) (*item.ItemMergeSuggestions, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSuggestions
+	}
+	if limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+
+	suggestions, err := productDB.SuggestItemMerges(ctx, userId, limit)
+	if err != nil {
+		return nil, itemError(err)
+	}
+
+	resp := &item.ItemMergeSuggestions{}
+	for i := range suggestions {
+		resp.Suggestions = append(resp.Suggestions, &item.ItemMergeSuggestion{
+			Item:       toItemMessage(&suggestions[i].Item),
+			Other:      toItemMessage(&suggestions[i].Other),
+			Similarity: suggestions[i].Similarity,
+		})
+	}
+	return resp, nil
+}
+
+// MergeItems folds some of the caller's items into another.
+func MergeItems(ctx context.Context, req *item.MergeItemsRequest) (*item.MergeItemsResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetTargetItemId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "target item is required")
+	}
+	if len(req.GetSourceItemIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one source item is required")
+	}
+	if len(req.GetSourceItemIds()) > maxMergeSources {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d items can be merged at once", maxMergeSources)
+	}
+
+	merged, moved, err := productDB.MergeItems(ctx, userId, req.GetTargetItemId(), req.GetSourceItemIds())
+	if err != nil {
+		return nil, itemError(err)
+	}
+	return &item.MergeItemsResponse{
+		Item:           toItemMessage(merged),
+		PurchasesMoved: int32(moved),
+	}, nil
+}
+
+// DismissItemMerge stops two of the caller's items being suggested for
+// merging.
+func DismissItemMerge(ctx context.Context, req *item.DismissItemMergeRequest) (*item.DismissItemMergeResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := productDB.DismissItemMerge(ctx, userId, req.GetItemId(), req.GetOtherItemId()); err != nil {
+		return nil, itemError(err)
+	}
+	return &item.DismissItemMergeResponse{Message: "Suggestion dismissed"}, nil
+}
@@ -0,0 +1,95 @@
+package items
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/item"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/pricing"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPriceHistory returns every purchase of one of the caller's items with
+// how its unit price moved, overall and per merchant.
+func GetPriceHistory(ctx context.Context, req *item.GetPriceHistoryRequest) (*item.PriceHistory, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to *time.Time
+	if req.GetFromDate() != "" {
+		parsed, err := time.Parse("2006-01-02", req.GetFromDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "from_date must be YYYY-MM-DD")
+		}
+		from = &parsed
+	}
+	if req.GetToDate() != "" {
+		parsed, err := time.Parse("2006-01-02", req.GetToDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "to_date must be YYYY-MM-DD")
+		}
+		end := parsed.AddDate(0, 0, 1)
+		to = &end
+	}
+
+	found, points, err := productDB.GetPriceHistory(ctx, userId, req.GetItemId(), strings.TrimSpace(req.GetMerchant()), from, to)
+	if err != nil {
+		return nil, itemError(err)
+	}
+
+	resp := &item.PriceHistory{Item: toItemMessage(found)}
+	all := make([]pricing.Point, 0, len(points))
+	byMerchant := map[string][]pricing.Point{}
+	var merchants []string
+	for _, p := range points {
+		msg := &item.PricePoint{
+			ProductId:   p.ProductID,
+			ProductName: p.ProductName,
+			Date:        p.Date.Format("2006-01-02"),
+			UnitPrice:   p.UnitPrice,
+			Quantity:    p.Quantity,
+			Merchant:    p.Merchant,
+		}
+		if p.FileName != nil {
+			msg.FileName = *p.FileName
+		}
+		resp.Points = append(resp.Points, msg)
+
+		all = append(all, p.Point)
+		key := strings.ToLower(p.Merchant)
+		if _, ok := byMerchant[key]; !ok {
+			merchants = append(merchants, p.Merchant)
+		}
+		byMerchant[key] = append(byMerchant[key], p.Point)
+	}
+
+	resp.Summary = toSummaryMessage("", pricing.Summarize(all))
+	for _, merchant := range merchants {
+		resp.Merchants = append(resp.Merchants, toSummaryMessage(merchant, pricing.Summarize(byMerchant[strings.ToLower(merchant)])))
+	}
+	sort.SliceStable(resp.Merchants, func(i, j int) bool {
+		return resp.Merchants[i].Purchases > resp.Merchants[j].Purchases
+	})
+	return resp, nil
+}
+
+func toSummaryMessage(merchant string, s pricing.Summary) *item.PriceSummary {
+	return &item.PriceSummary{
+		Merchant:                merchant,
+		Purchases:               int32(s.Purchases),
+		FirstUnitPrice:          s.First,
+		LatestUnitPrice:         s.Latest,
+		MinUnitPrice:            s.Min,
+		MaxUnitPrice:            s.Max,
+		AverageUnitPrice:        s.Average,
+		ChangeSinceFirstPercent: s.ChangeSinceFirst,
+		ChangeSinceLastPercent:  s.ChangeSinceLast,
+	}
+}
@@ -0,0 +1,96 @@
+package items
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/item"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultInsightDays = 30
+	defaultMinChange   = 5.0
+	defaultInsights    = 20
+	maxInsights        = 100
+)
+
+// GetPriceInsights reports items whose price changed since the caller last
+// bought them.
+func GetPriceInsights(ctx context.Context, req *item.GetPriceInsightsRequest) (*item.PriceInsights, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -defaultInsightDays)
+	if req.GetSince() != "" {
+		since, err = time.Parse("2006-01-02", req.GetSince())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "since must be YYYY-MM-DD")
+		}
+	}
+	minChange := defaultMinChange
+	if req.MinChangePercent != nil {
+		minChange = math.Abs(req.GetMinChangePercent())
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultInsights
+	}
+	if limit > maxInsights {
+		limit = maxInsights
+	}
+
+	insights, err := productDB.GetPriceInsights(ctx, userId, productDB.PriceInsightQuery{
+		Since:      since,
+		CategoryID: req.GetCategoryId(),
+		ByMerchant: req.GetByMerchant(),
+	})
+	if err != nil {
+		return nil, itemError(err)
+	}
+
+	resp := &item.PriceInsights{ItemsCompared: int32(len(insights))}
+	var total float64
+	for i := range insights {
+		insight := &insights[i]
+		total += insight.ChangePercent
+		if math.Abs(insight.ChangePercent) < minChange || len(resp.Insights) == limit {
+			continue
+		}
+		resp.Insights = append(resp.Insights, &item.PriceInsight{
+			Item:              toItemMessage(&insight.Item),
+			Merchant:          insight.Merchant,
+			LatestDate:        insight.LatestDate.Format("2006-01-02"),
+			LatestUnitPrice:   insight.LatestPrice,
+			PreviousDate:      insight.PreviousDate.Format("2006-01-02"),
+			PreviousUnitPrice: insight.PreviousPrice,
+			PreviousMerchant:  insight.PreviousMerchant,
+			ChangePercent:     insight.ChangePercent,
+			Message:           insightMessage(insight),
+		})
+	}
+	if len(insights) > 0 {
+		resp.AverageChangePercent = total / float64(len(insights))
+	}
+	return resp, nil
+}
+
+func insightMessage(i *productDB.PriceInsight) string {
+	direction := "up"
+	if i.ChangePercent < 0 {
+		direction = "down"
+	}
+	msg := fmt.Sprintf("%s went %s %.0f%% since your last purchase on %s",
+		i.Item.Name, direction, math.Abs(i.ChangePercent), i.PreviousDate.Format("2 Jan 2006"))
+	if i.Merchant != "" && i.Merchant == i.PreviousMerchant {
+		msg += " at " + i.Merchant
+	}
+	return msg
+}
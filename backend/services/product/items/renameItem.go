@@ -0,0 +1,31 @@
+package items
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/item"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/pricing"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RenameItem changes the name one of the caller's items is shown under.
+func RenameItem(ctx context.Context, req *item.RenameItemRequest) (*item.Item, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := pricing.DisplayName(req.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "item name is required")
+	}
+
+	renamed, err := productDB.RenameItem(ctx, userId, req.GetItemId(), name)
+	if err != nil {
+		return nil, itemError(err)
+	}
+	return toItemMessage(renamed), nil
+}
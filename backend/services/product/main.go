@@ -11,10 +11,25 @@ import (
 	"syscall"
 	"time"
 	
+	"github.com/Aneesh-Hegde/expenseManager/analytics"
+	"github.com/Aneesh-Hegde/expenseManager/budget"
+	"github.com/Aneesh-Hegde/expenseManager/category"
+	"github.com/Aneesh-Hegde/expenseManager/imports"
+	"github.com/Aneesh-Hegde/expenseManager/item"
 	grpcMiddlware "github.com/Aneesh-Hegde/expenseManager/middleware"
 	"github.com/Aneesh-Hegde/expenseManager/product"
-	"github.com/Aneesh-Hegde/expenseManager/services/product/products"
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
+	"github.com/Aneesh-Hegde/expenseManager/rule"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/budgets"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/categories"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/items"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/products"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/recurringexpenses"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/reports"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/rules"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/statementimports"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
@@ -80,6 +95,287 @@ func (s *ProductService) GetProductsByUser(ctx context.Context, req *product.Get
 	return products.GetUserProduct(ctx, req)
 }
 
+func (s *ProductService) AddProduct(ctx context.Context, req *product.AddProductRequest) (*product.ProductResponse, error) {
+	return products.AddProduct(ctx, req)
+}
+
+func (s *ProductService) UpdateProduct(ctx context.Context, req *product.UpdateProductRequest) (*product.ProductResponse, error) {
+	return products.UpdateProduct(ctx, req)
+}
+
+func (s *ProductService) DeleteProduct(ctx context.Context, req *product.DeleteProductRequest) (*product.ProductResponse, error) {
+	return products.DeleteProduct(ctx, req)
+}
+
+func (s *ProductService) SearchExpenses(ctx context.Context, req *product.SearchExpensesRequest) (*product.SearchExpensesResponse, error) {
+	return products.SearchExpenses(ctx, req)
+}
+
+func (s *ProductService) ExportProducts(req *product.ExportProductsRequest, stream product.ProductService_ExportProductsServer) error {
+	return products.ExportProducts(req, stream)
+}
+
+func (s *ProductService) ListCustomFields(ctx context.Context, req *product.ListCustomFieldsRequest) (*product.CustomFieldList, error) {
+	return products.ListCustomFields(ctx, req)
+}
+
+func (s *ProductService) CreateCustomField(ctx context.Context, req *product.CreateCustomFieldRequest) (*product.CustomField, error) {
+	return products.CreateCustomField(ctx, req)
+}
+
+func (s *ProductService) UpdateCustomField(ctx context.Context, req *product.UpdateCustomFieldRequest) (*product.CustomField, error) {
+	return products.UpdateCustomField(ctx, req)
+}
+
+func (s *ProductService) DeleteCustomField(ctx context.Context, req *product.DeleteCustomFieldRequest) (*product.DeleteCustomFieldResponse, error) {
+	return products.DeleteCustomField(ctx, req)
+}
+
+func (s *ProductService) GetReceiptAnnotation(ctx context.Context, req *product.GetReceiptAnnotationRequest) (*product.ReceiptAnnotation, error) {
+	return products.GetReceiptAnnotation(ctx, req)
+}
+
+func (s *ProductService) UpdateReceiptAnnotation(ctx context.Context, req *product.UpdateReceiptAnnotationRequest) (*product.ReceiptAnnotation, error) {
+	return products.UpdateReceiptAnnotation(ctx, req)
+}
+
+func (s *ProductService) GetProductSplits(ctx context.Context, req *product.GetProductSplitsRequest) (*product.ProductSplits, error) {
+	return products.GetProductSplits(ctx, req)
+}
+
+func (s *ProductService) SetProductSplits(ctx context.Context, req *product.SetProductSplitsRequest) (*product.ProductSplits, error) {
+	return products.SetProductSplits(ctx, req)
+}
+
+func (s *ProductService) SplitReceipt(ctx context.Context, req *product.SplitReceiptRequest) (*product.SplitReceiptResponse, error) {
+	return products.SplitReceipt(ctx, req)
+}
+
+func (s *ProductService) CreateRefund(ctx context.Context, req *product.CreateRefundRequest) (*product.Refund, error) {
+	return products.CreateRefund(ctx, req)
+}
+
+func (s *ProductService) ListRefunds(ctx context.Context, req *product.ListRefundsRequest) (*product.RefundList, error) {
+	return products.ListRefunds(ctx, req)
+}
+
+func (s *ProductService) DeleteRefund(ctx context.Context, req *product.DeleteRefundRequest) (*product.DeleteRefundResponse, error) {
+	return products.DeleteRefund(ctx, req)
+}
+
+// CategoryService serves the user's category tree. It and the services
+// below run in the product service's process, on its schema, since
+// categories, rules and budgets all work on the products table.
+type CategoryService struct {
+	category.UnimplementedCategoryServiceServer
+}
+
+func (s *CategoryService) ListCategories(ctx context.Context, req *category.ListCategoriesRequest) (*category.CategoryList, error) {
+	return categories.ListCategories(ctx, req)
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, req *category.CreateCategoryRequest) (*category.Category, error) {
+	return categories.CreateCategory(ctx, req)
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, req *category.UpdateCategoryRequest) (*category.Category, error) {
+	return categories.UpdateCategory(ctx, req)
+}
+
+func (s *CategoryService) ArchiveCategory(ctx context.Context, req *category.ArchiveCategoryRequest) (*category.Category, error) {
+	return categories.ArchiveCategory(ctx, req)
+}
+
+func (s *CategoryService) MergeCategories(ctx context.Context, req *category.MergeCategoriesRequest) (*category.MergeCategoriesResponse, error) {
+	return categories.MergeCategories(ctx, req)
+}
+
+func (s *CategoryService) SuggestCategory(ctx context.Context, req *category.SuggestCategoryRequest) (*category.SuggestCategoryResponse, error) {
+	return categories.SuggestCategory(ctx, req)
+}
+
+func (s *CategoryService) GetClassifierStats(ctx context.Context, req *category.ClassifierStatsRequest) (*category.ClassifierStats, error) {
+	return categories.GetClassifierStats(ctx, req)
+}
+
+func (s *CategoryService) RetrainClassifier(ctx context.Context, req *category.RetrainClassifierRequest) (*category.ClassifierStats, error) {
+	return categories.RetrainClassifier(ctx, req)
+}
+
+// RuleService serves auto-categorisation rules.
+type RuleService struct {
+	rule.UnimplementedRuleServiceServer
+}
+
+func (s *RuleService) ListRules(ctx context.Context, req *rule.ListRulesRequest) (*rule.RuleList, error) {
+	return rules.ListRules(ctx, req)
+}
+
+func (s *RuleService) CreateRule(ctx context.Context, req *rule.RuleRequest) (*rule.Rule, error) {
+	return rules.CreateRule(ctx, req)
+}
+
+func (s *RuleService) UpdateRule(ctx context.Context, req *rule.RuleRequest) (*rule.Rule, error) {
+	return rules.UpdateRule(ctx, req)
+}
+
+func (s *RuleService) DeleteRule(ctx context.Context, req *rule.DeleteRuleRequest) (*rule.DeleteRuleResponse, error) {
+	return rules.DeleteRule(ctx, req)
+}
+
+func (s *RuleService) ReapplyRules(ctx context.Context, req *rule.ReapplyRulesRequest) (*rule.ReapplyRulesResponse, error) {
+	return rules.ReapplyRules(ctx, req)
+}
+
+// BudgetService serves budgets and their spend.
+type BudgetService struct {
+	budget.UnimplementedBudgetServiceServer
+}
+
+func (s *BudgetService) ListBudgets(ctx context.Context, req *budget.ListBudgetsRequest) (*budget.BudgetList, error) {
+	return budgets.ListBudgets(ctx, req)
+}
+
+func (s *BudgetService) CreateBudget(ctx context.Context, req *budget.BudgetRequest) (*budget.Budget, error) {
+	return budgets.CreateBudget(ctx, req)
+}
+
+func (s *BudgetService) UpdateBudget(ctx context.Context, req *budget.BudgetRequest) (*budget.Budget, error) {
+	return budgets.UpdateBudget(ctx, req)
+}
+
+func (s *BudgetService) DeleteBudget(ctx context.Context, req *budget.DeleteBudgetRequest) (*budget.DeleteBudgetResponse, error) {
+	return budgets.DeleteBudget(ctx, req)
+}
+
+func (s *BudgetService) GetBudgetStatus(ctx context.Context, req *budget.GetBudgetStatusRequest) (*budget.GetBudgetStatusResponse, error) {
+	return budgets.GetBudgetStatus(ctx, req)
+}
+
+// AnalyticsService serves spending reports.
+type AnalyticsService struct {
+	analytics.UnimplementedAnalyticsServiceServer
+}
+
+func (s *AnalyticsService) GetSpendingBreakdown(ctx context.Context, req *analytics.SpendingBreakdownRequest) (*analytics.SpendingBreakdown, error) {
+	return reports.GetSpendingBreakdown(ctx, req)
+}
+
+func (s *AnalyticsService) GetCashFlow(ctx context.Context, req *analytics.CashFlowRequest) (*analytics.CashFlow, error) {
+	return reports.GetCashFlow(ctx, req)
+}
+
+func (s *AnalyticsService) GetTopItems(ctx context.Context, req *analytics.TopItemsRequest) (*analytics.TopItems, error) {
+	return reports.GetTopItems(ctx, req)
+}
+
+func (s *AnalyticsService) ComparePeriods(ctx context.Context, req *analytics.ComparePeriodsRequest) (*analytics.PeriodComparison, error) {
+	return reports.ComparePeriods(ctx, req)
+}
+
+// ItemService serves the items that group purchases of the same thing.
+type ItemService struct {
+	item.UnimplementedItemServiceServer
+}
+
+func (s *ItemService) ListItems(ctx context.Context, req *item.ListItemsRequest) (*item.ItemList, error) {
+	return items.ListItems(ctx, req)
+}
+
+func (s *ItemService) RenameItem(ctx context.Context, req *item.RenameItemRequest) (*item.Item, error) {
+	return items.RenameItem(ctx, req)
+}
+
+func (s *ItemService) SuggestItemMerges(ctx context.Context, req *item.SuggestItemMergesRequest) (*item.ItemMergeSuggestions, error) {
+	return items.SuggestItemMerges(ctx, req)
+}
+
+func (s *ItemService) MergeItems(ctx context.Context, req *item.MergeItemsRequest) (*item.MergeItemsResponse, error) {
+	return items.MergeItems(ctx, req)
+}
+
+func (s *ItemService) DismissItemMerge(ctx context.Context, req *item.DismissItemMergeRequest) (*item.DismissItemMergeResponse, error) {
+	return items.DismissItemMerge(ctx, req)
+}
+
+func (s *ItemService) GetPriceHistory(ctx context.Context, req *item.GetPriceHistoryRequest) (*item.PriceHistory, error) {
+	return items.GetPriceHistory(ctx, req)
+}
+
+func (s *ItemService) GetPriceInsights(ctx context.Context, req *item.GetPriceInsightsRequest) (*item.PriceInsights, error) {
+	return items.GetPriceInsights(ctx, req)
+}
+
+// RecurringService serves recurring expenses and detected subscriptions.
+type RecurringService struct {
+	recurring.UnimplementedRecurringServiceServer
+}
+
+func (s *RecurringService) ListRecurringExpenses(ctx context.Context, req *recurring.ListRecurringExpensesRequest) (*recurring.RecurringExpenseList, error) {
+	return recurringexpenses.ListRecurringExpenses(ctx, req)
+}
+
+func (s *RecurringService) CreateRecurringExpense(ctx context.Context, req *recurring.RecurringExpenseRequest) (*recurring.RecurringExpense, error) {
+	return recurringexpenses.CreateRecurringExpense(ctx, req)
+}
+
+func (s *RecurringService) UpdateRecurringExpense(ctx context.Context, req *recurring.RecurringExpenseRequest) (*recurring.RecurringExpense, error) {
+	return recurringexpenses.UpdateRecurringExpense(ctx, req)
+}
+
+func (s *RecurringService) DeleteRecurringExpense(ctx context.Context, req *recurring.DeleteRecurringExpenseRequest) (*recurring.DeleteRecurringExpenseResponse, error) {
+	return recurringexpenses.DeleteRecurringExpense(ctx, req)
+}
+
+func (s *RecurringService) GetUpcomingExpenses(ctx context.Context, req *recurring.GetUpcomingExpensesRequest) (*recurring.UpcomingExpenses, error) {
+	return recurringexpenses.GetUpcomingExpenses(ctx, req)
+}
+
+func (s *RecurringService) DetectSubscriptions(ctx context.Context, req *recurring.DetectSubscriptionsRequest) (*recurring.SubscriptionSuggestions, error) {
+	return recurringexpenses.DetectSubscriptions(ctx, req)
+}
+
+func (s *RecurringService) DismissSubscription(ctx context.Context, req *recurring.DismissSubscriptionRequest) (*recurring.DismissSubscriptionResponse, error) {
+	return recurringexpenses.DismissSubscription(ctx, req)
+}
+
+// ImportService serves bank and card statement imports.
+type ImportService struct {
+	imports.UnimplementedImportServiceServer
+}
+
+func (s *ImportService) ListImportMappings(ctx context.Context, req *imports.ListImportMappingsRequest) (*imports.ImportMappingList, error) {
+	return statementimports.ListImportMappings(ctx, req)
+}
+
+func (s *ImportService) SaveImportMapping(ctx context.Context, req *imports.ImportMapping) (*imports.ImportMapping, error) {
+	return statementimports.SaveImportMapping(ctx, req)
+}
+
+func (s *ImportService) DeleteImportMapping(ctx context.Context, req *imports.DeleteImportMappingRequest) (*imports.DeleteImportMappingResponse, error) {
+	return statementimports.DeleteImportMapping(ctx, req)
+}
+
+func (s *ImportService) PreviewImport(ctx context.Context, req *imports.PreviewImportRequest) (*imports.ImportBatch, error) {
+	return statementimports.PreviewImport(ctx, req)
+}
+
+func (s *ImportService) CommitImport(ctx context.Context, req *imports.CommitImportRequest) (*imports.ImportBatch, error) {
+	return statementimports.CommitImport(ctx, req)
+}
+
+func (s *ImportService) UndoImport(ctx context.Context, req *imports.UndoImportRequest) (*imports.ImportBatch, error) {
+	return statementimports.UndoImport(ctx, req)
+}
+
+func (s *ImportService) GetImport(ctx context.Context, req *imports.GetImportRequest) (*imports.ImportBatch, error) {
+	return statementimports.GetImport(ctx, req)
+}
+
+func (s *ImportService) ListImports(ctx context.Context, req *imports.ListImportsRequest) (*imports.ImportBatchList, error) {
+	return statementimports.ListImports(ctx, req)
+}
+
 // Simplified metrics interceptor
 func metricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	start := time.Now()
@@ -122,6 +418,30 @@ func authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServe
 	return handler(newCtx, req)
 }
 
+// authStreamInterceptor authenticates streaming calls as authInterceptor
+// does unary ones.
+func authStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	fmt.Println("Authenticating:", info.FullMethod)
+
+	newCtx, err := grpcMiddlware.AuthInterceptor(ss.Context())
+	if err != nil {
+		log.Println("Authentication failed:", err)
+		return status.Error(codes.Unauthenticated, "Authentication required")
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: newCtx})
+}
+
+// authenticatedStream carries the context authStreamInterceptor built.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
 // Chain interceptors
 func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -176,6 +496,35 @@ func startMetricsCleaner() {
 	}()
 }
 
+// recurringInterval is how often due recurring expenses are recorded.
+const recurringInterval = time.Hour
+
+// Record recurring expenses as they fall due, once at startup and then on
+// every tick.
+func startRecurringScheduler() {
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		recorded, err := productDB.RecordDueRecurring(ctx, time.Now().UTC())
+		if err != nil {
+			log.Printf("Recording recurring expenses failed: %v", err)
+			return
+		}
+		if recorded > 0 {
+			log.Printf("Recorded %d recurring expenses", recorded)
+		}
+	}
+
+	ticker := time.NewTicker(recurringInterval)
+	go func() {
+		defer ticker.Stop()
+		run()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
+
 // Start metrics server
 func startMetricsServer() {
 	http.Handle("/metrics", promhttp.Handler())
@@ -230,6 +579,9 @@ func main() {
 	// Start metrics cleaner
 	startMetricsCleaner()
 
+	// Start recording recurring expenses
+	startRecurringScheduler()
+
 	// Setup gRPC server
 	listener, err := net.Listen("tcp", ":50054")
 	if err != nil {
@@ -239,10 +591,18 @@ func main() {
 	// Create gRPC server with interceptors
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(chainUnaryInterceptors(metricsInterceptor, authInterceptor)),
+		grpc.StreamInterceptor(authStreamInterceptor),
 	)
 
 	// Register services
 	product.RegisterProductServiceServer(grpcServer, &ProductService{})
+	category.RegisterCategoryServiceServer(grpcServer, &CategoryService{})
+	rule.RegisterRuleServiceServer(grpcServer, &RuleService{})
+	budget.RegisterBudgetServiceServer(grpcServer, &BudgetService{})
+	analytics.RegisterAnalyticsServiceServer(grpcServer, &AnalyticsService{})
+	item.RegisterItemServiceServer(grpcServer, &ItemService{})
+	recurring.RegisterRecurringServiceServer(grpcServer, &RecurringService{})
+	imports.RegisterImportServiceServer(grpcServer, &ImportService{})
 	reflection.Register(grpcServer)
 
 	// Setup graceful shutdown
@@ -0,0 +1,84 @@
+package pricing
+
+import "time"
+
+// Point is one purchase of an item.
+type Point struct {
+	Date      time.Time
+	UnitPrice float64
+	Quantity  int32
+	Merchant  string
+}
+
+// Summary describes how an item's unit price moved over a run of purchases.
+type Summary struct {
+	Purchases int
+	First     float64
+	Latest    float64
+	Min       float64
+	Max       float64
+	// Average is weighted by quantity.
+	Average float64
+	// ChangeSinceFirst and ChangeSinceLast are percentage changes of the
+	// latest price against the first purchase and against the purchase
+	// before it; nil when there is nothing to compare.
+	ChangeSinceFirst *float64
+	ChangeSinceLast  *float64
+}
+
+// Summarize describes points, which must be oldest first. Purchases on the
+// same day count as one when working out the change since the last one.
+func Summarize(points []Point) Summary {
+	var s Summary
+	if len(points) == 0 {
+		return s
+	}
+	s.Purchases = len(points)
+	s.First = points[0].UnitPrice
+	s.Latest = points[len(points)-1].UnitPrice
+	s.Min, s.Max = s.First, s.First
+
+	var spent float64
+	var units int64
+	for _, p := range points {
+		if p.UnitPrice < s.Min {
+			s.Min = p.UnitPrice
+		}
+		if p.UnitPrice > s.Max {
+			s.Max = p.UnitPrice
+		}
+		spent += p.UnitPrice * float64(p.Quantity)
+		units += int64(p.Quantity)
+	}
+	if units > 0 {
+		s.Average = spent / float64(units)
+	}
+
+	last := points[len(points)-1]
+	if len(points) > 1 && !sameDay(points[0].Date, last.Date) {
+		s.ChangeSinceFirst = Change(s.First, s.Latest)
+	}
+	for i := len(points) - 2; i >= 0; i-- {
+		if !sameDay(points[i].Date, last.Date) {
+			s.ChangeSinceLast = Change(points[i].UnitPrice, s.Latest)
+			break
+		}
+	}
+	return s
+}
+
+// Change is the percentage change from before to after, or nil if before is
+// not a price.
+func Change(before, after float64) *float64 {
+	if before <= 0 {
+		return nil
+	}
+	change := (after - before) / before * 100
+	return &change
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,63 @@
+// Package pricing groups product names into canonical items and summarises
+// how an item's unit price moves between purchases.
+package pricing
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MaxKeyLength matches the width of canonical_items.normalized_key.
+const MaxKeyLength = 255
+
+// sizeToken matches quantities and pack sizes such as 500g, 1kg, 330ml, 6pk
+// and x2, which vary between purchases of the same item.
+var sizeToken = regexp.MustCompile(`^(x?\d+|\d+x\d+)(g|gm|gms|gr|kg|mg|l|lt|ltr|ml|cl|oz|lb|lbs|pk|pc|pcs|ct|x)?$`)
+
+// unitWords are size units that appear on their own when the receipt puts a
+// space between the number and the unit.
+var unitWords = map[string]bool{
+	"g": true, "gm": true, "gms": true, "gr": true, "kg": true, "mg": true,
+	"l": true, "lt": true, "ltr": true, "ml": true, "cl": true, "oz": true,
+	"lb": true, "lbs": true, "pk": true, "pc": true, "pcs": true, "ct": true,
+	"x": true, "pack": true, "each": true, "ea": true,
+}
+
+// ItemKey folds a product name into the key its canonical item is stored
+// under: lower case, without sizes, quantities or punctuation, and with the
+// remaining words in a fixed order so "Milk Whole 1L" and "whole milk"
+// share a key. Names made up only of sizes keep all their words.
+func ItemKey(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var kept []string
+	seen := map[string]bool{}
+	for _, word := range words {
+		if sizeToken.MatchString(word) || unitWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		kept = append(kept, word)
+	}
+	if len(kept) == 0 {
+		kept = words
+	}
+	sort.Strings(kept)
+	key := strings.Join(kept, " ")
+	if runes := []rune(key); len(runes) > MaxKeyLength {
+		key = strings.TrimSpace(string(runes[:MaxKeyLength]))
+	}
+	return key
+}
+
+// DisplayName tidies a product name for use as an item's name.
+func DisplayName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > MaxKeyLength {
+		name = string(runes[:MaxKeyLength])
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AddProduct records a manually entered expense for the caller. Without a
+// category_id the caller's rules pick one, falling back to Uncategorized.
+func AddProduct(ctx context.Context, req *product.AddProductRequest) (*product.ProductResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "product name is required")
+	}
+	requested, err := money.Requested(float64(req.GetPrice()), req.GetPriceMoney())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// A price naming no currency is in the default one.
+	price, err := requested.In(requested.Currency())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if price.Minor < 0 {
+		return nil, status.Error(codes.InvalidArgument, "price must not be negative")
+	}
+	quantity := req.GetQuantity()
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+	date, err := parseProductDate(req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	newProduct := productDB.Product{
+		CategoryID:  req.GetCategoryId(),
+		ProductName: name,
+		Quantity:    quantity,
+		Price:       price,
+		DateAdded:   date,
+	}
+	if newProduct.CategoryID != 0 {
+		source := productDB.CategorySourceManual
+		newProduct.CategorySource = &source
+	}
+	if fileName := strings.TrimSpace(req.GetFileName()); fileName != "" {
+		newProduct.FileName = &fileName
+	}
+	if description := strings.TrimSpace(req.GetDescription()); description != "" {
+		newProduct.Description = &description
+	}
+	if newProduct.Tags, err = normalizeTags(req.GetTags()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if newProduct.Notes, err = normalizeNotes(req.GetNotes()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if newProduct.CustomFields, err = fromFieldValues(req.GetCustomFields()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	saved, err := productDB.InsertProduct(ctx, userId, newProduct)
+	if err != nil {
+		return nil, productError(err)
+	}
+
+	fileTotal := refreshFile(ctx, userId, saved.FileName)
+	return &product.ProductResponse{
+		Message:        fmt.Sprintf("Added %s", saved.ProductName),
+		Product:        toProductMessage(saved),
+		FileTotal:      fileTotal.GetValue(),
+		FileTotalMoney: fileTotal,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/fields"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/ruleengine"
+)
+
+const (
+	maxTags        = 20
+	maxNotesLength = 2000
+)
+
+// normalizeTags trims tags and drops blanks and duplicates.
+func normalizeTags(tags []string) ([]string, error) {
+	merged := ruleengine.MergeTags(nil, tags)
+	if len(merged) > maxTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxTags)
+	}
+	if merged == nil {
+		merged = []string{}
+	}
+	return merged, nil
+}
+
+// normalizeNotes trims notes; nil means no notes.
+func normalizeNotes(notes string) (*string, error) {
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		return nil, nil
+	}
+	if len([]rune(notes)) > maxNotesLength {
+		return nil, fmt.Errorf("notes must be at most %d characters", maxNotesLength)
+	}
+	return &notes, nil
+}
+
+// fromFieldValues converts request values to the form package fields checks.
+// Keys are matched case-insensitively.
+func fromFieldValues(values map[string]*product.FieldValue) (map[string]interface{}, error) {
+	converted := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		key = strings.ToLower(strings.TrimSpace(key))
+		switch kind := value.GetKind().(type) {
+		case *product.FieldValue_Text:
+			converted[key] = kind.Text
+		case *product.FieldValue_Number:
+			converted[key] = kind.Number
+		case *product.FieldValue_Boolean:
+			converted[key] = kind.Boolean
+		case *product.FieldValue_Date:
+			converted[key] = kind.Date
+		default:
+			return nil, fmt.Errorf("custom field %s has no value", key)
+		}
+	}
+	return converted, nil
+}
+
+// toFieldValues converts stored values for responses. Text, select and date
+// values are all returned as text.
+func toFieldValues(values map[string]interface{}) map[string]*product.FieldValue {
+	converted := make(map[string]*product.FieldValue, len(values))
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			converted[key] = &product.FieldValue{Kind: &product.FieldValue_Text{Text: v}}
+		case float64:
+			converted[key] = &product.FieldValue{Kind: &product.FieldValue_Number{Number: v}}
+		case bool:
+			converted[key] = &product.FieldValue{Kind: &product.FieldValue_Boolean{Boolean: v}}
+		}
+	}
+	return converted
+}
+
+// applyFieldChanges removes the cleared keys from current and sets values,
+// returning a new map.
+func applyFieldChanges(current map[string]interface{}, set map[string]*product.FieldValue, clear []string) (map[string]interface{}, error) {
+	values, err := fromFieldValues(set)
+	if err != nil {
+		return nil, err
+	}
+	updated := make(map[string]interface{}, len(current)+len(values))
+	for key, value := range current {
+		updated[key] = value
+	}
+	for _, key := range clear {
+		delete(updated, strings.ToLower(strings.TrimSpace(key)))
+	}
+	for key, value := range values {
+		updated[key] = value
+	}
+	return updated, nil
+}
+
+// checkFieldFilter normalises filter values the way stored values are, so
+// that select options and dates match however they were typed.
+func checkFieldFilter(ctx context.Context, userID string, values map[string]interface{}) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	list, err := productDB.ListCustomFields(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]fields.Definition, len(list))
+	for _, field := range list {
+		defs[field.Key] = field.Definition
+	}
+	checked, err := fields.CheckAll(defs, values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", productDB.ErrInvalidField, err)
+	}
+	return checked, nil
+}
@@ -0,0 +1,95 @@
+package products
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/fields"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toCustomFieldMessage(field *productDB.CustomField) *product.CustomField {
+	return &product.CustomField{
+		FieldId: field.FieldID,
+		Key:     field.Key,
+		Label:   field.Label,
+		Type:    field.Type,
+		Options: field.Options,
+	}
+}
+
+// ListCustomFields returns the caller's custom field definitions.
+func ListCustomFields(ctx context.Context, req *product.ListCustomFieldsRequest) (*product.CustomFieldList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := productDB.ListCustomFields(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &product.CustomFieldList{}
+	for i := range list {
+		resp.Fields = append(resp.Fields, toCustomFieldMessage(&list[i]))
+	}
+	return resp, nil
+}
+
+// CreateCustomField defines a new custom field for the caller.
+func CreateCustomField(ctx context.Context, req *product.CreateCustomFieldRequest) (*product.CustomField, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := productDB.CreateCustomField(ctx, userId, fields.Definition{
+		Key:     req.GetKey(),
+		Label:   req.GetLabel(),
+		Type:    req.GetType(),
+		Options: req.GetOptions(),
+	})
+	if err != nil {
+		return nil, productError(err)
+	}
+	return toCustomFieldMessage(field), nil
+}
+
+// UpdateCustomField relabels a field or changes a select field's options.
+func UpdateCustomField(ctx context.Context, req *product.UpdateCustomFieldRequest) (*product.CustomField, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFieldId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "field_id is required")
+	}
+
+	field, err := productDB.UpdateCustomField(ctx, userId, req.GetFieldId(), req.GetLabel(), req.GetOptions())
+	if err != nil {
+		return nil, productError(err)
+	}
+	return toCustomFieldMessage(field), nil
+}
+
+// DeleteCustomField removes a field and every value stored under it.
+func DeleteCustomField(ctx context.Context, req *product.DeleteCustomFieldRequest) (*product.DeleteCustomFieldResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFieldId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "field_id is required")
+	}
+
+	if err := productDB.DeleteCustomField(ctx, userId, req.GetFieldId()); err != nil {
+		return nil, productError(err)
+	}
+	return &product.DeleteCustomFieldResponse{
+		Message: fmt.Sprintf("Deleted custom field %d", req.GetFieldId()),
+	}, nil
+}
@@ -0,0 +1,31 @@
+package products
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// DeleteProduct removes one of the caller's products.
+func DeleteProduct(ctx context.Context, req *product.DeleteProductRequest) (*product.ProductResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := productDB.DeleteProduct(ctx, userId, req.GetProductId())
+	if err != nil {
+		return nil, productError(err)
+	}
+
+	fileTotal := refreshFile(ctx, userId, deleted.FileName)
+	return &product.ProductResponse{
+		Message:        fmt.Sprintf("Deleted %s", deleted.ProductName),
+		Product:        toProductMessage(deleted),
+		FileTotal:      fileTotal.GetValue(),
+		FileTotalMoney: fileTotal,
+	}, nil
+}
@@ -0,0 +1,201 @@
+package products
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/fields"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxExportRows bounds a single export.
+const maxExportRows = 50000
+
+// exportChunkRows is how many products each part of an export carries.
+const exportChunkRows = 1000
+
+// ExportProducts streams every product matching the filter as a file, in
+// parts of at most exportChunkRows products. Paging fields in the filter are
+// ignored.
+func ExportProducts(req *product.ExportProductsRequest, stream product.ProductService_ExportProductsServer) error {
+	ctx := stream.Context()
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(strings.TrimSpace(req.GetFormat()))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" {
+		return status.Error(codes.InvalidArgument, "format must be csv")
+	}
+
+	filter := req.GetFilter()
+	if filter == nil {
+		filter = &product.GetProductsByUserRequest{}
+	}
+	q, err := productQueryFromRequest(&product.GetProductsByUserRequest{
+		FromDate:     filter.GetFromDate(),
+		ToDate:       filter.GetToDate(),
+		CategoryIds:  filter.GetCategoryIds(),
+		MinAmount:    filter.MinAmount,
+		MaxAmount:    filter.MaxAmount,
+		FileName:     filter.GetFileName(),
+		Query:        filter.GetQuery(),
+		Sort:         filter.GetSort(),
+		Tags:         filter.GetTags(),
+		CustomFields: filter.GetCustomFields(),
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if q.CustomFields, err = checkFieldFilter(ctx, userId, q.CustomFields); err != nil {
+		return productError(err)
+	}
+	q.Limit = exportChunkRows
+
+	var w *productsCSV
+	exported := 0
+	for {
+		rows, customFields, total, err := productDB.ExportProducts(ctx, userId, q)
+		if err != nil {
+			log.Printf("Error exporting products for user %s: %v", userId, err)
+			return err
+		}
+
+		part := &product.ExportProductsResponse{RowCount: int32(len(rows))}
+		if w == nil {
+			if total > maxExportRows {
+				return status.Errorf(codes.FailedPrecondition,
+					"export is limited to %d products; narrow the filter", maxExportRows)
+			}
+			w = newProductsCSV(customFields)
+			part.FileName = fmt.Sprintf("expenses-%s.csv", time.Now().Format("2006-01-02"))
+			part.ContentType = "text/csv"
+		}
+		if part.Content, err = w.write(rows); err != nil {
+			return err
+		}
+		if err := stream.Send(part); err != nil {
+			return err
+		}
+		exported += len(rows)
+
+		if len(rows) < q.Limit {
+			break
+		}
+		last := rows[len(rows)-1]
+		q.After = &productDB.ProductCursor{
+			DateAdded:   last.DateAdded,
+			Amount:      last.LineTotal,
+			ProductName: last.ProductName,
+			ProductID:   last.ProductID,
+		}
+	}
+
+	log.Printf("Exported %d products for user %s", exported, userId)
+	return nil
+}
+
+// productsCSV writes an export a part at a time. The header goes out with
+// the first part.
+type productsCSV struct {
+	customFields []productDB.CustomField
+	header       []string
+	buf          bytes.Buffer
+	w            *csv.Writer
+}
+
+func newProductsCSV(customFields []productDB.CustomField) *productsCSV {
+	header := []string{"Date", "Product", "Description", "Category", "Quantity", "Currency", "Unit Price", "Total",
+		"Receipt", "Merchant", "Tags", "Notes", "Receipt Tags", "Receipt Notes"}
+	for _, field := range customFields {
+		header = append(header, csvText(field.Label))
+	}
+	c := &productsCSV{customFields: customFields, header: header}
+	c.w = csv.NewWriter(&c.buf)
+	return c
+}
+
+// write returns the CSV lines of rows, preceded by the header the first time.
+// Amounts are written with as many decimals as their currency has.
+func (c *productsCSV) write(rows []productDB.ExportRow) ([]byte, error) {
+	c.buf.Reset()
+	if c.header != nil {
+		if err := c.w.Write(c.header); err != nil {
+			return nil, fmt.Errorf("error writing export: %v", err)
+		}
+		c.header = nil
+	}
+
+	for _, row := range rows {
+		record := []string{
+			row.DateAdded.Format("2006-01-02"),
+			csvText(row.ProductName),
+			csvText(stringOrEmpty(row.Description)),
+			csvText(row.CategoryName),
+			strconv.Itoa(int(row.Quantity)),
+			row.Price.Currency,
+			row.Price.String(),
+			row.Price.Mul(float64(row.Quantity)).String(),
+			csvText(stringOrEmpty(row.FileName)),
+			csvText(row.Merchant),
+			csvText(strings.Join(row.Tags, "; ")),
+			csvText(stringOrEmpty(row.Notes)),
+		}
+		receiptFields := map[string]interface{}{}
+		if row.Receipt != nil {
+			record = append(record, csvText(strings.Join(row.Receipt.Tags, "; ")), csvText(stringOrEmpty(row.Receipt.Notes)))
+			receiptFields = row.Receipt.CustomFields
+		} else {
+			record = append(record, "", "")
+		}
+		for _, field := range c.customFields {
+			value, ok := row.CustomFields[field.Key]
+			if !ok {
+				value = receiptFields[field.Key]
+			}
+			cell := fields.Format(value)
+			if _, isText := value.(string); isText {
+				cell = csvText(cell)
+			}
+			record = append(record, cell)
+		}
+		if err := c.w.Write(record); err != nil {
+			return nil, fmt.Errorf("error writing export: %v", err)
+		}
+	}
+
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return nil, fmt.Errorf("error writing export: %v", err)
+	}
+	return append([]byte(nil), c.buf.Bytes()...), nil
+}
+
+// csvText keeps spreadsheets from running user text as a formula by
+// prefixing text that starts like one with a quote.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
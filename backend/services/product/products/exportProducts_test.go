@@ -0,0 +1,33 @@
+package products
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+func TestProductsCSVWritesAmountsInTheirCurrency(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := []productDB.ExportRow{
+		{Product: productDB.Product{ProductName: "Ramen", Quantity: 2, Price: money.New(850, "JPY"), DateAdded: date}},
+		{Product: productDB.Product{ProductName: "Coffee", Quantity: 3, Price: money.New(350, "USD"), DateAdded: date}},
+		{Product: productDB.Product{ProductName: "Dates", Quantity: 1, Price: money.New(1250, "KWD"), DateAdded: date}},
+	}
+	content, err := newProductsCSV(nil).write(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Date,Product,Description,Category,Quantity,Currency,Unit Price,Total,Receipt,Merchant,Tags,Notes,Receipt Tags,Receipt Notes",
+		"2024-03-01,Ramen,,,2,JPY,850,1700,,,,,,",
+		"2024-03-01,Coffee,,,3,USD,3.50,10.50,,,,,,",
+		"2024-03-01,Dates,,,1,KWD,1.250,1.250,,,,,,",
+	}
+	if got := string(content); got != strings.Join(want, "\n")+"\n" {
+		t.Errorf("export =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
@@ -2,43 +2,172 @@ package products
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
+	"time"
+
 	"github.com/Aneesh-Hegde/expenseManager/product"
 	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
-	"log"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/ruleengine"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func GetUserProduct(ctx context.Context, req *product.GetProductsByUserRequest) (*product.ProductsList, error) {
-	md, _ := metadata.FromIncomingContext(ctx)
+// maxPageSize caps page_size; page_size 0 still returns every match for
+// clients that predate paging.
+const maxPageSize = 500
+
+// productCursor is the opaque next_cursor handed to clients. It records the
+// sort it was issued for so it cannot be replayed against another ordering.
+type productCursor struct {
+	Sort      string    `json:"s"`
+	DateAdded time.Time `json:"d"`
+	Amount    float64   `json:"a"`
+	Name      string    `json:"n"`
+	ID        int32     `json:"i"`
+}
+
+func encodeCursor(sort string, last productDB.Product) string {
+	raw, _ := json.Marshal(productCursor{
+		Sort:      sort,
+		DateAdded: last.DateAdded,
+		Amount:    last.LineTotal,
+		Name:      last.ProductName,
+		ID:        last.ProductID,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor, sort string) (*productDB.ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	var c productCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if c.Sort != sort {
+		return nil, fmt.Errorf("cursor was issued for sort %q", c.Sort)
+	}
+	return &productDB.ProductCursor{DateAdded: c.DateAdded, Amount: c.Amount, ProductName: c.Name, ProductID: c.ID}, nil
+}
+
+// productQueryFromRequest validates the filters and turns them into a DB query.
+func productQueryFromRequest(req *product.GetProductsByUserRequest) (productDB.ProductQuery, error) {
+	q := productDB.ProductQuery{
+		CategoryIDs: req.GetCategoryIds(),
+		FileName:    strings.TrimSpace(req.GetFileName()),
+		NameQuery:   strings.TrimSpace(req.GetQuery()),
+		Tags:        ruleengine.MergeTags(nil, req.GetTags()),
+		Sort:        req.GetSort(),
+	}
+	if q.Sort == "" {
+		q.Sort = productDB.SortDateDesc
+	}
+	switch q.Sort {
+	case productDB.SortDateDesc, productDB.SortDateAsc, productDB.SortAmountDesc,
+		productDB.SortAmountAsc, productDB.SortNameAsc:
+	default:
+		return q, fmt.Errorf("unknown sort %q", q.Sort)
+	}
 
-	if len(md["token"]) > 0 && len(md["token"][0]) > 0 {
-		headers := metadata.Pairs("token", md["token"][0])
-		grpc.SendHeader(ctx, headers)
+	if req.GetPageSize() < 0 {
+		return q, fmt.Errorf("page_size must not be negative")
+	}
+	q.Limit = int(req.GetPageSize())
+	if q.Limit > maxPageSize {
+		q.Limit = maxPageSize
 	}
 
-	userId := md["user_id"][0]
+	if req.GetFromDate() != "" {
+		from, err := time.Parse("2006-01-02", req.GetFromDate())
+		if err != nil {
+			return q, fmt.Errorf("from_date must be YYYY-MM-DD")
+		}
+		q.From = &from
+	}
+	if req.GetToDate() != "" {
+		to, err := time.Parse("2006-01-02", req.GetToDate())
+		if err != nil {
+			return q, fmt.Errorf("to_date must be YYYY-MM-DD")
+		}
+		// Inclusive: everything before the start of the following day.
+		to = to.AddDate(0, 0, 1)
+		q.To = &to
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return q, fmt.Errorf("from_date must not be after to_date")
+	}
 
-	productResults, err := productDB.GetUserProducts(ctx, userId)
+	if req.MinAmount != nil {
+		min := float64(req.GetMinAmount())
+		q.MinAmount = &min
+	}
+	if req.MaxAmount != nil {
+		max := float64(req.GetMaxAmount())
+		q.MaxAmount = &max
+	}
+	if q.MinAmount != nil && q.MaxAmount != nil && *q.MinAmount > *q.MaxAmount {
+		return q, fmt.Errorf("min_amount must not exceed max_amount")
+	}
+
+	if len(req.GetCustomFields()) > 0 {
+		values, err := fromFieldValues(req.GetCustomFields())
+		if err != nil {
+			return q, err
+		}
+		q.CustomFields = values
+	}
+
+	if req.GetCursor() != "" {
+		after, err := decodeCursor(req.GetCursor(), q.Sort)
+		if err != nil {
+			return q, err
+		}
+		q.After = after
+	}
+	return q, nil
+}
+
+func GetUserProduct(ctx context.Context, req *product.GetProductsByUserRequest) (*product.ProductsList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := productQueryFromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if q.CustomFields, err = checkFieldFilter(ctx, userId, q.CustomFields); err != nil {
+		return nil, productError(err)
+	}
+
+	page, err := productDB.ListProducts(ctx, userId, q)
 	if err != nil {
 		log.Printf("Error fetching products for user %s: %v", userId, err)
 		return nil, err
 	}
 
-	var products product.ProductsList
-	for _, result := range productResults {
-		product := &product.Product{
-			ProductName: result.ProductName,
-			Amount:      float32(result.Price),
-			Category:    result.CategoryName,
-			Quantity:    float32(result.Quantity),
-			Date:        result.DateAdded.Format("2006-01-02"),
-		}
-		products.Products = append(products.Products, product)
-		fmt.Printf("Product: %s | Quantity: %d | Date: %v | Price: %.2f | Category: %s\n",
-			result.ProductName, result.Quantity, result.DateAdded, result.Price, result.CategoryName)
+	total := money.MessageOf(page.TotalAmount, page.BaseCurrency)
+	products := product.ProductsList{
+		TotalCount:       int32(page.TotalCount),
+		TotalAmount:      total.GetValue(),
+		TotalAmountMoney: total,
+	}
+	for i := range page.Products {
+		products.Products = append(products.Products, toProductMessage(&page.Products[i]))
+	}
+	if q.Limit > 0 && len(page.Products) == q.Limit {
+		products.NextCursor = encodeCursor(q.Sort, page.Products[len(page.Products)-1])
 	}
 
+	log.Printf("Returned %d of %d products for user %s", len(products.Products), page.TotalCount, userId)
 	return &products, nil
 }
@@ -0,0 +1,120 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/grpc_money"
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	"github.com/Aneesh-Hegde/expenseManager/redis"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/fx"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseProductDate accepts the ISO dates the dashboard sends and the
+// DD/MM/YYYY dates receipts are extracted with.
+func parseProductDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Now(), nil
+	}
+	for _, layout := range []string{"2006-01-02", "02/01/2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date must be YYYY-MM-DD or DD/MM/YYYY")
+}
+
+func toProductMessage(p *productDB.Product) *product.Product {
+	msg := &product.Product{
+		ProductId:   p.ProductID,
+		ProductName: p.ProductName,
+		Quantity:    float32(p.Quantity),
+		Amount:      float32(p.Price.Float64()),
+		Date:        p.DateAdded.Format("2006-01-02"),
+		Category:    p.CategoryName,
+		CategoryId:  p.CategoryID,
+		Tags:        p.Tags,
+		Split:       p.Split,
+		PriceMoney:  p.Price.Message(),
+		TotalMoney:  p.Price.Mul(float64(p.Quantity)).Message(),
+	}
+	if p.ExchangeRate != nil {
+		msg.ExchangeRate = *p.ExchangeRate
+	}
+	if p.FileName != nil {
+		msg.FileName = *p.FileName
+	}
+	if p.Description != nil {
+		msg.Description = *p.Description
+	}
+	if p.Notes != nil {
+		msg.Notes = *p.Notes
+	}
+	if p.CategorySource != nil {
+		msg.CategorySource = *p.CategorySource
+	}
+	if p.ItemID != nil {
+		msg.ItemId = *p.ItemID
+	}
+	if len(p.CustomFields) > 0 {
+		msg.CustomFields = toFieldValues(p.CustomFields)
+	}
+	return msg
+}
+
+// refreshFile drops the cached extraction for a receipt whose products
+// changed and returns the receipt's recomputed total. Manual entries without a
+// receipt have no cache entry and no total.
+func refreshFile(ctx context.Context, userID string, fileName *string) *grpc_money.Money {
+	if fileName == nil || *fileName == "" {
+		return nil
+	}
+
+	userIDInt, err := strconv.Atoi(userID)
+	if err == nil {
+		if err := redis.DeleteCachedProductData(userIDInt, *fileName); err != nil {
+			log.Printf("Warning: could not clear cache for %s: %v", *fileName, err)
+		}
+	}
+
+	total, currency, err := productDB.GetFileTotal(ctx, userID, *fileName)
+	if err != nil {
+		log.Printf("Warning: could not recompute total for %s: %v", *fileName, err)
+		return nil
+	}
+	return money.MessageOf(total, currency)
+}
+
+// productError reports what a product refers to being gone as NotFound,
+// and changes its state forbids, such as deleting a refunded product, as
+// FailedPrecondition.
+func productError(err error) error {
+	switch {
+	case errors.Is(err, productDB.ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrCategoryNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, productDB.ErrCategoryArchived):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, productDB.ErrFieldNotFound), errors.Is(err, productDB.ErrReceiptNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrInvalidField), errors.Is(err, productDB.ErrInvalidSplit),
+		errors.Is(err, productDB.ErrInvalidRefund):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, productDB.ErrRefundNotFound), errors.Is(err, productDB.ErrBalanceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case fx.IsNoRate(err), errors.Is(err, productDB.ErrProductRefunded):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package products
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toReceiptAnnotationMessage(a *productDB.ReceiptAnnotation) *product.ReceiptAnnotation {
+	msg := &product.ReceiptAnnotation{
+		FileName:     a.FileName,
+		Tags:         a.Tags,
+		CustomFields: toFieldValues(a.CustomFields),
+	}
+	if a.Notes != nil {
+		msg.Notes = *a.Notes
+	}
+	if a.UpdatedAt != nil {
+		msg.UpdatedAt = a.UpdatedAt.Format(time.RFC3339)
+	}
+	return msg
+}
+
+// GetReceiptAnnotation returns the tags, notes and custom field values of
+// one of the caller's receipts.
+func GetReceiptAnnotation(ctx context.Context, req *product.GetReceiptAnnotationRequest) (*product.ReceiptAnnotation, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fileName := strings.TrimSpace(req.GetFileName())
+	if fileName == "" {
+		return nil, status.Error(codes.InvalidArgument, "file_name is required")
+	}
+
+	annotation, err := productDB.GetReceiptAnnotation(ctx, userId, fileName)
+	if err != nil {
+		return nil, productError(err)
+	}
+	return toReceiptAnnotationMessage(annotation), nil
+}
+
+// UpdateReceiptAnnotation changes the given parts of a receipt's annotation.
+// Products on the receipt pick up its custom field values in filters and
+// exports unless they set their own.
+func UpdateReceiptAnnotation(ctx context.Context, req *product.UpdateReceiptAnnotationRequest) (*product.ReceiptAnnotation, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fileName := strings.TrimSpace(req.GetFileName())
+	if fileName == "" {
+		return nil, status.Error(codes.InvalidArgument, "file_name is required")
+	}
+
+	var update productDB.ReceiptAnnotationUpdate
+	if req.Tags != nil {
+		tags, err := normalizeTags(req.GetTags().GetTags())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		update.Tags = &tags
+	}
+	if req.Notes != nil {
+		notes, err := normalizeNotes(req.GetNotes())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// An empty string clears the notes.
+		if notes == nil {
+			notes = new(string)
+		}
+		update.Notes = notes
+	}
+	if update.CustomFields, err = fromFieldValues(req.GetCustomFields()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	for _, key := range req.GetClearCustomFields() {
+		update.ClearFields = append(update.ClearFields, strings.ToLower(strings.TrimSpace(key)))
+	}
+
+	annotation, err := productDB.UpdateReceiptAnnotation(ctx, userId, fileName, update)
+	if err != nil {
+		return nil, productError(err)
+	}
+	return toReceiptAnnotationMessage(annotation), nil
+}
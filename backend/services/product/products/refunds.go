@@ -0,0 +1,156 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toRefundMessage(r *productDB.Refund) *product.Refund {
+	msg := &product.Refund{
+		RefundId:    r.RefundID,
+		Amount:      r.Amount.Float64(),
+		AmountMoney: r.Amount.Message(),
+		Date:        r.Date.Format("2006-01-02"),
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+	}
+	if r.ProductID != nil {
+		msg.ProductId = *r.ProductID
+	}
+	if r.FileName != nil {
+		msg.FileName = *r.FileName
+	}
+	if r.BalanceID != nil {
+		msg.BalanceId = *r.BalanceID
+	}
+	if r.Reason != nil {
+		msg.Reason = *r.Reason
+	}
+	for _, item := range r.Items {
+		msg.Items = append(msg.Items, &product.RefundItem{
+			ProductId:   item.ProductID,
+			ProductName: item.ProductName,
+			CategoryId:  item.CategoryID,
+			Category:    item.CategoryName,
+			Quantity:    item.Quantity,
+			Amount:      item.Amount.Float64(),
+			AmountMoney: item.Amount.Message(),
+		})
+	}
+	return msg
+}
+
+// CreateRefund records money back for one of the caller's products or
+// receipts, crediting the balance account the request names.
+func CreateRefund(ctx context.Context, req *product.CreateRefundRequest) (*product.Refund, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := strings.TrimSpace(req.GetFileName())
+	if req.GetProductId() == 0 && fileName == "" {
+		return nil, status.Error(codes.InvalidArgument, "product_id or file_name is required")
+	}
+	if req.GetProductId() != 0 && fileName != "" {
+		return nil, status.Error(codes.InvalidArgument, "set product_id or file_name, not both")
+	}
+	date, err := parseProductDate(req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	amount, err := money.Requested(req.GetAmount(), req.GetAmountMoney())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	refund := productDB.RefundRequest{
+		ProductID: req.GetProductId(),
+		FileName:  fileName,
+		Quantity:  req.GetQuantity(),
+		Amount:    amount,
+		BalanceID: req.GetBalanceId(),
+		Date:      date,
+	}
+	if reason := strings.TrimSpace(req.GetReason()); reason != "" {
+		refund.Reason = &reason
+	}
+
+	created, err := productDB.CreateRefund(ctx, userId, refund)
+	if err != nil {
+		return nil, productError(err)
+	}
+	return toRefundMessage(created), nil
+}
+
+// ListRefunds returns the caller's refunds, optionally only those covering
+// one product or receipt, or dated within a range.
+func ListRefunds(ctx context.Context, req *product.ListRefundsRequest) (*product.RefundList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := productDB.RefundQuery{
+		ProductID: req.GetProductId(),
+		FileName:  strings.TrimSpace(req.GetFileName()),
+	}
+	if req.GetFromDate() != "" {
+		from, err := time.Parse("2006-01-02", req.GetFromDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "from_date must be YYYY-MM-DD")
+		}
+		q.From = &from
+	}
+	if req.GetToDate() != "" {
+		to, err := time.Parse("2006-01-02", req.GetToDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "to_date must be YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+		q.To = &to
+	}
+
+	refunds, err := productDB.ListRefunds(ctx, userId, q)
+	if err != nil {
+		return nil, productError(err)
+	}
+	resp := &product.RefundList{}
+	total := money.Zero(money.DefaultCurrency)
+	for i := range refunds {
+		resp.Refunds = append(resp.Refunds, toRefundMessage(&refunds[i]))
+		if i == 0 {
+			total = money.Zero(refunds[i].BaseCurrency)
+		}
+		if total, err = total.Add(money.FromFloat(refunds[i].BaseAmount, refunds[i].BaseCurrency)); err != nil {
+			return nil, err
+		}
+	}
+	resp.Total = total.Float64()
+	resp.TotalMoney = total.Message()
+	return resp, nil
+}
+
+// DeleteRefund removes one of the caller's refunds and takes the amount back
+// off the balance account it credited.
+func DeleteRefund(ctx context.Context, req *product.DeleteRefundRequest) (*product.DeleteRefundResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := productDB.DeleteRefund(ctx, userId, req.GetRefundId())
+	if err != nil {
+		return nil, productError(err)
+	}
+	return &product.DeleteRefundResponse{
+		Message: fmt.Sprintf("Deleted refund of %s %s", deleted.Amount, deleted.Amount.Currency),
+	}, nil
+}
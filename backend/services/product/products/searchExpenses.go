@@ -0,0 +1,82 @@
+package products
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchExpenses finds the caller's products by name, description, merchant or
+// receipt text, tolerating OCR misspellings.
+func SearchExpenses(ctx context.Context, req *product.SearchExpensesRequest) (*product.SearchExpensesResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	var from, to *time.Time
+	if req.GetFromDate() != "" {
+		date, err := time.Parse("2006-01-02", req.GetFromDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "from_date must be YYYY-MM-DD")
+		}
+		from = &date
+	}
+	if req.GetToDate() != "" {
+		date, err := time.Parse("2006-01-02", req.GetToDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "to_date must be YYYY-MM-DD")
+		}
+		date = date.AddDate(0, 0, 1)
+		to = &date
+	}
+
+	results, err := productDB.SearchProducts(ctx, userId, query, from, to, limit)
+	if err != nil {
+		log.Printf("Search for user %s failed: %v", userId, err)
+		return nil, err
+	}
+
+	response := &product.SearchExpensesResponse{}
+	for i := range results {
+		result := &results[i]
+		hit := &product.SearchHit{
+			Product:                toProductMessage(&result.Product),
+			Rank:                   float32(result.Rank),
+			HighlightedName:        result.HighlightedName,
+			HighlightedDescription: result.HighlightedDescription,
+			TextSnippet:            result.TextSnippet,
+			MatchedFields:          result.MatchedFields,
+		}
+		if result.MerchantName != nil {
+			hit.MerchantName = *result.MerchantName
+		}
+		response.Hits = append(response.Hits, hit)
+	}
+	return response, nil
+}
@@ -0,0 +1,138 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/splitting"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toAllocations(requested []*product.SplitAllocation) []splitting.Allocation {
+	allocations := make([]splitting.Allocation, 0, len(requested))
+	for _, r := range requested {
+		a := splitting.Allocation{
+			Participant: r.GetParticipant(),
+			Note:        r.GetNote(),
+		}
+		if r.GetCategoryId() != 0 {
+			categoryID := r.GetCategoryId()
+			a.CategoryID = &categoryID
+		}
+		if r.GetParticipantUserId() != 0 {
+			userID := r.GetParticipantUserId()
+			a.ParticipantUserID = &userID
+		}
+		switch share := r.GetShare().(type) {
+		case *product.SplitAllocation_Amount:
+			a.Amount = &share.Amount
+		case *product.SplitAllocation_Percent:
+			a.Percent = &share.Percent
+		}
+		allocations = append(allocations, a)
+	}
+	return allocations
+}
+
+func toProductSplits(p *productDB.Product, splits []productDB.ProductSplit) *product.ProductSplits {
+	resp := &product.ProductSplits{
+		Product: toProductMessage(p),
+		Total:   p.LineTotal,
+	}
+	if len(splits) == 0 {
+		resp.OwnAmount = p.LineTotal
+		return resp
+	}
+
+	shares := make([]float64, len(splits))
+	for i, s := range splits {
+		shares[i] = s.Share
+	}
+	amounts := splitting.Amounts(p.LineTotal, shares)
+	for i, s := range splits {
+		msg := &product.ProductSplit{
+			SplitId:  s.SplitID,
+			Category: s.CategoryName,
+			Amount:   amounts[i],
+			Percent:  s.Share * 100,
+		}
+		if s.CategoryID != nil {
+			msg.CategoryId = *s.CategoryID
+		}
+		if s.Participant != nil {
+			msg.Participant = *s.Participant
+		}
+		if s.ParticipantUserID != nil {
+			msg.ParticipantUserId = *s.ParticipantUserID
+		}
+		if s.Note != nil {
+			msg.Note = *s.Note
+		}
+		if s.Shared() {
+			resp.SharedAmount += amounts[i]
+		} else {
+			resp.OwnAmount += amounts[i]
+		}
+		resp.Splits = append(resp.Splits, msg)
+	}
+	return resp
+}
+
+// GetProductSplits returns how one of the caller's products is split.
+func GetProductSplits(ctx context.Context, req *product.GetProductSplitsRequest) (*product.ProductSplits, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, splits, err := productDB.GetProductSplits(ctx, userId, req.GetProductId())
+	if err != nil {
+		return nil, productError(err)
+	}
+	return toProductSplits(p, splits), nil
+}
+
+// SetProductSplits replaces the splits of one of the caller's products.
+func SetProductSplits(ctx context.Context, req *product.SetProductSplitsRequest) (*product.ProductSplits, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, splits, err := productDB.SetProductSplits(ctx, userId, req.GetProductId(), toAllocations(req.GetAllocations()))
+	if err != nil {
+		return nil, productError(err)
+	}
+	return toProductSplits(p, splits), nil
+}
+
+// SplitReceipt splits every product on one of the caller's receipts in the
+// same proportions.
+func SplitReceipt(ctx context.Context, req *product.SplitReceiptRequest) (*product.SplitReceiptResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fileName := strings.TrimSpace(req.GetFileName())
+	if fileName == "" {
+		return nil, status.Error(codes.InvalidArgument, "file_name is required")
+	}
+
+	count, err := productDB.SplitReceipt(ctx, userId, fileName, toAllocations(req.GetAllocations()))
+	if err != nil {
+		return nil, productError(err)
+	}
+	message := fmt.Sprintf("Split %d products on %s", count, fileName)
+	if len(req.GetAllocations()) == 0 {
+		message = fmt.Sprintf("Removed splits from %d products on %s", count, fileName)
+	}
+	return &product.SplitReceiptResponse{
+		Message:       message,
+		ProductsSplit: int32(count),
+	}, nil
+}
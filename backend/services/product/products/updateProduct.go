@@ -0,0 +1,103 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/product"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpdateProduct edits one of the caller's products. Name, description and
+// price are replaced; category, quantity, date, tags and notes only change
+// when given, and custom fields are set or cleared by key. Rules run again
+// over the edited product.
+func UpdateProduct(ctx context.Context, req *product.UpdateProductRequest) (*product.ProductResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "product name is required")
+	}
+	requested, err := money.Requested(float64(req.GetPrice()), req.GetPriceMoney())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if requested.Sign() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "price must not be negative")
+	}
+
+	current, err := productDB.GetProduct(ctx, productDB.Pool(), userId, req.GetProductId())
+	if err != nil {
+		return nil, productError(err)
+	}
+
+	updated := *current
+	updated.ProductName = name
+	// A price naming no currency stays in the product's.
+	currency := requested.Currency()
+	if currency == "" {
+		currency = current.Price.Currency
+	}
+	if updated.Price, err = requested.In(currency); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	updated.Description = nil
+	if description := strings.TrimSpace(req.GetDescription()); description != "" {
+		updated.Description = &description
+	}
+	if req.CategoryId != nil {
+		// A category picked by hand is kept when rules run again.
+		source := productDB.CategorySourceManual
+		updated.CategoryID = req.GetCategoryId()
+		updated.CategorySource = &source
+	}
+	if req.Quantity != nil {
+		if req.GetQuantity() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+		}
+		updated.Quantity = req.GetQuantity()
+	}
+	if req.Date != nil {
+		date, err := parseProductDate(req.GetDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		updated.DateAdded = date
+	}
+	if req.Tags != nil {
+		if updated.Tags, err = normalizeTags(req.GetTags().GetTags()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if req.Notes != nil {
+		if updated.Notes, err = normalizeNotes(req.GetNotes()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	updated.CustomFields, err = applyFieldChanges(current.CustomFields, req.GetCustomFields(), req.GetClearCustomFields())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	before, after, err := productDB.UpdateProduct(ctx, userId, updated)
+	if err != nil {
+		return nil, productError(err)
+	}
+
+	fileTotal := refreshFile(ctx, userId, before.FileName)
+	return &product.ProductResponse{
+		Message:        fmt.Sprintf("Updated %s", after.ProductName),
+		Product:        toProductMessage(after),
+		FileTotal:      fileTotal.GetValue(),
+		FileTotalMoney: fileTotal,
+	}, nil
+}
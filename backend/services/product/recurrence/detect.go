@@ -0,0 +1,200 @@
+package recurrence
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AmountTolerance is how far apart, as a fraction, charges can be and still
+// count as the same amount; subscriptions change price now and then.
+const AmountTolerance = 0.05
+
+// MinRegularity is the share of gaps between charges that must match a
+// cadence for the charges to count as recurring.
+const MinRegularity = 0.75
+
+// Charge is one payment in a user's history.
+type Charge struct {
+	Merchant string
+	Amount   float64
+	Date     time.Time
+}
+
+// Candidate is a run of charges that looks like a subscription.
+type Candidate struct {
+	// Merchant is spelled as on the latest charge; Key is the folded form
+	// charges were matched on.
+	Merchant string
+	Key      string
+	// Amount is the latest charge.
+	Amount       float64
+	Frequency    string
+	Interval     int
+	Charges      int
+	First        time.Time
+	Last         time.Time
+	NextExpected time.Time
+	// Confidence grows with how regular and how long the run is, from 0
+	// to 1.
+	Confidence float64
+}
+
+// cadence is a repeat period charges are matched against, with the slack
+// allowed either side in days.
+type cadence struct {
+	frequency  string
+	interval   int
+	days       float64
+	slack      float64
+	minCharges int
+}
+
+var cadences = []cadence{
+	{Weekly, 1, 7, 1, 4},
+	{Weekly, 2, 14, 2, 3},
+	{Monthly, 1, 30.44, 4, 3},
+	{Monthly, 3, 91.31, 8, 3},
+	{Monthly, 6, 182.62, 10, 3},
+	{Yearly, 1, 365.25, 14, 2},
+}
+
+// MerchantKey folds a merchant name so spellings differing only in case or
+// spacing match.
+func MerchantKey(merchant string) string {
+	return strings.ToLower(strings.Join(strings.Fields(merchant), " "))
+}
+
+// Detect finds runs of charges from the same merchant for about the same
+// amount at a steady cadence that are still going at now. Candidates are
+// returned most confident first.
+func Detect(charges []Charge, now time.Time) []Candidate {
+	byMerchant := map[string][]Charge{}
+	for _, c := range charges {
+		key := MerchantKey(c.Merchant)
+		if key == "" || c.Amount <= 0 {
+			continue
+		}
+		byMerchant[key] = append(byMerchant[key], c)
+	}
+
+	var candidates []Candidate
+	for key, list := range byMerchant {
+		for _, run := range clusterAmounts(list) {
+			if candidate, ok := detectRun(key, run, now); ok {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		if candidates[i].Key != candidates[j].Key {
+			return candidates[i].Key < candidates[j].Key
+		}
+		return candidates[i].Amount < candidates[j].Amount
+	})
+	return candidates
+}
+
+// clusterAmounts splits one merchant's charges into runs of about the same
+// amount, so a monthly subscription and the odd one-off purchase from the
+// same shop are told apart.
+func clusterAmounts(charges []Charge) [][]Charge {
+	sort.Slice(charges, func(i, j int) bool { return charges[i].Amount < charges[j].Amount })
+	var runs [][]Charge
+	var run []Charge
+	for _, c := range charges {
+		if len(run) > 0 && c.Amount > run[0].Amount*(1+AmountTolerance) {
+			runs = append(runs, run)
+			run = nil
+		}
+		run = append(run, c)
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+func detectRun(key string, run []Charge, now time.Time) (Candidate, bool) {
+	sort.Slice(run, func(i, j int) bool { return run[i].Date.Before(run[j].Date) })
+	// Several charges on one day are one payment as far as cadence goes.
+	var days []time.Time
+	for _, c := range run {
+		day := Day(c.Date)
+		if len(days) == 0 || !days[len(days)-1].Equal(day) {
+			days = append(days, day)
+		}
+	}
+	if len(days) < 2 {
+		return Candidate{}, false
+	}
+
+	var best *cadence
+	var bestFit float64
+	for i := range cadences {
+		c := &cadences[i]
+		if len(days) < c.minCharges {
+			continue
+		}
+		matched := 0
+		for j := 1; j < len(days); j++ {
+			gap := days[j].Sub(days[j-1]).Hours() / 24
+			if math.Abs(gap-c.days) <= c.slack {
+				matched++
+			}
+		}
+		fit := float64(matched) / float64(len(days)-1)
+		if fit > bestFit {
+			best, bestFit = c, fit
+		}
+	}
+	if best == nil || bestFit < MinRegularity {
+		return Candidate{}, false
+	}
+
+	last := days[len(days)-1]
+	// A run that has missed more than one charge has probably been
+	// cancelled.
+	if Day(now).Sub(last).Hours()/24 > best.days*1.5+best.slack {
+		return Candidate{}, false
+	}
+
+	latest := run[len(run)-1]
+	next, _ := Schedule{Frequency: best.frequency, Interval: best.interval, Start: last}.Occurrence(1)
+	return Candidate{
+		Merchant:     strings.TrimSpace(latest.Merchant),
+		Key:          key,
+		Amount:       latest.Amount,
+		Frequency:    best.frequency,
+		Interval:     best.interval,
+		Charges:      len(days),
+		First:        days[0],
+		Last:         last,
+		NextExpected: next,
+		Confidence:   bestFit * math.Min(1, float64(len(days))/float64(best.minCharges+2)),
+	}, true
+}
+
+// MonthlyCost spreads one charge of amount at the given cadence over a
+// month, to compare subscriptions billed at different intervals.
+func MonthlyCost(amount float64, frequency string, interval int) float64 {
+	if interval < 1 {
+		interval = 1
+	}
+	var perYear float64
+	switch frequency {
+	case Daily:
+		perYear = 365.25
+	case Weekly:
+		perYear = 365.25 / 7
+	case Monthly:
+		perYear = 12
+	case Yearly:
+		perYear = 1
+	}
+	return amount * perYear / float64(interval) / 12
+}
@@ -0,0 +1,102 @@
+// Package recurrence works out when recurring expenses fall due, and spots
+// charges in a user's history that look like they recur.
+package recurrence
+
+import (
+	"fmt"
+	"time"
+)
+
+// Frequencies a schedule can repeat at.
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+)
+
+// MaxInterval bounds how many periods apart occurrences can be.
+const MaxInterval = 365
+
+// Schedule repeats every Interval periods of Frequency from Start, until End
+// when it is set. Dates are whole days in UTC.
+type Schedule struct {
+	Frequency string
+	Interval  int
+	Start     time.Time
+	End       *time.Time
+}
+
+// Validate checks the schedule can be followed.
+func (s Schedule) Validate() error {
+	switch s.Frequency {
+	case Daily, Weekly, Monthly, Yearly:
+	default:
+		return fmt.Errorf("frequency must be daily, weekly, monthly or yearly")
+	}
+	if s.Interval < 1 || s.Interval > MaxInterval {
+		return fmt.Errorf("interval must be between 1 and %d", MaxInterval)
+	}
+	if s.Start.IsZero() {
+		return fmt.Errorf("a start date is required")
+	}
+	if s.End != nil && s.End.Before(Day(s.Start)) {
+		return fmt.Errorf("the end date is before the start date")
+	}
+	return nil
+}
+
+// Occurrence returns the date of the n-th occurrence, counting the start as
+// 0, and whether the schedule still runs then. Monthly and yearly schedules
+// keep to the start's day of the month, falling back to the last day of
+// shorter months, so a schedule starting on the 31st does not drift.
+func (s Schedule) Occurrence(n int) (time.Time, bool) {
+	start := Day(s.Start)
+	var date time.Time
+	switch s.Frequency {
+	case Daily:
+		date = start.AddDate(0, 0, n*s.Interval)
+	case Weekly:
+		date = start.AddDate(0, 0, 7*n*s.Interval)
+	case Monthly:
+		date = addMonths(start, n*s.Interval)
+	case Yearly:
+		date = addMonths(start, 12*n*s.Interval)
+	default:
+		return time.Time{}, false
+	}
+	if s.End != nil && date.After(Day(*s.End)) {
+		return date, false
+	}
+	return date, true
+}
+
+// Due returns the occurrences from the n-th on that fall on or before
+// through, at most limit of them.
+func (s Schedule) Due(n int, through time.Time, limit int) []time.Time {
+	through = Day(through)
+	var due []time.Time
+	for len(due) < limit {
+		date, ok := s.Occurrence(n + len(due))
+		if !ok || date.After(through) {
+			break
+		}
+		due = append(due, date)
+	}
+	return due
+}
+
+// Day truncates t to midnight UTC of its calendar day.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func addMonths(start time.Time, months int) time.Time {
+	first := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	day := start.Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,28 @@
+package recurringexpenses
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// CreateRecurringExpense saves a new recurring expense for the caller.
+func CreateRecurringExpense(ctx context.Context, req *recurring.RecurringExpenseRequest) (*recurring.RecurringExpense, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newRecurring, err := fromRecurringMessage(req.GetRecurringExpense())
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := productDB.CreateRecurringExpense(ctx, userId, newRecurring)
+	if err != nil {
+		return nil, recurringError(err)
+	}
+	return toRecurringMessage(created), nil
+}
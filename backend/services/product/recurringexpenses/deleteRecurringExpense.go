@@ -0,0 +1,32 @@
+package recurringexpenses
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// DeleteRecurringExpense removes one of the caller's recurring expenses and,
+// when asked, the products it recorded.
+func DeleteRecurringExpense(ctx context.Context, req *recurring.DeleteRecurringExpenseRequest) (*recurring.DeleteRecurringExpenseResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := productDB.DeleteRecurringExpense(ctx, userId, req.GetRecurringId(), req.GetDeleteProducts())
+	if err != nil {
+		return nil, recurringError(err)
+	}
+	message := fmt.Sprintf("Deleted recurring expense %d", req.GetRecurringId())
+	if req.GetDeleteProducts() {
+		message = fmt.Sprintf("Deleted recurring expense %d and %d products", req.GetRecurringId(), deleted)
+	}
+	return &recurring.DeleteRecurringExpenseResponse{
+		Message:         message,
+		ProductsDeleted: int32(deleted),
+	}, nil
+}
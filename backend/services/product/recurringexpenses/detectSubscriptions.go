@@ -0,0 +1,80 @@
+package recurringexpenses
+
+import (
+	"context"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/recurrence"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultLookbackDays  = 400
+	maxLookbackDays      = 1100
+	defaultMinConfidence = 0.5
+	defaultSuggestions   = 20
+	maxSuggestions       = 100
+)
+
+// DetectSubscriptions suggests charges in the caller's history that look
+// like subscriptions.
+func DetectSubscriptions(ctx context.Context, req *recurring.DetectSubscriptionsRequest) (*recurring.SubscriptionSuggestions, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lookback := int(req.GetLookbackDays())
+	if lookback <= 0 {
+		lookback = defaultLookbackDays
+	}
+	if lookback > maxLookbackDays {
+		lookback = maxLookbackDays
+	}
+	minConfidence := req.GetMinConfidence()
+	if minConfidence < 0 || minConfidence > 1 {
+		return nil, status.Error(codes.InvalidArgument, "min_confidence must be between 0 and 1")
+	}
+	if minConfidence == 0 {
+		minConfidence = defaultMinConfidence
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSuggestions
+	}
+	if limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+
+	now := time.Now().UTC()
+	candidates, err := productDB.DetectSubscriptions(ctx, userId, recurrence.Day(now).AddDate(0, 0, -lookback), now)
+	if err != nil {
+		return nil, recurringError(err)
+	}
+
+	resp := &recurring.SubscriptionSuggestions{}
+	for _, c := range candidates {
+		if c.Confidence < minConfidence || len(resp.Suggestions) == limit {
+			continue
+		}
+		monthly := recurrence.MonthlyCost(c.Amount, c.Frequency, c.Interval)
+		resp.Suggestions = append(resp.Suggestions, &recurring.SubscriptionSuggestion{
+			Merchant:     c.Merchant,
+			Amount:       c.Amount,
+			Frequency:    c.Frequency,
+			Interval:     int32(c.Interval),
+			Charges:      int32(c.Charges),
+			FirstDate:    c.First.Format("2006-01-02"),
+			LastDate:     c.Last.Format("2006-01-02"),
+			NextExpected: c.NextExpected.Format("2006-01-02"),
+			Confidence:   c.Confidence,
+			MonthlyCost:  monthly,
+		})
+		resp.MonthlyTotal += monthly
+	}
+	return resp, nil
+}
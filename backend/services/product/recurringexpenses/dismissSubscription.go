@@ -0,0 +1,25 @@
+package recurringexpenses
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// DismissSubscription stops a suggested subscription being suggested again.
+func DismissSubscription(ctx context.Context, req *recurring.DismissSubscriptionRequest) (*recurring.DismissSubscriptionResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := productDB.DismissSubscription(ctx, userId, req.GetMerchant(), req.GetAmount()); err != nil {
+		return nil, recurringError(err)
+	}
+	return &recurring.DismissSubscriptionResponse{
+		Message: fmt.Sprintf("%s at %.2f will no longer be suggested", req.GetMerchant(), req.GetAmount()),
+	}, nil
+}
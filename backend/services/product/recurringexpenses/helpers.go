@@ -0,0 +1,138 @@
+package recurringexpenses
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/recurrence"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxNameLength     = 255
+	maxMerchantLength = 255
+	maxNotesLength    = 2000
+)
+
+func optionalText(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// fromRecurringMessage checks the parts of a recurring expense the DB layer
+// does not and converts it.
+func fromRecurringMessage(msg *recurring.RecurringExpense) (productDB.RecurringExpense, error) {
+	if msg == nil {
+		return productDB.RecurringExpense{}, status.Error(codes.InvalidArgument, "recurring_expense is required")
+	}
+	name := strings.TrimSpace(msg.GetName())
+	if name == "" {
+		return productDB.RecurringExpense{}, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if len([]rune(name)) > maxNameLength {
+		return productDB.RecurringExpense{}, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", maxNameLength)
+	}
+	if len([]rune(strings.TrimSpace(msg.GetMerchant()))) > maxMerchantLength {
+		return productDB.RecurringExpense{}, status.Errorf(codes.InvalidArgument, "merchant must be at most %d characters", maxMerchantLength)
+	}
+	if len([]rune(strings.TrimSpace(msg.GetNotes()))) > maxNotesLength {
+		return productDB.RecurringExpense{}, status.Errorf(codes.InvalidArgument, "notes must be at most %d characters", maxNotesLength)
+	}
+
+	r := productDB.RecurringExpense{
+		RecurringID: msg.GetRecurringId(),
+		Name:        name,
+		Merchant:    optionalText(msg.GetMerchant()),
+		Amount:      msg.GetAmount(),
+		Notes:       optionalText(msg.GetNotes()),
+		Paused:      msg.GetPaused(),
+		Schedule: recurrence.Schedule{
+			Frequency: strings.ToLower(strings.TrimSpace(msg.GetFrequency())),
+			Interval:  int(msg.GetInterval()),
+		},
+	}
+	if r.Schedule.Interval == 0 {
+		r.Schedule.Interval = 1
+	}
+	if categoryID := msg.GetCategoryId(); categoryID != 0 {
+		r.CategoryID = &categoryID
+	}
+
+	if msg.GetStartDate() == "" {
+		r.Schedule.Start = recurrence.Day(time.Now().UTC())
+	} else {
+		start, err := time.Parse("2006-01-02", msg.GetStartDate())
+		if err != nil {
+			return productDB.RecurringExpense{}, status.Error(codes.InvalidArgument, "start_date must be YYYY-MM-DD")
+		}
+		r.Schedule.Start = start
+	}
+	if msg.GetEndDate() != "" {
+		end, err := time.Parse("2006-01-02", msg.GetEndDate())
+		if err != nil {
+			return productDB.RecurringExpense{}, status.Error(codes.InvalidArgument, "end_date must be YYYY-MM-DD")
+		}
+		r.Schedule.End = &end
+	}
+	return r, nil
+}
+
+func toRecurringMessage(r *productDB.RecurringExpense) *recurring.RecurringExpense {
+	msg := &recurring.RecurringExpense{
+		RecurringId: r.RecurringID,
+		Name:        r.Name,
+		Amount:      r.Amount,
+		Frequency:   r.Schedule.Frequency,
+		Interval:    int32(r.Schedule.Interval),
+		StartDate:   r.Schedule.Start.Format("2006-01-02"),
+		Paused:      r.Paused,
+		Occurrences: int32(r.Occurrences),
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		MonthlyCost: recurrence.MonthlyCost(r.Amount, r.Schedule.Frequency, r.Schedule.Interval),
+	}
+	if r.Merchant != nil {
+		msg.Merchant = *r.Merchant
+	}
+	if r.CategoryID != nil {
+		msg.CategoryId = *r.CategoryID
+	}
+	if r.CategoryName != nil {
+		msg.CategoryName = *r.CategoryName
+	}
+	if r.Schedule.End != nil {
+		msg.EndDate = r.Schedule.End.Format("2006-01-02")
+	}
+	if r.Notes != nil {
+		msg.Notes = *r.Notes
+	}
+	if r.NextDue != nil {
+		msg.NextDue = r.NextDue.Format("2006-01-02")
+	}
+	if r.LastDue != nil {
+		msg.LastDue = r.LastDue.Format("2006-01-02")
+	}
+	return msg
+}
+
+// recurringError refuses schedules filed under archived categories, and
+// deleting one whose recorded occurrences have been refunded.
+func recurringError(err error) error {
+	switch {
+	case errors.Is(err, productDB.ErrRecurringNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrInvalidRecurring),
+		errors.Is(err, productDB.ErrCategoryNotFound),
+		errors.Is(err, productDB.ErrInvalidField):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, productDB.ErrCategoryArchived), errors.Is(err, productDB.ErrProductRefunded):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
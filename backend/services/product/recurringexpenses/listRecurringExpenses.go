@@ -0,0 +1,28 @@
+package recurringexpenses
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// ListRecurringExpenses returns the caller's recurring expenses, next due
+// first.
+func ListRecurringExpenses(ctx context.Context, req *recurring.ListRecurringExpensesRequest) (*recurring.RecurringExpenseList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := productDB.ListRecurringExpenses(ctx, userId)
+	if err != nil {
+		return nil, recurringError(err)
+	}
+	resp := &recurring.RecurringExpenseList{}
+	for i := range list {
+		resp.RecurringExpenses = append(resp.RecurringExpenses, toRecurringMessage(&list[i]))
+	}
+	return resp, nil
+}
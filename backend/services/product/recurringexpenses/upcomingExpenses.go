@@ -0,0 +1,50 @@
+package recurringexpenses
+
+import (
+	"context"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/recurrence"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+const (
+	defaultUpcomingDays = 30
+	maxUpcomingDays     = 366
+)
+
+// GetUpcomingExpenses lists what the caller's recurring expenses will cost
+// over the coming days.
+func GetUpcomingExpenses(ctx context.Context, req *recurring.GetUpcomingExpensesRequest) (*recurring.UpcomingExpenses, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	days := int(req.GetDays())
+	if days <= 0 {
+		days = defaultUpcomingDays
+	}
+	if days > maxUpcomingDays {
+		days = maxUpcomingDays
+	}
+	through := recurrence.Day(time.Now().UTC()).AddDate(0, 0, days)
+
+	upcoming, err := productDB.GetUpcomingExpenses(ctx, userId, through)
+	if err != nil {
+		return nil, recurringError(err)
+	}
+	resp := &recurring.UpcomingExpenses{}
+	for _, u := range upcoming {
+		resp.Expenses = append(resp.Expenses, &recurring.UpcomingExpense{
+			RecurringId: u.RecurringID,
+			Name:        u.Name,
+			Amount:      u.Amount,
+			Date:        u.Date.Format("2006-01-02"),
+		})
+		resp.Total += u.Amount
+	}
+	return resp, nil
+}
@@ -0,0 +1,28 @@
+package recurringexpenses
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/recurring"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// UpdateRecurringExpense replaces one of the caller's recurring expenses.
+func UpdateRecurringExpense(ctx context.Context, req *recurring.RecurringExpenseRequest) (*recurring.RecurringExpense, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := fromRecurringMessage(req.GetRecurringExpense())
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := productDB.UpdateRecurringExpense(ctx, userId, changed)
+	if err != nil {
+		return nil, recurringError(err)
+	}
+	return toRecurringMessage(updated), nil
+}
@@ -0,0 +1,57 @@
+package reports
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/analytics"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// GetCashFlow reports the caller's income against their spend per period,
+// with the share of income saved.
+func GetCashFlow(ctx context.Context, req *analytics.CashFlowRequest) (*analytics.CashFlow, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := dateRange(req.GetFromDate(), req.GetToDate())
+	if err != nil {
+		return nil, err
+	}
+	interval, err := parseInterval(req.GetInterval(), productDB.IntervalMonth, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := productDB.GetCashFlow(ctx, userId, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := productDB.CashFlowPeriod{Start: from}
+	resp := &analytics.CashFlow{}
+	for _, period := range periods {
+		resp.Periods = append(resp.Periods, toCashFlowMessage(period))
+		totals.Income += period.Income
+		totals.Expense += period.Expense
+		totals.Transferred += period.Transferred
+	}
+	resp.Totals = toCashFlowMessage(totals)
+	return resp, nil
+}
+
+func toCashFlowMessage(p productDB.CashFlowPeriod) *analytics.CashFlowPeriod {
+	msg := &analytics.CashFlowPeriod{
+		PeriodStart: p.Start.Format("2006-01-02"),
+		Income:      p.Income,
+		Expense:     p.Expense,
+		Net:         p.Income - p.Expense,
+		Transferred: p.Transferred,
+	}
+	if p.Income > 0 {
+		msg.SavingsRate = msg.Net / p.Income * 100
+	}
+	return msg
+}
@@ -0,0 +1,117 @@
+package reports
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/analytics"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ComparePeriods sets the caller's spend and income in one period against
+// another, by default the one just before it.
+func ComparePeriods(ctx context.Context, req *analytics.ComparePeriodsRequest) (*analytics.PeriodComparison, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := dateRange(req.GetFromDate(), req.GetToDate())
+	if err != nil {
+		return nil, err
+	}
+	// By default the comparison period is as long as the current one; giving
+	// only one end of it keeps that length.
+	length := to.Sub(from)
+	previousFrom, previousTo := from.Add(-length), from
+	if req.GetCompareFromDate() != "" {
+		if previousFrom, err = parseDate("compare_from_date", req.GetCompareFromDate()); err != nil {
+			return nil, err
+		}
+		previousTo = previousFrom.Add(length)
+	}
+	if req.GetCompareToDate() != "" {
+		last, err := parseDate("compare_to_date", req.GetCompareToDate())
+		if err != nil {
+			return nil, err
+		}
+		previousTo = last.AddDate(0, 0, 1)
+		if req.GetCompareFromDate() == "" {
+			previousFrom = previousTo.Add(-length)
+		}
+	}
+	if !previousFrom.Before(previousTo) {
+		return nil, status.Error(codes.InvalidArgument, "compare_from_date must not be after compare_to_date")
+	}
+	if previousTo.Sub(previousFrom) > maxDays*24*time.Hour {
+		return nil, status.Errorf(codes.InvalidArgument, "date range must be at most %d days", maxDays)
+	}
+	groupBy, err := parseGroupBy(req.GetGroupBy())
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := productDB.GetSpending(ctx, userId, productDB.SpendingQuery{
+		From: from, To: to, GroupBy: groupBy, TopLevel: req.GetTopLevelCategories(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	previous, err := productDB.GetSpending(ctx, userId, productDB.SpendingQuery{
+		From: previousFrom, To: previousTo, GroupBy: groupBy, TopLevel: req.GetTopLevelCategories(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &analytics.PeriodComparison{
+		FromDate:        from.Format("2006-01-02"),
+		ToDate:          to.AddDate(0, 0, -1).Format("2006-01-02"),
+		CompareFromDate: previousFrom.Format("2006-01-02"),
+		CompareToDate:   previousTo.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+	if resp.CurrentIncome, err = productDB.GetIncomeTotal(ctx, userId, from, to); err != nil {
+		return nil, err
+	}
+	if resp.PreviousIncome, err = productDB.GetIncomeTotal(ctx, userId, previousFrom, previousTo); err != nil {
+		return nil, err
+	}
+
+	deltas := map[string]*analytics.GroupDelta{}
+	var order []string
+	delta := func(group productDB.SpendingGroup) *analytics.GroupDelta {
+		d, ok := deltas[group.Key]
+		if !ok {
+			d = &analytics.GroupDelta{CategoryId: group.CategoryID, Label: group.Label}
+			deltas[group.Key] = d
+			order = append(order, group.Key)
+		}
+		return d
+	}
+	for _, group := range current {
+		delta(group).Current = group.Total
+		resp.CurrentTotal += group.Total
+	}
+	for _, group := range previous {
+		delta(group).Previous = group.Total
+		resp.PreviousTotal += group.Total
+	}
+	resp.Delta = resp.CurrentTotal - resp.PreviousTotal
+	resp.DeltaPercent = percentChange(resp.CurrentTotal, resp.PreviousTotal)
+
+	for _, key := range order {
+		d := deltas[key]
+		d.Delta = d.Current - d.Previous
+		d.DeltaPercent = percentChange(d.Current, d.Previous)
+		resp.Groups = append(resp.Groups, d)
+	}
+	sort.SliceStable(resp.Groups, func(i, j int) bool {
+		return math.Abs(resp.Groups[i].Delta) > math.Abs(resp.Groups[j].Delta)
+	})
+	return resp, nil
+}
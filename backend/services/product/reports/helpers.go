@@ -0,0 +1,92 @@
+package reports
+
+import (
+	"strings"
+	"time"
+
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxDays bounds the range of a single query; daily buckets are capped
+// further so a chart never gets more points than it can show.
+const (
+	maxDays      = 3660
+	maxDailyDays = 366
+)
+
+func parseDate(field, value string) (time.Time, error) {
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "%s must be YYYY-MM-DD", field)
+	}
+	return parsed, nil
+}
+
+// dateRange parses an inclusive YYYY-MM-DD range, defaulting to the current
+// month so far, and returns it with an exclusive end.
+func dateRange(fromDate, toDate string) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var err error
+	if toDate != "" {
+		if to, err = parseDate("to_date", toDate); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if fromDate != "" {
+		if from, err = parseDate("from_date", fromDate); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	to = to.AddDate(0, 0, 1)
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "from_date must not be after to_date")
+	}
+	if to.Sub(from) > maxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "date range must be at most %d days", maxDays)
+	}
+	return from, to, nil
+}
+
+// parseInterval checks a bucket size against the range it splits. fallback
+// is used when none was given.
+func parseInterval(interval, fallback string, from, to time.Time) (string, error) {
+	interval = strings.ToLower(strings.TrimSpace(interval))
+	if interval == "" {
+		interval = fallback
+	}
+	switch interval {
+	case "", productDB.IntervalWeek, productDB.IntervalMonth:
+	case productDB.IntervalDay:
+		if to.Sub(from) > maxDailyDays*24*time.Hour {
+			return "", status.Errorf(codes.InvalidArgument, "daily intervals cover at most %d days", maxDailyDays)
+		}
+	default:
+		return "", status.Error(codes.InvalidArgument, "interval must be day, week or month")
+	}
+	return interval, nil
+}
+
+func parseGroupBy(groupBy string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(groupBy)) {
+	case "", productDB.GroupByCategory:
+		return productDB.GroupByCategory, nil
+	case productDB.GroupByMerchant:
+		return productDB.GroupByMerchant, nil
+	}
+	return "", status.Error(codes.InvalidArgument, "group_by must be category or merchant")
+}
+
+// percentChange is the change from previous to current as a percentage, or
+// nil when there is nothing to compare against.
+func percentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := (current - previous) / previous * 100
+	return &change
+}
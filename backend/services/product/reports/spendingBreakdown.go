@@ -0,0 +1,69 @@
+package reports
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/analytics"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// GetSpendingBreakdown sums the caller's spend per category or merchant,
+// optionally split by day, week or month.
+func GetSpendingBreakdown(ctx context.Context, req *analytics.SpendingBreakdownRequest) (*analytics.SpendingBreakdown, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := dateRange(req.GetFromDate(), req.GetToDate())
+	if err != nil {
+		return nil, err
+	}
+	groupBy, err := parseGroupBy(req.GetGroupBy())
+	if err != nil {
+		return nil, err
+	}
+	interval, err := parseInterval(req.GetInterval(), "", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := productDB.GetSpending(ctx, userId, productDB.SpendingQuery{
+		From:     from,
+		To:       to,
+		GroupBy:  groupBy,
+		Interval: interval,
+		TopLevel: req.GetTopLevelCategories(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &analytics.SpendingBreakdown{}
+	for _, group := range groups {
+		resp.TotalSpent += group.Total
+	}
+	if limit := int(req.GetLimit()); limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+	for _, group := range groups {
+		msg := &analytics.SpendingGroup{
+			CategoryId: group.CategoryID,
+			Label:      group.Label,
+			Total:      group.Total,
+			ItemCount:  int32(group.Items),
+		}
+		if resp.TotalSpent > 0 {
+			msg.Share = group.Total / resp.TotalSpent * 100
+		}
+		for _, period := range group.Periods {
+			msg.Periods = append(msg.Periods, &analytics.PeriodAmount{
+				PeriodStart: period.Start.Format("2006-01-02"),
+				Amount:      period.Amount,
+			})
+		}
+		resp.Groups = append(resp.Groups, msg)
+	}
+	return resp, nil
+}
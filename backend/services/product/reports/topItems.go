@@ -0,0 +1,76 @@
+package reports
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/analytics"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultTopItems = 10
+	maxTopItems     = 100
+)
+
+// GetTopItems returns the items the caller spent most on or bought most
+// often.
+func GetTopItems(ctx context.Context, req *analytics.TopItemsRequest) (*analytics.TopItems, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := dateRange(req.GetFromDate(), req.GetToDate())
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := strings.ToLower(strings.TrimSpace(req.GetOrderBy()))
+	switch orderBy {
+	case "":
+		orderBy = productDB.TopItemsByAmount
+	case productDB.TopItemsByAmount, productDB.TopItemsByCount:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "order_by must be amount or count")
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultTopItems
+	}
+	if limit > maxTopItems {
+		limit = maxTopItems
+	}
+
+	items, err := productDB.GetTopItems(ctx, userId, productDB.TopItemsQuery{
+		From:       from,
+		To:         to,
+		OrderBy:    orderBy,
+		CategoryID: req.GetCategoryId(),
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &analytics.TopItems{}
+	for _, item := range items {
+		msg := &analytics.TopItem{
+			ProductName:   item.ProductName,
+			Total:         item.Total,
+			Quantity:      int32(item.Quantity),
+			Purchases:     int32(item.Purchases),
+			LastPurchased: item.LastPurchased.Format("2006-01-02"),
+			Category:      item.Category,
+		}
+		if item.Quantity > 0 {
+			msg.AveragePrice = item.Total / float64(item.Quantity)
+		}
+		resp.Items = append(resp.Items, msg)
+	}
+	return resp, nil
+}
@@ -0,0 +1,269 @@
+// Package ruleengine evaluates a user's auto-categorisation rules against a
+// product. It has no database dependencies so the upload and product services
+// apply rules identically.
+package ruleengine
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields a condition can test.
+const (
+	FieldProductName = "product_name"
+	FieldDescription = "description"
+	FieldMerchant    = "merchant"
+	FieldCategory    = "category"
+	FieldFileName    = "file_name"
+	FieldAmount      = "amount"
+	FieldQuantity    = "quantity"
+)
+
+// Operators a condition can use. Text operators are case-insensitive.
+const (
+	OpContains   = "contains"
+	OpEquals     = "equals"
+	OpStartsWith = "starts_with"
+	OpRegex      = "regex"
+	OpGT         = "gt"
+	OpGTE        = "gte"
+	OpLT         = "lt"
+	OpLTE        = "lte"
+)
+
+var textFields = map[string]bool{
+	FieldProductName: true,
+	FieldDescription: true,
+	FieldMerchant:    true,
+	FieldCategory:    true,
+	FieldFileName:    true,
+}
+
+var numericFields = map[string]bool{
+	FieldAmount:   true,
+	FieldQuantity: true,
+}
+
+// Condition is a single test, e.g. merchant contains "tesco".
+type Condition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+
+	regex  *regexp.Regexp
+	number float64
+}
+
+// Rule sets a category, tags or notes on products its conditions match.
+// Rules run in ascending Priority; ties go to the older rule.
+type Rule struct {
+	RuleID         int32
+	Name           string
+	Priority       int32
+	Enabled        bool
+	MatchAll       bool
+	Conditions     []Condition
+	SetCategoryID  *int32
+	AddTags        []string
+	SetNotes       *string
+	StopProcessing bool
+}
+
+// Subject is the product a rule set is evaluated against. Amount is the line
+// total, quantity times price.
+type Subject struct {
+	ProductName string
+	Description string
+	Merchant    string
+	Category    string
+	FileName    string
+	Amount      float64
+	Quantity    float64
+}
+
+// Outcome is what the matching rules decided. Category and notes come from
+// the first matching rule that sets them; tags accumulate.
+type Outcome struct {
+	CategoryID *int32
+	Tags       []string
+	Notes      *string
+	RuleIDs    []int32
+}
+
+// Compile validates a rule and prepares its conditions for evaluation.
+func Compile(rule *Rule) error {
+	if len(rule.Conditions) == 0 {
+		return fmt.Errorf("rule needs at least one condition")
+	}
+	if rule.SetCategoryID == nil && len(rule.AddTags) == 0 && rule.SetNotes == nil {
+		return fmt.Errorf("rule needs at least one action")
+	}
+
+	for i := range rule.Conditions {
+		c := &rule.Conditions[i]
+		c.Field = strings.ToLower(strings.TrimSpace(c.Field))
+		c.Operator = strings.ToLower(strings.TrimSpace(c.Operator))
+
+		switch {
+		case textFields[c.Field]:
+			switch c.Operator {
+			case OpContains, OpEquals, OpStartsWith:
+				if strings.TrimSpace(c.Value) == "" {
+					return fmt.Errorf("condition %d: value is required", i+1)
+				}
+			case OpRegex:
+				re, err := regexp.Compile("(?i)" + c.Value)
+				if err != nil {
+					return fmt.Errorf("condition %d: invalid regex: %v", i+1, err)
+				}
+				c.regex = re
+			default:
+				return fmt.Errorf("condition %d: operator %q does not apply to %s", i+1, c.Operator, c.Field)
+			}
+		case numericFields[c.Field]:
+			switch c.Operator {
+			case OpEquals, OpGT, OpGTE, OpLT, OpLTE:
+				n, err := strconv.ParseFloat(strings.TrimSpace(c.Value), 64)
+				if err != nil {
+					return fmt.Errorf("condition %d: %s needs a number", i+1, c.Field)
+				}
+				c.number = n
+			default:
+				return fmt.Errorf("condition %d: operator %q does not apply to %s", i+1, c.Operator, c.Field)
+			}
+		default:
+			return fmt.Errorf("condition %d: unknown field %q", i+1, c.Field)
+		}
+	}
+	return nil
+}
+
+// Sort orders rules for evaluation.
+func Sort(ruleSet []Rule) {
+	sort.SliceStable(ruleSet, func(i, j int) bool {
+		if ruleSet[i].Priority != ruleSet[j].Priority {
+			return ruleSet[i].Priority < ruleSet[j].Priority
+		}
+		return ruleSet[i].RuleID < ruleSet[j].RuleID
+	})
+}
+
+// Apply runs compiled, sorted rules against a product.
+func Apply(ruleSet []Rule, subject Subject) Outcome {
+	var outcome Outcome
+
+	for i := range ruleSet {
+		rule := &ruleSet[i]
+		if !rule.Enabled || !rule.matches(subject) {
+			continue
+		}
+
+		outcome.RuleIDs = append(outcome.RuleIDs, rule.RuleID)
+		if outcome.CategoryID == nil && rule.SetCategoryID != nil {
+			outcome.CategoryID = rule.SetCategoryID
+		}
+		if outcome.Notes == nil && rule.SetNotes != nil {
+			outcome.Notes = rule.SetNotes
+		}
+		outcome.Tags = MergeTags(outcome.Tags, rule.AddTags)
+		if rule.StopProcessing {
+			break
+		}
+	}
+	return outcome
+}
+
+// MaxTagLength is the longest tag kept; longer ones are cut.
+const MaxTagLength = 50
+
+// MergeTags appends extra to tags, trimming them and dropping blanks and
+// case-insensitive duplicates. The first spelling of a tag wins.
+func MergeTags(tags, extra []string) []string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, tag := range append(append([]string(nil), tags...), extra...) {
+		tag = strings.TrimSpace(tag)
+		if runes := []rune(tag); len(runes) > MaxTagLength {
+			tag = string(runes[:MaxTagLength])
+		}
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
+
+func (r *Rule) matches(subject Subject) bool {
+	for i := range r.Conditions {
+		matched := r.Conditions[i].matches(subject)
+		if r.MatchAll && !matched {
+			return false
+		}
+		if !r.MatchAll && matched {
+			return true
+		}
+	}
+	return r.MatchAll
+}
+
+func (c *Condition) matches(subject Subject) bool {
+	switch c.Field {
+	case FieldAmount:
+		return compare(subject.Amount, c.Operator, c.number)
+	case FieldQuantity:
+		return compare(subject.Quantity, c.Operator, c.number)
+	}
+
+	var text string
+	switch c.Field {
+	case FieldProductName:
+		text = subject.ProductName
+	case FieldDescription:
+		text = subject.Description
+	case FieldMerchant:
+		text = subject.Merchant
+	case FieldCategory:
+		text = subject.Category
+	case FieldFileName:
+		text = subject.FileName
+	}
+
+	if c.Operator == OpRegex {
+		return c.regex != nil && c.regex.MatchString(text)
+	}
+	text = strings.ToLower(strings.TrimSpace(text))
+	value := strings.ToLower(strings.TrimSpace(c.Value))
+	switch c.Operator {
+	case OpContains:
+		return strings.Contains(text, value)
+	case OpEquals:
+		return text == value
+	case OpStartsWith:
+		return strings.HasPrefix(text, value)
+	}
+	return false
+}
+
+// compare treats amounts equal to the cent as equal.
+func compare(actual float64, operator string, expected float64) bool {
+	const epsilon = 0.005
+	switch operator {
+	case OpEquals:
+		return actual > expected-epsilon && actual < expected+epsilon
+	case OpGT:
+		return actual > expected+epsilon
+	case OpGTE:
+		return actual > expected-epsilon
+	case OpLT:
+		return actual < expected-epsilon
+	case OpLTE:
+		return actual < expected+epsilon
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/rule"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// CreateRule saves a new rule. It applies to products saved from now on;
+// ReapplyRules brings older products in line.
+func CreateRule(ctx context.Context, req *rule.RuleRequest) (*rule.Rule, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newRule, err := fromRuleMessage(req.GetRule())
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := productDB.CreateRule(ctx, userId, newRule)
+	if err != nil {
+		return nil, ruleError(err)
+	}
+	return toRuleMessage(created), nil
+}
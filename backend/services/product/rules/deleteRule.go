@@ -0,0 +1,24 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/rule"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// DeleteRule removes one of the caller's rules. Products it already changed
+// are left as they are.
+func DeleteRule(ctx context.Context, req *rule.DeleteRuleRequest) (*rule.DeleteRuleResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := productDB.DeleteRule(ctx, userId, req.GetRuleId()); err != nil {
+		return nil, ruleError(err)
+	}
+	return &rule.DeleteRuleResponse{Message: fmt.Sprintf("Deleted rule %d", req.GetRuleId())}, nil
+}
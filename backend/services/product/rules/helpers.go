@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Aneesh-Hegde/expenseManager/rule"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/ruleengine"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxConditions bounds the work a single rule adds to every saved product.
+const maxConditions = 20
+
+// fromRuleMessage checks the parts of a rule the engine does not and converts
+// it. Conditions and actions are validated when the rule is saved.
+func fromRuleMessage(msg *rule.Rule) (ruleengine.Rule, error) {
+	if msg == nil {
+		return ruleengine.Rule{}, status.Error(codes.InvalidArgument, "rule is required")
+	}
+	name := strings.TrimSpace(msg.GetName())
+	if name == "" {
+		return ruleengine.Rule{}, status.Error(codes.InvalidArgument, "rule name is required")
+	}
+	if len([]rune(name)) > 100 {
+		return ruleengine.Rule{}, status.Error(codes.InvalidArgument, "rule name must be at most 100 characters")
+	}
+	if len(msg.GetConditions()) > maxConditions {
+		return ruleengine.Rule{}, status.Errorf(codes.InvalidArgument, "a rule can have at most %d conditions", maxConditions)
+	}
+
+	r := ruleengine.Rule{
+		RuleID:         msg.GetRuleId(),
+		Name:           name,
+		Priority:       msg.GetPriority(),
+		Enabled:        !msg.GetDisabled(),
+		MatchAll:       !msg.GetMatchAny(),
+		AddTags:        msg.GetAddTags(),
+		StopProcessing: msg.GetStopProcessing(),
+	}
+	for _, c := range msg.GetConditions() {
+		r.Conditions = append(r.Conditions, ruleengine.Condition{
+			Field:    c.GetField(),
+			Operator: c.GetOperator(),
+			Value:    c.GetValue(),
+		})
+	}
+	if categoryID := msg.GetSetCategoryId(); categoryID != 0 {
+		r.SetCategoryID = &categoryID
+	}
+	if notes := strings.TrimSpace(msg.GetSetNotes()); notes != "" {
+		r.SetNotes = &notes
+	}
+	return r, nil
+}
+
+func toRuleMessage(r *ruleengine.Rule) *rule.Rule {
+	msg := &rule.Rule{
+		RuleId:         r.RuleID,
+		Name:           r.Name,
+		Priority:       r.Priority,
+		Disabled:       !r.Enabled,
+		MatchAny:       !r.MatchAll,
+		AddTags:        r.AddTags,
+		StopProcessing: r.StopProcessing,
+	}
+	for _, c := range r.Conditions {
+		msg.Conditions = append(msg.Conditions, &rule.RuleCondition{
+			Field:    c.Field,
+			Operator: c.Operator,
+			Value:    c.Value,
+		})
+	}
+	if r.SetCategoryID != nil {
+		msg.SetCategoryId = *r.SetCategoryID
+	}
+	if r.SetNotes != nil {
+		msg.SetNotes = *r.SetNotes
+	}
+	return msg
+}
+
+// ruleError refuses rules that file products under archived categories.
+func ruleError(err error) error {
+	switch {
+	case errors.Is(err, productDB.ErrRuleNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrInvalidRule),
+		errors.Is(err, productDB.ErrCategoryNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, productDB.ErrCategoryArchived):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,28 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/rule"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// ListRules returns the caller's rules in the order they run.
+func ListRules(ctx context.Context, req *rule.ListRulesRequest) (*rule.RuleList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleList, err := productDB.ListRules(ctx, userId)
+	if err != nil {
+		return nil, ruleError(err)
+	}
+
+	resp := &rule.RuleList{}
+	for i := range ruleList {
+		resp.Rules = append(resp.Rules, toRuleMessage(&ruleList[i]))
+	}
+	return resp, nil
+}
@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/redis"
+	"github.com/Aneesh-Hegde/expenseManager/rule"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxChanges bounds the preview; products_changed still counts every change.
+const maxChanges = 1000
+
+// ReapplyRules runs the caller's current rules over their stored products, or
+// with dry_run previews what that would change.
+func ReapplyRules(ctx context.Context, req *rule.ReapplyRulesRequest) (*rule.ReapplyRulesResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := productDB.ReapplyOptions{
+		IncludeManual: req.GetIncludeManual(),
+		DryRun:        req.GetDryRun(),
+	}
+	if req.GetFromDate() != "" {
+		from, err := time.Parse("2006-01-02", req.GetFromDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "from_date must be YYYY-MM-DD")
+		}
+		opts.From = &from
+	}
+	if req.GetToDate() != "" {
+		to, err := time.Parse("2006-01-02", req.GetToDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "to_date must be YYYY-MM-DD")
+		}
+		// Inclusive: everything before the start of the following day.
+		to = to.AddDate(0, 0, 1)
+		opts.To = &to
+	}
+	if opts.From != nil && opts.To != nil && !opts.From.Before(*opts.To) {
+		return nil, status.Error(codes.InvalidArgument, "from_date must not be after to_date")
+	}
+
+	result, err := productDB.ReapplyRules(ctx, userId, opts)
+	if err != nil {
+		return nil, ruleError(err)
+	}
+
+	resp := &rule.ReapplyRulesResponse{
+		Applied:         !opts.DryRun,
+		ProductsScanned: int32(result.Scanned),
+		ProductsChanged: int32(len(result.Changes)),
+	}
+	for i, change := range result.Changes {
+		if i == maxChanges {
+			break
+		}
+		resp.Changes = append(resp.Changes, toChangeMessage(change))
+	}
+
+	if !opts.DryRun {
+		clearReceiptCaches(userId, result.Changes)
+	}
+	return resp, nil
+}
+
+func toChangeMessage(change productDB.RuleChange) *rule.RuleChange {
+	msg := &rule.RuleChange{
+		ProductId:     change.After.ProductID,
+		ProductName:   change.After.ProductName,
+		Date:          change.After.DateAdded.Format("2006-01-02"),
+		OldCategoryId: change.Before.CategoryID,
+		OldCategory:   change.Before.CategoryName,
+		NewCategoryId: change.After.CategoryID,
+		NewCategory:   change.After.CategoryName,
+		OldTags:       change.Before.Tags,
+		NewTags:       change.After.Tags,
+		RuleIds:       change.RuleIDs,
+	}
+	if change.Before.Notes != nil {
+		msg.OldNotes = *change.Before.Notes
+	}
+	if change.After.Notes != nil {
+		msg.NewNotes = *change.After.Notes
+	}
+	return msg
+}
+
+// clearReceiptCaches drops the cached extraction of every receipt whose
+// products changed, so the dashboard does not show the old categories.
+func clearReceiptCaches(userID string, changes []productDB.RuleChange) {
+	userIDInt, err := strconv.Atoi(userID)
+	if err != nil {
+		return
+	}
+	cleared := map[string]bool{}
+	for _, change := range changes {
+		fileName := change.After.FileName
+		if fileName == nil || *fileName == "" || cleared[*fileName] {
+			continue
+		}
+		cleared[*fileName] = true
+		if err := redis.DeleteCachedProductData(userIDInt, *fileName); err != nil {
+			log.Printf("Warning: could not clear cache for %s: %v", *fileName, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/rule"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// UpdateRule replaces one of the caller's rules with the one given.
+func UpdateRule(ctx context.Context, req *rule.RuleRequest) (*rule.Rule, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRule, err := fromRuleMessage(req.GetRule())
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := productDB.UpdateRule(ctx, userId, updatedRule)
+	if err != nil {
+		return nil, ruleError(err)
+	}
+	return toRuleMessage(updated), nil
+}
@@ -0,0 +1,141 @@
+// Package splitting divides a product's cost into allocations across
+// categories and people. Allocations are stored as shares of the product's
+// line total, so they keep their proportions when the price or quantity is
+// edited later.
+package splitting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MaxAllocations bounds how many ways one product can be split.
+const MaxAllocations = 20
+
+// MaxParticipantLength is the longest participant name kept.
+const MaxParticipantLength = 100
+
+// tolerance absorbs rounding when amounts or percentages are summed.
+const tolerance = 0.005
+
+// Allocation is one part of a split as the user describes it. At most one
+// of Amount and Percent is set; one allocation per split may set neither and
+// takes whatever the others leave over.
+type Allocation struct {
+	// CategoryID files the part under another category; nil keeps the
+	// product's own.
+	CategoryID *int32
+	Amount     *float64
+	Percent    *float64
+	// Participant names whoever the part belongs to, when it is not the
+	// user's own: a contact, or another user of the app.
+	Participant       string
+	ParticipantUserID *int32
+	Note              string
+}
+
+// Shared reports whether the part belongs to someone other than the user.
+func (a Allocation) Shared() bool {
+	return a.Participant != "" || a.ParticipantUserID != nil
+}
+
+// Resolve checks allocations against a total and returns each one's share
+// of it, a fraction between 0 and 1. Shares add up to 1.
+func Resolve(total float64, allocations []Allocation) ([]float64, error) {
+	if len(allocations) == 0 {
+		return nil, nil
+	}
+	if len(allocations) > MaxAllocations {
+		return nil, fmt.Errorf("a product can be split at most %d ways", MaxAllocations)
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("only products with a positive total can be split")
+	}
+
+	shares := make([]float64, len(allocations))
+	remainder := -1
+	var allocated float64
+	for i, a := range allocations {
+		if len([]rune(strings.TrimSpace(a.Participant))) > MaxParticipantLength {
+			return nil, fmt.Errorf("participant names must be at most %d characters", MaxParticipantLength)
+		}
+		switch {
+		case a.Amount != nil && a.Percent != nil:
+			return nil, fmt.Errorf("allocation %d sets both an amount and a percentage", i+1)
+		case a.Amount != nil:
+			if *a.Amount <= 0 {
+				return nil, fmt.Errorf("allocation %d must have a positive amount", i+1)
+			}
+			shares[i] = *a.Amount / total
+		case a.Percent != nil:
+			if *a.Percent <= 0 || *a.Percent > 100 {
+				return nil, fmt.Errorf("allocation %d must have a percentage above 0 and at most 100", i+1)
+			}
+			shares[i] = *a.Percent / 100
+		default:
+			if remainder >= 0 {
+				return nil, fmt.Errorf("only one allocation can take the remainder")
+			}
+			remainder = i
+			continue
+		}
+		allocated += shares[i]
+	}
+
+	// Tolerances are in money: a cent either way of the total is fine.
+	over := (allocated - 1) * total
+	if remainder >= 0 {
+		left := 1 - allocated
+		if left*total < 0.01-tolerance {
+			return nil, fmt.Errorf("allocations leave nothing for the remainder")
+		}
+		shares[remainder] = left
+		return shares, nil
+	}
+	if math.Abs(over) > 0.01+tolerance {
+		return nil, fmt.Errorf("allocations add up to %.2f of a total of %.2f", allocated*total, total)
+	}
+	// Spread the rounding difference so the shares add up to exactly 1.
+	for i := range shares {
+		shares[i] /= allocated
+	}
+	return shares, nil
+}
+
+// Amounts divides total by shares in whole cents, handing leftover cents to
+// the shares that lost most to rounding, so the amounts add up to total.
+func Amounts(total float64, shares []float64) []float64 {
+	parts := Parts(int64(math.Round(total*100)), shares)
+	amounts := make([]float64, len(parts))
+	for i, cents := range parts {
+		amounts[i] = float64(cents) / 100
+	}
+	return amounts
+}
+
+// Parts divides total, a whole number of a currency's minor unit, by shares
+// as Amounts does.
+func Parts(total int64, shares []float64) []int64 {
+	amounts := make([]int64, len(shares))
+	type part struct {
+		index int
+		frac  float64
+	}
+	parts := make([]part, len(shares))
+	var assigned int64
+	for i, share := range shares {
+		exact := float64(total) * share
+		whole := math.Floor(exact)
+		amounts[i] = int64(whole)
+		assigned += int64(whole)
+		parts[i] = part{i, exact - whole}
+	}
+	sort.SliceStable(parts, func(i, j int) bool { return parts[i].frac > parts[j].frac })
+	for i := 0; assigned < total && i < len(parts); i++ {
+		amounts[parts[i].index]++
+		assigned++
+	}
+	return amounts
+}
@@ -0,0 +1,38 @@
+package statementimports
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/imports"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// CommitImport records a previewed batch of the caller's.
+func CommitImport(ctx context.Context, req *imports.CommitImportRequest) (*imports.ImportBatch, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := productDB.CommitImport(ctx, userId, req.GetBatchId(), req.GetIncludeRows(), req.GetSkipRows())
+	if err != nil {
+		return nil, importError(err)
+	}
+	return toBatchMessage(batch), nil
+}
+
+// UndoImport deletes what one of the caller's batches recorded, or
+// discards it while it is in preview.
+func UndoImport(ctx context.Context, req *imports.UndoImportRequest) (*imports.ImportBatch, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := productDB.UndoImport(ctx, userId, req.GetBatchId())
+	if err != nil {
+		return nil, importError(err)
+	}
+	return toBatchMessage(batch), nil
+}
@@ -0,0 +1,165 @@
+package statementimports
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/imports"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/product/statements"
+	"github.com/Aneesh-Hegde/expenseManager/shared/fx"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxMappingNameLength = 100
+	maxColumnLength      = 100
+	// maxFileSize bounds the statements PreviewImport reads.
+	maxFileSize = 10 << 20
+)
+
+// fromMappingMessage checks the lengths the table allows and converts a
+// mapping; the DB layer checks the rest.
+func fromMappingMessage(msg *imports.ImportMapping) (productDB.ImportMapping, error) {
+	if msg == nil {
+		return productDB.ImportMapping{}, status.Error(codes.InvalidArgument, "mapping is required")
+	}
+	name := strings.TrimSpace(msg.GetName())
+	if len([]rune(name)) > maxMappingNameLength {
+		return productDB.ImportMapping{}, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", maxMappingNameLength)
+	}
+	for _, column := range []string{msg.GetDateColumn(), msg.GetAmountColumn(), msg.GetDebitColumn(),
+		msg.GetCreditColumn(), msg.GetPayeeColumn(), msg.GetMemoColumn(), msg.GetIdColumn()} {
+		if len([]rune(strings.TrimSpace(column))) > maxColumnLength {
+			return productDB.ImportMapping{}, status.Errorf(codes.InvalidArgument, "column names must be at most %d characters", maxColumnLength)
+		}
+	}
+	return productDB.ImportMapping{
+		MappingID: msg.GetMappingId(),
+		Name:      name,
+		Mapping: statements.Mapping{
+			Delimiter:     msg.GetDelimiter(),
+			HasHeader:     msg.GetHasHeader(),
+			SkipLines:     int(msg.GetSkipLines()),
+			DateColumn:    strings.TrimSpace(msg.GetDateColumn()),
+			AmountColumn:  strings.TrimSpace(msg.GetAmountColumn()),
+			DebitColumn:   strings.TrimSpace(msg.GetDebitColumn()),
+			CreditColumn:  strings.TrimSpace(msg.GetCreditColumn()),
+			PayeeColumn:   strings.TrimSpace(msg.GetPayeeColumn()),
+			MemoColumn:    strings.TrimSpace(msg.GetMemoColumn()),
+			IDColumn:      strings.TrimSpace(msg.GetIdColumn()),
+			DateFormat:    strings.TrimSpace(msg.GetDateFormat()),
+			NegateAmounts: msg.GetNegateAmounts(),
+			DecimalComma:  msg.GetDecimalComma(),
+		},
+	}, nil
+}
+
+func toMappingMessage(m *productDB.ImportMapping) *imports.ImportMapping {
+	return &imports.ImportMapping{
+		MappingId:     m.MappingID,
+		Name:          m.Name,
+		Delimiter:     m.Delimiter,
+		HasHeader:     m.HasHeader,
+		SkipLines:     int32(m.SkipLines),
+		DateColumn:    m.DateColumn,
+		AmountColumn:  m.AmountColumn,
+		DebitColumn:   m.DebitColumn,
+		CreditColumn:  m.CreditColumn,
+		PayeeColumn:   m.PayeeColumn,
+		MemoColumn:    m.MemoColumn,
+		IdColumn:      m.IDColumn,
+		DateFormat:    m.DateFormat,
+		NegateAmounts: m.NegateAmounts,
+		DecimalComma:  m.DecimalComma,
+		CreatedAt:     m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toBatchMessage(b *productDB.ImportBatch) *imports.ImportBatch {
+	msg := &imports.ImportBatch{
+		BatchId:         b.BatchID,
+		AccountId:       b.AccountID,
+		AccountName:     b.AccountName,
+		Format:          b.Format,
+		Currency:        b.Currency,
+		Status:          b.Status,
+		CreatedAt:       b.CreatedAt.Format(time.RFC3339),
+		NewRows:         int32(b.Counts[productDB.RowNew]),
+		DuplicateRows:   int32(b.Counts[productDB.RowDuplicate]),
+		UnsupportedRows: int32(b.Counts[productDB.RowUnsupported]),
+		ImportedRows:    int32(b.Counts[productDB.RowImported]),
+		SkippedRows:     int32(b.Counts[productDB.RowSkipped]),
+	}
+	if b.FileName != nil {
+		msg.FileName = *b.FileName
+	}
+	if b.MappingID != nil {
+		msg.MappingId = *b.MappingID
+	}
+	if b.CommittedAt != nil {
+		msg.CommittedAt = b.CommittedAt.Format(time.RFC3339)
+	}
+	if b.UndoneAt != nil {
+		msg.UndoneAt = b.UndoneAt.Format(time.RFC3339)
+	}
+	for _, r := range b.Rows {
+		m := money.FromFloat(r.Amount, b.Currency)
+		row := &imports.ImportRow{
+			RowNumber:   r.RowNumber,
+			Line:        r.Line,
+			Date:        r.Date.Format("2006-01-02"),
+			Amount:      r.Amount,
+			AmountMoney: m.Message(),
+			Status:      r.Status,
+		}
+		if r.Payee != nil {
+			row.Payee = *r.Payee
+		}
+		if r.Memo != nil {
+			row.Memo = *r.Memo
+		}
+		if r.ExternalID != nil {
+			row.ExternalId = *r.ExternalID
+		}
+		if r.DuplicateType != nil {
+			row.DuplicateType = *r.DuplicateType
+		}
+		if r.DuplicateID != nil {
+			row.DuplicateId = *r.DuplicateID
+		}
+		if r.Note != nil {
+			row.Note = *r.Note
+		}
+		if r.ProductID != nil {
+			row.ProductId = *r.ProductID
+		}
+		if r.IncomeID != nil {
+			row.IncomeId = *r.IncomeID
+		}
+		msg.Rows = append(msg.Rows, row)
+	}
+	return msg
+}
+
+// importError reports missing batches, mappings and accounts as NotFound,
+// statements that cannot be read as InvalidArgument, and batches no longer
+// in a state to commit or undo as FailedPrecondition.
+func importError(err error) error {
+	switch {
+	case errors.Is(err, productDB.ErrImportNotFound), errors.Is(err, productDB.ErrMappingNotFound),
+		errors.Is(err, productDB.ErrBalanceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, productDB.ErrInvalidImport), errors.Is(err, statements.ErrInvalidStatement),
+		errors.Is(err, productDB.ErrInvalidField):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, productDB.ErrImportStatus), errors.Is(err, productDB.ErrProductRefunded),
+		fx.IsNoRate(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package statementimports
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/imports"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// GetImport returns one of the caller's batches with its rows.
+func GetImport(ctx context.Context, req *imports.GetImportRequest) (*imports.ImportBatch, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := productDB.GetImportBatch(ctx, userId, req.GetBatchId())
+	if err != nil {
+		return nil, importError(err)
+	}
+	return toBatchMessage(batch), nil
+}
+
+// ListImports returns the caller's batches, newest first, without their
+// rows.
+func ListImports(ctx context.Context, req *imports.ListImportsRequest) (*imports.ImportBatchList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batches, err := productDB.ListImportBatches(ctx, userId, req.GetAccountId())
+	if err != nil {
+		return nil, importError(err)
+	}
+	resp := &imports.ImportBatchList{}
+	for i := range batches {
+		resp.Imports = append(resp.Imports, toBatchMessage(&batches[i]))
+	}
+	return resp, nil
+}
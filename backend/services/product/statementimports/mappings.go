@@ -0,0 +1,61 @@
+package statementimports
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/imports"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+// ListImportMappings returns the caller's saved CSV mappings by name.
+func ListImportMappings(ctx context.Context, req *imports.ListImportMappingsRequest) (*imports.ImportMappingList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, err := productDB.ListImportMappings(ctx, userId)
+	if err != nil {
+		return nil, importError(err)
+	}
+	resp := &imports.ImportMappingList{}
+	for i := range mappings {
+		resp.Mappings = append(resp.Mappings, toMappingMessage(&mappings[i]))
+	}
+	return resp, nil
+}
+
+// SaveImportMapping creates a CSV mapping for the caller, or replaces one.
+func SaveImportMapping(ctx context.Context, req *imports.ImportMapping) (*imports.ImportMapping, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := fromMappingMessage(req)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := productDB.SaveImportMapping(ctx, userId, mapping)
+	if err != nil {
+		return nil, importError(err)
+	}
+	return toMappingMessage(saved), nil
+}
+
+// DeleteImportMapping removes one of the caller's CSV mappings.
+func DeleteImportMapping(ctx context.Context, req *imports.DeleteImportMappingRequest) (*imports.DeleteImportMappingResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := productDB.DeleteImportMapping(ctx, userId, req.GetMappingId()); err != nil {
+		return nil, importError(err)
+	}
+	return &imports.DeleteImportMappingResponse{
+		Message: fmt.Sprintf("Deleted import mapping %d", req.GetMappingId()),
+	}, nil
+}
@@ -0,0 +1,51 @@
+package statementimports
+
+import (
+	"context"
+
+	"github.com/Aneesh-Hegde/expenseManager/imports"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PreviewImport reads a statement into a batch for the caller to review.
+// Nothing is recorded until the batch is committed.
+func PreviewImport(ctx context.Context, req *imports.PreviewImportRequest) (*imports.ImportBatch, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetAccountId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+	if len(req.GetContent()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "content is required")
+	}
+	if len(req.GetContent()) > maxFileSize {
+		return nil, status.Errorf(codes.InvalidArgument, "files must be at most %d MB", maxFileSize>>20)
+	}
+
+	request := productDB.ImportRequest{
+		AccountID: req.GetAccountId(),
+		Format:    req.GetFormat(),
+		FileName:  req.GetFileName(),
+		Data:      req.GetContent(),
+		MappingID: req.GetMappingId(),
+	}
+	if req.GetMapping() != nil {
+		mapping, err := fromMappingMessage(req.GetMapping())
+		if err != nil {
+			return nil, err
+		}
+		request.Mapping = &mapping.Mapping
+	}
+
+	batch, err := productDB.PreviewImport(ctx, userId, request)
+	if err != nil {
+		return nil, importError(err)
+	}
+	return toBatchMessage(batch), nil
+}
@@ -0,0 +1,197 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mapping says which CSV columns hold what. Columns are named by their
+// header, ignoring case, or by 1-based position, which files without a
+// header need. A file has either an amount column or debit and credit
+// columns, of which one may be left out.
+type Mapping struct {
+	// Delimiter separates fields; empty means a comma.
+	Delimiter string
+	HasHeader bool
+	// SkipLines are skipped before the header, or before the first row
+	// when there is none, for exports that start with account details.
+	SkipLines    int
+	DateColumn   string
+	AmountColumn string
+	DebitColumn  string
+	CreditColumn string
+	PayeeColumn  string
+	MemoColumn   string
+	// IDColumn holds the bank's reference for each transaction, if the
+	// file has one.
+	IDColumn string
+	// DateFormat is written like "DD/MM/YYYY"; see DateLayout. Empty tries
+	// common formats, month first.
+	DateFormat string
+	// NegateAmounts is for exports, usually of cards, that show spending
+	// as positive amounts.
+	NegateAmounts bool
+	// DecimalComma reads "1.234,56" as 1234.56.
+	DecimalComma bool
+}
+
+// Validate checks the mapping names the columns a statement needs.
+func (m Mapping) Validate() error {
+	if utf8.RuneCountInString(m.Delimiter) > 1 {
+		return fmt.Errorf("%w: the delimiter must be one character", ErrInvalidStatement)
+	}
+	if m.SkipLines < 0 || m.SkipLines > 100 {
+		return fmt.Errorf("%w: skip_lines must be between 0 and 100", ErrInvalidStatement)
+	}
+	if strings.TrimSpace(m.DateColumn) == "" {
+		return fmt.Errorf("%w: the mapping needs a date column", ErrInvalidStatement)
+	}
+	amount := strings.TrimSpace(m.AmountColumn) != ""
+	debitCredit := strings.TrimSpace(m.DebitColumn) != "" || strings.TrimSpace(m.CreditColumn) != ""
+	if amount == debitCredit {
+		return fmt.Errorf("%w: the mapping needs either an amount column or debit and credit columns", ErrInvalidStatement)
+	}
+	if _, err := DateLayout(m.DateFormat); err != nil {
+		return err
+	}
+	if !m.HasHeader {
+		for _, column := range []string{m.DateColumn, m.AmountColumn, m.DebitColumn, m.CreditColumn,
+			m.PayeeColumn, m.MemoColumn, m.IDColumn} {
+			if n, err := strconv.Atoi(strings.TrimSpace(column)); column != "" && (err != nil || n < 1) {
+				return fmt.Errorf("%w: without a header, columns are numbered from 1", ErrInvalidStatement)
+			}
+		}
+	}
+	return nil
+}
+
+// columnIndex finds a mapped column: by name in header, or by position.
+// It returns -1 for a column the mapping leaves out.
+func columnIndex(column string, header []string) (int, error) {
+	column = strings.TrimSpace(column)
+	if column == "" {
+		return -1, nil
+	}
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(column); err == nil && n >= 1 {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("%w: the file has no %q column", ErrInvalidStatement, column)
+}
+
+// ParseCSV reads a CSV export as mapping describes it. Blank rows are
+// skipped.
+func ParseCSV(data []byte, mapping Mapping) (*Statement, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	layout, _ := DateLayout(mapping.DateFormat)
+
+	// A byte order mark would end up in the first header name.
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	lines := bytes.SplitN(data, []byte("\n"), mapping.SkipLines+1)
+	if len(lines) <= mapping.SkipLines {
+		return &Statement{}, nil
+	}
+	reader := csv.NewReader(bytes.NewReader(lines[mapping.SkipLines]))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if mapping.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(mapping.Delimiter)
+	}
+
+	var header []string
+	if mapping.HasHeader {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return &Statement{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+		}
+		header = record
+	}
+	columns := map[string]int{}
+	for name, column := range map[string]string{
+		"date": mapping.DateColumn, "amount": mapping.AmountColumn, "debit": mapping.DebitColumn,
+		"credit": mapping.CreditColumn, "payee": mapping.PayeeColumn, "memo": mapping.MemoColumn,
+		"id": mapping.IDColumn,
+	} {
+		i, err := columnIndex(column, header)
+		if err != nil {
+			return nil, err
+		}
+		columns[name] = i
+	}
+
+	statement := &Statement{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		line += mapping.SkipLines
+		field := func(name string) string {
+			if i := columns[name]; i >= 0 && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		t := Transaction{Line: line, Payee: field("payee"), Memo: field("memo"), ID: field("id")}
+		if t.Date, err = parseDate(field("date"), layout); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, line, err)
+		}
+		if columns["amount"] >= 0 {
+			if t.Amount, err = parseAmount(field("amount"), mapping.DecimalComma); err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, line, err)
+			}
+		} else {
+			debit, credit := field("debit"), field("credit")
+			if debit == "" && credit == "" {
+				return nil, fmt.Errorf("%w: line %d: no debit or credit amount", ErrInvalidStatement, line)
+			}
+			if debit != "" {
+				amount, err := parseAmount(debit, mapping.DecimalComma)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, line, err)
+				}
+				if amount < 0 {
+					amount = -amount
+				}
+				t.Amount -= amount
+			}
+			if credit != "" {
+				amount, err := parseAmount(credit, mapping.DecimalComma)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, line, err)
+				}
+				if amount < 0 {
+					amount = -amount
+				}
+				t.Amount += amount
+			}
+		}
+		if mapping.NegateAmounts {
+			t.Amount = -t.Amount
+		}
+		statement.Transactions = append(statement.Transactions, t)
+	}
+	return statement, nil
+}
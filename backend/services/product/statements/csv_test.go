@@ -0,0 +1,142 @@
+package statements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping Mapping
+		want    []Transaction
+	}{
+		{
+			name: "header after skipped lines",
+			data: "Account: 1234\nExported 2024-02-01\n" +
+				"Date,Description,Amount,Reference\n" +
+				"2024-01-31,Coffee,-3.50,A1\n" +
+				"\n" +
+				"2024-02-01,\"Refund, partial\",(10.00),A2\n",
+			mapping: Mapping{HasHeader: true, SkipLines: 2, DateColumn: "date", AmountColumn: "AMOUNT",
+				PayeeColumn: "Description", IDColumn: "Reference"},
+			want: []Transaction{
+				{Line: 4, Date: day(2024, 1, 31), Amount: -3.5, Payee: "Coffee", ID: "A1"},
+				{Line: 6, Date: day(2024, 2, 1), Amount: -10, Payee: "Refund, partial", ID: "A2"},
+			},
+		},
+		{
+			name:    "byte order mark before the header",
+			data:    "\xef\xbb\xbfDate,Amount\r\n01/31/2024,12.00 CR\r\n",
+			mapping: Mapping{HasHeader: true, DateColumn: "Date", AmountColumn: "Amount"},
+			want:    []Transaction{{Line: 2, Date: day(2024, 1, 31), Amount: 12}},
+		},
+		{
+			name: "debit and credit columns by position",
+			data: "31.01.2024;Coffee;3,50;\n01.02.2024;Salary;;1.234,56\n",
+			mapping: Mapping{Delimiter: ";", DateColumn: "1", PayeeColumn: "2", DebitColumn: "3",
+				CreditColumn: "4", DateFormat: "DD.MM.YYYY", DecimalComma: true},
+			want: []Transaction{
+				{Line: 1, Date: day(2024, 1, 31), Amount: -3.5, Payee: "Coffee"},
+				{Line: 2, Date: day(2024, 2, 1), Amount: 1234.56, Payee: "Salary"},
+			},
+		},
+		{
+			name:    "card spending as positive amounts",
+			data:    "2024-01-31,25.00,Books\n2024-02-01,-5.00,Refund\n",
+			mapping: Mapping{DateColumn: "1", AmountColumn: "2", MemoColumn: "3", NegateAmounts: true},
+			want: []Transaction{
+				{Line: 1, Date: day(2024, 1, 31), Amount: -25, Memo: "Books"},
+				{Line: 2, Date: day(2024, 2, 1), Amount: 5, Memo: "Refund"},
+			},
+		},
+		{
+			name:    "only skipped lines",
+			data:    "Account: 1234\n",
+			mapping: Mapping{HasHeader: true, SkipLines: 3, DateColumn: "Date", AmountColumn: "Amount"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := ParseCSV([]byte(tt.data), tt.mapping)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkTransactions(t, statement.Transactions, tt.want)
+		})
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping Mapping
+		wantErr string
+	}{
+		{
+			name:    "bad date reports its line past the skipped ones",
+			data:    "Bank export\nDate,Amount\n2024-01-31,1.00\nyesterday,2.00\n",
+			mapping: Mapping{HasHeader: true, SkipLines: 1, DateColumn: "Date", AmountColumn: "Amount"},
+			wantErr: `line 4: bad date "yesterday"`,
+		},
+		{
+			name:    "bad amount",
+			data:    "2024-01-31,1.00\n2024-02-01,ten\n",
+			mapping: Mapping{DateColumn: "1", AmountColumn: "2"},
+			wantErr: `line 2: bad amount "ten"`,
+		},
+		{
+			name:    "neither debit nor credit",
+			data:    "2024-01-31,,\n",
+			mapping: Mapping{DateColumn: "1", DebitColumn: "2", CreditColumn: "3"},
+			wantErr: "line 1: no debit or credit amount",
+		},
+		{
+			name:    "missing column",
+			data:    "Date,Amount\n2024-01-31,1.00\n",
+			mapping: Mapping{HasHeader: true, DateColumn: "Date", AmountColumn: "Value"},
+			wantErr: `no "Value" column`,
+		},
+		{
+			name:    "amount and debit columns",
+			mapping: Mapping{DateColumn: "1", AmountColumn: "2", DebitColumn: "3"},
+			wantErr: "either an amount column or debit and credit columns",
+		},
+		{
+			name:    "named column without a header",
+			mapping: Mapping{DateColumn: "Date", AmountColumn: "2"},
+			wantErr: "columns are numbered from 1",
+		},
+		{
+			name:    "too many skipped lines",
+			mapping: Mapping{SkipLines: 101, DateColumn: "1", AmountColumn: "2"},
+			wantErr: "skip_lines must be between 0 and 100",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCSV([]byte(tt.data), tt.mapping)
+			if !errors.Is(err, ErrInvalidStatement) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseCSV() = %v, want an invalid statement error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// checkTransactions compares what a parser read with what it should have.
+func checkTransactions(t *testing.T, got, want []Transaction) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d transactions, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Line != w.Line || !g.Date.Equal(w.Date) || g.Amount != w.Amount || g.Payee != w.Payee ||
+			g.Memo != w.Memo || g.ID != w.ID {
+			t.Errorf("transaction %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
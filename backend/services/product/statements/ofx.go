@@ -0,0 +1,126 @@
+package statements
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ofxEntities are the escapes OFX values may hold.
+var ofxEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ")
+
+// ParseOFX reads an OFX or QFX file: the SGML of OFX 1, whose elements
+// need not be closed, or the XML of OFX 2. Every STMTTRN in it is read,
+// whether it is in a bank or a card statement; TRNAMT is already negative
+// for money out.
+func ParseOFX(data []byte) (*Statement, error) {
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, fmt.Errorf("%w: the file has no <OFX> element", ErrInvalidStatement)
+	}
+
+	// Tags are met in order, so lines are counted from the last one.
+	line, counted := 1, 0
+	lineAt := func(offset int) int {
+		line += bytes.Count(data[counted:offset], []byte("\n"))
+		counted = offset
+		return line
+	}
+
+	statement := &Statement{}
+	var current *Transaction
+	var amount, posted string
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		t := current
+		current = nil
+		date, err := parseOFXDate(posted)
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, t.Line, err)
+		}
+		t.Date = date
+		if !strings.Contains(amount, ".") {
+			amount = strings.ReplaceAll(amount, ",", ".")
+		}
+		if t.Amount, err = parseAmount(amount, false); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, t.Line, err)
+		}
+		if t.Payee == "" {
+			t.Payee, t.Memo = t.Memo, ""
+		}
+		statement.Transactions = append(statement.Transactions, *t)
+		return nil
+	}
+
+	for i := start; i < len(data); {
+		open := bytes.IndexByte(data[i:], '<')
+		if open < 0 {
+			break
+		}
+		open += i
+		end := bytes.IndexByte(data[open:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: line %d: unterminated tag", ErrInvalidStatement, lineAt(open))
+		}
+		end += open
+		tag := strings.ToUpper(strings.TrimSpace(string(data[open+1 : end])))
+		next := bytes.IndexByte(data[end+1:], '<')
+		if next < 0 {
+			next = len(data)
+		} else {
+			next += end + 1
+		}
+		value := strings.TrimSpace(ofxEntities.Replace(string(data[end+1 : next])))
+		i = next
+
+		switch tag {
+		case "STMTTRN":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			current = &Transaction{Line: lineAt(open)}
+			amount, posted = "", ""
+		case "/STMTTRN", "/BANKTRANLIST":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+		case "CURDEF":
+			statement.Currency = strings.ToUpper(value)
+		}
+		if current == nil {
+			continue
+		}
+		switch tag {
+		case "DTPOSTED":
+			posted = value
+		case "TRNAMT":
+			amount = value
+		case "FITID":
+			current.ID = value
+		case "NAME":
+			current.Payee = value
+		case "MEMO":
+			current.Memo = value
+		}
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// parseOFXDate reads the day of an OFX date, YYYYMMDD followed by an
+// optional time and zone.
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("bad date %q", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad date %q", value)
+	}
+	return date, nil
+}
@@ -0,0 +1,120 @@
+package statements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ofxSGML is an OFX 1 statement, whose elements are left open.
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>eur
+<BANKTRANLIST>
+<DTSTART>20240101
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240131120000.000[-5:EST]
+<TRNAMT>-12,50
+<FITID>1001
+<NAME>Coffee &amp; Co
+<MEMO>Card 1234
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240201
+<TRNAMT>1000.00
+<FITID>1002
+<MEMO>Salary
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>987.50<DTASOF>20240201
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+// ofxXML is the same statement as OFX 2 writes it.
+const ofxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+    <CURDEF>EUR</CURDEF>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <TRNTYPE>DEBIT</TRNTYPE>
+        <DTPOSTED>20240131</DTPOSTED>
+        <TRNAMT>-12.50</TRNAMT>
+        <FITID>1001</FITID>
+        <NAME>Coffee &amp; Co</NAME>
+        <MEMO>Card 1234</MEMO>
+      </STMTTRN>
+      <STMTTRN>
+        <TRNTYPE>CREDIT</TRNTYPE>
+        <DTPOSTED>20240201000000</DTPOSTED>
+        <TRNAMT>1000.00</TRNAMT>
+        <FITID>1002</FITID>
+        <MEMO>Salary</MEMO>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		lines [2]int
+	}{
+		{name: "SGML", data: ofxSGML, lines: [2]int{10, 17}},
+		{name: "XML", data: ofxXML, lines: [2]int{7, 15}},
+		{name: "byte order mark", data: "\xef\xbb\xbf" + ofxXML, lines: [2]int{7, 15}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := ParseOFX([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if statement.Currency != "EUR" {
+				t.Errorf("currency = %q, want EUR", statement.Currency)
+			}
+			// A transaction without a NAME is named by its memo.
+			checkTransactions(t, statement.Transactions, []Transaction{
+				{Line: tt.lines[0], Date: day(2024, 1, 31), Amount: -12.5, Payee: "Coffee & Co", Memo: "Card 1234", ID: "1001"},
+				{Line: tt.lines[1], Date: day(2024, 2, 1), Amount: 1000, Payee: "Salary", ID: "1002"},
+			})
+		})
+	}
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "no OFX element", data: "OFXHEADER:100\n", wantErr: "no <OFX> element"},
+		{
+			name:    "bad date",
+			data:    "<OFX>\n<STMTTRN>\n<DTPOSTED>2024\n<TRNAMT>1.00\n</OFX>",
+			wantErr: `line 2: bad date "2024"`,
+		},
+		{
+			name:    "bad amount",
+			data:    "<OFX>\n\n<STMTTRN><DTPOSTED>20240131<TRNAMT>lots</STMTTRN>\n</OFX>",
+			wantErr: `line 3: bad amount "lots"`,
+		},
+		{name: "unterminated tag", data: "<OFX>\n<STMTTRN\n", wantErr: "line 2: unterminated tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOFX([]byte(tt.data))
+			if !errors.Is(err, ErrInvalidStatement) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseOFX() = %v, want an invalid statement error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package statements
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// qifTypes are the QIF sections that hold an account's transactions;
+// others, such as category and memorised-transaction lists, are skipped.
+var qifTypes = map[string]bool{
+	"BANK": true, "CASH": true, "CCARD": true, "OTH A": true, "OTH L": true, "INVST": true,
+}
+
+// qifDateLayouts read QIF dates, which Quicken writes month first with an
+// apostrophe before years from 2000, such as 1/31'24.
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "01/02/2006", "01/02/06", "2006-01-02"}
+
+// ParseQIF reads the transactions of a QIF file's bank, cash, card and
+// other account sections. Split lines are left out: each transaction is
+// imported as its total. dateFormat, when set, overrides the month-first
+// dates QIF usually has; see DateLayout.
+func ParseQIF(data []byte, dateFormat string) (*Statement, error) {
+	layout, err := DateLayout(dateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{}
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	inSection := false
+	var current *Transaction
+	var date, amount string
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		t := current
+		current = nil
+		if date == "" || amount == "" {
+			return fmt.Errorf("%w: line %d: the transaction needs a date and an amount", ErrInvalidStatement, t.Line)
+		}
+		if t.Date, err = parseQIFDate(date, layout); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, t.Line, err)
+		}
+		if t.Amount, err = parseAmount(amount, false); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, t.Line, err)
+		}
+		statement.Transactions = append(statement.Transactions, *t)
+		return nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "!") {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			header := strings.ToUpper(strings.TrimSpace(text))
+			if strings.HasPrefix(header, "!TYPE:") {
+				inSection = qifTypes[strings.TrimSpace(strings.TrimPrefix(header, "!TYPE:"))]
+			} else if strings.HasPrefix(header, "!ACCOUNT") {
+				inSection = false
+			}
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if text == "^" {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if current == nil {
+			current = &Transaction{Line: line}
+			date, amount = "", ""
+		}
+		value := strings.TrimSpace(text[1:])
+		switch text[0] {
+		case 'D':
+			date = value
+		case 'T', 'U':
+			amount = value
+		case 'P':
+			current.Payee = value
+		case 'M':
+			current.Memo = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// parseQIFDate reads a QIF date with layout, or with qifDateLayouts when
+// layout is empty.
+func parseQIFDate(value, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if layout != "" {
+		return parseDate(value, layout)
+	}
+	// 1/31'24 is 2024 and 1/ 5' 4 is 2004; 1/31'2024, 1/31/24 and
+	// 1/31/1999 are read as written.
+	if monthDay, year, ok := strings.Cut(value, "'"); ok {
+		year = strings.TrimSpace(year)
+		if len(year) == 1 {
+			year = "0" + year
+		}
+		if len(year) == 2 {
+			year = "20" + year
+		}
+		value = monthDay + "/" + year
+	}
+	value = strings.ReplaceAll(value, " ", "")
+	for _, l := range qifDateLayouts {
+		if date, err := parseDate(value, l); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("bad date %q", value)
+}
@@ -0,0 +1,95 @@
+package statements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQIF(t *testing.T) {
+	data := "\xef\xbb\xbf!Type:Cat\n" +
+		"NGroceries\n" +
+		"^\n" +
+		"!Type:Bank\n" +
+		"D1/31'24\n" +
+		"T-1,234.56\n" +
+		"PRent\n" +
+		"MJanuary\n" +
+		"SHousing\n" +
+		"$-1,234.56\n" +
+		"^\n" +
+		"\n" +
+		"D2/ 1' 4\n" +
+		"U100.00\n" +
+		"PSalary\n" +
+		"^\n" +
+		"!Account\n" +
+		"NSavings\n" +
+		"^\n" +
+		"!Type:CCard\n" +
+		"D12/31/1999\r\n" +
+		"T(20.00)\r\n" +
+		"PBooks\r\n"
+	statement, err := ParseQIF([]byte(data), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Category lists and account headers are skipped, and splits are left
+	// out of the transaction they belong to.
+	checkTransactions(t, statement.Transactions, []Transaction{
+		{Line: 5, Date: day(2024, 1, 31), Amount: -1234.56, Payee: "Rent", Memo: "January"},
+		{Line: 13, Date: day(2004, 2, 1), Amount: 100, Payee: "Salary"},
+		{Line: 21, Date: day(1999, 12, 31), Amount: -20, Payee: "Books"},
+	})
+}
+
+func TestParseQIFDate(t *testing.T) {
+	tests := []struct {
+		value  string
+		layout string
+		want   time.Time
+	}{
+		{value: "1/31'24", want: day(2024, 1, 31)},
+		{value: "01/31'2024", want: day(2024, 1, 31)},
+		{value: " 1/ 5' 4", want: day(2004, 1, 5)},
+		{value: "1/31/24", want: day(2024, 1, 31)},
+		{value: "12/31/1999", want: day(1999, 12, 31)},
+		{value: "12/31/99", want: day(1999, 12, 31)},
+		{value: "2024-01-31", want: day(2024, 1, 31)},
+		{value: "31/01/2024", layout: "02/01/2006", want: day(2024, 1, 31)},
+	}
+	for _, tt := range tests {
+		got, err := parseQIFDate(tt.value, tt.layout)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseQIFDate(%q, %q) = %v, %v; want %v", tt.value, tt.layout, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"31/01/2024", "1/31", "yesterday"} {
+		if got, err := parseQIFDate(value, ""); err == nil {
+			t.Errorf("parseQIFDate(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestParseQIFErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		dateFormat string
+		wantErr    string
+	}{
+		{name: "no amount", data: "!Type:Bank\n\nD1/31'24\nPRent\n^\n", wantErr: "line 3: the transaction needs a date and an amount"},
+		{name: "bad date", data: "!Type:Bank\nD31/01/2024\nT1.00\n", wantErr: `line 2: bad date "31/01/2024"`},
+		{name: "bad amount", data: "!Type:Bank\nD1/31'24\nTten\n", wantErr: `line 2: bad amount "ten"`},
+		{name: "bad date format", data: "!Type:Bank\n", dateFormat: "DD/MM", wantErr: "needs a year, month and day"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQIF([]byte(tt.data), tt.dateFormat)
+			if !errors.Is(err, ErrInvalidStatement) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseQIF() = %v, want an invalid statement error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Package statements reads the transaction exports banks and card issuers
+// offer, CSV, OFX/QFX and QIF, into transactions to import.
+package statements
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats a statement can be read from. QFX is OFX with a vendor header, so
+// it is read as FormatOFX.
+const (
+	FormatCSV = "csv"
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+)
+
+// MaxTransactions bounds how many transactions one statement may hold.
+const MaxTransactions = 5000
+
+var ErrInvalidStatement = errors.New("invalid statement")
+
+// Transaction is one line of a statement. Amount is money into the account
+// when positive and out of it when negative, whatever sign convention the
+// file used.
+type Transaction struct {
+	// Line is where the transaction starts in the file, for messages.
+	Line   int
+	Date   time.Time
+	Amount float64
+	Payee  string
+	Memo   string
+	// ID is the bank's own identifier for the transaction, such as an OFX
+	// FITID; empty when the file has none.
+	ID string
+}
+
+// Statement is what a file holds. Currency is the one the file says its
+// amounts are in, empty when it does not say.
+type Statement struct {
+	Currency     string
+	Transactions []Transaction
+}
+
+// DetectFormat works out a file's format from its name, or from its
+// contents when the name does not tell. It returns "" when neither does.
+func DetectFormat(fileName string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv", ".txt":
+		return FormatCSV
+	case ".ofx", ".qfx":
+		return FormatOFX
+	case ".qif":
+		return FormatQIF
+	}
+	head := bytes.ToUpper(bytes.TrimSpace(data))
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte("OFXHEADER")), bytes.Contains(head, []byte("<OFX>")):
+		return FormatOFX
+	case bytes.HasPrefix(head, []byte("!TYPE:")), bytes.HasPrefix(head, []byte("!OPTION:")),
+		bytes.HasPrefix(head, []byte("!ACCOUNT")):
+		return FormatQIF
+	}
+	return ""
+}
+
+// Parse reads a statement in format. mapping says how to read CSV and,
+// through its DateFormat, QIF dates; OFX needs none.
+func Parse(format string, data []byte, mapping Mapping) (*Statement, error) {
+	var statement *Statement
+	var err error
+	switch format {
+	case FormatCSV:
+		statement, err = ParseCSV(data, mapping)
+	case FormatOFX:
+		statement, err = ParseOFX(data)
+	case FormatQIF:
+		statement, err = ParseQIF(data, mapping.DateFormat)
+	default:
+		return nil, fmt.Errorf("%w: format must be csv, ofx or qif", ErrInvalidStatement)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(statement.Transactions) > MaxTransactions {
+		return nil, fmt.Errorf("%w: at most %d transactions can be imported at once", ErrInvalidStatement, MaxTransactions)
+	}
+	return statement, nil
+}
+
+// defaultDateLayouts are tried in order when no date format is given. Dates
+// such as 03/04/2024 are read month first, as most US exports write them;
+// a mapping's DateFormat says otherwise.
+var defaultDateLayouts = []string{
+	"2006-01-02", "2006/01/02", "20060102",
+	"01/02/2006", "1/2/2006", "01/02/06", "1/2/06",
+	"02.01.2006", "2.1.2006", "02-01-2006",
+	"2 Jan 2006", "02 Jan 2006", "2-Jan-2006", "02-Jan-2006", "2-Jan-06", "02-Jan-06",
+	"Jan 2, 2006", "January 2, 2006",
+}
+
+// dateTokens turn a format such as "DD/MM/YYYY" into a Go layout, longest
+// tokens first.
+var dateTokens = strings.NewReplacer(
+	"YYYY", "2006", "YY", "06",
+	"MMMM", "January", "MMM", "Jan", "MM", "01", "M", "1",
+	"DD", "02", "D", "2",
+)
+
+// DateLayout converts a date format written with YYYY, YY, MMMM, MMM, MM,
+// M, DD and D, such as "DD/MM/YYYY", into a Go time layout.
+func DateLayout(format string) (string, error) {
+	format = strings.TrimSpace(format)
+	if format == "" {
+		return "", nil
+	}
+	upper := strings.ToUpper(format)
+	if !strings.Contains(upper, "Y") || !strings.Contains(upper, "M") || !strings.Contains(upper, "D") {
+		return "", fmt.Errorf("%w: date format %q needs a year, month and day", ErrInvalidStatement, format)
+	}
+	return dateTokens.Replace(upper), nil
+}
+
+// parseDate reads a date with layout, or with the default layouts when
+// layout is empty. Only the day is kept.
+func parseDate(value, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	layouts := defaultDateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, l := range layouts {
+		if date, err := time.Parse(l, value); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("bad date %q", value)
+}
+
+// currencySymbols are stripped from amounts before they are read.
+var currencySymbols = strings.NewReplacer("$", "", "€", "", "£", "", "¥", "", "₹", "", " ", "", " ", "", "'", "")
+
+// parseAmount reads an amount as exports write them: with thousands
+// separators and currency symbols, negative when it has a leading or
+// trailing minus sign, is in parentheses or ends in DR; CR marks a credit.
+// decimalComma reads "1.234,56" as 1234.56.
+func parseAmount(value string, decimalComma bool) (float64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	negative := false
+	switch {
+	case strings.HasSuffix(s, "DR"):
+		negative, s = true, strings.TrimSpace(strings.TrimSuffix(s, "DR"))
+	case strings.HasSuffix(s, "CR"):
+		s = strings.TrimSpace(strings.TrimSuffix(s, "CR"))
+	}
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative, s = !negative, s[1:len(s)-1]
+	}
+	s = currencySymbols.Replace(s)
+	if strings.HasSuffix(s, "-") {
+		negative, s = !negative, strings.TrimSuffix(s, "-")
+	}
+	if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+	if strings.HasPrefix(s, "-") {
+		negative, s = !negative, s[1:]
+	}
+	if decimalComma {
+		s = strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	if s == "" {
+		return 0, fmt.Errorf("bad amount %q", value)
+	}
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad amount %q", value)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
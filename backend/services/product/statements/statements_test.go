@@ -0,0 +1,142 @@
+package statements
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in           string
+		decimalComma bool
+		want         float64
+		wantErr      bool
+	}{
+		{in: "12.50", want: 12.5},
+		{in: "-12.50", want: -12.5},
+		{in: "+12.50", want: 12.5},
+		{in: "12.50-", want: -12.5},
+		{in: "(12.50)", want: -12.5},
+		{in: "($1,234.56)", want: -1234.56},
+		{in: "12.50 DR", want: -12.5},
+		{in: "12.50dr", want: -12.5},
+		{in: "12.50 CR", want: 12.5},
+		{in: "$ 1,234.56", want: 1234.56},
+		{in: "€1 234.56", want: 1234.56},
+		{in: "1'234.56", want: 1234.56},
+		{in: "1.234,56", decimalComma: true, want: 1234.56},
+		{in: "-3,50 €", decimalComma: true, want: -3.5},
+		{in: "(1.000,00)", decimalComma: true, want: -1000},
+		{in: "1,5", decimalComma: true, want: 1.5},
+		{in: "", wantErr: true},
+		{in: "DR", wantErr: true},
+		{in: "$", wantErr: true},
+		{in: "12.5.0", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.in, tt.decimalComma)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseAmount(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseAmount(%q, %v) = %v, %v; want %v", tt.in, tt.decimalComma, got, err, tt.want)
+		}
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{format: "", want: ""},
+		{format: "DD/MM/YYYY", want: "02/01/2006"},
+		{format: "mm/dd/yy", want: "01/02/06"},
+		{format: "D.M.YYYY", want: "2.1.2006"},
+		{format: "YYYYMMDD", want: "20060102"},
+		{format: "DD MMM YYYY", want: "02 Jan 2006"},
+		{format: "MMMM D, YYYY", want: "January 2, 2006"},
+		{format: " YYYY-MM-DD ", want: "2006-01-02"},
+		{format: "MM/YYYY", wantErr: true},
+		{format: "DD/MM", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := DateLayout(tt.format)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidStatement) {
+				t.Errorf("DateLayout(%q) = %q, %v; want ErrInvalidStatement", tt.format, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("DateLayout(%q) = %q, %v; want %q", tt.format, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value  string
+		layout string
+		want   time.Time
+	}{
+		{value: "2024-01-31", want: day(2024, 1, 31)},
+		{value: "03/04/2024", want: day(2024, 3, 4)},
+		{value: "03/04/2024", layout: "02/01/2006", want: day(2024, 4, 3)},
+		{value: "31.01.2024", want: day(2024, 1, 31)},
+		{value: "5-Feb-24", want: day(2024, 2, 5)},
+		{value: " Jan 31, 2024 ", want: day(2024, 1, 31)},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.value, tt.layout)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q, %q) = %v, %v; want %v", tt.value, tt.layout, got, err, tt.want)
+		}
+	}
+	if _, err := parseDate("31/01/2024", "01/02/2006"); err == nil {
+		t.Error("parseDate read a day first date with a month first layout")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "export.CSV", want: FormatCSV},
+		{name: "export.qfx", want: FormatOFX},
+		{name: "export.qif", want: FormatQIF},
+		{name: "export", data: "OFXHEADER:100\nDATA:OFXSGML", want: FormatOFX},
+		{name: "export", data: `<?xml version="1.0"?><OFX></OFX>`, want: FormatOFX},
+		{name: "export", data: "\n!Type:Bank\nD1/31'24", want: FormatQIF},
+		{name: "export", data: "Date,Amount", want: ""},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.name, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectFormat(%q, %q) = %q, want %q", tt.name, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestParseLimitsTransactions(t *testing.T) {
+	data := "Date,Amount\n" + strings.Repeat("2024-01-31,1.00\n", MaxTransactions+1)
+	mapping := Mapping{HasHeader: true, DateColumn: "Date", AmountColumn: "Amount"}
+	if _, err := Parse(FormatCSV, []byte(data), mapping); !errors.Is(err, ErrInvalidStatement) {
+		t.Errorf("Parse of %d transactions = %v, want ErrInvalidStatement", MaxTransactions+1, err)
+	}
+	if _, err := Parse("xlsx", nil, mapping); !errors.Is(err, ErrInvalidStatement) {
+		t.Errorf("Parse of an unknown format = %v, want ErrInvalidStatement", err)
+	}
+}
@@ -0,0 +1,133 @@
+// Package taxonomy defines the default category tree every user starts with
+// and maps free-form category labels, such as the ones the LLM produces, onto
+// it.
+package taxonomy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Uncategorized is where labels that match nothing end up.
+const Uncategorized = "Uncategorized"
+
+// Category is a node of the default taxonomy.
+type Category struct {
+	Name     string
+	Color    string
+	Icon     string
+	Children []string
+}
+
+// Defaults is seeded for every user the first time their categories are used.
+// Names are unique across the whole tree.
+var Defaults = []Category{
+	{Name: "Food & Dining", Color: "#E67E22", Icon: "utensils",
+		Children: []string{"Groceries", "Restaurants", "Coffee & Snacks", "Alcohol"}},
+	{Name: "Household", Color: "#8E44AD", Icon: "home",
+		Children: []string{"Cleaning Supplies", "Home Improvement", "Furniture", "Utilities"}},
+	{Name: "Transport", Color: "#2980B9", Icon: "car",
+		Children: []string{"Fuel", "Public Transit", "Parking", "Taxi & Rideshare"}},
+	{Name: "Health", Color: "#27AE60", Icon: "heart",
+		Children: []string{"Pharmacy", "Medical", "Personal Care"}},
+	{Name: "Shopping", Color: "#C0392B", Icon: "shopping-bag",
+		Children: []string{"Clothing", "Electronics", "Books & Stationery", "Gifts"}},
+	{Name: "Entertainment", Color: "#F1C40F", Icon: "film",
+		Children: []string{"Subscriptions", "Events", "Hobbies"}},
+	{Name: "Bills & Fees", Color: "#7F8C8D", Icon: "receipt",
+		Children: []string{"Rent", "Insurance", "Phone & Internet", "Bank Fees"}},
+	{Name: "Education", Color: "#16A085", Icon: "graduation-cap"},
+	{Name: "Travel", Color: "#D35400", Icon: "plane"},
+	{Name: "Pets", Color: "#A0522D", Icon: "paw"},
+	{Name: Uncategorized, Color: "#95A5A6", Icon: "tag"},
+}
+
+// synonyms maps normalised labels the LLM commonly uses onto default names.
+var synonyms = map[string]string{
+	"food":               "Groceries",
+	"grocery":            "Groceries",
+	"food and grocery":   "Groceries",
+	"food and groceries": "Groceries",
+	"supermarket":        "Groceries",
+	"produce":            "Groceries",
+	"dairy":              "Groceries",
+	"bakery":             "Groceries",
+	"meat":               "Groceries",
+	"vegetable":          "Groceries",
+	"fruit":              "Groceries",
+	"beverage":           "Groceries",
+	"drink":              "Groceries",
+	"snack":              "Coffee & Snacks",
+	"coffee":             "Coffee & Snacks",
+	"dining":             "Restaurants",
+	"restaurant":         "Restaurants",
+	"fast food":          "Restaurants",
+	"takeaway":           "Restaurants",
+	"eating out":         "Restaurants",
+	"liquor":             "Alcohol",
+	"wine":               "Alcohol",
+	"beer":               "Alcohol",
+	"household item":     "Household",
+	"home":               "Household",
+	"cleaning":           "Cleaning Supplies",
+	"hardware":           "Home Improvement",
+	"utility":            "Utilities",
+	"transportation":     "Transport",
+	"gas":                "Fuel",
+	"petrol":             "Fuel",
+	"taxi":               "Taxi & Rideshare",
+	"medicine":           "Pharmacy",
+	"healthcare":         "Health",
+	"toiletry":           "Personal Care",
+	"toiletries":         "Personal Care",
+	"hygiene":            "Personal Care",
+	"beauty":             "Personal Care",
+	"cosmetic":           "Personal Care",
+	"clothes":            "Clothing",
+	"apparel":            "Clothing",
+	"book":               "Books & Stationery",
+	"stationery":         "Books & Stationery",
+	"office supplies":    "Books & Stationery",
+	"gift":               "Gifts",
+	"subscription":       "Subscriptions",
+	"bill":               "Bills & Fees",
+	"fee":                "Bills & Fees",
+	"pet":                "Pets",
+	"pet supplies":       "Pets",
+	"other":              Uncategorized,
+	"misc":               Uncategorized,
+	"miscellaneous":      Uncategorized,
+	"general":            Uncategorized,
+	"unknown":            Uncategorized,
+}
+
+// Normalize reduces a label to lowercase words so that "Food & Groceries",
+// "food and groceries" and "FOOD-AND-GROCERIES " compare equal.
+func Normalize(label string) string {
+	label = strings.ReplaceAll(strings.ToLower(label), "&", " and ")
+	fields := strings.FieldsFunc(label, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Candidates returns the normalised forms a label should be looked up under,
+// most specific first: the label itself, its singular, and any default
+// category it is a known synonym of.
+func Candidates(label string) []string {
+	normalized := Normalize(label)
+	if normalized == "" {
+		return nil
+	}
+
+	candidates := []string{normalized}
+	if singular := strings.TrimSuffix(normalized, "s"); singular != normalized && singular != "" {
+		candidates = append(candidates, singular)
+	}
+	for _, c := range append([]string(nil), candidates...) {
+		if name, ok := synonyms[c]; ok {
+			candidates = append(candidates, Normalize(name))
+		}
+	}
+	return candidates
+}
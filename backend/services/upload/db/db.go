@@ -9,8 +9,11 @@ import (
 
 	pb "github.com/Aneesh-Hegde/expenseManager/grpc"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
+	productDB "github.com/Aneesh-Hegde/expenseManager/services/product/db"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"github.com/Aneesh-Hegde/expenseManager/states"
+	"github.com/jackc/pgx/v4"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -30,95 +33,73 @@ func SaveProducts(ctx context.Context, req *pb.GetProducts) (*pb.DBMessage, erro
 	return StoreProductData(ctx, userId, req.GetFilename(), products)
 }
 
-func StoreProductData(ctx context.Context, userID int, filename string, products []*pb.Product) (*pb.DBMessage, error) {
-	fmt.Printf("Starting StoreProductData: userID=%d, filename=%s, products=%d\n", userID, filename, len(products))
-	
-	// Step 1: Collect all unique categories from the products
-	categoryNames := make(map[string]bool)
-	for _, product := range products {
-		categoryNames[product.Category] = true
+// unitPrice is a product's price in the default currency, which receipts
+// are stored in: its Money amount when the client sent one, else the legacy
+// float rounded.
+func unitPrice(product *pb.Product) (money.Money, error) {
+	requested, err := money.Requested(float64(product.GetAmount()), product.GetAmountMoney())
+	if err != nil {
+		return money.Money{}, fmt.Errorf("invalid price for %s: %v", product.GetProductName(), err)
 	}
-
-	// Convert map keys to a slice for the query
-	categoryNamesList := make([]string, 0, len(categoryNames))
-	for category := range categoryNames {
-		categoryNamesList = append(categoryNamesList, category)
+	price, err := requested.In(money.DefaultCurrency)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("price for %s must be in %s", product.GetProductName(), money.DefaultCurrency)
 	}
+	return price, nil
+}
 
-	fmt.Printf("Categories to process: %v\n", categoryNamesList)
-
-	// Step 2: Query for existing categories using the ANY operator
-	categoryQuery := `SELECT category_id, name FROM product_category_service.categories WHERE name = ANY($1)`
-	rows, err := sharedDB.GetDB().Query(context.Background(), categoryQuery, categoryNamesList)
+func StoreProductData(ctx context.Context, userID int, filename string, products []*pb.Product) (*pb.DBMessage, error) {
+	fmt.Printf("Starting StoreProductData: userID=%d, filename=%s, products=%d\n", userID, filename, len(products))
+	
+	// Step 1: Begin transaction for categories and products
+	fmt.Println("Starting database transaction...")
+	tx, err := sharedDB.GetDB().Begin(ctx)
 	if err != nil {
-		log.Printf("Error querying categories: %v", err)
+		log.Printf("Error starting transaction: %v", err)
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback(context.Background())
 
-	// Step 3: Store the existing categories in a map
-	existingCategories := map[string]int{}
-	for rows.Next() {
-		var categoryID int
-		var categoryName string
-		if err := rows.Scan(&categoryID, &categoryName); err != nil {
-			log.Printf("Error scanning category: %v", err)
-			return nil, err
-		}
-		existingCategories[categoryName] = categoryID
-	}
+	fmt.Println("Transaction started successfully")
 
-	// Check for any errors during row iteration
-	if err := rows.Err(); err != nil {
-		log.Printf("Error during rows iteration: %v", err)
+	// Step 2: Map the extracted category labels onto the user's taxonomy
+	// instead of creating a global category for every label the LLM invents
+	if err := productDB.EnsureUserCategories(ctx, tx, int32(userID)); err != nil {
+		log.Printf("Error seeding categories: %v", err)
 		return nil, err
 	}
-
-	fmt.Printf("Existing categories found: %v\n", existingCategories)
-
-	// Step 4: Handle missing categories and insert them if necessary
-	missingCategories := []string{}
-	for category := range categoryNames {
-		if _, exists := existingCategories[category]; !exists {
-			missingCategories = append(missingCategories, category)
-		}
+	ruleSet, err := productDB.LoadRuleSet(ctx, tx, int32(userID))
+	if err != nil {
+		log.Printf("Error loading categories and rules: %v", err)
+		return nil, err
 	}
-
-	fmt.Printf("Missing categories: %v\n", missingCategories)
-
-	if len(missingCategories) > 0 {
-		// Insert missing categories
-		insertCategoryQuery := `INSERT INTO product_category_service.categories (name) VALUES ($1) RETURNING category_id`
-		for _, category := range missingCategories {
-			var newCategoryID int
-			err := sharedDB.GetDB().QueryRow(context.Background(), insertCategoryQuery, category).Scan(&newCategoryID)
-			if err != nil {
-				log.Printf("Error inserting missing category: %v", err)
-				return nil, err
-			}
-			existingCategories[category] = newCategoryID
-			fmt.Printf("Inserted category '%s' with ID %d\n", category, newCategoryID)
-		}
+	resolver := ruleSet.Resolver()
+	merchant, err := productDB.ReceiptMerchant(ctx, tx, int32(userID), filename)
+	if err != nil {
+		log.Printf("Error loading merchant: %v", err)
+		return nil, err
 	}
-
-	// Step 5: Begin transaction for inserting products
-	fmt.Println("Starting database transaction...")
-	tx, err := sharedDB.GetDB().Begin(ctx)
+	model, err := productDB.LoadClassifier(ctx, tx, int32(userID))
 	if err != nil {
-		log.Printf("Error starting transaction: %v", err)
+		log.Printf("Error loading classifier: %v", err)
+		return nil, err
+	}
+	// Budgets are refreshed for the days the receipt was dated before and after saving
+	previousDays, err := productDB.FileProductDates(ctx, tx, int32(userID), filename)
+	if err != nil {
+		log.Printf("Error loading product dates: %v", err)
 		return nil, err
 	}
-	defer tx.Rollback(context.Background())
-
-	fmt.Println("Transaction started successfully")
 
-	// Step 6: Insert products into the database
+	// Step 3: Insert products into the database
 	insertProductQuery := `
-        INSERT INTO product_category_service.products (user_id, product_name, quantity, price, category_id, file_name, description,date_added)
-        VALUES ($1, $2, $3, $4, $5, $6, $7,TO_TIMESTAMP($8,'DD/MM/YYYY')) RETURNING product_id`
+        INSERT INTO product_category_service.products (user_id, product_name, quantity, price, category_id, file_name, description,date_added, suggested_category, tags, notes, category_source)
+        VALUES ($1, $2, $3, $4, $5, $6, $7,TO_TIMESTAMP($8,'DD/MM/YYYY'), $9, $10, $11, $12) RETURNING product_id`
 
 	UpdateProductQuery := `
-        UPDATE product_category_service.products SET product_name = $1, quantity = $2, price = $3, category_id = $4, file_name = $5, description = $6, date_added = TO_TIMESTAMP($7, 'DD/MM/YYYY')
+        UPDATE product_category_service.products SET item_id = CASE WHEN product_name IS NOT DISTINCT FROM $1 THEN item_id END, product_name = $1, quantity = $2, price = $3, category_id = $4, file_name = $5, description = COALESCE(NULLIF(description, ''), NULLIF($6, '')), date_added = TO_TIMESTAMP($7, 'DD/MM/YYYY'), suggested_category = $10,
+            tags = tags || ARRAY(SELECT t FROM unnest($11::text[]) t WHERE lower(t) <> ALL (SELECT lower(x) FROM unnest(tags) x)),
+            notes = COALESCE(NULLIF(notes, ''), $12), category_source = $13
         WHERE user_id = $8 AND product_id = $9`
 
 	existsQuery := `SELECT COUNT(*) FROM product_category_service.products WHERE user_id = $1 AND product_id = $2 AND file_name = $3`
@@ -127,10 +108,48 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 	
 	for i, product := range products {
 		fmt.Printf("Processing product %d/%d: %s\n", i+1, len(products), product.ProductName)
+		price, err := unitPrice(product)
+		if err != nil {
+			return nil, err
+		}
 		
-		categoryID, exists := existingCategories[product.Category]
-		if !exists {
-			return nil, fmt.Errorf("category %s not found in existingCategories", product.Category)
+		category, matched := resolver.Lookup(product.Category)
+		if !matched {
+			category = resolver.Resolve(product.Category)
+		}
+		fmt.Printf("Category '%s' mapped to '%s' (%d)\n", product.Category, category.Name, category.CategoryID)
+
+		// Products only reach the database when the user saves them from the
+		// review screen, so a category naming one of theirs is their choice
+		source := productDB.CategorySourceManual
+		if !matched {
+			source = productDB.CategorySourceExtraction
+		}
+		ruled := productDB.Product{
+			CategoryID:     category.CategoryID,
+			CategoryName:   category.Name,
+			ProductName:    product.ProductName,
+			Quantity:       int32(product.Quantity),
+			Price:          price,
+			FileName:       &filename,
+			LineTotal:      price.Mul(float64(product.Quantity)).Float64(),
+			CategorySource: &source,
+		}
+
+		// Step 3a: When the extracted label is missing or means nothing to the
+		// user's taxonomy, fall back to what their own corrections predict
+		if !matched && productDB.AssignPredictedCategory(model, resolver, &ruled, merchant) {
+			fmt.Printf("Classifier filed '%s' under '%s' (%d)\n", product.ProductName, ruled.CategoryName, ruled.CategoryID)
+		}
+
+		// Step 3b: Let the user's rules override the category and add tags and notes
+		if ruleIDs := ruleSet.ApplyTo(&ruled, merchant); len(ruleIDs) > 0 {
+			fmt.Printf("Rules %v matched; category is '%s' (%d)\n", ruleIDs, ruled.CategoryName, ruled.CategoryID)
+		}
+		categoryID := ruled.CategoryID
+		tags := ruled.Tags
+		if tags == nil {
+			tags = []string{}
 		}
 		
 		var count, productID int
@@ -167,13 +186,17 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 			_, err = tx.Exec(updateCtx, UpdateProductQuery,
 				product.ProductName,
 				product.Quantity,
-				product.Amount,
+				price.String(),
 				categoryID,
 				filename,
 				"", // Description (if available)
 				product.Date,
 				userID,
 				id,
+				product.Category,
+				tags,
+				ruled.Notes,
+				ruled.CategorySource,
 			)
 			if err != nil {
 				fmt.Printf("Error in UPDATE: %v\n", err)
@@ -190,11 +213,15 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 				userID,
 				product.ProductName,
 				product.Quantity,
-				product.Amount, // Map Amount to Price
+				price.String(), // Map Amount to Price
 				categoryID,
 				filename, // File Name as per schema
-				"",       // Assuming we don't have description, set it to an empty string or NULL
+				nil,      // Extraction finds no description; users add one later
 				product.Date,
+				product.Category,
+				tags,
+				ruled.Notes,
+				ruled.CategorySource,
 			).Scan(&productID)
 			if err != nil {
 				fmt.Printf("Error inserting product %s: %v\n", product.ProductName, err)
@@ -209,8 +236,8 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 			ID:          product.Id,
 			ProductName: product.ProductName,
 			Quantity:    float64(product.Quantity),
-			Amount:      float64(product.Amount),
-			Category:    product.Category,
+			Amount:      price.Float64(),
+			Category:    ruled.CategoryName,
 			Date:        product.Date,
 		})
 		
@@ -218,7 +245,24 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 		log.Printf("Processed product: %+v", product)
 	}
 
-	// Step 7: Commit the transaction
+	// Step 4: Link new and renamed products to canonical items for price tracking
+	if err := productDB.AssignItems(ctx, tx, int32(userID)); err != nil {
+		log.Printf("Error linking items: %v", err)
+		return nil, err
+	}
+
+	// Step 5: Update budget consumption for the affected periods
+	days, err := productDB.FileProductDates(ctx, tx, int32(userID), filename)
+	if err != nil {
+		log.Printf("Error loading product dates: %v", err)
+		return nil, err
+	}
+	if err := productDB.RefreshBudgetSpend(ctx, tx, int32(userID), append(previousDays, days...)); err != nil {
+		log.Printf("Error updating budgets: %v", err)
+		return nil, err
+	}
+
+	// Step 6: Commit the transaction
 	fmt.Println("Committing transaction...")
 	if err := tx.Commit(context.Background()); err != nil {
 		log.Printf("Error committing transaction: %v", err)
@@ -235,3 +279,43 @@ func StoreProductData(ctx context.Context, userID int, filename string, products
 	
 	return &pb.DBMessage{Message: message}, nil
 }
+
+// GetObjectKey returns the storage key of the user's most recent upload with the
+// given file name.
+func GetObjectKey(ctx context.Context, userID int, filename string) (string, error) {
+	var objectKey *string
+	err := sharedDB.GetDB().QueryRow(ctx, `
+        SELECT object_key FROM file_management_service.file_metadata
+        WHERE user_id = $1 AND file_name = $2
+        ORDER BY upload_date DESC LIMIT 1`,
+		userID, filename).Scan(&objectKey)
+	if err == pgx.ErrNoRows {
+		return "", fmt.Errorf("no file named %s for user %d", filename, userID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching object key: %v", err)
+	}
+	if objectKey == nil || *objectKey == "" {
+		return "", fmt.Errorf("no object key recorded for %s", filename)
+	}
+	return *objectKey, nil
+}
+
+// SaveReceiptText stores the raw OCR output of a receipt, replacing the text
+// from any earlier extraction of the same file.
+func SaveReceiptText(ctx context.Context, userID int, filename, merchantName, rawText string) error {
+	var merchant *string
+	if merchantName != "" {
+		merchant = &merchantName
+	}
+	_, err := sharedDB.GetDB().Exec(ctx, `
+        INSERT INTO product_category_service.receipt_texts (user_id, file_name, merchant_name, raw_text, extracted_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (user_id, file_name) DO UPDATE
+        SET merchant_name = EXCLUDED.merchant_name, raw_text = EXCLUDED.raw_text, extracted_at = EXCLUDED.extracted_at`,
+		userID, filename, merchant, rawText)
+	if err != nil {
+		return fmt.Errorf("error saving receipt text: %v", err)
+	}
+	return nil
+}
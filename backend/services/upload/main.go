@@ -211,6 +211,10 @@ func main() {
 	sharedDB.InitDB()
 	redis.InitRedis()
 
+	if err := utils.InitMinIO(); err != nil {
+		log.Fatalf("Failed to initialize MinIO: %v", err)
+	}
+
 	// Start background health monitoring
 	startDBHealthMonitor()
 
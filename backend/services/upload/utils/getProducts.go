@@ -8,6 +8,7 @@ import (
 
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
 	"github.com/Aneesh-Hegde/expenseManager/grpc"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 )
 
 func GetFileProduct(ctx context.Context, filename string, userId string) (*grpc.GetTextResponse, error) {
@@ -18,7 +19,7 @@ func GetFileProduct(ctx context.Context, filename string, userId string) (*grpc.
 		return nil, err
 	}
 	var products []*grpc.Product
-	var totalAmount float32 // To calculate total as quantity * price
+	totalAmount := money.Zero(money.DefaultCurrency) // To calculate total as quantity * price
 	if !rows.Next() {
 		return nil, fmt.Errorf("No data found") // No data case
 	}
@@ -37,12 +38,16 @@ func GetFileProduct(ctx context.Context, filename string, userId string) (*grpc.
 		product.Id = strconv.Itoa(product_id)
 		product.Quantity = float32(quantity)
 		product.Amount = float32(price)
+		product.AmountMoney = money.MessageOf(price, money.DefaultCurrency)
 		// product.Category = fmt.Sprintf("%d", categoryID)
 		formattedDate := date_added.Format("02/01/2006")
 		product.Date = formattedDate
 
 		// Compute total amount (sum of quantity * price for all products)
-		totalAmount += float32(quantity) * float32(price)
+		totalAmount, err = totalAmount.Add(lineTotal(float64(quantity), price))
+		if err != nil {
+			return nil, err
+		}
 
 		products = append(products, &product)
 		if !rows.Next() {
@@ -57,8 +62,9 @@ func GetFileProduct(ctx context.Context, filename string, userId string) (*grpc.
 
 	// Create response
 	response := &grpc.GetTextResponse{
-		Products: products,
-		Total:    fmt.Sprintf("%.2f", totalAmount), // Format total amount as string with 2 decimal places
+		Products:   products,
+		Total:      totalAmount.String(), // Format total amount as string with 2 decimal places
+		TotalMoney: totalAmount.Message(),
 	}
 
 	return response, nil
@@ -8,12 +8,14 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	pb "github.com/Aneesh-Hegde/expenseManager/grpc"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
 	"github.com/Aneesh-Hegde/expenseManager/services/file/utils"
+	uploadDB "github.com/Aneesh-Hegde/expenseManager/services/upload/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"github.com/Aneesh-Hegde/expenseManager/states"
-	"github.com/minio/minio-go/v7"
 	"github.com/otiai10/gosseract/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -21,9 +23,18 @@ import (
 
 var minioClient *utils.MinIOClient
 
-// DownloadImageFromMinIO downloads an image from MinIO to local temp directory for OCR processing
-// Now supports user-based folder structure
-func DownloadImageFromMinIO(filename string, userId int) (string, error) {
+// InitMinIO connects the OCR pipeline to the bucket the file service uploads to.
+func InitMinIO() error {
+	if err := utils.InitMinIO(); err != nil {
+		return err
+	}
+	minioClient = utils.MinIOClientInstance
+	return nil
+}
+
+// DownloadImageFromMinIO downloads an object from MinIO to a user-specific temp
+// directory for OCR processing
+func DownloadImageFromMinIO(objectKey string, userId int) (string, error) {
 	if minioClient == nil {
 		return "", fmt.Errorf("MinIO Client not initialized")
 	}
@@ -37,96 +48,21 @@ func DownloadImageFromMinIO(filename string, userId int) (string, error) {
 		return "", fmt.Errorf("failed to create temp directory: %w", err)
 	}
 
-	// Construct object name with user-based folder structure
-	objectName := fmt.Sprintf("%s/user_%d/%s", minioClient.FolderPath, userId, filename)
-
 	// Local file path for temporary storage
-	localFilePath := filepath.Join(tempDir, filename)
+	localFilePath := filepath.Join(tempDir, filepath.Base(objectKey))
 
-	fmt.Printf("Downloading from MinIO: %s to %s\n", objectName, localFilePath)
+	fmt.Printf("Downloading from MinIO: %s to %s\n", objectKey, localFilePath)
 
-	// Download object from MinIO
-	err = minioClient.Client.FGetObject(ctx, minioClient.BucketName, objectName, localFilePath, minio.GetObjectOptions{})
+	// Download object from MinIO, decrypting it if the file service sealed it
+	data, err := minioClient.ReadObject(ctx, int64(userId), objectKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to download image from MinIO: %w", err)
 	}
-
-	return localFilePath, nil
-}
-
-// FindImageInMinIO tries to find the image file by searching for different variations of the filename
-// Now searches within user-specific folder
-func FindImageInMinIO(baseFilename string, userId int) (string, error) {
-	fmt.Println(minioClient)
-	if minioClient == nil {
-		return "", fmt.Errorf("MinIO Client not initialized")
-	}
-
-	ctx := context.Background()
-
-	fmt.Printf("Searching for image in MinIO with base filename: %s for user: %d\n", baseFilename, userId)
-
-	// Search within user-specific folder
-	userFolderPrefix := fmt.Sprintf("%s/user_%d/", minioClient.FolderPath, userId)
-	fmt.Printf("Searching in bucket: %s, user folder: %s\n", minioClient.BucketName, userFolderPrefix)
-
-	// List objects in the user's upload folder
-	objectCh := minioClient.Client.ListObjects(ctx, minioClient.BucketName, minio.ListObjectsOptions{
-		Prefix:    userFolderPrefix,
-		Recursive: true,
-	})
-
-	// Common image extensions (both cases)
-	extensions := []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".JPG", ".JPEG", ".PNG", ".GIF", ".WEBP"}
-
-	// Remove extension from base filename for comparison
-	baseNameWithoutExt := strings.TrimSuffix(baseFilename, filepath.Ext(baseFilename))
-	fmt.Printf("Base filename without extension: %s\n", baseNameWithoutExt)
-
-	var foundObjects []string
-
-	for object := range objectCh {
-		if object.Err != nil {
-			fmt.Printf("Error listing object: %v\n", object.Err)
-			continue
-		}
-
-		foundObjects = append(foundObjects, object.Key)
-
-		// Get just the filename from the full object key
-		objectFilename := filepath.Base(object.Key)
-		fmt.Printf("Checking object: %s (filename: %s)\n", object.Key, objectFilename)
-
-		// Check for exact match first
-		if objectFilename == baseFilename {
-			fmt.Printf("Found exact match: %s\n", objectFilename)
-			return objectFilename, nil
-		}
-
-		// Check if it starts with the base filename (for unique ID appended files)
-		// This handles cases like: basefilename_uniqueid.extension
-		if strings.HasPrefix(objectFilename, baseNameWithoutExt+"_") {
-			// Check if it has a valid image extension
-			objectExt := strings.ToLower(filepath.Ext(objectFilename))
-			for _, ext := range extensions {
-				if objectExt == strings.ToLower(ext) {
-					fmt.Printf("Found pattern match: %s\n", objectFilename)
-					return objectFilename, nil
-				}
-			}
-		}
-
-		// Additional check: try with different extensions
-		for _, ext := range extensions {
-			if objectFilename == baseNameWithoutExt+ext {
-				fmt.Printf("Found extension match: %s\n", objectFilename)
-				return objectFilename, nil
-			}
-		}
+	if err := os.WriteFile(localFilePath, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write temp image: %w", err)
 	}
 
-	fmt.Printf("Available objects in user folder: %v\n", foundObjects)
-	return "", fmt.Errorf("image file not found in MinIO for filename: %s, user: %d", baseFilename, userId)
+	return localFilePath, nil
 }
 
 // Helper function to get user ID from metadata
@@ -177,12 +113,17 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 				Amount:      float32(product.Amount),
 				Date:        product.Date,
 				Category:    product.Category,
+				AmountMoney: money.MessageOf(product.Amount, money.DefaultCurrency),
 			})
 		}
-		total := calculateTotal(cachedProducts)
+		total, err := calculateTotal(cachedProducts)
+		if err != nil {
+			return nil, err
+		}
 		return &pb.GetTextResponse{
-			Products: grpcProducts,
-			Total:    strconv.FormatFloat(total, 'f', 2, 64),
+			Products:   grpcProducts,
+			Total:      total.String(),
+			TotalMoney: total.Message(),
 		}, nil
 	}
 
@@ -203,16 +144,16 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 		}
 	}
 
-	// Try to find the actual image file in MinIO (user-specific)
-	actualFilename, err := FindImageInMinIO(filename, userID)
+	// Look up the object key recorded when the file was uploaded
+	objectKey, err := uploadDB.GetObjectKey(ctx, userID, filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find image in MinIO for user %d: %w", userID, err)
+		return nil, fmt.Errorf("failed to find image for user %d: %w", userID, err)
 	}
 
-	fmt.Printf("Found image in MinIO for user %d: %s\n", userID, actualFilename)
+	fmt.Printf("Found image in MinIO for user %d: %s\n", userID, objectKey)
 
 	// Download image from MinIO to local temp directory (user-specific)
-	localImagePath, err := DownloadImageFromMinIO(actualFilename, userID)
+	localImagePath, err := DownloadImageFromMinIO(objectKey, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download image from MinIO for user %d: %w", userID, err)
 	}
@@ -250,6 +191,12 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 
 	fmt.Printf("Extracted text: %s\n", text)
 
+	// Keep the raw text for search and later re-parsing; extraction goes on
+	// even if it cannot be stored.
+	if err := uploadDB.SaveReceiptText(ctx, userID, filename, guessMerchant(text), text); err != nil {
+		fmt.Printf("Warning: Failed to store OCR text for user %d: %v\n", userID, err)
+	}
+
 	// Extract product data from text
 	extractedData, err := ExtractProductDataFromText(text)
 	if err != nil {
@@ -260,14 +207,12 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 
 	// Process extracted data
 	var products []states.Product
-	total := 0.0
 	for i, product := range extractedData {
 		product.ID = strconv.Itoa(i + 1)
 		product.FileName = filename
 		if product.Date == "" {
 			product.Date = time.Now().Format("2006-01-02")
 		}
-		total += product.Quantity * product.Amount
 		products = append(products, product)
 	}
 
@@ -297,19 +242,54 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 			Name:        filename,
 			Date:        product.Date,
 			Category:    product.Category,
+			AmountMoney: money.MessageOf(product.Amount, money.DefaultCurrency),
 		})
 	}
 
+	total, err := calculateTotal(products)
+	if err != nil {
+		return nil, err
+	}
 	return &pb.GetTextResponse{
-		Products: grpcProducts,
-		Total:    strconv.FormatFloat(total, 'f', 2, 64),
+		Products:   grpcProducts,
+		Total:      total.String(),
+		TotalMoney: total.Message(),
 	}, nil
 }
 
-func calculateTotal(products []states.Product) float64 {
-	total := 0.0
+// calculateTotal sums the products' line totals, each rounded to the cent
+// as it is when stored.
+func calculateTotal(products []states.Product) (money.Money, error) {
+	total := money.Zero(money.DefaultCurrency)
 	for _, product := range products {
-		total += product.Quantity * product.Amount
+		var err error
+		if total, err = total.Add(lineTotal(product.Quantity, product.Amount)); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// guessMerchant takes the store name from the receipt header: the first line
+// that is mostly letters. Receipts without a recognisable header get none.
+func guessMerchant(text string) string {
+	for i, line := range strings.Split(text, "\n") {
+		if i >= 5 {
+			break
+		}
+		line = strings.TrimSpace(line)
+		letters := 0
+		for _, r := range line {
+			if unicode.IsLetter(r) {
+				letters++
+			}
+		}
+		if letters >= 3 && letters*2 >= len([]rune(line)) {
+			if runes := []rune(line); len(runes) > 255 {
+				line = string(runes[:255])
+			}
+			return line
+		}
 	}
-	return total
+	return ""
 }
@@ -0,0 +1,9 @@
+package utils
+
+import "github.com/Aneesh-Hegde/expenseManager/shared/money"
+
+// lineTotal is quantity times a unit price, both as extracted or stored,
+// rounded to the cent.
+func lineTotal(quantity, price float64) money.Money {
+	return money.FromFloat(price, money.DefaultCurrency).Mul(quantity)
+}
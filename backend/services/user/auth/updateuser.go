@@ -3,9 +3,12 @@ package auth
 import (
 	"context"
 	userDB "github.com/Aneesh-Hegde/expenseManager/services/user/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	user "github.com/Aneesh-Hegde/expenseManager/user_grpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"log"
 )
 
@@ -23,6 +26,16 @@ func UpdateUser(ctx context.Context, req *user.UpdateUserRequest) (*user.UserRes
 		log.Printf("Error updating user: %v", err)
 		return nil, err
 	}
+	if req.BaseCurrency != nil {
+		currency := money.Normalize(req.GetBaseCurrency())
+		if len(currency) != 3 {
+			return nil, status.Error(codes.InvalidArgument, "base_currency must be a three-letter currency code")
+		}
+		if err := userDB.SetBaseCurrency(ctx, userId, currency); err != nil {
+			log.Printf("Error updating base currency: %v", err)
+			return nil, err
+		}
+	}
 
 	return &user.UserResponse{
 		Message: "User information updated successfully",
@@ -20,15 +20,16 @@ func UserProfile(ctx context.Context, req *user.GetUserProfileRequest) (*user.Us
 	}
 
 	userId := md["user_id"][0]
-	id, username, email, err := userDB.GetUserProfile(ctx, userId)
+	id, username, email, baseCurrency, err := userDB.GetUserProfile(ctx, userId)
 	if err != nil {
 		log.Printf("Error fetching user profile: %v", err)
 		return nil, err
 	}
 
 	return &user.UserProfile{
-		UserId:   id,
-		Username: username,
-		Email:    email,
+		UserId:       id,
+		Username:     username,
+		Email:        email,
+		BaseCurrency: baseCurrency,
 	}, nil
 }
@@ -52,21 +52,54 @@ func UpdateUser(ctx context.Context, userID, username, email string) error {
     return nil
 }
 
-func GetUserProfile(ctx context.Context, userID string) (int32, string, string, error) {
+func GetUserProfile(ctx context.Context, userID string) (int32, string, string, string, error) {
     userIDInt, err := parseUserID(userID)
     if err != nil {
-        return 0, "", "", err
+        return 0, "", "", "", err
     }
 
     var id int32
-    var username, email string
+    var username, email, baseCurrency string
     err = sharedDB.GetDB().QueryRow(ctx,
-        "SELECT user_id, username, email FROM user_service.users WHERE user_id = $1",
-        userIDInt).Scan(&id, &username, &email)
+        "SELECT user_id, username, email, base_currency FROM user_service.users WHERE user_id = $1",
+        userIDInt).Scan(&id, &username, &email, &baseCurrency)
     if err != nil {
-        return 0, "", "", fmt.Errorf("could not find user profile: %v", err)
+        return 0, "", "", "", fmt.Errorf("could not find user profile: %v", err)
     }
-    return id, username, email, nil
+    return id, username, email, baseCurrency, nil
+}
+
+// SetBaseCurrency changes the currency the user's totals are reported in
+// and records the rates of their incomes and products to it again. Those
+// with no rate to the new currency yet are converted once one is known.
+func SetBaseCurrency(ctx context.Context, userID, currency string) error {
+    userIDInt, err := parseUserID(userID)
+    if err != nil {
+        return err
+    }
+
+    tx, err := sharedDB.GetDB().Begin(ctx)
+    if err != nil {
+        return fmt.Errorf("could not start transaction: %v", err)
+    }
+    defer tx.Rollback(ctx)
+
+    tag, err := tx.Exec(ctx,
+        "UPDATE user_service.users SET base_currency = $1 WHERE user_id = $2 AND base_currency <> $1",
+        currency, userIDInt)
+    if err != nil {
+        return fmt.Errorf("could not update base currency: %v", err)
+    }
+    if tag.RowsAffected() == 0 {
+        return nil
+    }
+    if _, err := tx.Exec(ctx, "SELECT account_income_service.rerate_user($1)", userIDInt); err != nil {
+        return fmt.Errorf("could not convert to %s: %v", currency, err)
+    }
+    if err := tx.Commit(ctx); err != nil {
+        return fmt.Errorf("could not commit base currency: %v", err)
+    }
+    return nil
 }
 
 func VerifyUser(ctx context.Context, email string) error {
@@ -0,0 +1,956 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/services/user/settlement"
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	// ErrGroupNotFound is also returned for groups the user is not in.
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrNotGroupMember     = errors.New("user is not a member of the group")
+	ErrInvalidGroup       = errors.New("invalid group")
+	ErrNotAllowed         = errors.New("not allowed")
+	ErrExpenseNotFound    = errors.New("group expense not found")
+	ErrInvalidExpense     = errors.New("invalid group expense")
+	ErrInvalidSettlement  = errors.New("invalid settlement")
+	ErrBalanceOutstanding = errors.New("balance outstanding")
+	ErrReceiptExpense     = errors.New("expense belongs to a receipt")
+	// ErrCannotInvite does not say why, so invitations cannot be used to
+	// find out which emails have accounts.
+	ErrCannotInvite = errors.New("cannot invite that email")
+)
+
+// MaxGroupMembers bounds the size of a group.
+const MaxGroupMembers = 50
+
+// Group is a set of users sharing expenses.
+type Group struct {
+	GroupID   int32
+	Name      string
+	CreatedBy int32
+	CreatedAt time.Time
+	Members   []GroupMember
+	// Invited are the users with a pending invitation; JoinedAt is when
+	// they were invited.
+	Invited []GroupMember
+}
+
+// GroupInvitation is an invitation for a user to join a group.
+type GroupInvitation struct {
+	InvitationID int32
+	GroupID      int32
+	GroupName    string
+	InvitedBy    int32
+	// InviterName is the username of whoever sent the invitation.
+	InviterName string
+	CreatedAt   time.Time
+}
+
+// GroupMember is a user in a group.
+type GroupMember struct {
+	UserID   int32
+	Username string
+	Email    string
+	JoinedAt time.Time
+}
+
+// IsMember reports whether userID is in the group.
+func (g *Group) IsMember(userID int32) bool {
+	for _, m := range g.Members {
+		if m.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Group) memberIDs() []int32 {
+	ids := make([]int32, len(g.Members))
+	for i, m := range g.Members {
+		ids[i] = m.UserID
+	}
+	return ids
+}
+
+// GroupExpense is something one member paid for and others share. Amounts
+// are in cents.
+type GroupExpense struct {
+	ExpenseID   int32
+	GroupID     int32
+	PaidBy      int32
+	Description string
+	Amount      int64
+	Date        time.Time
+	// FileID is the receipt the expense was recorded from, if any.
+	FileID    *int32
+	CreatedBy int32
+	CreatedAt time.Time
+	Shares    map[int32]int64
+}
+
+// Settlement is a payment from one member to another that settles debt.
+type Settlement struct {
+	SettlementID int32
+	GroupID      int32
+	From         int32
+	To           int32
+	Amount       int64
+	// TransferID links the BalanceService transfer the money moved with.
+	TransferID *int32
+	Note       *string
+	Date       time.Time
+	CreatedBy  int32
+	CreatedAt  time.Time
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// loadGroup returns a group with its members if userID is one of them.
+func loadGroup(ctx context.Context, q querier, userID, groupID int32, lock bool) (*Group, error) {
+	query := `
+        SELECT group_id, name, created_by, created_at FROM user_service.expense_groups
+        WHERE group_id = $1`
+	if lock {
+		// Serialises changes to the group's balances.
+		query += " FOR UPDATE"
+	}
+	group := &Group{}
+	err := q.QueryRow(ctx, query, groupID).Scan(&group.GroupID, &group.Name, &group.CreatedBy, &group.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching group: %v", err)
+	}
+
+	rows, err := q.Query(ctx, `
+        SELECT m.user_id, u.username, u.email, m.joined_at
+        FROM user_service.group_members m
+        JOIN user_service.users u ON u.user_id = m.user_id
+        WHERE m.group_id = $1
+        ORDER BY m.joined_at, m.user_id`,
+		groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading group members: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m GroupMember
+		if err := rows.Scan(&m.UserID, &m.Username, &m.Email, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("error scanning group member: %v", err)
+		}
+		group.Members = append(group.Members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	if !group.IsMember(userID) {
+		return nil, ErrGroupNotFound
+	}
+
+	invited, err := q.Query(ctx, `
+        SELECT i.user_id, u.username, u.email, i.created_at
+        FROM user_service.group_invitations i
+        JOIN user_service.users u ON u.user_id = i.user_id
+        WHERE i.group_id = $1
+        ORDER BY i.created_at, i.user_id`,
+		groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading group invitations: %v", err)
+	}
+	defer invited.Close()
+	for invited.Next() {
+		var m GroupMember
+		if err := invited.Scan(&m.UserID, &m.Username, &m.Email, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("error scanning group invitation: %v", err)
+		}
+		group.Invited = append(group.Invited, m)
+	}
+	if err := invited.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return group, nil
+}
+
+// withGroup runs fn in a transaction holding the group locked, once the
+// user is known to be a member.
+func withGroup(ctx context.Context, userID string, groupID int32, fn func(tx pgx.Tx, userID int32, group *Group) error) error {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	group, err := loadGroup(ctx, tx, userIDInt, groupID, true)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx, userIDInt, group); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing group change: %v", err)
+	}
+	return nil
+}
+
+// invite invites the user with the given email to a group. Inviting someone
+// who is already a member or already invited changes nothing.
+func invite(ctx context.Context, tx pgx.Tx, group *Group, invitedBy int32, email string) error {
+	var id int32
+	err := tx.QueryRow(ctx, `
+        SELECT user_id FROM user_service.users WHERE lower(email) = lower($1)`,
+		strings.TrimSpace(email)).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return ErrCannotInvite
+	}
+	if err != nil {
+		return fmt.Errorf("error looking up user: %v", err)
+	}
+	if group.IsMember(id) {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO user_service.group_invitations (group_id, user_id, invited_by)
+        VALUES ($1, $2, $3)
+        ON CONFLICT DO NOTHING`,
+		group.GroupID, id, invitedBy)
+	if err != nil {
+		return fmt.Errorf("error inviting group member: %v", err)
+	}
+	return nil
+}
+
+func addMember(ctx context.Context, tx pgx.Tx, groupID, userID int32) error {
+	_, err := tx.Exec(ctx, `
+        INSERT INTO user_service.group_members (group_id, user_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING`,
+		groupID, userID)
+	if err != nil {
+		return fmt.Errorf("error adding group member: %v", err)
+	}
+	return nil
+}
+
+// CreateGroup starts a group of the user and invites the users with the
+// given emails.
+func CreateGroup(ctx context.Context, userID string, name string, memberEmails []string) (*Group, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberEmails)+1 > MaxGroupMembers {
+		return nil, fmt.Errorf("%w: a group has at most %d members", ErrInvalidGroup, MaxGroupMembers)
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var groupID int32
+	err = tx.QueryRow(ctx, `
+        INSERT INTO user_service.expense_groups (name, created_by)
+        VALUES ($1, $2)
+        RETURNING group_id`,
+		name, userIDInt).Scan(&groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating group: %v", err)
+	}
+	if err := addMember(ctx, tx, groupID, userIDInt); err != nil {
+		return nil, err
+	}
+	group, err := loadGroup(ctx, tx, userIDInt, groupID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range memberEmails {
+		if err := invite(ctx, tx, group, userIDInt, email); err != nil {
+			return nil, err
+		}
+	}
+
+	group, err = loadGroup(ctx, tx, userIDInt, groupID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing group: %v", err)
+	}
+	return group, nil
+}
+
+// ListGroups returns the groups the user is in, by name.
+func ListGroups(ctx context.Context, userID string) ([]Group, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT g.group_id FROM user_service.expense_groups g
+        JOIN user_service.group_members m ON m.group_id = g.group_id
+        WHERE m.user_id = $1
+        ORDER BY lower(g.name), g.group_id`,
+		userIDInt)
+	if err != nil {
+		return nil, fmt.Errorf("error listing groups: %v", err)
+	}
+	var ids []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning group: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	groups := make([]Group, 0, len(ids))
+	for _, id := range ids {
+		group, err := loadGroup(ctx, sharedDB.GetDB(), userIDInt, id, false)
+		if errors.Is(err, ErrGroupNotFound) {
+			// Left the group since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+	return groups, nil
+}
+
+// GetGroup returns one of the user's groups.
+func GetGroup(ctx context.Context, userID string, groupID int32) (*Group, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return loadGroup(ctx, sharedDB.GetDB(), userIDInt, groupID, false)
+}
+
+// InviteGroupMember invites the user with the given email to a group the
+// user is in. Pending invitations count towards the group's size.
+func InviteGroupMember(ctx context.Context, userID string, groupID int32, email string) (*Group, error) {
+	var updated *Group
+	err := withGroup(ctx, userID, groupID, func(tx pgx.Tx, userIDInt int32, group *Group) error {
+		if len(group.Members)+len(group.Invited) >= MaxGroupMembers {
+			return fmt.Errorf("%w: a group has at most %d members", ErrInvalidGroup, MaxGroupMembers)
+		}
+		if err := invite(ctx, tx, group, userIDInt, email); err != nil {
+			return err
+		}
+		var err error
+		updated, err = loadGroup(ctx, tx, userIDInt, groupID, false)
+		return err
+	})
+	return updated, err
+}
+
+// ListGroupInvitations returns the user's pending invitations, newest first.
+func ListGroupInvitations(ctx context.Context, userID string) ([]GroupInvitation, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT i.invitation_id, i.group_id, g.name, i.invited_by, u.username, i.created_at
+        FROM user_service.group_invitations i
+        JOIN user_service.expense_groups g ON g.group_id = i.group_id
+        JOIN user_service.users u ON u.user_id = i.invited_by
+        WHERE i.user_id = $1
+        ORDER BY i.created_at DESC, i.invitation_id DESC`,
+		userIDInt)
+	if err != nil {
+		return nil, fmt.Errorf("error listing group invitations: %v", err)
+	}
+	defer rows.Close()
+
+	var invitations []GroupInvitation
+	for rows.Next() {
+		var inv GroupInvitation
+		if err := rows.Scan(&inv.InvitationID, &inv.GroupID, &inv.GroupName, &inv.InvitedBy,
+			&inv.InviterName, &inv.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning group invitation: %v", err)
+		}
+		invitations = append(invitations, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return invitations, nil
+}
+
+// RespondToGroupInvitation accepts or declines one of the user's
+// invitations. Accepting joins the group and returns it; declining returns
+// nil.
+func RespondToGroupInvitation(ctx context.Context, userID string, invitationID int32, accept bool) (*Group, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var groupID int32
+	err = tx.QueryRow(ctx, `
+        DELETE FROM user_service.group_invitations
+        WHERE invitation_id = $1 AND user_id = $2
+        RETURNING group_id`,
+		invitationID, userIDInt).Scan(&groupID)
+	if err == pgx.ErrNoRows {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error answering group invitation: %v", err)
+	}
+
+	var group *Group
+	if accept {
+		// Serialises joining with other changes to the group.
+		if _, err := tx.Exec(ctx, `
+            SELECT 1 FROM user_service.expense_groups WHERE group_id = $1 FOR UPDATE`,
+			groupID); err != nil {
+			return nil, fmt.Errorf("error locking group: %v", err)
+		}
+		if err := addMember(ctx, tx, groupID, userIDInt); err != nil {
+			return nil, err
+		}
+		if group, err = loadGroup(ctx, tx, userIDInt, groupID, false); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing group invitation: %v", err)
+	}
+	return group, nil
+}
+
+// RemoveGroupMember takes a member out of a group, or withdraws a pending
+// invitation. Members can leave and the group's creator can remove anyone,
+// but only once their balance in the group is settled. It returns nil once
+// the user has left.
+func RemoveGroupMember(ctx context.Context, userID string, groupID int32, memberID int32) (*Group, error) {
+	var updated *Group
+	err := withGroup(ctx, userID, groupID, func(tx pgx.Tx, userIDInt int32, group *Group) error {
+		if memberID != userIDInt && group.CreatedBy != userIDInt {
+			return fmt.Errorf("%w: only the group's creator can remove other members", ErrNotAllowed)
+		}
+		if !group.IsMember(memberID) {
+			tag, err := tx.Exec(ctx, `
+                DELETE FROM user_service.group_invitations WHERE group_id = $1 AND user_id = $2`,
+				groupID, memberID)
+			if err != nil {
+				return fmt.Errorf("error withdrawing group invitation: %v", err)
+			}
+			if tag.RowsAffected() == 0 {
+				return ErrNotGroupMember
+			}
+			updated, err = loadGroup(ctx, tx, userIDInt, groupID, false)
+			return err
+		}
+		ledger, err := groupLedger(ctx, tx, groupID)
+		if err != nil {
+			return err
+		}
+		if balance := ledger.Net()[memberID]; balance != 0 {
+			return fmt.Errorf("%w: %.2f must be settled first", ErrBalanceOutstanding, settlement.Amount(balance))
+		}
+
+		if _, err := tx.Exec(ctx, `
+            DELETE FROM user_service.group_members WHERE group_id = $1 AND user_id = $2`,
+			groupID, memberID); err != nil {
+			return fmt.Errorf("error removing group member: %v", err)
+		}
+		if memberID == userIDInt {
+			return nil
+		}
+		updated, err = loadGroup(ctx, tx, userIDInt, groupID, false)
+		return err
+	})
+	return updated, err
+}
+
+// GroupExpenseInput describes an expense to record. Amount is in cents; no
+// Shares splits it equally between every member.
+type GroupExpenseInput struct {
+	PaidBy      int32
+	Description string
+	Amount      int64
+	Date        time.Time
+	Shares      []settlement.Share
+}
+
+// resolveShares checks that everyone involved is a member and divides the
+// expense between them.
+func resolveShares(group *Group, input GroupExpenseInput) (map[int32]int64, error) {
+	if !group.IsMember(input.PaidBy) {
+		return nil, fmt.Errorf("%w: user %d", ErrNotGroupMember, input.PaidBy)
+	}
+	shares := input.Shares
+	if len(shares) == 0 {
+		for _, id := range group.memberIDs() {
+			shares = append(shares, settlement.Share{Person: id})
+		}
+	}
+	for _, s := range shares {
+		if !group.IsMember(s.Person) {
+			return nil, fmt.Errorf("%w: user %d", ErrNotGroupMember, s.Person)
+		}
+	}
+	split, err := settlement.SplitShares(input.Amount, shares)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpense, err)
+	}
+	return split, nil
+}
+
+func insertExpense(ctx context.Context, tx pgx.Tx, userID int32, groupID int32, input GroupExpenseInput,
+	shares map[int32]int64, fileID *int32) (int32, error) {
+	var expenseID int32
+	err := tx.QueryRow(ctx, `
+        INSERT INTO user_service.group_expenses
+            (group_id, paid_by, description, amount, expense_date, file_id, created_by)
+        VALUES ($1, $2, $3, $4::numeric / 100, $5, $6, $7)
+        RETURNING expense_id`,
+		groupID, input.PaidBy, input.Description, input.Amount, input.Date, fileID, userID).Scan(&expenseID)
+	if err != nil {
+		return 0, fmt.Errorf("error recording group expense: %v", err)
+	}
+	for person, share := range shares {
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO user_service.group_expense_shares (expense_id, user_id, amount)
+            VALUES ($1, $2, $3::numeric / 100)`,
+			expenseID, person, share); err != nil {
+			return 0, fmt.Errorf("error recording expense share: %v", err)
+		}
+	}
+	return expenseID, nil
+}
+
+const groupExpenseColumns = `expense_id, group_id, paid_by, description, (amount * 100)::bigint,
+        expense_date, file_id, created_by, created_at`
+
+func scanGroupExpense(row pgx.Row) (GroupExpense, error) {
+	var e GroupExpense
+	err := row.Scan(&e.ExpenseID, &e.GroupID, &e.PaidBy, &e.Description, &e.Amount,
+		&e.Date, &e.FileID, &e.CreatedBy, &e.CreatedAt)
+	return e, err
+}
+
+// loadShares fills in the shares of expenses.
+func loadShares(ctx context.Context, q querier, expenses []GroupExpense) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+	ids := make([]int32, len(expenses))
+	index := map[int32]int{}
+	for i := range expenses {
+		ids[i] = expenses[i].ExpenseID
+		index[expenses[i].ExpenseID] = i
+		expenses[i].Shares = map[int32]int64{}
+	}
+	rows, err := q.Query(ctx, `
+        SELECT expense_id, user_id, (amount * 100)::bigint
+        FROM user_service.group_expense_shares
+        WHERE expense_id = ANY($1)`,
+		ids)
+	if err != nil {
+		return fmt.Errorf("error loading expense shares: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var expenseID, person int32
+		var share int64
+		if err := rows.Scan(&expenseID, &person, &share); err != nil {
+			return fmt.Errorf("error scanning expense share: %v", err)
+		}
+		expenses[index[expenseID]].Shares[person] = share
+	}
+	return rows.Err()
+}
+
+func getGroupExpense(ctx context.Context, q querier, groupID, expenseID int32) (*GroupExpense, error) {
+	expense, err := scanGroupExpense(q.QueryRow(ctx, `
+        SELECT `+groupExpenseColumns+` FROM user_service.group_expenses
+        WHERE group_id = $1 AND expense_id = $2`,
+		groupID, expenseID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrExpenseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching group expense: %v", err)
+	}
+	list := []GroupExpense{expense}
+	if err := loadShares(ctx, q, list); err != nil {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// AddGroupExpense records an expense in one of the user's groups.
+func AddGroupExpense(ctx context.Context, userID string, groupID int32, input GroupExpenseInput) (*GroupExpense, error) {
+	var expense *GroupExpense
+	err := withGroup(ctx, userID, groupID, func(tx pgx.Tx, userIDInt int32, group *Group) error {
+		shares, err := resolveShares(group, input)
+		if err != nil {
+			return err
+		}
+		expenseID, err := insertExpense(ctx, tx, userIDInt, groupID, input, shares, nil)
+		if err != nil {
+			return err
+		}
+		expense, err = getGroupExpense(ctx, tx, groupID, expenseID)
+		return err
+	})
+	return expense, err
+}
+
+// ListGroupExpenses returns a group's expenses newest first. beforeID pages
+// back from an earlier page's last expense; limit 0 returns them all.
+func ListGroupExpenses(ctx context.Context, userID string, groupID int32, limit int, beforeID int32) ([]GroupExpense, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := loadGroup(ctx, sharedDB.GetDB(), userIDInt, groupID, false); err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT ` + groupExpenseColumns + ` FROM user_service.group_expenses
+        WHERE group_id = $1 AND ($2::int = 0 OR expense_id < $2)
+        ORDER BY expense_id DESC`
+	args := []interface{}{groupID, beforeID}
+	if limit > 0 {
+		query += " LIMIT $3"
+		args = append(args, limit)
+	}
+	rows, err := sharedDB.GetDB().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing group expenses: %v", err)
+	}
+	var expenses []GroupExpense
+	for rows.Next() {
+		expense, err := scanGroupExpense(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning group expense: %v", err)
+		}
+		expenses = append(expenses, expense)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	if err := loadShares(ctx, sharedDB.GetDB(), expenses); err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+// DeleteGroupExpense removes an expense. Only whoever recorded or paid it
+// can; expenses recorded from receipts are removed by unassigning the
+// receipt instead.
+func DeleteGroupExpense(ctx context.Context, userID string, groupID, expenseID int32) error {
+	return withGroup(ctx, userID, groupID, func(tx pgx.Tx, userIDInt int32, group *Group) error {
+		expense, err := getGroupExpense(ctx, tx, groupID, expenseID)
+		if err != nil {
+			return err
+		}
+		if expense.FileID != nil {
+			return ErrReceiptExpense
+		}
+		if expense.CreatedBy != userIDInt && expense.PaidBy != userIDInt {
+			return fmt.Errorf("%w: only whoever recorded or paid it can delete it", ErrNotAllowed)
+		}
+		if _, err := tx.Exec(ctx, `
+            DELETE FROM user_service.group_expenses WHERE expense_id = $1`,
+			expenseID); err != nil {
+			return fmt.Errorf("error deleting group expense: %v", err)
+		}
+		return nil
+	})
+}
+
+// groupLedger replays a group's expenses and settlements.
+func groupLedger(ctx context.Context, q querier, groupID int32) (*settlement.Ledger, error) {
+	ledger := settlement.NewLedger()
+
+	rows, err := q.Query(ctx, `
+        SELECT e.paid_by, s.user_id, (s.amount * 100)::bigint
+        FROM user_service.group_expenses e
+        JOIN user_service.group_expense_shares s ON s.expense_id = e.expense_id
+        WHERE e.group_id = $1`,
+		groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading group expenses: %v", err)
+	}
+	for rows.Next() {
+		var paidBy, person int32
+		var share int64
+		if err := rows.Scan(&paidBy, &person, &share); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning expense share: %v", err)
+		}
+		ledger.AddExpense(paidBy, map[int32]int64{person: share})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	rows, err = q.Query(ctx, `
+        SELECT from_user_id, to_user_id, (amount * 100)::bigint
+        FROM user_service.group_settlements
+        WHERE group_id = $1`,
+		groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading settlements: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var from, to int32
+		var amount int64
+		if err := rows.Scan(&from, &to, &amount); err != nil {
+			return nil, fmt.Errorf("error scanning settlement: %v", err)
+		}
+		ledger.AddPayment(from, to, amount)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return ledger, nil
+}
+
+// GroupBalances is where a group's members stand with each other.
+type GroupBalances struct {
+	Group *Group
+	// Net is each member's overall balance, positive when they are owed.
+	Net map[int32]int64
+	// Pairs is what each member owes each other member directly.
+	Pairs []settlement.Debt
+	// Simplified settles every balance with the payments settlement.Simplify
+	// picks greedily; they are few but not always the fewest possible.
+	Simplified []settlement.Debt
+}
+
+// GetGroupBalances works out the balances of one of the user's groups.
+func GetGroupBalances(ctx context.Context, userID string, groupID int32) (*GroupBalances, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	group, err := loadGroup(ctx, sharedDB.GetDB(), userIDInt, groupID, false)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := groupLedger(ctx, sharedDB.GetDB(), groupID)
+	if err != nil {
+		return nil, err
+	}
+	net := ledger.Net()
+	return &GroupBalances{
+		Group:      group,
+		Net:        net,
+		Pairs:      ledger.Pairs(),
+		Simplified: settlement.Simplify(net),
+	}, nil
+}
+
+// SettlementInput describes a payment from the user to another member.
+// Amount 0 settles everything the user owes them directly.
+type SettlementInput struct {
+	To         int32
+	Amount     int64
+	TransferID *int32
+	Note       string
+	Date       time.Time
+}
+
+const settlementColumns = `settlement_id, group_id, from_user_id, to_user_id, (amount * 100)::bigint,
+        transfer_id, note, settled_at, created_by, created_at`
+
+func scanSettlement(row pgx.Row) (Settlement, error) {
+	var s Settlement
+	err := row.Scan(&s.SettlementID, &s.GroupID, &s.From, &s.To, &s.Amount,
+		&s.TransferID, &s.Note, &s.Date, &s.CreatedBy, &s.CreatedAt)
+	return s, err
+}
+
+// SettleUp records a payment from the user to another member of the group.
+// A linked transfer must be one of the user's and not already linked to
+// another settlement.
+func SettleUp(ctx context.Context, userID string, groupID int32, input SettlementInput) (*Settlement, error) {
+	var recorded *Settlement
+	err := withGroup(ctx, userID, groupID, func(tx pgx.Tx, userIDInt int32, group *Group) error {
+		if input.To == userIDInt {
+			return fmt.Errorf("%w: cannot settle up with yourself", ErrInvalidSettlement)
+		}
+		if !group.IsMember(input.To) {
+			return fmt.Errorf("%w: user %d", ErrNotGroupMember, input.To)
+		}
+		if input.Amount < 0 {
+			return fmt.Errorf("%w: amount must be positive", ErrInvalidSettlement)
+		}
+		if input.Amount == 0 {
+			ledger, err := groupLedger(ctx, tx, groupID)
+			if err != nil {
+				return err
+			}
+			input.Amount = ledger.Owed(userIDInt, input.To)
+			if input.Amount <= 0 {
+				return fmt.Errorf("%w: you do not owe user %d anything", ErrInvalidSettlement, input.To)
+			}
+		}
+
+		if input.TransferID != nil {
+			var owner int32
+			err := tx.QueryRow(ctx, `
+                SELECT user_id FROM transfer_service.transfers WHERE transfer_id = $1`,
+				*input.TransferID).Scan(&owner)
+			if err == pgx.ErrNoRows || (err == nil && owner != userIDInt) {
+				return fmt.Errorf("%w: transfer %d not found", ErrInvalidSettlement, *input.TransferID)
+			}
+			if err != nil {
+				return fmt.Errorf("error checking transfer: %v", err)
+			}
+			var linked bool
+			err = tx.QueryRow(ctx, `
+                SELECT EXISTS (SELECT 1 FROM user_service.group_settlements WHERE transfer_id = $1)`,
+				*input.TransferID).Scan(&linked)
+			if err != nil {
+				return fmt.Errorf("error checking transfer: %v", err)
+			}
+			if linked {
+				return fmt.Errorf("%w: transfer %d already settles another payment", ErrInvalidSettlement, *input.TransferID)
+			}
+		}
+
+		var note *string
+		if n := strings.TrimSpace(input.Note); n != "" {
+			note = &n
+		}
+		s, err := scanSettlement(tx.QueryRow(ctx, `
+            INSERT INTO user_service.group_settlements
+                (group_id, from_user_id, to_user_id, amount, transfer_id, note, settled_at, created_by)
+            VALUES ($1, $2, $3, $4::numeric / 100, $5, $6, $7, $2)
+            RETURNING `+settlementColumns,
+			groupID, userIDInt, input.To, input.Amount, input.TransferID, note, input.Date))
+		if err != nil {
+			return fmt.Errorf("error recording settlement: %v", err)
+		}
+		recorded = &s
+		return nil
+	})
+	return recorded, err
+}
+
+// ListSettlements returns a group's settlements newest first.
+func ListSettlements(ctx context.Context, userID string, groupID int32) ([]Settlement, error) {
+	userIDInt, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := loadGroup(ctx, sharedDB.GetDB(), userIDInt, groupID, false); err != nil {
+		return nil, err
+	}
+
+	rows, err := sharedDB.GetDB().Query(ctx, `
+        SELECT `+settlementColumns+` FROM user_service.group_settlements
+        WHERE group_id = $1
+        ORDER BY settled_at DESC, settlement_id DESC`,
+		groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing settlements: %v", err)
+	}
+	defer rows.Close()
+	var list []Settlement
+	for rows.Next() {
+		s, err := scanSettlement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning settlement: %v", err)
+		}
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return list, nil
+}
+
+// AssignReceipt records a receipt the user paid for as an expense of one
+// of their groups, shared equally by sharedBy, or by every member when
+// sharedBy is empty. A receipt already assigned elsewhere is moved. It
+// returns the expense with its shares.
+func AssignReceipt(ctx context.Context, tx pgx.Tx, userID, groupID, fileID int32, description string,
+	amount int64, date time.Time, sharedBy []int32) (*GroupExpense, error) {
+	group, err := loadGroup(ctx, tx, userID, groupID, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := UnassignReceipt(ctx, tx, userID, fileID); err != nil {
+		return nil, err
+	}
+
+	input := GroupExpenseInput{PaidBy: userID, Description: description, Amount: amount, Date: date}
+	ids := append([]int32(nil), sharedBy...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		input.Shares = append(input.Shares, settlement.Share{Person: id})
+	}
+	shares, err := resolveShares(group, input)
+	if err != nil {
+		return nil, err
+	}
+	expenseID, err := insertExpense(ctx, tx, userID, groupID, input, shares, &fileID)
+	if err != nil {
+		return nil, err
+	}
+	return getGroupExpense(ctx, tx, groupID, expenseID)
+}
+
+// UnassignReceipt removes the group expense recorded from a receipt, if
+// any, and reports whether there was one.
+func UnassignReceipt(ctx context.Context, tx pgx.Tx, userID, fileID int32) (bool, error) {
+	result, err := tx.Exec(ctx, `
+        DELETE FROM user_service.group_expenses
+        WHERE file_id = $1 AND paid_by = $2`,
+		fileID, userID)
+	if err != nil {
+		return false, fmt.Errorf("error removing receipt expense: %v", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
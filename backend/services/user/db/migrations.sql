@@ -0,0 +1,84 @@
+-- Schema changes for user_service, applied in order on top of the existing
+-- tables.
+
+-- Groups of users sharing expenses. Balances are not stored: they are
+-- replayed from expenses and settlements.
+CREATE TABLE IF NOT EXISTS user_service.expense_groups (
+    group_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_by INT NOT NULL REFERENCES user_service.users (user_id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_service.group_members (
+    group_id INT NOT NULL REFERENCES user_service.expense_groups (group_id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES user_service.users (user_id) ON DELETE CASCADE,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_members_user
+    ON user_service.group_members (user_id);
+
+-- What one member paid for, and each member's share of it. file_id is the
+-- receipt (file_management_service.file_metadata) it was recorded from.
+CREATE TABLE IF NOT EXISTS user_service.group_expenses (
+    expense_id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES user_service.expense_groups (group_id) ON DELETE CASCADE,
+    paid_by INT NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    expense_date DATE NOT NULL,
+    file_id INT UNIQUE,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_expenses_group
+    ON user_service.group_expenses (group_id, expense_id DESC);
+
+CREATE TABLE IF NOT EXISTS user_service.group_expense_shares (
+    expense_id INT NOT NULL REFERENCES user_service.group_expenses (expense_id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (expense_id, user_id)
+);
+
+-- Payments between members that settle debt, optionally linked to the
+-- transfer_service transfer the money moved with.
+CREATE TABLE IF NOT EXISTS user_service.group_settlements (
+    settlement_id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES user_service.expense_groups (group_id) ON DELETE CASCADE,
+    from_user_id INT NOT NULL,
+    to_user_id INT NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    transfer_id INT UNIQUE,
+    note TEXT,
+    settled_at DATE NOT NULL,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_settlements_group
+    ON user_service.group_settlements (group_id);
+
+-- The currency a user's totals, goals and net worth are reported in.
+-- Changing it re-records the rates of their incomes and products; see
+-- account_income_service.rerate_user.
+ALTER TABLE user_service.users
+    ADD COLUMN IF NOT EXISTS base_currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Invitations to groups. People only become members once they accept, so
+-- nobody is added to a group, or shown its expenses, without agreeing to it.
+CREATE TABLE IF NOT EXISTS user_service.group_invitations (
+    invitation_id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES user_service.expense_groups (group_id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES user_service.users (user_id) ON DELETE CASCADE,
+    invited_by INT NOT NULL REFERENCES user_service.users (user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_invitations_user
+    ON user_service.group_invitations (user_id);
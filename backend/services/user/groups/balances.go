@@ -0,0 +1,126 @@
+package groups
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/group"
+	userDB "github.com/Aneesh-Hegde/expenseManager/services/user/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/user/settlement"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+)
+
+func toDebtMessages(debts []settlement.Debt) []*group.Debt {
+	var msgs []*group.Debt
+	for _, d := range debts {
+		msgs = append(msgs, &group.Debt{
+			FromUserId: d.From,
+			ToUserId:   d.To,
+			Amount:     settlement.Amount(d.Amount),
+		})
+	}
+	return msgs
+}
+
+func toSettlementMessage(s *userDB.Settlement) *group.Settlement {
+	msg := &group.Settlement{
+		SettlementId: s.SettlementID,
+		GroupId:      s.GroupID,
+		FromUserId:   s.From,
+		ToUserId:     s.To,
+		Amount:       settlement.Amount(s.Amount),
+		Date:         s.Date.Format("2006-01-02"),
+		CreatedAt:    s.CreatedAt.Format(time.RFC3339),
+	}
+	if s.TransferID != nil {
+		msg.TransferId = *s.TransferID
+	}
+	if s.Note != nil {
+		msg.Note = *s.Note
+	}
+	return msg
+}
+
+// GetGroupBalances returns each member's net balance, what every pair of
+// members owes each other, and a greedy set of payments that settles the
+// group.
+func GetGroupBalances(ctx context.Context, req *group.GetGroupBalancesRequest) (*group.GroupBalances, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := userDB.GetGroupBalances(ctx, userId, req.GetGroupId())
+	if err != nil {
+		return nil, groupError(err)
+	}
+	resp := &group.GroupBalances{
+		GroupId:    balances.Group.GroupID,
+		Pairwise:   toDebtMessages(balances.Pairs),
+		Simplified: toDebtMessages(balances.Simplified),
+	}
+	for _, m := range balances.Group.Members {
+		resp.Members = append(resp.Members, &group.MemberBalance{
+			UserId:   m.UserID,
+			Username: m.Username,
+			Net:      settlement.Amount(balances.Net[m.UserID]),
+		})
+	}
+	sort.SliceStable(resp.Members, func(i, j int) bool { return resp.Members[i].Net < resp.Members[j].Net })
+	return resp, nil
+}
+
+// SettleUp records a payment from the caller to another member, optionally
+// linked to a transfer made through the balance service.
+func SettleUp(ctx context.Context, req *group.SettleUpRequest) (*group.Settlement, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var amount int64
+	if req.GetAmount() != 0 {
+		if amount, err = cents("amount", req.GetAmount()); err != nil {
+			return nil, err
+		}
+	}
+	date, err := parseDate(req.GetDate())
+	if err != nil {
+		return nil, err
+	}
+	input := userDB.SettlementInput{
+		To:     req.GetToUserId(),
+		Amount: amount,
+		Note:   req.GetNote(),
+		Date:   date,
+	}
+	if req.GetTransferId() != 0 {
+		transferID := req.GetTransferId()
+		input.TransferID = &transferID
+	}
+
+	recorded, err := userDB.SettleUp(ctx, userId, req.GetGroupId(), input)
+	if err != nil {
+		return nil, groupError(err)
+	}
+	return toSettlementMessage(recorded), nil
+}
+
+// ListSettlements returns a group's settlements, newest first.
+func ListSettlements(ctx context.Context, req *group.ListSettlementsRequest) (*group.SettlementList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := userDB.ListSettlements(ctx, userId, req.GetGroupId())
+	if err != nil {
+		return nil, groupError(err)
+	}
+	resp := &group.SettlementList{}
+	for i := range list {
+		resp.Settlements = append(resp.Settlements, toSettlementMessage(&list[i]))
+	}
+	return resp, nil
+}
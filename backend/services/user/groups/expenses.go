@@ -0,0 +1,137 @@
+package groups
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/group"
+	userDB "github.com/Aneesh-Hegde/expenseManager/services/user/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/user/settlement"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxDescriptionLength   = 200
+	defaultExpensePageSize = 50
+	maxExpensePageSize     = 200
+)
+
+func toExpenseMessage(e *userDB.GroupExpense) *group.GroupExpense {
+	msg := &group.GroupExpense{
+		ExpenseId:   e.ExpenseID,
+		GroupId:     e.GroupID,
+		PaidBy:      e.PaidBy,
+		Description: e.Description,
+		Amount:      settlement.Amount(e.Amount),
+		Date:        e.Date.Format("2006-01-02"),
+		CreatedBy:   e.CreatedBy,
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.FileID != nil {
+		msg.FileId = *e.FileID
+	}
+	for person, amount := range e.Shares {
+		msg.Shares = append(msg.Shares, &group.ShareAmount{UserId: person, Amount: settlement.Amount(amount)})
+	}
+	sort.Slice(msg.Shares, func(i, j int) bool { return msg.Shares[i].UserId < msg.Shares[j].UserId })
+	return msg
+}
+
+// AddGroupExpense records an expense paid by one member and shared by
+// others.
+func AddGroupExpense(ctx context.Context, req *group.AddGroupExpenseRequest) (*group.GroupExpense, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(req.GetDescription())
+	if description == "" {
+		return nil, status.Error(codes.InvalidArgument, "description is required")
+	}
+	if len([]rune(description)) > maxDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description must be at most %d characters", maxDescriptionLength)
+	}
+	amount, err := cents("amount", req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseDate(req.GetDate())
+	if err != nil {
+		return nil, err
+	}
+	paidBy := req.GetPaidBy()
+	if paidBy == 0 {
+		if paidBy, err = callerID(userId); err != nil {
+			return nil, err
+		}
+	}
+
+	input := userDB.GroupExpenseInput{
+		PaidBy:      paidBy,
+		Description: description,
+		Amount:      amount,
+		Date:        date,
+	}
+	for _, s := range req.GetShares() {
+		share := settlement.Share{Person: s.GetUserId()}
+		switch part := s.GetPart().(type) {
+		case *group.ExpenseShare_Amount:
+			c := settlement.Cents(part.Amount)
+			share.Amount = &c
+		case *group.ExpenseShare_Percent:
+			p := part.Percent
+			share.Percent = &p
+		}
+		input.Shares = append(input.Shares, share)
+	}
+
+	expense, err := userDB.AddGroupExpense(ctx, userId, req.GetGroupId(), input)
+	if err != nil {
+		return nil, groupError(err)
+	}
+	return toExpenseMessage(expense), nil
+}
+
+// ListGroupExpenses pages through a group's expenses, newest first.
+func ListGroupExpenses(ctx context.Context, req *group.ListGroupExpensesRequest) (*group.GroupExpenseList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultExpensePageSize
+	}
+	if pageSize > maxExpensePageSize {
+		pageSize = maxExpensePageSize
+	}
+
+	expenses, err := userDB.ListGroupExpenses(ctx, userId, req.GetGroupId(), pageSize, req.GetBeforeExpenseId())
+	if err != nil {
+		return nil, groupError(err)
+	}
+	resp := &group.GroupExpenseList{}
+	for i := range expenses {
+		resp.Expenses = append(resp.Expenses, toExpenseMessage(&expenses[i]))
+	}
+	return resp, nil
+}
+
+// DeleteGroupExpense removes an expense the caller created or paid.
+func DeleteGroupExpense(ctx context.Context, req *group.DeleteGroupExpenseRequest) (*group.DeleteGroupExpenseResponse, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := userDB.DeleteGroupExpense(ctx, userId, req.GetGroupId(), req.GetExpenseId()); err != nil {
+		return nil, groupError(err)
+	}
+	return &group.DeleteGroupExpenseResponse{Message: "Expense deleted successfully"}, nil
+}
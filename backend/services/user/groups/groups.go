@@ -0,0 +1,155 @@
+package groups
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/group"
+	userDB "github.com/Aneesh-Hegde/expenseManager/services/user/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxGroupNameLength = 100
+
+// CreateGroup starts a group of the caller and invites the users with the
+// given emails.
+func CreateGroup(ctx context.Context, req *group.CreateGroupRequest) (*group.Group, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "group name is required")
+	}
+	if len([]rune(name)) > maxGroupNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "group name must be at most %d characters", maxGroupNameLength)
+	}
+
+	created, err := userDB.CreateGroup(ctx, userId, name, req.GetMemberEmails())
+	if err != nil {
+		return nil, groupError(err)
+	}
+	return toGroupMessage(created), nil
+}
+
+// ListGroups returns the groups the caller is in.
+func ListGroups(ctx context.Context, req *group.ListGroupsRequest) (*group.GroupList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := userDB.ListGroups(ctx, userId)
+	if err != nil {
+		return nil, groupError(err)
+	}
+	resp := &group.GroupList{}
+	for i := range list {
+		resp.Groups = append(resp.Groups, toGroupMessage(&list[i]))
+	}
+	return resp, nil
+}
+
+// GetGroup returns one of the caller's groups with its members.
+func GetGroup(ctx context.Context, req *group.GetGroupRequest) (*group.Group, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := userDB.GetGroup(ctx, userId, req.GetGroupId())
+	if err != nil {
+		return nil, groupError(err)
+	}
+	return toGroupMessage(g), nil
+}
+
+// InviteGroupMember invites a user to one of the caller's groups by email.
+func InviteGroupMember(ctx context.Context, req *group.InviteGroupMemberRequest) (*group.Group, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.GetEmail()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	g, err := userDB.InviteGroupMember(ctx, userId, req.GetGroupId(), req.GetEmail())
+	if err != nil {
+		return nil, groupError(err)
+	}
+	return toGroupMessage(g), nil
+}
+
+// ListGroupInvitations returns the caller's pending invitations.
+func ListGroupInvitations(ctx context.Context, req *group.ListGroupInvitationsRequest) (*group.GroupInvitationList, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := userDB.ListGroupInvitations(ctx, userId)
+	if err != nil {
+		return nil, groupError(err)
+	}
+	resp := &group.GroupInvitationList{}
+	for _, inv := range invitations {
+		resp.Invitations = append(resp.Invitations, &group.GroupInvitation{
+			InvitationId:      inv.InvitationID,
+			GroupId:           inv.GroupID,
+			GroupName:         inv.GroupName,
+			InvitedBy:         inv.InvitedBy,
+			InvitedByUsername: inv.InviterName,
+			CreatedAt:         inv.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+// RespondToGroupInvitation accepts or declines one of the caller's
+// invitations. Declining returns an empty group.
+func RespondToGroupInvitation(ctx context.Context, req *group.RespondToGroupInvitationRequest) (*group.Group, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := userDB.RespondToGroupInvitation(ctx, userId, req.GetInvitationId(), req.GetAccept())
+	if err != nil {
+		return nil, groupError(err)
+	}
+	if g == nil {
+		return &group.Group{}, nil
+	}
+	return toGroupMessage(g), nil
+}
+
+// RemoveGroupMember takes a settled-up member out of a group. Leaving
+// returns an empty group.
+func RemoveGroupMember(ctx context.Context, req *group.RemoveGroupMemberRequest) (*group.Group, error) {
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberID := req.GetUserId()
+	if memberID == 0 {
+		if memberID, err = callerID(userId); err != nil {
+			return nil, err
+		}
+	}
+
+	g, err := userDB.RemoveGroupMember(ctx, userId, req.GetGroupId(), memberID)
+	if err != nil {
+		return nil, groupError(err)
+	}
+	if g == nil {
+		return &group.Group{GroupId: req.GetGroupId()}, nil
+	}
+	return toGroupMessage(g), nil
+}
@@ -0,0 +1,94 @@
+package groups
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/group"
+	userDB "github.com/Aneesh-Hegde/expenseManager/services/user/db"
+	"github.com/Aneesh-Hegde/expenseManager/services/user/settlement"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// callerID converts the caller's user ID for comparison with member IDs.
+func callerID(userId string) (int32, error) {
+	id, err := strconv.ParseInt(userId, 10, 32)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+	return int32(id), nil
+}
+
+// parseDate reads an optional YYYY-MM-DD date, defaulting to today.
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, status.Error(codes.InvalidArgument, "date must be YYYY-MM-DD")
+	}
+	return date, nil
+}
+
+// cents converts a requested amount, which must be positive and in whole
+// cents.
+func cents(field string, amount float64) (int64, error) {
+	if amount <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "%s must be positive", field)
+	}
+	if math.Abs(amount*100-math.Round(amount*100)) > 1e-6 {
+		return 0, status.Errorf(codes.InvalidArgument, "%s must be in whole cents", field)
+	}
+	return settlement.Cents(amount), nil
+}
+
+func toGroupMessage(g *userDB.Group) *group.Group {
+	msg := &group.Group{
+		GroupId:   g.GroupID,
+		Name:      g.Name,
+		CreatedBy: g.CreatedBy,
+		CreatedAt: g.CreatedAt.Format(time.RFC3339),
+	}
+	for _, m := range g.Members {
+		msg.Members = append(msg.Members, toMemberMessage(m))
+	}
+	for _, m := range g.Invited {
+		msg.Invited = append(msg.Invited, toMemberMessage(m))
+	}
+	return msg
+}
+
+func toMemberMessage(m userDB.GroupMember) *group.GroupMember {
+	return &group.GroupMember{
+		UserId:   m.UserID,
+		Username: m.Username,
+		Email:    m.Email,
+		JoinedAt: m.JoinedAt.Format(time.RFC3339),
+	}
+}
+
+// groupError reports what only some members may do as PermissionDenied,
+// and changes that outstanding balances or receipts forbid as
+// FailedPrecondition.
+func groupError(err error) error {
+	switch {
+	case errors.Is(err, userDB.ErrGroupNotFound), errors.Is(err, userDB.ErrExpenseNotFound),
+		errors.Is(err, userDB.ErrInvitationNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, userDB.ErrNotGroupMember), errors.Is(err, userDB.ErrInvalidGroup), errors.Is(err, userDB.ErrCannotInvite),
+		errors.Is(err, userDB.ErrInvalidExpense), errors.Is(err, userDB.ErrInvalidSettlement):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, userDB.ErrNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, userDB.ErrBalanceOutstanding), errors.Is(err, userDB.ErrReceiptExpense):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
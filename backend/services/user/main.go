@@ -11,9 +11,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Aneesh-Hegde/expenseManager/group"
 	grpcMiddlware "github.com/Aneesh-Hegde/expenseManager/middleware"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
 	"github.com/Aneesh-Hegde/expenseManager/services/user/auth"
+	"github.com/Aneesh-Hegde/expenseManager/services/user/groups"
 	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
 	user "github.com/Aneesh-Hegde/expenseManager/user_grpc"
 	"github.com/joho/godotenv"
@@ -93,6 +95,62 @@ func (s *UserServiceServer) VerifyUser(ctx context.Context, req *user.VerifyRequ
 	return auth.VerifyEmail(ctx, req)
 }
 
+type GroupService struct {
+	group.UnimplementedGroupServiceServer
+}
+
+func (s *GroupService) CreateGroup(ctx context.Context, req *group.CreateGroupRequest) (*group.Group, error) {
+	return groups.CreateGroup(ctx, req)
+}
+
+func (s *GroupService) ListGroups(ctx context.Context, req *group.ListGroupsRequest) (*group.GroupList, error) {
+	return groups.ListGroups(ctx, req)
+}
+
+func (s *GroupService) GetGroup(ctx context.Context, req *group.GetGroupRequest) (*group.Group, error) {
+	return groups.GetGroup(ctx, req)
+}
+
+func (s *GroupService) InviteGroupMember(ctx context.Context, req *group.InviteGroupMemberRequest) (*group.Group, error) {
+	return groups.InviteGroupMember(ctx, req)
+}
+
+func (s *GroupService) ListGroupInvitations(ctx context.Context, req *group.ListGroupInvitationsRequest) (*group.GroupInvitationList, error) {
+	return groups.ListGroupInvitations(ctx, req)
+}
+
+func (s *GroupService) RespondToGroupInvitation(ctx context.Context, req *group.RespondToGroupInvitationRequest) (*group.Group, error) {
+	return groups.RespondToGroupInvitation(ctx, req)
+}
+
+func (s *GroupService) RemoveGroupMember(ctx context.Context, req *group.RemoveGroupMemberRequest) (*group.Group, error) {
+	return groups.RemoveGroupMember(ctx, req)
+}
+
+func (s *GroupService) AddGroupExpense(ctx context.Context, req *group.AddGroupExpenseRequest) (*group.GroupExpense, error) {
+	return groups.AddGroupExpense(ctx, req)
+}
+
+func (s *GroupService) ListGroupExpenses(ctx context.Context, req *group.ListGroupExpensesRequest) (*group.GroupExpenseList, error) {
+	return groups.ListGroupExpenses(ctx, req)
+}
+
+func (s *GroupService) DeleteGroupExpense(ctx context.Context, req *group.DeleteGroupExpenseRequest) (*group.DeleteGroupExpenseResponse, error) {
+	return groups.DeleteGroupExpense(ctx, req)
+}
+
+func (s *GroupService) GetGroupBalances(ctx context.Context, req *group.GetGroupBalancesRequest) (*group.GroupBalances, error) {
+	return groups.GetGroupBalances(ctx, req)
+}
+
+func (s *GroupService) SettleUp(ctx context.Context, req *group.SettleUpRequest) (*group.Settlement, error) {
+	return groups.SettleUp(ctx, req)
+}
+
+func (s *GroupService) ListSettlements(ctx context.Context, req *group.ListSettlementsRequest) (*group.SettlementList, error) {
+	return groups.ListSettlements(ctx, req)
+}
+
 // Authentication interceptor - same as in your old implementation
 func authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	fmt.Println(info.FullMethod)
@@ -239,6 +297,7 @@ func main() {
 	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(chainInterceptor(metricInterceptor,authInterceptor)))
 
 	user.RegisterUserServiceServer(grpcServer, &UserServiceServer{})
+	group.RegisterGroupServiceServer(grpcServer, &GroupService{})
 	reflection.Register(grpcServer)
 
 	// Setup graceful shutdown
@@ -0,0 +1,273 @@
+// Package settlement works out who owes whom in a group of people sharing
+// expenses. Amounts are whole cents so balances add up exactly.
+package settlement
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Debt is money From owes To.
+type Debt struct {
+	From   int32
+	To     int32
+	Amount int64
+}
+
+// Ledger keeps a running balance for every pair of people.
+type Ledger struct {
+	// owed is keyed by the pair in ascending order; a positive amount is
+	// owed by the first to the second.
+	owed map[[2]int32]int64
+}
+
+// NewLedger returns an empty ledger.
+func NewLedger() *Ledger {
+	return &Ledger{owed: map[[2]int32]int64{}}
+}
+
+func (l *Ledger) add(from, to int32, amount int64) {
+	if from == to || amount == 0 {
+		return
+	}
+	if from < to {
+		l.owed[[2]int32{from, to}] += amount
+	} else {
+		l.owed[[2]int32{to, from}] -= amount
+	}
+}
+
+// AddExpense records that paidBy paid for everyone's shares; each person
+// then owes paidBy their share.
+func (l *Ledger) AddExpense(paidBy int32, shares map[int32]int64) {
+	for person, share := range shares {
+		l.add(person, paidBy, share)
+	}
+}
+
+// AddPayment records that from paid amount to to, settling that much of
+// what from owed.
+func (l *Ledger) AddPayment(from, to int32, amount int64) {
+	l.add(from, to, -amount)
+}
+
+// Owed returns what from owes to, net of what to owes from; negative when
+// to owes from.
+func (l *Ledger) Owed(from, to int32) int64 {
+	if from < to {
+		return l.owed[[2]int32{from, to}]
+	}
+	return -l.owed[[2]int32{to, from}]
+}
+
+// Pairs returns every outstanding pairwise debt, largest first.
+func (l *Ledger) Pairs() []Debt {
+	var debts []Debt
+	for pair, amount := range l.owed {
+		switch {
+		case amount > 0:
+			debts = append(debts, Debt{From: pair[0], To: pair[1], Amount: amount})
+		case amount < 0:
+			debts = append(debts, Debt{From: pair[1], To: pair[0], Amount: -amount})
+		}
+	}
+	sortDebts(debts)
+	return debts
+}
+
+// Net returns each person's overall balance: positive when they are owed
+// money, negative when they owe it. The balances add up to zero.
+func (l *Ledger) Net() map[int32]int64 {
+	net := map[int32]int64{}
+	for pair, amount := range l.owed {
+		net[pair[0]] -= amount
+		net[pair[1]] += amount
+	}
+	return net
+}
+
+// Simplify returns payments that settle the net balances with as few
+// transfers as a greedy match allows: the largest debtor repeatedly pays
+// the largest creditor. Debtors only pay and creditors only receive, so
+// nobody pays more than they owe overall.
+func Simplify(net map[int32]int64) []Debt {
+	type balance struct {
+		person int32
+		amount int64
+	}
+	var debtors, creditors []balance
+	for person, amount := range net {
+		switch {
+		case amount < 0:
+			debtors = append(debtors, balance{person, -amount})
+		case amount > 0:
+			creditors = append(creditors, balance{person, amount})
+		}
+	}
+	byAmount := func(list []balance) func(i, j int) bool {
+		return func(i, j int) bool {
+			if list[i].amount != list[j].amount {
+				return list[i].amount > list[j].amount
+			}
+			return list[i].person < list[j].person
+		}
+	}
+
+	var debts []Debt
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sort.Slice(debtors, byAmount(debtors))
+		sort.Slice(creditors, byAmount(creditors))
+		d, c := &debtors[0], &creditors[0]
+		amount := d.amount
+		if c.amount < amount {
+			amount = c.amount
+		}
+		debts = append(debts, Debt{From: d.person, To: c.person, Amount: amount})
+		d.amount -= amount
+		c.amount -= amount
+		if d.amount == 0 {
+			debtors = debtors[1:]
+		}
+		if c.amount == 0 {
+			creditors = creditors[1:]
+		}
+	}
+	sortDebts(debts)
+	return debts
+}
+
+func sortDebts(debts []Debt) {
+	sort.Slice(debts, func(i, j int) bool {
+		if debts[i].Amount != debts[j].Amount {
+			return debts[i].Amount > debts[j].Amount
+		}
+		if debts[i].From != debts[j].From {
+			return debts[i].From < debts[j].From
+		}
+		return debts[i].To < debts[j].To
+	})
+}
+
+// Allocate divides total cents by weights, handing leftover cents to the
+// largest remainders so the parts add up to total exactly.
+func Allocate(total int64, weights []float64) []int64 {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	parts := make([]int64, len(weights))
+	if sum <= 0 {
+		return parts
+	}
+	type remainder struct {
+		index int
+		frac  float64
+	}
+	remainders := make([]remainder, len(weights))
+	var assigned int64
+	for i, w := range weights {
+		exact := float64(total) * w / sum
+		whole := math.Floor(exact)
+		parts[i] = int64(whole)
+		assigned += parts[i]
+		remainders[i] = remainder{i, exact - whole}
+	}
+	sort.SliceStable(remainders, func(i, j int) bool { return remainders[i].frac > remainders[j].frac })
+	for i := 0; assigned < total && i < len(remainders); i++ {
+		parts[remainders[i].index]++
+		assigned++
+	}
+	return parts
+}
+
+// Cents converts an amount of money to whole cents.
+func Cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// Amount converts cents back to money.
+func Amount(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// Share is the part of an expense one person asked to take on. At most one
+// of Amount and Percent is set; when no share sets either, the expense is
+// split equally between everyone named.
+type Share struct {
+	Person  int32
+	Amount  *int64
+	Percent *float64
+}
+
+// SplitShares divides total cents between people as shares ask. Amounts
+// must add up to total and percentages to 100; the two cannot be mixed.
+func SplitShares(total int64, shares []Share) (map[int32]int64, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("an expense needs at least one person to share it")
+	}
+
+	var amounts, percents int
+	seen := map[int32]bool{}
+	for _, s := range shares {
+		if seen[s.Person] {
+			return nil, fmt.Errorf("user %d is listed more than once", s.Person)
+		}
+		seen[s.Person] = true
+		if s.Amount != nil && s.Percent != nil {
+			return nil, fmt.Errorf("the share of user %d sets both an amount and a percentage", s.Person)
+		}
+		if s.Amount != nil {
+			if *s.Amount < 0 {
+				return nil, fmt.Errorf("the share of user %d must not be negative", s.Person)
+			}
+			amounts++
+		}
+		if s.Percent != nil {
+			if *s.Percent < 0 || *s.Percent > 100 {
+				return nil, fmt.Errorf("the share of user %d must be between 0 and 100 percent", s.Person)
+			}
+			percents++
+		}
+	}
+
+	split := make(map[int32]int64, len(shares))
+	switch {
+	case amounts == 0 && percents == 0:
+		weights := make([]float64, len(shares))
+		for i := range weights {
+			weights[i] = 1
+		}
+		for i, part := range Allocate(total, weights) {
+			split[shares[i].Person] = part
+		}
+	case amounts == len(shares):
+		var sum int64
+		for _, s := range shares {
+			split[s.Person] = *s.Amount
+			sum += *s.Amount
+		}
+		if sum != total {
+			return nil, fmt.Errorf("shares add up to %.2f, not %.2f", Amount(sum), Amount(total))
+		}
+	case percents == len(shares):
+		weights := make([]float64, len(shares))
+		var sum float64
+		for i, s := range shares {
+			weights[i] = *s.Percent
+			sum += *s.Percent
+		}
+		if math.Abs(sum-100) > 0.01 {
+			return nil, fmt.Errorf("shares add up to %.2f%%, not 100%%", sum)
+		}
+		for i, part := range Allocate(total, weights) {
+			split[shares[i].Person] = part
+		}
+	default:
+		return nil, fmt.Errorf("give every share an amount, every share a percentage, or none of them")
+	}
+	return split, nil
+}
@@ -0,0 +1,32 @@
+// Package auth reads who a request is from. The gateway authenticates
+// callers and passes the user on to the services in the request metadata,
+// with the access token to hand back when it was refreshed.
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UserID reads the authenticated user from the incoming metadata and
+// forwards a refreshed access token back to the caller.
+func UserID(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "metadata not found in context")
+	}
+
+	if len(md["token"]) > 0 && len(md["token"][0]) > 0 {
+		headers := metadata.Pairs("token", md["token"][0])
+		grpc.SendHeader(ctx, headers)
+	}
+
+	if len(md["user_id"]) == 0 {
+		return "", status.Error(codes.Unauthenticated, "user ID not found in context")
+	}
+	return md["user_id"][0], nil
+}
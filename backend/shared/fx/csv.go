@@ -0,0 +1,101 @@
+package fx
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVFile reads rates from a file in the format ParseCSV reads, for
+// running without network access.
+type CSVFile struct {
+	Path string
+}
+
+// Name is the file's base name.
+func (f CSVFile) Name() string {
+	return "file:" + filepath.Base(f.Path)
+}
+
+// Rates reads the file.
+func (f CSVFile) Rates(ctx context.Context) ([]Rate, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseCSV(file)
+}
+
+// ParseCSV reads rates from CSV with a header naming the columns date,
+// base, quote and rate, in any order; other columns are ignored. Dates are
+// YYYY-MM-DD, and each row says one unit of base bought rate units of
+// quote.
+func ParseCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRate, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: the header has no %s column", ErrInvalidRate, name)
+		}
+	}
+
+	var rates []Rate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRate, err)
+		}
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", field("date"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad date %q", ErrInvalidRate, line, field("date"))
+		}
+		value, err := strconv.ParseFloat(field("rate"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: bad rate %q", ErrInvalidRate, line, field("rate"))
+		}
+		rate := Rate{
+			Base:  strings.ToUpper(field("base")),
+			Quote: strings.ToUpper(field("quote")),
+			Date:  date,
+			Rate:  value,
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -0,0 +1,176 @@
+// Package fx converts amounts between currencies at the exchange rates kept
+// in account_income_service.exchange_rates. Rates are loaded into the table
+// by a Provider: a CSV file for offline use, or an HTTP endpoint.
+//
+// A rate applies from its date until a later one is stored, so an amount is
+// converted at the latest rate on or before its own date. Pairs without a
+// rate of their own are crossed through USD.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	sharedDB "github.com/Aneesh-Hegde/expenseManager/shared/db"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrNoRate is returned when no rate converts between two currencies on a
+// date.
+var ErrNoRate = errors.New("no exchange rate")
+
+// ErrInvalidRate is returned for rates that cannot be stored.
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// noRateState is the SQLSTATE account_income_service.fx_rate_required
+// raises.
+const noRateState = "FX001"
+
+// IsNoRate reports whether err is a missing rate, whether found here or
+// raised by the database while recording one.
+func IsNoRate(err error) bool {
+	if errors.Is(err, ErrNoRate) {
+		return true
+	}
+	var pgErr interface{ SQLState() string }
+	return errors.As(err, &pgErr) && pgErr.SQLState() == noRateState
+}
+
+// Rate is how many units of Quote one unit of Base bought on Date.
+type Rate struct {
+	Base  string
+	Quote string
+	Date  time.Time
+	Rate  float64
+}
+
+// Validate checks the rate names two different currencies and is positive.
+func (r Rate) Validate() error {
+	for _, code := range []string{r.Base, r.Quote} {
+		if len(code) != 3 || strings.ToUpper(code) != code || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return fmt.Errorf("%w: %q is not a currency code", ErrInvalidRate, code)
+		}
+	}
+	if r.Base == r.Quote {
+		return fmt.Errorf("%w: %s to itself", ErrInvalidRate, r.Base)
+	}
+	if !(r.Rate > 0) {
+		return fmt.Errorf("%w: %s to %s must be positive", ErrInvalidRate, r.Base, r.Quote)
+	}
+	if r.Date.IsZero() {
+		return fmt.Errorf("%w: %s to %s has no date", ErrInvalidRate, r.Base, r.Quote)
+	}
+	return nil
+}
+
+// Provider supplies exchange rates.
+type Provider interface {
+	// Name identifies the provider as the source of the rates it stored.
+	Name() string
+	Rates(ctx context.Context) ([]Rate, error)
+}
+
+// Store writes rates, replacing any stored for the same pair and date. It
+// returns how many were written.
+func Store(ctx context.Context, tx pgx.Tx, source string, rates []Rate) (int, error) {
+	for _, r := range rates {
+		if err := r.Validate(); err != nil {
+			return 0, err
+		}
+		_, err := tx.Exec(ctx, `
+            INSERT INTO account_income_service.exchange_rates
+                (base_currency, quote_currency, rate_date, rate, source)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (base_currency, quote_currency, rate_date)
+            DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source, imported_at = CURRENT_TIMESTAMP`,
+			r.Base, r.Quote, r.Date, r.Rate, source)
+		if err != nil {
+			return 0, fmt.Errorf("error storing %s to %s rate: %v", r.Base, r.Quote, err)
+		}
+	}
+	return len(rates), nil
+}
+
+// Import stores rates in one transaction, so a bad rate leaves none of
+// them stored.
+func Import(ctx context.Context, source string, rates []Rate) (int, error) {
+	tx, err := sharedDB.GetDB().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	stored, err := Store(ctx, tx, source, rates)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error committing rates: %v", err)
+	}
+	return stored, nil
+}
+
+// Refresh fetches the provider's rates and stores them.
+func Refresh(ctx context.Context, p Provider) (int, error) {
+	rates, err := p.Rates(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching rates from %s: %v", p.Name(), err)
+	}
+	return Import(ctx, p.Name(), rates)
+}
+
+// ProvidersFromEnv returns the providers configured by EXCHANGE_RATES_FILE,
+// a CSV file, and EXCHANGE_RATES_URL, an HTTP endpoint.
+func ProvidersFromEnv() []Provider {
+	var providers []Provider
+	if path := os.Getenv("EXCHANGE_RATES_FILE"); path != "" {
+		providers = append(providers, CSVFile{Path: path})
+	}
+	if url := os.Getenv("EXCHANGE_RATES_URL"); url != "" {
+		providers = append(providers, HTTP{URL: url})
+	}
+	return providers
+}
+
+// Querier is satisfied by both the pool and a transaction.
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// Lookup returns how many units of to one unit of from bought on date.
+func Lookup(ctx context.Context, q Querier, from, to string, date time.Time) (float64, error) {
+	from, to = money.Normalize(from), money.Normalize(to)
+	var rate *float64
+	err := q.QueryRow(ctx, "SELECT account_income_service.fx_rate($1, $2, $3)::float8",
+		from, to, date).Scan(&rate)
+	if err != nil {
+		return 0, fmt.Errorf("error looking up exchange rate: %v", err)
+	}
+	if rate == nil {
+		return 0, fmt.Errorf("%w from %s to %s on %s", ErrNoRate, from, to, date.Format("2006-01-02"))
+	}
+	return *rate, nil
+}
+
+// Convert returns amount in currency to, at date's rate, with the rate it
+// used.
+func Convert(ctx context.Context, q Querier, amount money.Money, to string, date time.Time) (money.Money, float64, error) {
+	rate, err := Lookup(ctx, q, amount.Currency, to, date)
+	if err != nil {
+		return money.Money{}, 0, err
+	}
+	return At(amount, to, rate), rate, nil
+}
+
+// At converts amount to currency to at rate, rounded to to's minor unit.
+func At(amount money.Money, to string, rate float64) money.Money {
+	if money.Normalize(to) == amount.Currency {
+		return amount
+	}
+	return money.FromFloat(amount.Float64()*rate, to)
+}
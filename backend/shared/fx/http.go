@@ -0,0 +1,75 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTP fetches the latest rates from a JSON endpoint answering in the
+// common {"base": "EUR", "date": "2024-01-02", "rates": {"USD": 1.09}}
+// shape, such as the ECB reference rates served by frankfurter.app.
+type HTTP struct {
+	URL string
+	// Client defaults to one with a 30 second timeout.
+	Client *http.Client
+}
+
+// Name is the endpoint's host.
+func (h HTTP) Name() string {
+	if u, err := url.Parse(h.URL); err == nil && u.Host != "" {
+		return "http:" + u.Host
+	}
+	return "http"
+}
+
+// Rates fetches the endpoint.
+func (h HTTP) Rates(ctx context.Context) ([]Rate, error) {
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Base  string             `json:"base"`
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error decoding rates: %v", err)
+	}
+	date, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		return nil, fmt.Errorf("bad date %q", body.Date)
+	}
+
+	rates := make([]Rate, 0, len(body.Rates))
+	for quote, value := range body.Rates {
+		rate := Rate{Base: strings.ToUpper(body.Base), Quote: strings.ToUpper(quote), Date: date, Rate: value}
+		if rate.Base == rate.Quote {
+			continue
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
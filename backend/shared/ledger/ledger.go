@@ -0,0 +1,282 @@
+// Package ledger posts entries to the double-entry journal that balances are
+// derived from. Entries are written through the journal's SQL functions in
+// the caller's transaction, so money only moves if the change recording it
+// commits.
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+	"github.com/jackc/pgx/v4"
+)
+
+// Types of ledger account.
+const (
+	Asset   = "asset"
+	Income  = "income"
+	Expense = "expense"
+	Equity  = "equity"
+	Goal    = "goal"
+)
+
+// DefaultCurrency is the currency of postings that do not name one.
+const DefaultCurrency = money.DefaultCurrency
+
+// Account names a ledger account. Asset accounts are balance accounts,
+// coded by account_id; see BalanceAccount.
+type Account struct {
+	Type string
+	Code string
+}
+
+// BalanceAccount is the asset ledger account of a balance account.
+func BalanceAccount(accountID int32) Account {
+	return Account{Asset, strconv.Itoa(int(accountID))}
+}
+
+// GoalAccount holds the money set aside for a goal.
+func GoalAccount(goalID string) Account {
+	return Account{Goal, goalID}
+}
+
+// Accounts used as the other side of movements in and out of balance
+// accounts.
+var (
+	IncomeAccount     = Account{Income, "income"}
+	AdjustmentAccount = Account{Equity, "adjustment"}
+	// OpeningAccount balances what accounts held, or owed, before the
+	// journal recorded them.
+	OpeningAccount = Account{Equity, "opening"}
+)
+
+// FXAccount is the equity account amounts pass through when they change
+// currency; see Exchange.
+var FXAccount = Account{Equity, "fx"}
+
+// ExpenseAccount is where spending under a category goes; 0 is for
+// spending without one.
+func ExpenseAccount(categoryID int32) Account {
+	if categoryID == 0 {
+		return Account{Expense, "uncategorized"}
+	}
+	return Account{Expense, strconv.Itoa(int(categoryID))}
+}
+
+// Posting moves Amount into Account, or out of it when negative.
+type Posting struct {
+	Account Account
+	Amount  money.Money
+}
+
+// Entry is one balanced movement of money. Kind says what it records, such
+// as "income" or "transfer"; the reference names the record it belongs to.
+type Entry struct {
+	UserID        int32
+	Kind          string
+	ReferenceType string
+	ReferenceID   string
+	Description   string
+	OccurredAt    time.Time
+	Postings      []Posting
+}
+
+// Move is the pair of postings that takes amount from one account to
+// another.
+func Move(from, to Account, amount money.Money) []Posting {
+	return []Posting{
+		{Account: from, Amount: money.New(-amount.Minor, amount.Currency)},
+		{Account: to, Amount: money.New(amount.Minor, amount.Currency)},
+	}
+}
+
+// Exchange takes out from one account and puts in, the same value in
+// another currency, into another. The two sides pass through FXAccount so
+// that each currency balances; in the same currency it is a Move of in.
+func Exchange(from Account, out money.Money, to Account, in money.Money) []Posting {
+	if out.Currency == in.Currency {
+		return Move(from, to, in)
+	}
+	return append(Move(from, FXAccount, out), Move(FXAccount, to, in)...)
+}
+
+// Validate checks that the postings sum to zero in each currency.
+func (e Entry) Validate() error {
+	if e.ReferenceType == "" || e.ReferenceID == "" {
+		return fmt.Errorf("journal entry needs a reference")
+	}
+	sums := map[string]money.Money{}
+	for _, p := range e.Postings {
+		amount := money.New(p.Amount.Minor, p.Amount.Currency)
+		if sum, ok := sums[amount.Currency]; ok {
+			var err error
+			if amount, err = sum.Add(amount); err != nil {
+				return err
+			}
+		}
+		sums[amount.Currency] = amount
+	}
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			return fmt.Errorf("journal entry for %s %s is off by %s %s",
+				e.ReferenceType, e.ReferenceID, sum, currency)
+		}
+	}
+	return nil
+}
+
+type jsonPosting struct {
+	Type     string `json:"type"`
+	Code     string `json:"code"`
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (e Entry) postingsJSON() (string, error) {
+	postings := make([]jsonPosting, 0, len(e.Postings))
+	for _, p := range e.Postings {
+		postings = append(postings, jsonPosting{
+			Type:     p.Account.Type,
+			Code:     p.Account.Code,
+			Amount:   p.Amount.String(),
+			Currency: money.Normalize(p.Amount.Currency),
+		})
+	}
+	encoded, err := json.Marshal(postings)
+	return string(encoded), err
+}
+
+func (e Entry) call(ctx context.Context, tx pgx.Tx, function string) (int64, error) {
+	if err := e.Validate(); err != nil {
+		return 0, err
+	}
+	postings, err := e.postingsJSON()
+	if err != nil {
+		return 0, fmt.Errorf("error encoding postings: %v", err)
+	}
+	occurredAt := e.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	var description *string
+	if e.Description != "" {
+		description = &e.Description
+	}
+
+	var entryID *int64
+	err = tx.QueryRow(ctx, `SELECT account_income_service.`+function+`($1, $2, $3, $4, $5, $6, $7::jsonb)`,
+		e.UserID, e.Kind, e.ReferenceType, e.ReferenceID, description, occurredAt, postings).Scan(&entryID)
+	if err != nil {
+		return 0, fmt.Errorf("error posting journal entry: %v", err)
+	}
+	if entryID == nil {
+		return 0, nil
+	}
+	return *entryID, nil
+}
+
+// Post appends the entry as it is. It returns the new entry's ID, or 0 when
+// the postings cancel out and nothing was posted.
+func Post(ctx context.Context, tx pgx.Tx, e Entry) (int64, error) {
+	return e.call(ctx, tx, "post_journal_entry")
+}
+
+// Restate makes the postings of the entry's reference, summed over every
+// entry posted for it, equal e.Postings, by posting the difference. Records
+// that change are restated each time; an entry without postings reverses
+// the reference. It returns 0 when nothing needed posting.
+func Restate(ctx context.Context, tx pgx.Tx, e Entry) (int64, error) {
+	return e.call(ctx, tx, "restate_journal")
+}
+
+// IncomeBalanceAccount returns the balance account an income row belongs
+// to, with the currency it holds. Balance IDs handed out by the goals
+// service and refunds are income IDs.
+func IncomeBalanceAccount(ctx context.Context, tx pgx.Tx, userID int32, incomeID int32) (Account, string, error) {
+	var accountID *int32
+	var currency *string
+	err := tx.QueryRow(ctx, `
+        SELECT i.account_id, a.currency
+        FROM account_income_service.incomes i
+        LEFT JOIN account_income_service.accounts a ON a.account_id = i.account_id
+        WHERE i.income_id = $1 AND i.user_id = $2`,
+		incomeID, userID).Scan(&accountID, &currency)
+	if err != nil {
+		return Account{}, "", fmt.Errorf("error finding balance account of income %d: %v", incomeID, err)
+	}
+	if accountID == nil || currency == nil {
+		return Account{}, "", fmt.Errorf("income %d has no balance account", incomeID)
+	}
+	return BalanceAccount(*accountID), *currency, nil
+}
+
+// FundingAccount returns the account spending without a named balance
+// account comes out of, with its currency: the user's cash account, or
+// equity "unfunded" when there is none. Equity "unfunded" takes any
+// currency, so its currency is empty.
+func FundingAccount(ctx context.Context, tx pgx.Tx, userID int32) (Account, string, error) {
+	var raw string
+	err := tx.QueryRow(ctx, `SELECT account_income_service.journal_funding_account($1)::text`, userID).Scan(&raw)
+	if err != nil {
+		return Account{}, "", fmt.Errorf("error finding funding account: %v", err)
+	}
+	var account struct {
+		Type     string `json:"type"`
+		Code     string `json:"code"`
+		Currency string `json:"currency"`
+	}
+	if err := json.Unmarshal([]byte(raw), &account); err != nil {
+		return Account{}, "", fmt.Errorf("error decoding funding account: %v", err)
+	}
+	return Account{account.Type, account.Code}, account.Currency, nil
+}
+
+// AccountCurrency returns the currency one of the user's balance accounts
+// holds.
+func AccountCurrency(ctx context.Context, tx pgx.Tx, userID int32, accountID int32) (string, error) {
+	var currency string
+	err := tx.QueryRow(ctx, `
+        SELECT currency FROM account_income_service.accounts
+        WHERE account_id = $1 AND user_id = $2`,
+		accountID, userID).Scan(&currency)
+	if err != nil {
+		return "", fmt.Errorf("error finding currency of account %d: %v", accountID, err)
+	}
+	return currency, nil
+}
+
+// Querier is satisfied by both the pool and a transaction.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// Balances returns the balance of each of the user's balance accounts in
+// the currency the account holds, by account_id.
+func Balances(ctx context.Context, q Querier, userID int32) (map[int32]float64, error) {
+	rows, err := q.Query(ctx, `
+        SELECT a.account_id, b.balance::float8
+        FROM account_income_service.ledger_balances($1) b
+        JOIN account_income_service.accounts a
+            ON b.code = a.account_id::text AND b.currency = a.currency
+        WHERE b.account_type = 'asset' AND a.user_id = $1`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading balances: %v", err)
+	}
+	defer rows.Close()
+
+	balances := map[int32]float64{}
+	for rows.Next() {
+		var accountID int32
+		var balance float64
+		if err := rows.Scan(&accountID, &balance); err != nil {
+			return nil, fmt.Errorf("error scanning balance: %v", err)
+		}
+		balances[accountID] = balance
+	}
+	return balances, rows.Err()
+}
@@ -0,0 +1,130 @@
+package ledger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
+)
+
+func sums(t *testing.T, postings []Posting) map[string]money.Money {
+	t.Helper()
+	totals := map[string]money.Money{}
+	for _, p := range postings {
+		amount := p.Amount
+		if sum, ok := totals[amount.Currency]; ok {
+			var err error
+			if amount, err = sum.Add(amount); err != nil {
+				t.Fatal(err)
+			}
+		}
+		totals[amount.Currency] = amount
+	}
+	return totals
+}
+
+func TestMoveBalances(t *testing.T) {
+	postings := Move(IncomeAccount, BalanceAccount(7), money.FromFloat(12.34, "USD"))
+	if len(postings) != 2 {
+		t.Fatalf("got %d postings, want 2", len(postings))
+	}
+	if postings[0].Account != IncomeAccount || postings[0].Amount != money.New(-1234, "USD") {
+		t.Errorf("from posting = %+v", postings[0])
+	}
+	if postings[1].Account != BalanceAccount(7) || postings[1].Amount != money.New(1234, "USD") {
+		t.Errorf("to posting = %+v", postings[1])
+	}
+	for currency, sum := range sums(t, postings) {
+		if !sum.IsZero() {
+			t.Errorf("%s postings sum to %s", currency, sum)
+		}
+	}
+}
+
+func TestExchangeBalancesEachCurrency(t *testing.T) {
+	postings := Exchange(BalanceAccount(1), money.FromFloat(110, "USD"), ExpenseAccount(3), money.FromFloat(100, "EUR"))
+	if len(postings) != 4 {
+		t.Fatalf("got %d postings, want 4", len(postings))
+	}
+	totals := sums(t, postings)
+	if len(totals) != 2 {
+		t.Fatalf("postings are in %d currencies, want 2", len(totals))
+	}
+	for currency, sum := range totals {
+		if !sum.IsZero() {
+			t.Errorf("%s postings sum to %s", currency, sum)
+		}
+	}
+
+	same := Exchange(BalanceAccount(1), money.FromFloat(100, "USD"), ExpenseAccount(3), money.FromFloat(100, "USD"))
+	if len(same) != 2 {
+		t.Errorf("same-currency exchange has %d postings, want a Move's 2", len(same))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   Entry
+		wantErr string
+	}{
+		{
+			name: "balanced",
+			entry: Entry{ReferenceType: "income", ReferenceID: "1",
+				Postings: Move(IncomeAccount, BalanceAccount(1), money.FromFloat(50, "USD"))},
+		},
+		{
+			name:  "no postings reverses",
+			entry: Entry{ReferenceType: "refund", ReferenceID: "2"},
+		},
+		{
+			name: "off by a cent",
+			entry: Entry{ReferenceType: "transfer", ReferenceID: "3", Postings: []Posting{
+				{Account: BalanceAccount(1), Amount: money.New(-1000, "USD")},
+				{Account: BalanceAccount(2), Amount: money.New(999, "USD")},
+			}},
+			wantErr: "is off by",
+		},
+		{
+			name: "balanced across currencies only",
+			entry: Entry{ReferenceType: "transfer", ReferenceID: "4", Postings: []Posting{
+				{Account: BalanceAccount(1), Amount: money.New(-1000, "USD")},
+				{Account: BalanceAccount(2), Amount: money.New(1000, "EUR")},
+			}},
+			wantErr: "is off by",
+		},
+		{
+			name:    "no reference",
+			entry:   Entry{Postings: Move(IncomeAccount, BalanceAccount(1), money.FromFloat(1, "USD"))},
+			wantErr: "needs a reference",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.entry.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPostingsJSONUsesExactAmounts(t *testing.T) {
+	entry := Entry{ReferenceType: "product", ReferenceID: "1",
+		Postings: Move(BalanceAccount(1), ExpenseAccount(0), money.FromFloat(0.1+0.2, "USD"))}
+	encoded, err := entry.postingsJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"type":"asset","code":"1","amount":"-0.30","currency":"USD"},` +
+		`{"type":"expense","code":"uncategorized","amount":"0.30","currency":"USD"}]`
+	if encoded != want {
+		t.Errorf("postingsJSON() = %s, want %s", encoded, want)
+	}
+}
@@ -0,0 +1,86 @@
+package money
+
+import (
+	"fmt"
+
+	"github.com/Aneesh-Hegde/expenseManager/grpc_money"
+)
+
+// Message is m as the shared Money message, with both minor_units and value
+// filled in.
+func (m Money) Message() *grpc_money.Money {
+	return &grpc_money.Money{MinorUnits: m.Minor, Currency: m.Currency, Value: m.String()}
+}
+
+// MessageOf converts an amount read from a NUMERIC column, which holds it to
+// the currency's minor unit, to its exact message.
+func MessageOf(amount float64, currency string) *grpc_money.Money {
+	return FromFloat(amount, currency).Message()
+}
+
+// FromMessage is the amount a message gives: its value when set, else its
+// minor units.
+func FromMessage(m *grpc_money.Money) (Money, error) {
+	if m.GetValue() != "" {
+		return Parse(m.GetValue(), m.GetCurrency())
+	}
+	return New(m.GetMinorUnits(), m.GetCurrency()), nil
+}
+
+// Amount is an amount a request asks for, kept as the request gave it until
+// the currency it is in is known: exact when the request sets the Money
+// field, else a legacy float in whatever currency the account or product
+// holds.
+type Amount struct {
+	legacy  float64
+	message *grpc_money.Money
+}
+
+// Requested is the amount a request asks for, from its legacy float and its
+// Money field, which wins when set. It checks that the field's value parses.
+func Requested(legacy float64, m *grpc_money.Money) (Amount, error) {
+	if m != nil {
+		if _, err := FromMessage(m); err != nil {
+			return Amount{}, err
+		}
+	}
+	return Amount{legacy: legacy, message: m}, nil
+}
+
+// Currency is the currency the request names, or empty for a legacy amount.
+func (a Amount) Currency() string {
+	if a.message == nil {
+		return ""
+	}
+	return Normalize(a.message.GetCurrency())
+}
+
+// In is the amount in currency, the one the account or product it applies
+// to holds. A legacy amount is rounded to that currency's minor unit; an
+// amount naming another currency is an ErrCurrencyMismatch.
+func (a Amount) In(currency string) (Money, error) {
+	currency = Normalize(currency)
+	if a.message == nil {
+		return FromFloat(a.legacy, currency), nil
+	}
+	if named := a.Currency(); named != currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, named, currency)
+	}
+	return FromMessage(a.message)
+}
+
+// Sign is -1, 0 or 1 as the amount is negative, zero or positive.
+func (a Amount) Sign() int {
+	value := a.legacy
+	if a.message != nil {
+		m, _ := FromMessage(a.message)
+		value = float64(m.Minor)
+	}
+	switch {
+	case value < 0:
+		return -1
+	case value > 0:
+		return 1
+	}
+	return 0
+}
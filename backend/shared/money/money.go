@@ -0,0 +1,159 @@
+// Package money represents amounts exactly, as a whole number of the
+// currency's minor unit (cents for USD), so sums and line totals do not pick
+// up the drift float arithmetic gives them.
+//
+// Wherever an amount has to be rounded to the minor unit it is rounded half
+// away from zero, as Postgres rounds NUMERIC: 0.005 becomes 0.01 and -0.005
+// becomes -0.01.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultCurrency is the currency of amounts that do not name one.
+const DefaultCurrency = "USD"
+
+// exponents lists currencies whose minor unit is not a hundredth.
+var exponents = map[string]int{
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// maxDigits bounds the digits an amount can have, well inside int64.
+const maxDigits = 17
+
+// Exponent is how many decimal places the currency's minor unit has.
+func Exponent(currency string) int {
+	if e, ok := exponents[Normalize(currency)]; ok {
+		return e
+	}
+	return 2
+}
+
+// Normalize upper-cases a currency code, defaulting an empty one.
+func Normalize(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// Money is Minor of the currency's minor unit.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// New is minor units of currency.
+func New(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: Normalize(currency)}
+}
+
+// FromFloat rounds amount to the currency's minor unit. Values read from
+// NUMERIC columns holding at most the currency's decimals convert exactly.
+func FromFloat(amount float64, currency string) Money {
+	currency = Normalize(currency)
+	scaled := amount * math.Pow10(Exponent(currency))
+	// Nudge values like 1.005, stored as 1.00499..., to the half they
+	// were written as before rounding.
+	return Money{Minor: int64(math.Round(scaled + math.Copysign(1e-7, scaled))), Currency: currency}
+}
+
+// Parse reads a decimal amount such as "-12.34". Digits past the currency's
+// minor unit are rounded.
+func Parse(s, currency string) (Money, error) {
+	currency = Normalize(currency)
+	exp := Exponent(currency)
+	text := strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		negative = text[0] == '-'
+		text = text[1:]
+	}
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" && fraction == "" {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return Money{}, fmt.Errorf("invalid amount %q", s)
+		}
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole)+exp > maxDigits {
+		return Money{}, fmt.Errorf("amount %q is too large", s)
+	}
+
+	roundUp := len(fraction) > exp && fraction[exp] >= '5'
+	if len(fraction) > exp {
+		fraction = fraction[:exp]
+	}
+	fraction += strings.Repeat("0", exp-len(fraction))
+
+	var minor int64
+	for _, r := range whole + fraction {
+		minor = minor*10 + int64(r-'0')
+	}
+	if roundUp {
+		minor++
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// Zero is no money in currency.
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// IsZero reports whether m is no money.
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// Float64 is m in major units. Use it only where an amount leaves exact
+// arithmetic, such as to fill a legacy float field.
+func (m Money) Float64() float64 {
+	return float64(m.Minor) / math.Pow10(Exponent(m.Currency))
+}
+
+// String renders m as a decimal with the currency's places, e.g. "-12.30".
+func (m Money) String() string {
+	exp := Exponent(m.Currency)
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	if exp == 0 {
+		return fmt.Sprintf("%s%d", sign, minor)
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/unit, exp, minor%unit)
+}
+
+// ErrCurrencyMismatch is returned when amounts of different currencies are
+// combined; converting between them is the caller's decision, not a rounding
+// question.
+var ErrCurrencyMismatch = errors.New("amounts in different currencies cannot be combined")
+
+// Add is m plus o, which must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if Normalize(m.Currency) != Normalize(o.Currency) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Minor: m.Minor + o.Minor, Currency: Normalize(m.Currency)}, nil
+}
+
+// Mul is m times quantity, rounded to the minor unit: the line total of a
+// quantity at unit price m.
+func (m Money) Mul(quantity float64) Money {
+	return Money{Minor: int64(math.Round(float64(m.Minor) * quantity)), Currency: Normalize(m.Currency)}
+}
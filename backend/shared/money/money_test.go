@@ -0,0 +1,158 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Aneesh-Hegde/expenseManager/grpc_money"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{in: "12.34", currency: "usd", want: Money{1234, "USD"}},
+		{in: "-12.3", currency: "", want: Money{-1230, "USD"}},
+		{in: "+7", currency: "EUR", want: Money{700, "EUR"}},
+		{in: ".5", currency: "USD", want: Money{50, "USD"}},
+		{in: "1.005", currency: "USD", want: Money{101, "USD"}},
+		{in: "-1.005", currency: "USD", want: Money{-101, "USD"}},
+		{in: "1.004", currency: "USD", want: Money{100, "USD"}},
+		{in: "1500", currency: "JPY", want: Money{1500, "JPY"}},
+		{in: "1.2345", currency: "KWD", want: Money{1235, "KWD"}},
+		{in: " 0042.10 ", currency: "USD", want: Money{4210, "USD"}},
+		{in: "", currency: "USD", wantErr: true},
+		{in: ".", currency: "USD", wantErr: true},
+		{in: "1,50", currency: "USD", wantErr: true},
+		{in: "12a", currency: "USD", wantErr: true},
+		{in: "1e3", currency: "USD", wantErr: true},
+		{in: "123456789012345678", currency: "USD", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.currency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q, %q) = %v, %v; want %v", tt.in, tt.currency, got, err, tt.want)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     int64
+	}{
+		// 1.005 is stored as 1.00499999999999989...; the nudge rounds it as
+		// written.
+		{1.005, "USD", 101},
+		{-1.005, "USD", -101},
+		{2.675, "USD", 268},
+		{0.1 + 0.2, "USD", 30},
+		{1.004, "USD", 100},
+		{12.34, "USD", 1234},
+		{0, "USD", 0},
+		{1499.5, "JPY", 1500},
+		{1.0005, "BHD", 1001},
+	}
+	for _, tt := range tests {
+		if got := FromFloat(tt.amount, tt.currency); got.Minor != tt.want {
+			t.Errorf("FromFloat(%v, %s) = %d minor units, want %d", tt.amount, tt.currency, got.Minor, tt.want)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		price    Money
+		quantity float64
+		want     int64
+	}{
+		{Money{199, "USD"}, 3, 597},
+		{Money{333, "USD"}, 0.5, 167},
+		{Money{-333, "USD"}, 0.5, -167},
+		{Money{1000, "USD"}, 0, 0},
+		{Money{125, "USD"}, 1.5, 188},
+	}
+	for _, tt := range tests {
+		got := tt.price.Mul(tt.quantity)
+		if got.Minor != tt.want || got.Currency != tt.price.Currency {
+			t.Errorf("%v.Mul(%v) = %v, want %d %s", tt.price, tt.quantity, got, tt.want, tt.price.Currency)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	sum, err := New(150, "usd").Add(New(-25, "USD"))
+	if err != nil || sum != (Money{125, "USD"}) {
+		t.Errorf("Add = %v, %v; want 1.25 USD", sum, err)
+	}
+	if _, err := New(150, "USD").Add(New(150, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add across currencies = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{Money{-1230, "USD"}, "-12.30"},
+		{Money{5, "USD"}, "0.05"},
+		{Money{1500, "JPY"}, "1500"},
+		{Money{1235, "KWD"}, "1.235"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestRequested(t *testing.T) {
+	tests := []struct {
+		name     string
+		legacy   float64
+		message  *grpc_money.Money
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{name: "legacy rounds to the cent", legacy: 1.005, currency: "USD", want: Money{101, "USD"}},
+		{name: "legacy rounds to the account's minor unit", legacy: 1.0005, currency: "kwd", want: Money{1001, "KWD"}},
+		{name: "legacy in a currency without decimals", legacy: 1500.4, currency: "JPY", want: Money{1500, "JPY"}},
+		{name: "value wins over minor units", message: &grpc_money.Money{MinorUnits: 1, Value: "2.50", Currency: "eur"}, currency: "EUR", want: Money{250, "EUR"}},
+		{name: "minor units", legacy: 9, message: &grpc_money.Money{MinorUnits: 1234}, currency: "USD", want: Money{1234, "USD"}},
+		{name: "another currency", message: &grpc_money.Money{Value: "2.50", Currency: "EUR"}, currency: "USD", wantErr: ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := Requested(tt.legacy, tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := amount.In(tt.currency)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("In(%q) = %v, %v; want %v, %v", tt.currency, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Requested(0, &grpc_money.Money{Value: "abc"}); err == nil {
+		t.Error("Requested() with a bad value = nil, want an error")
+	}
+	legacy, _ := Requested(-3, nil)
+	exact, _ := Requested(0, &grpc_money.Money{Value: "0.01"})
+	if legacy.Currency() != "" || legacy.Sign() != -1 || exact.Currency() != "USD" || exact.Sign() != 1 {
+		t.Errorf("Currency() and Sign() = %q %d and %q %d, want \"\" -1 and USD 1",
+			legacy.Currency(), legacy.Sign(), exact.Currency(), exact.Sign())
+	}
+}
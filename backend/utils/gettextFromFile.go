@@ -12,6 +12,7 @@ import (
 	"github.com/Aneesh-Hegde/expenseManager/data"
 	pb "github.com/Aneesh-Hegde/expenseManager/grpc"
 	"github.com/Aneesh-Hegde/expenseManager/redis"
+	"github.com/Aneesh-Hegde/expenseManager/shared/money"
 	"github.com/Aneesh-Hegde/expenseManager/states"
 	"github.com/minio/minio-go/v7"
 	"github.com/otiai10/gosseract/v2"
@@ -174,12 +175,17 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 				Amount:      float32(product.Amount),
 				Date:        product.Date,
 				Category:    product.Category,
+				AmountMoney: money.MessageOf(product.Amount, money.DefaultCurrency),
 			})
 		}
-		total := calculateTotal(cachedProducts)
+		total, err := calculateTotal(cachedProducts)
+		if err != nil {
+			return nil, err
+		}
 		return &pb.GetTextResponse{
-			Products: grpcProducts,
-			Total:    strconv.FormatFloat(total, 'f', 2, 64),
+			Products:   grpcProducts,
+			Total:      total.String(),
+			TotalMoney: total.Message(),
 		}, nil
 	}
 
@@ -257,14 +263,12 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 
 	// Process extracted data
 	var products []states.Product
-	total := 0.0
 	for i, product := range extractedData {
 		product.ID = strconv.Itoa(i + 1)
 		product.FileName = filename
 		if product.Date == "" {
 			product.Date = time.Now().Format("2006-01-02")
 		}
-		total += product.Quantity * product.Amount
 		products = append(products, product)
 	}
 
@@ -294,19 +298,31 @@ func GetText(ctx context.Context, req *pb.GetTextRequest) (*pb.GetTextResponse,
 			Name:        filename,
 			Date:        product.Date,
 			Category:    product.Category,
+			AmountMoney: money.MessageOf(product.Amount, money.DefaultCurrency),
 		})
 	}
 
+	total, err := calculateTotal(products)
+	if err != nil {
+		return nil, err
+	}
 	return &pb.GetTextResponse{
-		Products: grpcProducts,
-		Total:    strconv.FormatFloat(total, 'f', 2, 64),
+		Products:   grpcProducts,
+		Total:      total.String(),
+		TotalMoney: total.Message(),
 	}, nil
 }
 
-func calculateTotal(products []states.Product) float64 {
-	total := 0.0
+// calculateTotal sums the products' line totals, each rounded to the cent
+// as it is when stored.
+func calculateTotal(products []states.Product) (money.Money, error) {
+	total := money.Zero(money.DefaultCurrency)
 	for _, product := range products {
-		total += product.Quantity * product.Amount
+		var err error
+		if total, err = total.Add(money.FromFloat(product.Amount, money.DefaultCurrency).Mul(product.Quantity)); err != nil {
+			return money.Money{}, err
+		}
 	}
-	return total
+	return total, nil
 }
+
@@ -8,7 +8,10 @@ mkdir -p "$GO_OUTPUT_DIR"
 mkdir -p "$JS_OUTPUT_DIR"
 
 echo "🛠 Generating Go protobuf files..."
-protoc -I"$PROTO_SRC" --go_out="$GO_OUTPUT_DIR" --go-grpc_out="$GO_OUTPUT_DIR" "$PROTO_SRC"/*.proto
+# money.proto is imported by the others, so its go_package is a full import
+# path; strip the module prefix to write it under backend/grpc_money.
+protoc -I"$PROTO_SRC" --go_out="$GO_OUTPUT_DIR" --go_opt=module=github.com/Aneesh-Hegde/expenseManager "$PROTO_SRC"/money.proto
+protoc -I"$PROTO_SRC" --go_out="$GO_OUTPUT_DIR" --go-grpc_out="$GO_OUTPUT_DIR" $(ls "$PROTO_SRC"/*.proto | grep -v '/money\.proto$')
 echo "✅ Go protobuf files generated in $GO_OUTPUT_DIR"
 
 echo "🛠 Generating JavaScript & gRPC-Web protobuf files..."